@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// videoAdjust holds the libvlc adjust filter settings of a player. They
+// change only what is shown on screen.
+type videoAdjust struct {
+	Brightness float64 // 0–2
+	Contrast   float64 // 0–2
+	Saturation float64 // 0–3
+	Gamma      float64 // 0.01–10
+}
+
+var defaultAdjust = videoAdjust{Brightness: 1, Contrast: 1, Saturation: 1, Gamma: 1}
+
+func (a videoAdjust) String() string {
+	return fmt.Sprintf("brightness %.2f, contrast %.2f, saturation %.2f, gamma %.2f",
+		a.Brightness, a.Contrast, a.Saturation, a.Gamma)
+}
+
+// adjusted reports whether the preview differs from the decoded frames.
+func (vp *VideoPlayer) adjusted() bool {
+	return vp.adjust != defaultAdjust || vp.levels != levelsAsEncoded
+}
+
+// setAdjust applies new preview adjustments through libvlc's adjust filter,
+// combined with any levels conversion.
+func (vp *VideoPlayer) setAdjust(a videoAdjust) {
+	vp.adjust = a
+	p := vp.player
+	enabled := vp.adjusted()
+	errs := []error{p.EnableVideoAdjustments(enabled)}
+	if enabled {
+		contrast, brightness, saturation := vp.levels.adjustFactors()
+		errs = append(errs, p.SetBrightness(a.Brightness+brightness), p.SetContrast(a.Contrast*contrast),
+			p.SetSaturation(a.Saturation*saturation), p.SetGamma(a.Gamma))
+	}
+	for _, err := range errs {
+		if err != nil {
+			log.Printf("%s: setting video adjustments: %v", vp.title, err)
+			break
+		}
+	}
+	vp.updateStats()
+}
+
+// decodeFrame decodes the frame at seconds from path with ffmpeg. libvlc
+// snapshots include the adjust filter, so grabs for exports and metrics go
+// through here while the preview is adjusted.
+func decodeFrame(path string, seconds float64) (image.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	out, err := runFFmpeg(ctx, "-ss", fmt.Sprintf("%.3f", seconds), "-i", path,
+		"-frames:v", "1", "-f", "image2pipe", "-c:v", "png", "-")
+	if err != nil {
+		return nil, err
+	}
+	img, err := png.Decode(bytes.NewReader(out))
+	if err != nil {
+		return nil, fmt.Errorf("decoding frame: %w", err)
+	}
+	return img, nil
+}
+
+// showAdjustDialog shows sliders for vp's preview adjustments.
+func (app *VideoCompareApp) showAdjustDialog(vp *VideoPlayer) {
+	a := vp.adjust
+	var sliders []*widget.Slider
+	slider := func(min, max float64, value *float64) *widget.Slider {
+		s := widget.NewSlider(min, max)
+		s.Step = 0.01
+		s.SetValue(*value)
+		s.OnChanged = func(v float64) {
+			*value = v
+			vp.setAdjust(a)
+		}
+		sliders = append(sliders, s)
+		return s
+	}
+	form := widget.NewForm(
+		widget.NewFormItem("Brightness", slider(0, 2, &a.Brightness)),
+		widget.NewFormItem("Contrast", slider(0, 2, &a.Contrast)),
+		widget.NewFormItem("Saturation", slider(0, 3, &a.Saturation)),
+		widget.NewFormItem("Gamma", slider(0.1, 4, &a.Gamma)),
+	)
+	levels := widget.NewSelect(levelsConversions, func(s string) {
+		vp.setLevels(levelsConversion(s))
+		app.fileSettings.remember(vp)
+		app.refreshMetadataTable()
+	})
+	levels.SetSelected(string(vp.levels))
+	levelsItem := widget.NewFormItem("Levels", levels)
+	levelsItem.HintText = fmt.Sprintf("Source range: %s", formatValue(func(f *videoFormat) string { return f.ColorRange })(vp))
+	form.AppendItem(levelsItem)
+	reset := widget.NewButtonWithIcon("Reset", theme.ViewRefreshIcon(), func() {
+		a = defaultAdjust
+		for i, v := range []float64{a.Brightness, a.Contrast, a.Saturation, a.Gamma} {
+			sliders[i].SetValue(v)
+		}
+		levels.SetSelected(string(levelsAsEncoded))
+		vp.setAdjust(a)
+	})
+	note := widget.NewLabel("Adjustments change the preview only. Snapshots, exports\nand metrics use the unadjusted frames, though metrics apply\nthe levels conversion under File > Normalize Color Range.")
+	if isNetworkSource(vp.path) {
+		// Streams can't be decoded at an arbitrary position, so grabs come from libvlc
+		note.SetText("Network streams are grabbed from the preview, so snapshots,\nexports and metrics include these adjustments.")
+	}
+
+	content := container.NewVBox(form, reset, note)
+	d := dialog.NewCustom(vp.title+" Preview Adjustments", "Close", content, app.window)
+	d.Resize(fyne.NewSize(420, 0))
+	d.Show()
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"videocompare"
+)
+
+// Outputs of the aligned clip export.
+const (
+	alignedTwoFiles   = "Two Files"
+	alignedSideBySide = "Side-by-Side"
+)
+
+// alignedCut is the part of one clip that overlaps the other once the
+// offset is applied.
+type alignedCut struct {
+	path  string
+	start float64
+	fps   float64
+}
+
+// exportAlignedClip writes duration seconds of cut to dir, named after the
+// side it came from, stream copying when the cut starts on a keyframe and
+// re-encoding otherwise. It returns the written path.
+func exportAlignedClip(ctx context.Context, cut alignedCut, side string, duration float64, dir string) (string, error) {
+	copyable, err := videocompare.KeyframeAt(ctx, cut.path, cut.start, cut.fps)
+	if err != nil {
+		log.Printf("checking keyframes of %s: %v", cut.path, err)
+	}
+	base := side + "-" + strings.TrimSuffix(filepath.Base(cut.path), filepath.Ext(cut.path))
+	args := []string{"-y", "-ss", fmt.Sprintf("%.3f", cut.start), "-i", cut.path, "-t", fmt.Sprintf("%.3f", duration)}
+	var out string
+	if copyable {
+		out = filepath.Join(dir, base+"-aligned"+filepath.Ext(cut.path))
+		args = append(args, "-map", "0", "-c", "copy", "-avoid_negative_ts", "make_zero", out)
+	} else {
+		out = filepath.Join(dir, base+"-aligned.mp4")
+		args = append(args, "-map", "0:v:0", "-map", "0:a?", "-c:v", "libx264", "-crf", "16",
+			"-pix_fmt", "yuv420p", "-c:a", "aac", out)
+	}
+	_, err = runFFmpeg(ctx, args...)
+	return out, err
+}
+
+// exportAlignedSideBySide encodes both cuts next to each other, the right
+// one scaled to the left's size.
+func exportAlignedSideBySide(ctx context.Context, left, right alignedCut, duration float64, dir string) (string, error) {
+	out := filepath.Join(dir, "side-by-side-aligned.mp4")
+	d := fmt.Sprintf("%.3f", duration)
+	_, err := runFFmpeg(ctx, "-y",
+		"-ss", fmt.Sprintf("%.3f", left.start), "-t", d, "-i", left.path,
+		"-ss", fmt.Sprintf("%.3f", right.start), "-t", d, "-i", right.path,
+		"-filter_complex", "[1:v][0:v]scale2ref[r][l];[l][r]hstack[v]",
+		"-map", "[v]", "-c:v", "libx264", "-crf", "16", "-pix_fmt", "yuv420p", out)
+	return out, err
+}
+
+// exportAlignedDialog asks for the offset between the clips and the kind
+// of output. The offset defaults to the difference between the players'
+// positions, so pausing both on the same frame lines them up.
+func (app *VideoCompareApp) exportAlignedDialog() {
+	l, r := app.leftPlayer, app.rightPlayer
+	if !l.canPlay() || !r.canPlay() || isNetworkSource(l.path) || isNetworkSource(r.path) {
+		dialog.ShowInformation("Export Aligned Clips", "Load a local video on both sides first.", app.window)
+		return
+	}
+	offsetEntry := widget.NewEntry()
+	offsetEntry.SetText(fmt.Sprintf("%.3f", r.currentTime-l.currentTime))
+	offsetEntry.Validator = func(s string) error {
+		_, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		return err
+	}
+	offsetItem := widget.NewFormItem("Right offset (s)", offsetEntry)
+	offsetItem.HintText = "Right clip time minus left clip time at the same frame"
+	outputSelect := widget.NewSelect([]string{alignedTwoFiles, alignedSideBySide}, nil)
+	outputSelect.SetSelected(alignedTwoFiles)
+
+	dialog.ShowForm("Export Aligned Clips", "Next", "Cancel",
+		[]*widget.FormItem{offsetItem, widget.NewFormItem("Output", outputSelect)},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			offset, _ := strconv.ParseFloat(strings.TrimSpace(offsetEntry.Text), 64)
+			app.chooseAlignedFolder(offset, outputSelect.Selected)
+		}, app.window)
+}
+
+func (app *VideoCompareApp) chooseAlignedFolder(offset float64, output string) {
+	l, r := app.leftPlayer, app.rightPlayer
+	leftStart, rightStart, duration := videocompare.CommonRange(l.duration, r.duration, offset)
+	if duration <= 0 {
+		dialog.ShowInformation("Export Aligned Clips", "The clips don't overlap with this offset.", app.window)
+		return
+	}
+	left := alignedCut{path: l.path, start: leftStart, fps: l.fps}
+	right := alignedCut{path: r.path, start: rightStart, fps: r.fps}
+
+	dialog.ShowFolderOpen(func(folder fyne.ListableURI, err error) {
+		if err != nil || folder == nil {
+			return
+		}
+		dir := folder.Path()
+		app.pauseAll()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		steps := 2
+		if output == alignedSideBySide {
+			steps = 1
+		}
+		bar := widget.NewProgressBar()
+		bar.Max = float64(steps)
+		status := widget.NewLabel(fmt.Sprintf("Exporting %s from %s + %s…",
+			formatTime(duration), formatTime(leftStart), formatTime(rightStart)))
+		progress := dialog.NewCustom("Exporting Aligned Clips", "Cancel", container.NewVBox(status, bar), app.window)
+		progress.SetOnClosed(cancel)
+		progress.Show()
+
+		go func() {
+			var written []string
+			var err error
+			if output == alignedSideBySide {
+				var out string
+				if out, err = exportAlignedSideBySide(ctx, left, right, duration, dir); err == nil {
+					written = append(written, out)
+				}
+			} else {
+				for i, cut := range []alignedCut{left, right} {
+					var out string
+					if out, err = exportAlignedClip(ctx, cut, []string{"left", "right"}[i], duration, dir); err != nil {
+						break
+					}
+					written = append(written, out)
+					fyne.Do(func() { bar.SetValue(float64(i + 1)) })
+				}
+			}
+			fyne.Do(func() {
+				cancelled := ctx.Err() != nil
+				progress.Hide()
+				switch {
+				case cancelled:
+				case err != nil:
+					log.Printf("aligned clip export: %v", err)
+					dialog.ShowError(err, app.window)
+				default:
+					names := make([]string, len(written))
+					for i, w := range written {
+						names[i] = filepath.Base(w)
+					}
+					dialog.ShowInformation("Export Aligned Clips", "Wrote "+strings.Join(names, ", "), app.window)
+				}
+			})
+		}()
+	}, app.window)
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	toolNone      = "None"
+	toolArrow     = "Arrow"
+	toolRectangle = "Rectangle"
+	toolFreehand  = "Freehand"
+)
+
+var annotationTools = []string{toolNone, toolArrow, toolRectangle, toolFreehand, toolMeasure, toolROI}
+
+var annotationColors = map[string]color.NRGBA{
+	"Red":    {R: 0xff, G: 0x30, B: 0x30, A: 0xff},
+	"Yellow": {R: 0xff, G: 0xe0, B: 0x20, A: 0xff},
+	"Green":  {R: 0x30, G: 0xe0, B: 0x50, A: 0xff},
+	"Cyan":   {R: 0x20, G: 0xd0, B: 0xff, A: 0xff},
+	"White":  {R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+}
+
+var annotationColorNames = []string{"Red", "Yellow", "Green", "Cyan", "White"}
+
+// annotationWidths are stroke widths in source pixels.
+var annotationWidths = map[string]float64{"Thin": 3, "Medium": 6, "Thick": 12}
+
+var annotationWidthNames = []string{"Thin", "Medium", "Thick"}
+
+// annotation is a mark drawn over a frame. Points are normalised to [0, 1]
+// of the whole frame, whatever the zoom they were drawn at, so the mark
+// scales with the display size and maps onto the source frame.
+type annotation struct {
+	Tool   string       `json:"tool"`
+	Points [][2]float64 `json:"points"`
+	Color  string       `json:"color"`
+	Width  float64      `json:"width"` // source pixels
+}
+
+// annotator holds the active drawing tool and the stroke being drawn.
+type annotator struct {
+	app   *VideoCompareApp
+	tool  string
+	color string
+	width float64
+
+	active       *annotation
+	activePlayer *VideoPlayer
+	measureLabel *widget.Label
+}
+
+func newAnnotator(app *VideoCompareApp) *annotator {
+	return &annotator{
+		app:   app,
+		tool:  toolNone,
+		color: annotationColorNames[0],
+		width: annotationWidths["Medium"],
+	}
+}
+
+func (an *annotator) toolbar() fyne.CanvasObject {
+	toolSelect := widget.NewSelect(annotationTools, func(tool string) { an.tool = tool })
+	toolSelect.SetSelected(an.tool)
+
+	colorSelect := widget.NewSelect(annotationColorNames, func(name string) { an.color = name })
+	colorSelect.SetSelected(an.color)
+
+	widthSelect := widget.NewSelect(annotationWidthNames, func(name string) { an.width = annotationWidths[name] })
+	widthSelect.SetSelected("Medium")
+
+	clearBtn := widget.NewButtonWithIcon("Clear Drawings", theme.ContentClearIcon(), func() {
+		an.setAnnotations(nil, nil)
+		an.changed()
+	})
+	clearMeasureBtn := widget.NewButton("Clear Measurements", an.clearMeasurements)
+	an.measureLabel = widget.NewLabel("")
+
+	return container.NewHBox(
+		widget.NewLabel("Draw:"), toolSelect,
+		widget.NewLabel("Color:"), colorSelect,
+		widget.NewLabel("Width:"), widthSelect,
+		clearBtn, clearMeasureBtn,
+		an.measureLabel,
+	)
+}
+
+// drag extends the stroke being drawn on vp's video area.
+func (an *annotator) drag(vp *VideoPlayer, ev *fyne.DragEvent) {
+	if an.tool == toolNone || vp.path == "" {
+		return
+	}
+	if an.tool == toolMeasure {
+		an.measureDrag(vp, ev)
+		return
+	}
+	if an.tool == toolROI {
+		an.app.roi.drag(vp, ev)
+		return
+	}
+	point := vp.framePoint(vp.display.clampedPosition(ev.Position))
+
+	if an.active == nil || an.activePlayer != vp {
+		start := vp.framePoint(vp.display.clampedPosition(ev.Position.Subtract(ev.Dragged)))
+		an.active = &annotation{Tool: an.tool, Points: [][2]float64{start}, Color: an.color, Width: an.width}
+		an.activePlayer = vp
+		vp.annotations = append(vp.annotations, an.active)
+	}
+
+	if an.tool == toolFreehand || len(an.active.Points) < 2 {
+		an.active.Points = append(an.active.Points, point)
+	} else {
+		an.active.Points[len(an.active.Points)-1] = point
+	}
+	vp.annotationLayer.Refresh()
+}
+
+func (an *annotator) dragEnd(vp *VideoPlayer) {
+	an.app.roi.dragEnd()
+	an.activePlayer = nil
+	if an.active == nil {
+		return
+	}
+	an.active = nil
+	an.changed()
+}
+
+// tap places measurement points while the measure tool is active.
+func (an *annotator) tap(vp *VideoPlayer, ev *fyne.PointEvent) {
+	if an.tool != toolMeasure || vp.path == "" {
+		return
+	}
+	an.measureTap(vp, ev)
+}
+
+// changed stores the current drawings on the selected bookmark, if any.
+func (an *annotator) changed() {
+	an.app.bookmarks.storeAnnotations(an.app.leftPlayer.annotations, an.app.rightPlayer.annotations)
+}
+
+// setAnnotations replaces the drawings shown on both players.
+func (an *annotator) setAnnotations(left, right []*annotation) {
+	an.app.leftPlayer.annotations = cloneAnnotations(left)
+	an.app.rightPlayer.annotations = cloneAnnotations(right)
+	an.app.leftPlayer.annotationLayer.Refresh()
+	an.app.rightPlayer.annotationLayer.Refresh()
+}
+
+func cloneAnnotations(in []*annotation) []*annotation {
+	out := make([]*annotation, len(in))
+	for i, a := range in {
+		c := *a
+		c.Points = append([][2]float64(nil), a.Points...)
+		out[i] = &c
+	}
+	return out
+}
+
+// newAnnotationLayer creates the raster drawing vp's annotations at the
+// current display size and zoom.
+func (vp *VideoPlayer) newAnnotationLayer() *canvas.Raster {
+	vp.annotationLayer = canvas.NewRaster(func(w, h int) image.Image {
+		img := image.NewRGBA(image.Rect(0, 0, w, h))
+		scale := vp.displayScale()
+		if vp.width > 0 {
+			scale *= float64(w) / float64(vp.width)
+		}
+		drawAnnotations(img, vp.displayAnnotations(), scale)
+		drawMeasurement(img, vp)
+		drawROI(img, vp)
+		return img
+	})
+	return vp.annotationLayer
+}
+
+// displayAnnotations maps vp's annotations onto its display.
+func (vp *VideoPlayer) displayAnnotations() []*annotation {
+	out := cloneAnnotations(vp.annotations)
+	for _, a := range out {
+		for i, p := range a.Points {
+			a.Points[i] = vp.displayPoint(p)
+		}
+	}
+	return out
+}
+
+// drawAnnotations renders annotations onto img. widthScale converts stroke
+// widths from source pixels to img pixels.
+func drawAnnotations(img *image.RGBA, annotations []*annotation, widthScale float64) {
+	b := img.Bounds()
+	w, h := float64(b.Dx()), float64(b.Dy())
+	for _, a := range annotations {
+		if len(a.Points) == 0 {
+			continue
+		}
+		c, ok := annotationColors[a.Color]
+		if !ok {
+			c = annotationColors["Red"]
+		}
+		width := math.Max(1, a.Width*widthScale)
+		pts := make([][2]float64, len(a.Points))
+		for i, p := range a.Points {
+			pts[i] = [2]float64{float64(b.Min.X) + p[0]*w, float64(b.Min.Y) + p[1]*h}
+		}
+
+		switch a.Tool {
+		case toolFreehand:
+			for i := 1; i < len(pts); i++ {
+				drawLine(img, pts[i-1], pts[i], width, c)
+			}
+		case toolRectangle:
+			if len(pts) < 2 {
+				continue
+			}
+			p0, p1 := pts[0], pts[len(pts)-1]
+			corners := [][2]float64{p0, {p1[0], p0[1]}, p1, {p0[0], p1[1]}}
+			for i := range corners {
+				drawLine(img, corners[i], corners[(i+1)%4], width, c)
+			}
+		case toolArrow:
+			if len(pts) < 2 {
+				continue
+			}
+			tail, head := pts[0], pts[len(pts)-1]
+			drawLine(img, tail, head, width, c)
+			angle := math.Atan2(head[1]-tail[1], head[0]-tail[0])
+			length := math.Max(12, 4*width)
+			for _, side := range []float64{-1, 1} {
+				a := angle + math.Pi - side*math.Pi/7
+				tip := [2]float64{head[0] + length*math.Cos(a), head[1] + length*math.Sin(a)}
+				drawLine(img, head, tip, width, c)
+			}
+		}
+	}
+}
+
+// drawLine draws a thick line by stamping discs along its length.
+func drawLine(img *image.RGBA, p0, p1 [2]float64, width float64, c color.NRGBA) {
+	dx, dy := p1[0]-p0[0], p1[1]-p0[1]
+	steps := int(math.Max(math.Abs(dx), math.Abs(dy))) + 1
+	r := width / 2
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		fillDisc(img, p0[0]+dx*t, p0[1]+dy*t, r, c)
+	}
+}
+
+func fillDisc(img *image.RGBA, cx, cy, r float64, c color.NRGBA) {
+	b := img.Bounds()
+	x0, x1 := max(b.Min.X, int(cx-r)), min(b.Max.X-1, int(cx+r))
+	y0, y1 := max(b.Min.Y, int(cy-r)), min(b.Max.Y-1, int(cy+r))
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			ddx, ddy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			if ddx*ddx+ddy*ddy <= r*r+0.25 {
+				img.Set(x, y, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,475 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"videocompare"
+)
+
+const (
+	audioSampleRate = 48000 // mono samples per second the overview is decoded at
+	peakBucket      = 128   // samples reduced into each overview peak
+	// detailColumns is how few overview peaks a view may span before it
+	// shows samples decoded at the clip's own rate instead
+	detailColumns = 2048
+	maxAudioZoom  = 65536
+	scrollSteps   = 1000
+)
+
+var (
+	audioBackground = color.RGBA{R: 0x20, G: 0x20, B: 0x20, A: 0xff}
+	audioTrace      = color.RGBA{R: 0x40, G: 0xa0, B: 0xff, A: 0xff}
+	audioPlayhead   = color.RGBA{R: 0xff, G: 0x40, B: 0x40, A: 0xff}
+)
+
+// peak is the sample range covered by one bucket of the pyramid.
+type peak struct {
+	lo, hi int16
+}
+
+// audioPeaks is a multi-resolution min/max pyramid over a clip's audio.
+// Level 0 holds the peaks it was built from and every further level
+// halves the resolution, so any zoom level is served from precomputed
+// data.
+type audioPeaks struct {
+	rate   float64 // level 0 peaks per second
+	start  float64 // seconds into the clip the first peak covers
+	levels [][]peak
+}
+
+// newAudioPeaks builds the pyramid over base, which holds rate peaks a
+// second from start on.
+func newAudioPeaks(base []peak, rate, start float64) *audioPeaks {
+	level := base
+	ap := &audioPeaks{rate: rate, start: start, levels: [][]peak{level}}
+	for len(level) > 1 {
+		next := make([]peak, (len(level)+1)/2)
+		for i := range next {
+			p := level[2*i]
+			if 2*i+1 < len(level) {
+				q := level[2*i+1]
+				p.lo, p.hi = min(p.lo, q.lo), max(p.hi, q.hi)
+			}
+			next[i] = p
+		}
+		ap.levels = append(ap.levels, next)
+		level = next
+	}
+	return ap
+}
+
+// columns returns one peak per output column for the seconds [start, end),
+// reading the coarsest level that still resolves a single column. Columns
+// past the end of the audio are left out.
+func (ap *audioPeaks) columns(start, end float64, n int) []peak {
+	perColumn := (end - start) * ap.rate / float64(n)
+	lvl := 0
+	if perColumn > 1 {
+		lvl = min(len(ap.levels)-1, int(math.Log2(perColumn)))
+	}
+	level := ap.levels[lvl]
+
+	out := make([]peak, 0, n)
+	for c := 0; c < n; c++ {
+		s0 := int(math.Floor((start + (end-start)*float64(c)/float64(n) - ap.start) * ap.rate))
+		s1 := int(math.Floor((start + (end-start)*float64(c+1)/float64(n) - ap.start) * ap.rate))
+		i0, i1 := s0>>lvl, max(s0>>lvl+1, s1>>lvl)
+		if i0 >= len(level) {
+			break
+		}
+		if i0 < 0 {
+			// Before the first peak
+			out = append(out, peak{})
+			continue
+		}
+		p := level[i0]
+		for i := i0 + 1; i < min(i1, len(level)); i++ {
+			p.lo, p.hi = min(p.lo, level[i].lo), max(p.hi, level[i].hi)
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// peakWriter reduces the mono 16-bit samples ffmpeg writes to one peak
+// per bucket samples as they arrive, so decoding a long clip never holds
+// its samples.
+type peakWriter struct {
+	bucket int
+	peaks  []peak
+	cur    peak
+	n      int // samples in cur
+
+	// A sample split across two writes
+	low   byte
+	split bool
+}
+
+func (pw *peakWriter) Write(p []byte) (int, error) {
+	written := len(p)
+	if pw.split && len(p) > 0 {
+		pw.add(int16(uint16(pw.low) | uint16(p[0])<<8))
+		p, pw.split = p[1:], false
+	}
+	for ; len(p) >= 2; p = p[2:] {
+		pw.add(int16(binary.LittleEndian.Uint16(p)))
+	}
+	if len(p) == 1 {
+		pw.low, pw.split = p[0], true
+	}
+	return written, nil
+}
+
+func (pw *peakWriter) add(s int16) {
+	if pw.n == 0 {
+		pw.cur = peak{s, s}
+	} else {
+		pw.cur.lo, pw.cur.hi = min(pw.cur.lo, s), max(pw.cur.hi, s)
+	}
+	pw.n++
+	if pw.n == pw.bucket {
+		pw.peaks = append(pw.peaks, pw.cur)
+		pw.n = 0
+	}
+}
+
+// result returns the peaks, including one for a final partial bucket.
+func (pw *peakWriter) result() []peak {
+	if pw.n > 0 {
+		pw.peaks = append(pw.peaks, pw.cur)
+		pw.n = 0
+	}
+	return pw.peaks
+}
+
+// extractPeaks decodes path's audio as mono 16-bit samples at
+// audioSampleRate, reducing them to one peak per peakBucket samples as
+// ffmpeg streams them, or reads the peaks from the cache.
+func extractPeaks(ctx context.Context, path string) ([]peak, error) {
+	if raw, ok := cacheGet("peaks", path); ok {
+		peaks := make([]peak, len(raw)/4)
+		for i := range peaks {
+			peaks[i] = peak{int16(binary.LittleEndian.Uint16(raw[4*i:])), int16(binary.LittleEndian.Uint16(raw[4*i+2:]))}
+		}
+		return peaks, nil
+	}
+	pw := &peakWriter{bucket: peakBucket}
+	if err := runFFmpegTo(ctx, pw, "-i", path, "-vn", "-ac", "1", "-ar", fmt.Sprint(audioSampleRate), "-f", "s16le", "-"); err != nil {
+		return nil, err
+	}
+	peaks := pw.result()
+	raw := make([]byte, 0, 4*len(peaks))
+	for _, p := range peaks {
+		raw = binary.LittleEndian.AppendUint16(raw, uint16(p.lo))
+		raw = binary.LittleEndian.AppendUint16(raw, uint16(p.hi))
+	}
+	cachePut("peaks", path, raw)
+	return peaks, nil
+}
+
+// extractDetail decodes the seconds [start, end) of path's audio at rate
+// samples per second, one peak per sample.
+func extractDetail(ctx context.Context, path string, rate int, start, end float64) (*audioPeaks, error) {
+	pw := &peakWriter{bucket: 1}
+	err := runFFmpegTo(ctx, pw, "-ss", fmt.Sprintf("%.6f", start), "-t", fmt.Sprintf("%.6f", end-start), "-i", path,
+		"-vn", "-ac", "1", "-ar", fmt.Sprint(rate), "-f", "s16le", "-")
+	if err != nil {
+		return nil, err
+	}
+	return newAudioPeaks(pw.result(), float64(rate), start), nil
+}
+
+// audioDetail is a window of a clip's audio decoded at its own rate, for
+// views zoomed in past the overview's resolution. peaks is nil while it
+// is decoding.
+type audioDetail struct {
+	start, end float64
+	peaks      *audioPeaks
+	cancel     context.CancelFunc
+}
+
+// audioPanel shows both clips' audio waveforms over a shared, zoomable
+// time window for checking audio alignment.
+type audioPanel struct {
+	app *VideoCompareApp
+
+	peaks   map[*VideoPlayer]*audioPeaks
+	rates   map[*VideoPlayer]int // sample rates of the clips' audio
+	details map[*VideoPlayer]*audioDetail
+	cancels map[*VideoPlayer]context.CancelFunc
+	views   map[*VideoPlayer]*canvas.Raster
+	labels  map[*VideoPlayer]*widget.Label
+
+	zoom           float64 // 1 shows the whole clip
+	viewStart      float64 // seconds
+	zoomLabel      *widget.Label
+	scroll         *widget.Slider
+	updatingScroll bool
+}
+
+func newAudioPanel(app *VideoCompareApp) *audioPanel {
+	return &audioPanel{
+		app:     app,
+		peaks:   make(map[*VideoPlayer]*audioPeaks),
+		rates:   make(map[*VideoPlayer]int),
+		details: make(map[*VideoPlayer]*audioDetail),
+		cancels: make(map[*VideoPlayer]context.CancelFunc),
+		views:   make(map[*VideoPlayer]*canvas.Raster),
+		labels:  make(map[*VideoPlayer]*widget.Label),
+		zoom:    1,
+	}
+}
+
+func (ap *audioPanel) content() fyne.CanvasObject {
+	rows := container.NewVBox()
+	for _, vp := range []*VideoPlayer{ap.app.leftPlayer, ap.app.rightPlayer} {
+		view := ap.newView(vp)
+		ap.views[vp] = view
+		ap.labels[vp] = widget.NewLabel(trf("%s: no audio", vp.title))
+		rows.Add(ap.labels[vp])
+		rows.Add(view)
+	}
+
+	ap.zoomLabel = widget.NewLabel("")
+	ap.scroll = widget.NewSlider(0, scrollSteps)
+	ap.scroll.OnChanged = func(value float64) {
+		if ap.updatingScroll {
+			return
+		}
+		ap.viewStart = value / scrollSteps * math.Max(0, ap.span()-ap.viewDuration())
+		ap.refresh()
+	}
+
+	zoomIn := widget.NewButtonWithIcon("", theme.ZoomInIcon(), func() { ap.setZoom(ap.zoom * 2) })
+	zoomOut := widget.NewButtonWithIcon("", theme.ZoomOutIcon(), func() { ap.setZoom(ap.zoom / 2) })
+	fit := widget.NewButtonWithIcon(tr("Fit"), theme.ZoomFitIcon(), func() { ap.setZoom(1) })
+	toolbar := container.NewHBox(zoomOut, zoomIn, fit, ap.zoomLabel)
+
+	return container.NewBorder(toolbar, ap.scroll, nil, nil, rows)
+}
+
+func (ap *audioPanel) newView(vp *VideoPlayer) *canvas.Raster {
+	view := canvas.NewRaster(func(w, h int) image.Image {
+		img := image.NewRGBA(image.Rect(0, 0, w, h))
+		for i := 0; i < len(img.Pix); i += 4 {
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = audioBackground.R, audioBackground.G, audioBackground.B, 0xff
+		}
+		peaks := ap.peaks[vp]
+		duration := ap.viewDuration()
+		if peaks == nil || w == 0 || duration <= 0 {
+			return img
+		}
+		if d := ap.details[vp]; d != nil && d.peaks != nil && d.covers(ap.viewStart, ap.viewStart+duration) {
+			peaks = d.peaks
+		}
+
+		mid := float64(h) / 2
+		for x, p := range peaks.columns(ap.viewStart, ap.viewStart+duration, w) {
+			top := int(mid - float64(p.hi)/32768*mid)
+			bottom := int(mid - float64(p.lo)/32768*mid)
+			for y := max(0, top); y <= min(h-1, bottom); y++ {
+				img.SetRGBA(x, y, audioTrace)
+			}
+		}
+
+		if x := int((vp.currentTime - ap.viewStart) / duration * float64(w)); x >= 0 && x < w {
+			for y := 0; y < h; y++ {
+				img.SetRGBA(x, y, audioPlayhead)
+			}
+		}
+		return img
+	})
+	view.SetMinSize(fyne.NewSize(0, 80))
+	return view
+}
+
+// load extracts vp's audio in the background, replacing any extraction
+// still running for a previously loaded file.
+func (ap *audioPanel) load(vp *VideoPlayer) {
+	if cancel := ap.cancels[vp]; cancel != nil {
+		cancel()
+	}
+	delete(ap.peaks, vp)
+	delete(ap.rates, vp)
+	ap.dropDetail(vp)
+	ap.refresh()
+
+	if vp.path == "" {
+		ap.labels[vp].SetText(trf("%s: no audio", vp.title))
+		return
+	}
+	if vp.still != nil || isNetworkSource(vp.path) {
+		ap.labels[vp].SetText(trf("%s: no audio waveform for this source", vp.title))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ap.cancels[vp] = cancel
+	path := vp.path
+	ap.labels[vp].SetText(trf("%s: extracting audio…", vp.title))
+
+	go func() {
+		base, err := extractPeaks(ctx, path)
+		var peaks *audioPeaks
+		rate := audioSampleRate
+		if err == nil && len(base) > 0 {
+			peaks = newAudioPeaks(base, float64(audioSampleRate)/peakBucket, 0)
+			// Zoomed-in views decode at the clip's own rate, or the
+			// overview's when it can't be read
+			if r, err := videocompare.ProbeSampleRate(ctx, path); err == nil {
+				rate = r
+			} else {
+				log.Printf("audio sample rate of %s: %v", path, err)
+			}
+		}
+		fyne.Do(func() {
+			if ctx.Err() != nil || vp.path != path {
+				return
+			}
+			cancel()
+			delete(ap.cancels, vp)
+			switch {
+			case err != nil:
+				log.Printf("audio waveform: %v", err)
+				ap.labels[vp].SetText(fmt.Sprintf("%s: %v", vp.title, err))
+			case peaks == nil:
+				ap.labels[vp].SetText(trf("%s: no audio", vp.title))
+			default:
+				ap.peaks[vp] = peaks
+				ap.rates[vp] = rate
+				ap.labels[vp].SetText(fmt.Sprintf("%s: %s", vp.title, displayName(path)))
+			}
+			ap.refresh()
+			ap.app.audioMode.refresh()
+		})
+	}()
+}
+
+// span is the length of the longer clip.
+func (ap *audioPanel) span() float64 {
+	return math.Max(ap.app.leftPlayer.duration, ap.app.rightPlayer.duration)
+}
+
+func (ap *audioPanel) viewDuration() float64 {
+	return ap.span() / ap.zoom
+}
+
+// playhead is the position the zoom centres on.
+func (ap *audioPanel) playhead() float64 {
+	if ap.app.leftPlayer.path != "" {
+		return ap.app.leftPlayer.currentTime
+	}
+	return ap.app.rightPlayer.currentTime
+}
+
+// playheadMoved redraws vp's waveform as it plays and, zoomed in, pages
+// the view on once the playhead leaves it.
+func (ap *audioPanel) playheadMoved(vp *VideoPlayer) {
+	view := ap.views[vp]
+	if view == nil {
+		return
+	}
+	if t := ap.playhead(); ap.zoom > 1 && (t < ap.viewStart || t >= ap.viewStart+ap.viewDuration()) {
+		ap.viewStart = t
+		ap.refresh()
+		return
+	}
+	view.Refresh()
+}
+
+// setZoom changes the zoom factor, keeping the playhead centred.
+func (ap *audioPanel) setZoom(zoom float64) {
+	ap.zoom = math.Min(maxAudioZoom, math.Max(1, zoom))
+	ap.viewStart = ap.playhead() - ap.viewDuration()/2
+	ap.refresh()
+}
+
+func (ap *audioPanel) updateZoomLabel() {
+	ap.zoomLabel.SetText(trf("Zoom %.0f× — %.3f s visible", ap.zoom, ap.viewDuration()))
+}
+
+// refresh clamps the visible window, syncs the scroll bar and redraws.
+func (ap *audioPanel) refresh() {
+	if ap.scroll == nil {
+		return
+	}
+	maxStart := math.Max(0, ap.span()-ap.viewDuration())
+	ap.viewStart = math.Min(maxStart, math.Max(0, ap.viewStart))
+
+	ap.updatingScroll = true
+	if maxStart > 0 {
+		ap.scroll.SetValue(ap.viewStart / maxStart * scrollSteps)
+	} else {
+		ap.scroll.SetValue(0)
+	}
+	ap.updatingScroll = false
+
+	ap.updateZoomLabel()
+	for vp, view := range ap.views {
+		ap.updateDetail(vp)
+		view.Refresh()
+	}
+}
+
+// covers reports whether the detail spans the seconds [start, end).
+func (d *audioDetail) covers(start, end float64) bool {
+	return d.start <= start && end <= d.end
+}
+
+// updateDetail decodes the audio around the visible window at vp's own
+// rate in the background once the overview is too coarse for it.
+func (ap *audioPanel) updateDetail(vp *VideoPlayer) {
+	overview := ap.peaks[vp]
+	start, duration := ap.viewStart, ap.viewDuration()
+	if overview == nil || duration*overview.rate >= detailColumns {
+		ap.dropDetail(vp)
+		return
+	}
+	if d := ap.details[vp]; d != nil && d.covers(start, start+duration) {
+		return // decoded or on its way
+	}
+	ap.dropDetail(vp)
+
+	// A view either side too, so scrolling and following the playhead
+	// don't wait for ffmpeg at every step
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &audioDetail{start: math.Max(0, start-duration), end: start + 2*duration, cancel: cancel}
+	ap.details[vp] = d
+	path, rate := vp.path, ap.rates[vp]
+	go func() {
+		peaks, err := extractDetail(ctx, path, rate, d.start, d.end)
+		fyne.Do(func() {
+			if ctx.Err() != nil || ap.details[vp] != d {
+				return
+			}
+			cancel()
+			if err != nil {
+				// Leave the overview showing rather than retrying at
+				// every redraw
+				log.Printf("audio detail: %v", err)
+				return
+			}
+			d.peaks = peaks
+			ap.views[vp].Refresh()
+		})
+	}()
+}
+
+// dropDetail cancels and forgets vp's detail window.
+func (ap *audioPanel) dropDetail(vp *VideoPlayer) {
+	if d := ap.details[vp]; d != nil {
+		d.cancel()
+		delete(ap.details, vp)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/binary"
+	"slices"
+	"testing"
+)
+
+func TestPeakWriterSplitSamples(t *testing.T) {
+	var raw []byte
+	for _, s := range []int16{1, -3, 7, 2, -8, 4, 5} {
+		raw = binary.LittleEndian.AppendUint16(raw, uint16(s))
+	}
+	// Every split, including ones through a sample, gives the same peaks
+	want := []peak{{-3, 7}, {-8, 4}, {5, 5}}
+	for cut := range raw {
+		pw := &peakWriter{bucket: 3}
+		pw.Write(raw[:cut])
+		pw.Write(raw[cut:])
+		if got := pw.result(); !slices.Equal(got, want) {
+			t.Errorf("split at byte %d: got %v, want %v", cut, got, want)
+		}
+	}
+}
+
+func TestAudioPeaksColumns(t *testing.T) {
+	// Four peaks a second starting 10 s into the clip
+	ap := newAudioPeaks([]peak{{-1, 1}, {-2, 2}, {-3, 3}, {-4, 4}}, 4, 10)
+	tests := []struct {
+		start, end float64
+		n          int
+		want       []peak
+	}{
+		{10, 11, 4, []peak{{-1, 1}, {-2, 2}, {-3, 3}, {-4, 4}}},
+		{10, 11, 2, []peak{{-2, 2}, {-4, 4}}},
+		{10, 11, 1, []peak{{-4, 4}}},
+		{9.5, 10.5, 2, []peak{{}, {-2, 2}}},
+		{10.5, 11.5, 2, []peak{{-4, 4}}},
+	}
+	for _, tt := range tests {
+		if got := ap.columns(tt.start, tt.end, tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("columns(%v, %v, %d) = %v, want %v", tt.start, tt.end, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"math"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const prefAudioOnly = "audio.only"
+
+// audioFormats are accepted on top of the configured extensions while the
+// audio-only comparison mode is on.
+var audioFormats = []string{
+	".wav", ".flac", ".mp3", ".aac", ".m4a", ".ogg", ".opus", ".ac3", ".eac3", ".wma", ".aif", ".aiff", ".mka",
+}
+
+const (
+	audioViewWaveform    = "Waveform"
+	audioViewSpectrogram = "Spectrogram"
+	audioViewDifference  = "Spectral Difference"
+
+	// spectrogramWidth and spectrogramHeight are the time and frequency
+	// resolution a clip's spectrogram is computed at, whatever its length.
+	spectrogramWidth      = 2048
+	spectrogramHeight     = 512
+	spectrogramSampleRate = 48000 // so both clips share a frequency axis
+	spectrogramColormap   = "Inferno"
+)
+
+var audioViews = []string{audioViewWaveform, audioViewSpectrogram, audioViewDifference}
+
+func audioOnlyEnabled() bool {
+	return fyne.CurrentApp().Preferences().Bool(prefAudioOnly)
+}
+
+// acceptedFormats is what the open dialog and dropped files accept: the
+// supported formats plus, in audio-only mode, the audio extensions.
+func acceptedFormats() []string {
+	exts := supportedFormats()
+	if audioOnlyEnabled() {
+		for _, ext := range audioFormats {
+			if !slices.Contains(exts, ext) {
+				exts = append(exts, ext)
+			}
+		}
+	}
+	return exts
+}
+
+// extractSpectrogram renders path's first audio stream, mixed to mono, as
+// a spectrogram of spectrogramWidth columns spanning the whole clip, or
+// reads it from the cache. Brighter pixels are louder; low frequencies
+// are at the bottom.
+func extractSpectrogram(ctx context.Context, path string) (*image.Gray, error) {
+	raw, ok := cacheGet("spectrogram", path)
+	if !ok {
+		var err error
+		raw, err = runFFmpeg(ctx, "-i", path, "-filter_complex",
+			fmt.Sprintf("[0:a:0]aresample=%d,aformat=channel_layouts=mono,showspectrumpic=s=%dx%d:legend=0,format=gray",
+				spectrogramSampleRate, spectrogramWidth, spectrogramHeight),
+			"-frames:v", "1", "-f", "rawvideo", "-")
+		if err != nil {
+			return nil, err
+		}
+		cachePut("spectrogram", path, raw)
+	}
+	if len(raw) != spectrogramWidth*spectrogramHeight {
+		return nil, fmt.Errorf("unexpected spectrogram size of %d bytes", len(raw))
+	}
+	img := image.NewGray(image.Rect(0, 0, spectrogramWidth, spectrogramHeight))
+	copy(img.Pix, raw)
+	return img, nil
+}
+
+// spectrumColumn returns the column of spec covering second t of a clip
+// lasting duration, or -1 outside the clip.
+func spectrumColumn(spec *image.Gray, t, duration float64) int {
+	if duration <= 0 || t < 0 || t >= duration {
+		return -1
+	}
+	return min(spec.Rect.Dx()-1, int(t/duration*float64(spec.Rect.Dx())))
+}
+
+// spectralDifference is the mean absolute difference between a's and b's
+// spectrograms, in percent of full scale, over the stretch where both
+// clips overlap with b offset seconds ahead of a.
+func spectralDifference(a, b *image.Gray, aDuration, bDuration, offset float64) (float64, bool) {
+	var sum, n float64
+	for x := 0; x < a.Rect.Dx(); x++ {
+		t := (float64(x) + 0.5) / float64(a.Rect.Dx()) * aDuration
+		bx := spectrumColumn(b, t+offset, bDuration)
+		if bx < 0 {
+			continue
+		}
+		for y := 0; y < min(a.Rect.Dy(), b.Rect.Dy()); y++ {
+			sum += math.Abs(float64(a.GrayAt(x, y).Y) - float64(b.GrayAt(bx, y).Y))
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return sum / n / 255 * 100, true
+}
+
+// audioModeView stands in for a player's video in audio-only mode. A click
+// seeks there, and the wheel steps like it does over the video.
+type audioModeView struct {
+	widget.BaseWidget
+
+	am     *audioMode
+	vp     *VideoPlayer
+	raster *canvas.Raster
+}
+
+func (v *audioModeView) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(v.raster)
+}
+
+func (v *audioModeView) Tapped(ev *fyne.PointEvent) {
+	if w := v.Size().Width; w > 0 && v.vp.duration > 0 {
+		v.am.app.scrubbed(v.vp, math.Min(1, math.Max(0, float64(ev.Position.X/w)))*v.vp.duration)
+	}
+}
+
+func (v *audioModeView) Scrolled(ev *fyne.ScrollEvent) {
+	v.am.app.wheel(v.vp, ev)
+}
+
+// audioMode compares two audio files: the video areas are replaced by a
+// waveform, spectrogram or spectral difference view of each player's
+// clip, while transport, sync, ranges and loudness work as for video.
+type audioMode struct {
+	app     *VideoCompareApp
+	enabled bool
+	view    string
+
+	spectrograms map[*VideoPlayer]*image.Gray
+	cancels      map[*VideoPlayer]context.CancelFunc
+	errs         map[*VideoPlayer]error
+	views        map[*VideoPlayer]*audioModeView
+
+	bar         *fyne.Container
+	statusLabel *widget.Label
+}
+
+func newAudioMode(app *VideoCompareApp) *audioMode {
+	return &audioMode{
+		app:          app,
+		enabled:      audioOnlyEnabled(),
+		view:         audioViewWaveform,
+		spectrograms: make(map[*VideoPlayer]*image.Gray),
+		cancels:      make(map[*VideoPlayer]context.CancelFunc),
+		errs:         make(map[*VideoPlayer]error),
+		views:        make(map[*VideoPlayer]*audioModeView),
+	}
+}
+
+// wrap returns vp's video area stacked with its audio view, showing the
+// one that applies to the mode.
+func (am *audioMode) wrap(vp *VideoPlayer) fyne.CanvasObject {
+	v := &audioModeView{am: am, vp: vp}
+	v.raster = canvas.NewRaster(func(w, h int) image.Image { return am.render(vp, w, h) })
+	v.ExtendBaseWidget(v)
+	am.views[vp] = v
+	am.showViews()
+	return container.NewStack(vp.display, v)
+}
+
+// toolbar picks the view and shows the spectral difference; it is hidden
+// outside audio-only mode.
+func (am *audioMode) toolbar() fyne.CanvasObject {
+	viewSelect := widget.NewSelect(audioViews, func(view string) {
+		am.view = view
+		am.refresh()
+	})
+	viewSelect.SetSelected(am.view)
+	am.statusLabel = widget.NewLabel("")
+	am.bar = container.NewHBox(widget.NewLabel("Audio-only view"), viewSelect, am.statusLabel)
+	am.showViews()
+	return am.bar
+}
+
+// menuItem toggles audio-only mode and remembers the choice.
+func (am *audioMode) menuItem() *fyne.MenuItem {
+	item := fyne.NewMenuItem(tr("Audio-Only Mode"), nil)
+	item.Checked = am.enabled
+	item.Action = func() {
+		item.Checked = !item.Checked
+		fyne.CurrentApp().Preferences().SetBool(prefAudioOnly, item.Checked)
+		am.setEnabled(item.Checked)
+		am.app.window.MainMenu().Refresh()
+	}
+	return item
+}
+
+func (am *audioMode) setEnabled(enabled bool) {
+	am.enabled = enabled
+	am.showViews()
+	for _, vp := range []*VideoPlayer{am.app.leftPlayer, am.app.rightPlayer} {
+		vp.updateControls()
+		am.load(vp)
+	}
+}
+
+func (am *audioMode) showViews() {
+	for vp, v := range am.views {
+		if am.enabled {
+			vp.display.Hide()
+			v.Show()
+		} else {
+			v.Hide()
+			vp.display.Show()
+		}
+	}
+	if am.bar != nil {
+		if am.enabled {
+			am.bar.Show()
+		} else {
+			am.bar.Hide()
+		}
+	}
+}
+
+// load computes vp's spectrogram in the background while the mode is on,
+// replacing any computation still running for a previous file.
+func (am *audioMode) load(vp *VideoPlayer) {
+	if cancel := am.cancels[vp]; cancel != nil {
+		cancel()
+		delete(am.cancels, vp)
+	}
+	delete(am.spectrograms, vp)
+	delete(am.errs, vp)
+	defer am.refresh()
+	if !am.enabled || vp.path == "" || vp.still != nil || isNetworkSource(vp.path) || am.app.unavailable(needsFFmpeg) != "" {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	am.cancels[vp] = cancel
+	path := vp.path
+	go func() {
+		spec, err := extractSpectrogram(ctx, path)
+		fyne.Do(func() {
+			if ctx.Err() != nil || vp.path != path {
+				return
+			}
+			cancel()
+			delete(am.cancels, vp)
+			if err != nil {
+				log.Printf("spectrogram of %s: %v", path, err)
+				am.errs[vp] = err
+			} else {
+				am.spectrograms[vp] = spec
+			}
+			am.refresh()
+		})
+	}()
+}
+
+// render draws vp's view, with the playhead at its current position.
+func (am *audioMode) render(vp *VideoPlayer, w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = audioBackground.R, audioBackground.G, audioBackground.B, 0xff
+	}
+	if !am.enabled || vp.duration <= 0 || w == 0 || h == 0 {
+		return img
+	}
+
+	stops := colormaps[spectrogramColormap]
+	spec := am.spectrograms[vp]
+	other := am.app.otherPlayer(vp)
+	otherSpec := am.spectrograms[other]
+	offset := other.currentTime - vp.currentTime
+	switch am.view {
+	case audioViewWaveform:
+		peaks := am.app.audio.peaks[vp]
+		if peaks == nil {
+			break
+		}
+		mid := float64(h) / 2
+		for x, p := range peaks.columns(0, vp.duration, w) {
+			top := int(mid - float64(p.hi)/32768*mid)
+			bottom := int(mid - float64(p.lo)/32768*mid)
+			for y := max(0, top); y <= min(h-1, bottom); y++ {
+				img.SetRGBA(x, y, audioTrace)
+			}
+		}
+	case audioViewSpectrogram, audioViewDifference:
+		if spec == nil || (am.view == audioViewDifference && otherSpec == nil) {
+			break
+		}
+		for x := 0; x < w; x++ {
+			t := (float64(x) + 0.5) / float64(w) * vp.duration
+			col := spectrumColumn(spec, t, vp.duration)
+			otherCol := 0
+			if am.view == audioViewDifference {
+				// Compare with what the other clip plays at the same moment
+				otherCol = spectrumColumn(otherSpec, t+offset, other.duration)
+			}
+			if col < 0 || otherCol < 0 {
+				continue
+			}
+			for y := 0; y < h; y++ {
+				row := y * spectrogramHeight / h
+				v := float64(spec.GrayAt(col, row).Y)
+				if am.view == audioViewDifference {
+					v = math.Abs(v - float64(otherSpec.GrayAt(otherCol, row).Y))
+				}
+				img.SetRGBA(x, y, colormapAt(stops, v/255))
+			}
+		}
+	}
+
+	if x := int(vp.currentTime / vp.duration * float64(w)); x >= 0 && x < w {
+		for y := 0; y < h; y++ {
+			img.SetRGBA(x, y, audioPlayhead)
+		}
+	}
+	return img
+}
+
+// playheadMoved redraws vp's view as it plays.
+func (am *audioMode) playheadMoved(vp *VideoPlayer) {
+	if v := am.views[vp]; v != nil && am.enabled {
+		v.raster.Refresh()
+	}
+}
+
+// refresh redraws both views and updates the status.
+func (am *audioMode) refresh() {
+	if !am.enabled {
+		return
+	}
+	for _, v := range am.views {
+		v.raster.Refresh()
+	}
+	if am.statusLabel == nil {
+		return
+	}
+	l, r := am.app.leftPlayer, am.app.rightPlayer
+	switch {
+	case am.app.unavailable(needsFFmpeg) != "":
+		am.statusLabel.SetText(trf("Spectrograms %s", am.app.unavailable(needsFFmpeg)))
+	case len(am.cancels) > 0:
+		am.statusLabel.SetText(tr("Computing spectrograms…"))
+	case am.errs[l] != nil:
+		am.statusLabel.SetText(fmt.Sprintf("%s: %v", l.title, am.errs[l]))
+	case am.errs[r] != nil:
+		am.statusLabel.SetText(fmt.Sprintf("%s: %v", r.title, am.errs[r]))
+	case am.spectrograms[l] != nil && am.spectrograms[r] != nil:
+		if d, ok := spectralDifference(am.spectrograms[l], am.spectrograms[r], l.duration, r.duration, r.currentTime-l.currentTime); ok {
+			am.statusLabel.SetText(trf("Mean spectral difference at the current offset: %.1f%%", d))
+		} else {
+			am.statusLabel.SetText(tr("The clips don't overlap at the current offset"))
+		}
+	default:
+		am.statusLabel.SetText("")
+	}
+}
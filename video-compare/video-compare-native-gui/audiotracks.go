@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	libvlc "github.com/adrg/libvlc-go/v3"
+)
+
+// audioTrack is one audio elementary stream of the loaded file.
+type audioTrack struct {
+	ID          int // libvlc track ID, as passed to SetAudioTrack
+	Language    string
+	Codec       string
+	Channels    uint
+	Description string
+}
+
+func newAudioTrack(t *libvlc.MediaTrack) audioTrack {
+	at := audioTrack{ID: t.ID, Language: t.Language, Description: t.Description}
+	if desc, err := t.CodecDescription(); err == nil && desc != "" {
+		at.Codec = desc
+	} else {
+		at.Codec = fourCC(t.Codec)
+	}
+	if t.Audio != nil {
+		at.Channels = t.Audio.Channels
+	}
+	return at
+}
+
+// fourCC spells out a libvlc codec code.
+func fourCC(code uint) string {
+	b := []byte{byte(code), byte(code >> 8), byte(code >> 16), byte(code >> 24)}
+	return strings.TrimSpace(string(b))
+}
+
+func (t audioTrack) String() string {
+	language := t.Language
+	if language == "" {
+		language = "und"
+	}
+	parts := []string{language}
+	if t.Codec != "" {
+		parts = append(parts, t.Codec)
+	}
+	if t.Channels > 0 {
+		parts = append(parts, fmt.Sprintf("%dch", t.Channels))
+	}
+	if t.Description != "" {
+		parts = append(parts, t.Description)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// createAudioTrackSelect builds the audio track dropdown of a player. With
+// the sync check on, picking a track on one side picks the same index on
+// the other when it has one.
+func (app *VideoCompareApp) createAudioTrackSelect(vp *VideoPlayer) fyne.CanvasObject {
+	vp.audioSelect = widget.NewSelect(nil, nil)
+	vp.audioSelect.OnChanged = func(string) {
+		index := vp.audioSelect.SelectedIndex()
+		if index < 0 || index == vp.audioTrack {
+			return
+		}
+		vp.setAudioTrack(index)
+		app.fileSettings.remember(vp)
+		if app.audioTrackSync.Checked {
+			if other := app.otherPlayer(vp); index < len(other.audioTracks) {
+				other.audioSelect.SetSelectedIndex(index)
+			}
+		}
+	}
+	vp.updateAudioTrackSelect()
+	return container.NewBorder(nil, nil, widget.NewLabel("Audio"), nil, vp.audioSelect)
+}
+
+// syncAudioTracks moves the right player to the left one's track index
+// when syncing is turned on.
+func (app *VideoCompareApp) syncAudioTracks(on bool) {
+	l, r := app.leftPlayer, app.rightPlayer
+	if on && len(l.audioTracks) > 0 && l.audioTrack < len(r.audioTracks) {
+		r.audioSelect.SetSelectedIndex(l.audioTrack)
+	}
+}
+
+// otherPlayer returns the player on the opposite side of vp.
+func (app *VideoCompareApp) otherPlayer(vp *VideoPlayer) *VideoPlayer {
+	if vp == app.leftPlayer {
+		return app.rightPlayer
+	}
+	return app.leftPlayer
+}
+
+// updateAudioTrackSelect lists the loaded file's audio tracks. The
+// dropdown is only enabled when there is a choice to make.
+func (vp *VideoPlayer) updateAudioTrackSelect() {
+	if vp.audioSelect == nil {
+		return
+	}
+	names := make([]string, len(vp.audioTracks))
+	for i, t := range vp.audioTracks {
+		names[i] = fmt.Sprintf("%d: %s", i+1, t)
+	}
+	vp.audioSelect.Options = names
+	vp.audioSelect.ClearSelected()
+	vp.audioSelect.PlaceHolder = "No audio track"
+	if len(names) > 0 {
+		vp.audioSelect.SetSelectedIndex(vp.audioTrack)
+	}
+	if len(names) > 1 {
+		vp.audioSelect.Enable()
+	} else {
+		vp.audioSelect.Disable()
+	}
+}
+
+// setAudioTrack switches to the track at index of audioTracks. libvlc only
+// accepts the switch while playing, so it is applied again on play.
+func (vp *VideoPlayer) setAudioTrack(index int) {
+	vp.audioTrack = index
+	if vp.playing() {
+		vp.applyAudioTrack()
+	}
+}
+
+func (vp *VideoPlayer) applyAudioTrack() {
+	if len(vp.audioTracks) < 2 || vp.audioTrack >= len(vp.audioTracks) {
+		return
+	}
+	if err := vp.player.SetAudioTrack(vp.audioTracks[vp.audioTrack].ID); err != nil {
+		log.Printf("%s: selecting audio track: %v", vp.title, err)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import "fyne.io/fyne/v2"
+
+const prefAutoPlay = "playback.autoplay"
+
+func autoPlayEnabled() bool {
+	return fyne.CurrentApp().Preferences().Bool(prefAutoPlay)
+}
+
+// autoPlayMenuItem toggles the auto-play preference from the menu.
+func (app *VideoCompareApp) autoPlayMenuItem() *fyne.MenuItem {
+	item := fyne.NewMenuItem(tr("Auto-play on Open"), nil)
+	item.Checked = autoPlayEnabled()
+	item.Action = func() {
+		item.Checked = !item.Checked
+		fyne.CurrentApp().Preferences().SetBool(prefAutoPlay, item.Checked)
+		app.window.MainMenu().Refresh()
+	}
+	return item
+}
+
+// autoPlayLoaded starts playback after vp was given a new file, when the
+// preference is on. If the other side has a file too, it is rewound to its
+// in point so the pair starts together from aligned positions.
+func (app *VideoCompareApp) autoPlayLoaded(vp *VideoPlayer) {
+	if !autoPlayEnabled() || !vp.canPlay() {
+		return
+	}
+	if other := app.otherPlayer(vp); other.canPlay() {
+		other.pause()
+		start, _ := other.playRange()
+		other.seekTo(start)
+		other.play()
+	}
+	vp.play()
+}
+
+// autoPlayRestored starts both players from the positions a session
+// restored, when the preference is on. Restored positions take precedence
+// over rewinding to the start.
+func (app *VideoCompareApp) autoPlayRestored() {
+	if !autoPlayEnabled() {
+		return
+	}
+	for _, vp := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		vp.whenParsed(func() {
+			if vp.canPlay() {
+				vp.play()
+			}
+		})
+	}
+}
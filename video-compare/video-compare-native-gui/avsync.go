@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+)
+
+// avSyncWindow is how many seconds from the start of a file the A/V sync
+// analysis looks at for a clap or flash.
+const avSyncWindow = 60
+
+// Detectability thresholds of ITU-R BT.1359: audio leading video is
+// noticed sooner than audio lagging it.
+const (
+	avSyncLeadTolerance = 0.045
+	avSyncLagTolerance  = 0.125
+)
+
+// Minimum jumps for a frame's mean luma and a 10 ms audio window's RMS
+// level to count as a flash and a clap, and the furthest apart the two
+// may be to count as the same event.
+const (
+	flashJump    = 20  // luma levels
+	clapJump     = 20  // dB
+	maxSyncDrift = 1.0 // seconds
+)
+
+// avSync is the A/V offset estimated within one file.
+type avSync struct {
+	found  bool    // a clap and flash were found close enough together
+	offset float64 // seconds the audio lags the video, negative when it leads
+}
+
+func (s avSync) withinTolerance() bool {
+	return s.offset >= -avSyncLeadTolerance && s.offset <= avSyncLagTolerance
+}
+
+func (s avSync) String() string {
+	if !s.found {
+		return "no clap or flash found"
+	}
+	ms := int(math.Round(s.offset * 1000))
+	verdict := "within tolerance"
+	if !s.withinTolerance() {
+		verdict = "out of sync"
+	}
+	switch {
+	case ms > 0:
+		return fmt.Sprintf("audio %d ms late (%s)", ms, verdict)
+	case ms < 0:
+		return fmt.Sprintf("audio %d ms early (%s)", -ms, verdict)
+	}
+	return "in sync"
+}
+
+// metadataSample is one value printed by ffmpeg's metadata filters.
+type metadataSample struct {
+	time  float64
+	value float64
+}
+
+// parseMetadataPrint reads the values of key from the output of ffmpeg's
+// metadata=print or ametadata=print, which lists each frame's pts_time
+// followed by its key=value lines.
+func parseMetadataPrint(out []byte, key string) []metadataSample {
+	var samples []metadataSample
+	t := -1.0
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "pts_time:"); i >= 0 {
+			if _, err := fmt.Sscan(line[i+len("pts_time:"):], &t); err != nil {
+				t = -1
+			}
+			continue
+		}
+		if v, ok := strings.CutPrefix(line, key+"="); ok && t >= 0 {
+			if f, err := strconv.ParseFloat(v, 64); err == nil && !math.IsInf(f, 0) {
+				samples = append(samples, metadataSample{t, f})
+			}
+		}
+	}
+	return samples
+}
+
+// strongestRise returns when the value rose most from one sample to the
+// next and by how much.
+func strongestRise(samples []metadataSample) (at, rise float64) {
+	for i := 1; i < len(samples); i++ {
+		if d := samples[i].value - samples[i-1].value; d > rise {
+			at, rise = samples[i].time, d
+		}
+	}
+	return at, rise
+}
+
+// detectAVSync estimates how far path's audio is off its video by finding
+// the sharpest brightness jump, a flash or clapperboard, and the sharpest
+// loudness jump, a clap, near the start of the file.
+func detectAVSync(ctx context.Context, path string) (avSync, error) {
+	window := strconv.Itoa(avSyncWindow)
+	video, err := runFFmpeg(ctx, "-t", window, "-i", path, "-an", "-map", "0:v:0",
+		"-vf", "scale=64:-2,signalstats,metadata=print:key=lavfi.signalstats.YAVG:file=-", "-f", "null", "-")
+	if err != nil {
+		return avSync{}, err
+	}
+	audio, err := runFFmpeg(ctx, "-t", window, "-i", path, "-vn", "-map", "0:a:0",
+		"-af", "aresample=48000,asetnsamples=n=480,astats=metadata=1:reset=1,"+
+			"ametadata=print:key=lavfi.astats.Overall.RMS_level:file=-", "-f", "null", "-")
+	if err != nil {
+		return avSync{}, err
+	}
+
+	flash, flashRise := strongestRise(parseMetadataPrint(video, "lavfi.signalstats.YAVG"))
+	clap, clapRise := strongestRise(parseMetadataPrint(audio, "lavfi.astats.Overall.RMS_level"))
+	if flashRise < flashJump || clapRise < clapJump || math.Abs(clap-flash) > maxSyncDrift {
+		return avSync{}, nil
+	}
+	return avSync{found: true, offset: clap - flash}, nil
+}
+
+// analyzeAVSync estimates vp's A/V offset in the background for its stats.
+func (app *VideoCompareApp) analyzeAVSync(vp *VideoPlayer) {
+	if cancel := vp.avSyncCancel; cancel != nil {
+		cancel()
+		vp.avSyncCancel = nil
+	}
+	vp.avSync = nil
+	if vp.path == "" || vp.still != nil || isNetworkSource(vp.path) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	vp.avSyncCancel = cancel
+	path := vp.path
+
+	go func() {
+		sync, err := detectAVSync(ctx, path)
+		fyne.Do(func() {
+			if ctx.Err() != nil || vp.path != path {
+				return
+			}
+			cancel()
+			vp.avSyncCancel = nil
+			if err != nil {
+				log.Printf("A/V sync analysis for %s: %v", path, err)
+				return
+			}
+			vp.avSync = &sync
+			vp.updateStats()
+		})
+	}()
+}
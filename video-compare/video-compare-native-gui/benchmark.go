@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+var (
+	benchFramePattern = regexp.MustCompile(`(?m)^frame=(\d+)`)
+	benchTimePattern  = regexp.MustCompile(`bench: utime=([\d.]+)s stime=([\d.]+)s rtime=([\d.]+)s`)
+	benchRSSPattern   = regexp.MustCompile(`bench: maxrss=(\d+)\s*(KiB|kB)`)
+)
+
+// decodeBenchmark is the cost of decoding a clip's video stream as fast as
+// ffmpeg can, without rendering or real-time pacing.
+type decodeBenchmark struct {
+	Frames  int
+	Wall    time.Duration // real time
+	CPU     time.Duration // user plus system time
+	PeakRSS int64         // bytes
+}
+
+// FPS is the average number of frames decoded per wall-clock second.
+func (b decodeBenchmark) FPS() float64 {
+	if b.Wall <= 0 {
+		return 0
+	}
+	return float64(b.Frames) / b.Wall.Seconds()
+}
+
+// benchmarkDecode decodes path's first video stream into the null muxer.
+// A positive length limits it to that many seconds from start.
+func benchmarkDecode(ctx context.Context, path string, start, length float64) (decodeBenchmark, error) {
+	var args []string
+	if start > 0 {
+		args = append(args, "-ss", fmt.Sprintf("%.3f", start))
+	}
+	if length > 0 {
+		args = append(args, "-t", fmt.Sprintf("%.3f", length))
+	}
+	args = append(args, "-benchmark", "-progress", "pipe:2", "-i", path, "-map", "0:v:0", "-f", "null", "-")
+	out, err := runFFmpegLog(ctx, args...)
+	if err != nil {
+		return decodeBenchmark{}, err
+	}
+	return parseBenchmark(out)
+}
+
+func parseBenchmark(out string) (decodeBenchmark, error) {
+	var b decodeBenchmark
+	frames := benchFramePattern.FindAllStringSubmatch(out, -1)
+	times := benchTimePattern.FindStringSubmatch(out)
+	if len(frames) == 0 || times == nil {
+		return b, errors.New("no benchmark summary in ffmpeg output")
+	}
+	b.Frames, _ = strconv.Atoi(frames[len(frames)-1][1])
+	seconds := func(s string) time.Duration {
+		v, _ := strconv.ParseFloat(s, 64)
+		return time.Duration(v * float64(time.Second))
+	}
+	b.CPU = seconds(times[1]) + seconds(times[2])
+	b.Wall = seconds(times[3])
+	if m := benchRSSPattern.FindStringSubmatch(out); m != nil {
+		kib, _ := strconv.ParseInt(m[1], 10, 64)
+		b.PeakRSS = kib * 1024
+	}
+	return b, nil
+}
+
+// benchmarkPanel decodes both clips one after the other, so they don't
+// compete for the CPU, and compares the results.
+type benchmarkPanel struct {
+	app     *VideoCompareApp
+	results map[*VideoPlayer]*decodeBenchmark
+	cancel  context.CancelFunc
+
+	rangeCheck *widget.Check
+	runBtn     *widget.Button
+	cancelBtn  *widget.Button
+	status     *widget.Label
+	table      *widget.Table
+}
+
+var benchmarkRows = []string{"Frames", "Decode Time", "Average FPS", "CPU Time", "Peak Memory"}
+
+func newBenchmarkPanel(app *VideoCompareApp) *benchmarkPanel {
+	return &benchmarkPanel{app: app, results: make(map[*VideoPlayer]*decodeBenchmark)}
+}
+
+func (bp *benchmarkPanel) content() fyne.CanvasObject {
+	bp.rangeCheck = widget.NewCheck(tr("Only the in/out range"), nil)
+	bp.runBtn = widget.NewButtonWithIcon(tr("Run Benchmark"), theme.MediaPlayIcon(), bp.run)
+	bp.cancelBtn = widget.NewButtonWithIcon(tr("Cancel"), theme.CancelIcon(), func() {
+		if bp.cancel != nil {
+			bp.cancel()
+		}
+	})
+	bp.cancelBtn.Disable()
+	bp.status = widget.NewLabel(tr("Decodes each file's video as fast as possible with ffmpeg"))
+	if reason := bp.app.unavailable(needsFFmpeg); reason != "" {
+		bp.runBtn.Disable()
+		bp.status.SetText(trf("The benchmark %s", reason))
+	}
+
+	bp.table = widget.NewTable(
+		func() (int, int) { return len(benchmarkRows) + 1, 4 },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(bp.cell(id.Row, id.Col))
+		},
+	)
+	bp.table.SetColumnWidth(0, 140)
+	bp.table.SetColumnWidth(1, 160)
+	bp.table.SetColumnWidth(2, 160)
+	bp.table.SetColumnWidth(3, 140)
+
+	controls := container.NewHBox(bp.runBtn, bp.cancelBtn, bp.rangeCheck, bp.status)
+	return container.NewBorder(controls, nil, nil, nil, bp.table)
+}
+
+// cell is the table text at row, col; row 0 is the header and the last
+// column is right relative to left.
+func (bp *benchmarkPanel) cell(row, col int) string {
+	if row == 0 {
+		return tr([]string{"", "Left", "Right", "Right / Left"}[col])
+	}
+	name := benchmarkRows[row-1]
+	if col == 0 {
+		return tr(name)
+	}
+	value := func(b *decodeBenchmark) float64 {
+		switch name {
+		case "Frames":
+			return float64(b.Frames)
+		case "Decode Time":
+			return b.Wall.Seconds()
+		case "Average FPS":
+			return b.FPS()
+		case "CPU Time":
+			return b.CPU.Seconds()
+		default:
+			return float64(b.PeakRSS)
+		}
+	}
+	format := func(v float64) string {
+		switch name {
+		case "Frames":
+			return strconv.Itoa(int(v))
+		case "Decode Time", "CPU Time":
+			return fmt.Sprintf("%.2f s", v)
+		case "Average FPS":
+			return fmt.Sprintf("%.1f", v)
+		default:
+			if v == 0 {
+				return "n/a"
+			}
+			return fmt.Sprintf("%.1f MiB", v/(1<<20))
+		}
+	}
+
+	l, r := bp.results[bp.app.leftPlayer], bp.results[bp.app.rightPlayer]
+	switch col {
+	case 1, 2:
+		b := l
+		if col == 2 {
+			b = r
+		}
+		if b == nil {
+			return "—"
+		}
+		return format(value(b))
+	default:
+		if l == nil || r == nil || value(l) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%.2f×", value(r)/value(l))
+	}
+}
+
+// run benchmarks whichever players have a local video loaded.
+func (bp *benchmarkPanel) run() {
+	type job struct {
+		vp            *VideoPlayer
+		path          string
+		start, length float64
+	}
+	var jobs []job
+	for _, vp := range []*VideoPlayer{bp.app.leftPlayer, bp.app.rightPlayer} {
+		delete(bp.results, vp)
+		if vp.path == "" || vp.still != nil || isNetworkSource(vp.path) {
+			continue
+		}
+		j := job{vp: vp, path: vp.path}
+		if bp.rangeCheck.Checked && vp.hasRange() {
+			start, end := vp.playRange()
+			j.start, j.length = start, end-start
+		}
+		jobs = append(jobs, j)
+	}
+	bp.table.Refresh()
+	if len(jobs) == 0 {
+		bp.status.SetText(tr("Load a local video file to benchmark"))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bp.cancel = cancel
+	bp.runBtn.Disable()
+	bp.cancelBtn.Enable()
+
+	go func() {
+		defer cancel()
+		var failed error
+		for _, j := range jobs {
+			fyne.Do(func() { bp.status.SetText(trf("Decoding %s…", displayName(j.path))) })
+			b, err := benchmarkDecode(ctx, j.path, j.start, j.length)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("benchmarking %s: %v", j.path, err)
+					failed = fmt.Errorf("%s: %w", displayName(j.path), err)
+				}
+				break
+			}
+			fyne.Do(func() {
+				if j.vp.path == j.path {
+					bp.results[j.vp] = &b
+					bp.table.Refresh()
+				}
+			})
+		}
+		status := tr("Benchmark finished")
+		switch {
+		case ctx.Err() != nil:
+			status = tr("Benchmark cancelled")
+		case failed != nil:
+			status = trf("Benchmark failed: %v", failed)
+		}
+		fyne.Do(func() {
+			bp.status.SetText(status)
+			bp.cancel = nil
+			bp.runBtn.Enable()
+			bp.cancelBtn.Disable()
+		})
+	}()
+}
+
+// forget drops vp's result when another file is loaded.
+func (bp *benchmarkPanel) forget(vp *VideoPlayer) {
+	delete(bp.results, vp)
+	if bp.table != nil {
+		bp.table.Refresh()
+	}
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+var bitrateGrid = color.RGBA{R: 0x38, G: 0x38, B: 0x38, A: 0xff}
+
+// frameSize is the coded size of one video frame.
+type frameSize struct {
+	time  float64 // presentation timestamp in seconds
+	bytes int
+	key   bool
+}
+
+// probeFrameSizes lists the coded size of each of path's video frames in
+// display order, read from the packet headers without decoding. ffprobe
+// doesn't report quantizers without decoding, so only sizes are read. The
+// probe output is cached like the frame timestamps.
+func probeFrameSizes(path string) ([]frameSize, error) {
+	out, ok := cacheGet("sizes", path)
+	if !ok {
+		var err error
+		out, err = runFFprobe("-select_streams", "v:0", "-show_entries", "packet=pts_time,size,flags", path)
+		if err != nil {
+			return nil, err
+		}
+		cachePut("sizes", path, out)
+	}
+	var probe struct {
+		Packets []struct {
+			PTS   string `json:"pts_time"`
+			Size  string `json:"size"`
+			Flags string `json:"flags"`
+		} `json:"packets"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, fmt.Errorf("parsing ffprobe output: %w", err)
+	}
+	frames := make([]frameSize, 0, len(probe.Packets))
+	for _, p := range probe.Packets {
+		t, err1 := strconv.ParseFloat(p.PTS, 64)
+		n, err2 := strconv.Atoi(p.Size)
+		if err1 == nil && err2 == nil {
+			frames = append(frames, frameSize{time: t, bytes: n, key: strings.Contains(p.Flags, "K")})
+		}
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("no video packets in %s", path)
+	}
+	// Packets come in decode order, which differs with B-frames
+	sort.Slice(frames, func(i, j int) bool { return frames[i].time < frames[j].time })
+	return frames, nil
+}
+
+// frameSizeColumns averages the frame sizes falling into each of n equal
+// time columns from 0 to span seconds; columns without frames are -1.
+func frameSizeColumns(frames []frameSize, span float64, n int) []float64 {
+	sums := make([]float64, n)
+	counts := make([]int, n)
+	for _, f := range frames {
+		x := int(f.time / span * float64(n))
+		if x >= 0 && x < n {
+			sums[x] += float64(f.bytes)
+			counts[x]++
+		}
+	}
+	for x := range sums {
+		if counts[x] == 0 {
+			sums[x] = -1
+		} else {
+			sums[x] /= float64(counts[x])
+		}
+	}
+	return sums
+}
+
+// bitratePanel plots both clips' frame sizes over time on one chart, to
+// show where each encoder spent its bits. Tapping the chart seeks there.
+type bitratePanel struct {
+	widget.BaseWidget
+
+	app     *VideoCompareApp
+	frames  map[*VideoPlayer][]frameSize
+	status  map[*VideoPlayer]string
+	chart   *canvas.Raster
+	summary *widget.Label
+}
+
+func newBitratePanel(app *VideoCompareApp) *bitratePanel {
+	bp := &bitratePanel{
+		app:    app,
+		frames: make(map[*VideoPlayer][]frameSize),
+		status: make(map[*VideoPlayer]string),
+	}
+	bp.chart = canvas.NewRaster(bp.draw)
+	bp.chart.SetMinSize(fyne.NewSize(0, 120))
+	bp.summary = widget.NewLabel("")
+	bp.ExtendBaseWidget(bp)
+	return bp
+}
+
+func (bp *bitratePanel) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(bp.chart)
+}
+
+func (bp *bitratePanel) content() fyne.CanvasObject {
+	bp.updateSummary()
+	return container.NewBorder(bp.summary, nil, nil, nil, bp)
+}
+
+// Tapped seeks both players to the time under the pointer.
+func (bp *bitratePanel) Tapped(ev *fyne.PointEvent) {
+	width := bp.Size().Width
+	span := bp.span()
+	if width <= 0 || span <= 0 {
+		return
+	}
+	t := float64(ev.Position.X/width) * span
+	for _, vp := range []*VideoPlayer{bp.app.leftPlayer, bp.app.rightPlayer} {
+		if vp.canPlay() && t <= vp.duration {
+			vp.seekTo(t)
+		}
+	}
+}
+
+// span is the length of the longer clip.
+func (bp *bitratePanel) span() float64 {
+	return math.Max(bp.app.leftPlayer.duration, bp.app.rightPlayer.duration)
+}
+
+func (bp *bitratePanel) draw(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = audioBackground.R, audioBackground.G, audioBackground.B, 0xff
+	}
+	span := bp.span()
+	if w == 0 || h == 0 || span <= 0 {
+		return img
+	}
+	for _, frac := range []float64{0.25, 0.5, 0.75} {
+		y := int(float64(h) * frac)
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, bitrateGrid)
+		}
+	}
+
+	players := []*VideoPlayer{bp.app.leftPlayer, bp.app.rightPlayer}
+	columns := make([][]float64, len(players))
+	peak := 0.0
+	for i, vp := range players {
+		if frames := bp.frames[vp]; frames != nil {
+			columns[i] = frameSizeColumns(frames, span, w)
+			for _, v := range columns[i] {
+				peak = math.Max(peak, v)
+			}
+		}
+	}
+	if peak <= 0 {
+		return img
+	}
+
+	// Both traces share one scale so their heights compare directly
+	for i, vp := range players {
+		c := bp.app.labels.color(i)
+		prev := -1
+		for x, v := range columns[i] {
+			if v < 0 {
+				prev = -1
+				continue
+			}
+			y := h - 1 - int(v/peak*float64(h-1))
+			from, to := y, y
+			if prev >= 0 {
+				from, to = min(y, prev), max(y, prev)
+			}
+			for yy := from; yy <= to; yy++ {
+				img.SetRGBA(x, yy, c)
+			}
+			prev = y
+		}
+		if vp.path != "" {
+			if x := int(vp.currentTime / span * float64(w)); x >= 0 && x < w {
+				for y := 0; y < h; y += 2 {
+					img.SetRGBA(x, y, c)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// load reads vp's frame sizes in the background.
+func (bp *bitratePanel) load(vp *VideoPlayer) {
+	delete(bp.frames, vp)
+	delete(bp.status, vp)
+	bp.refresh()
+	if vp.path == "" {
+		return
+	}
+	if vp.still != nil || isNetworkSource(vp.path) {
+		bp.status[vp] = "no frame sizes for this source"
+		bp.refresh()
+		return
+	}
+	bp.status[vp] = "reading frame sizes…"
+	bp.refresh()
+
+	path := vp.path
+	go func() {
+		frames, err := probeFrameSizes(path)
+		fyne.Do(func() {
+			if vp.path != path {
+				return
+			}
+			if err != nil {
+				log.Printf("frame sizes: %v", err)
+				bp.status[vp] = err.Error()
+			} else {
+				bp.frames[vp] = frames
+				delete(bp.status, vp)
+			}
+			bp.refresh()
+		})
+	}()
+}
+
+// updateSummary lists each clip's average bitrate and largest frame.
+func (bp *bitratePanel) updateSummary() {
+	var lines []string
+	for i, vp := range []*VideoPlayer{bp.app.leftPlayer, bp.app.rightPlayer} {
+		name := fmt.Sprintf("%s (%s)", vp.title, bp.app.labels.colors[i])
+		frames := bp.frames[vp]
+		switch {
+		case bp.status[vp] != "":
+			lines = append(lines, fmt.Sprintf("%s: %s", name, bp.status[vp]))
+		case frames == nil:
+			lines = append(lines, name+": no video")
+		default:
+			var total, largest int
+			for _, f := range frames {
+				total += f.bytes
+				largest = max(largest, f.bytes)
+			}
+			kbps := "—"
+			if vp.duration > 0 {
+				kbps = fmt.Sprintf("%.0f kb/s", float64(total)*8/vp.duration/1000)
+			}
+			lines = append(lines, fmt.Sprintf("%s: %d frames, average %s, largest frame %s",
+				name, len(frames), kbps, formatSize(int64(largest))))
+		}
+	}
+	bp.summary.SetText(strings.Join(lines, "\n"))
+}
+
+// refresh redraws the chart, for new data or a moved playhead.
+func (bp *bitratePanel) refresh() {
+	bp.updateSummary()
+	bp.chart.Refresh()
+}
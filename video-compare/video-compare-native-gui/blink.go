@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+var (
+	blinkRates       = []string{"1 Hz", "2 Hz", "4 Hz", "8 Hz"}
+	defaultBlinkRate = 2.0
+)
+
+// blinkComparator alternates a single pane between both players' current
+// frames, so anything that differs appears to jump. The frames must show
+// the same moment, so it asks to line the players up before it starts.
+type blinkComparator struct {
+	app     *VideoCompareApp
+	enabled bool
+	rate    float64 // full left-right cycles per second
+	pending int     // bumped on every refresh so stale results are dropped
+
+	window  fyne.Window
+	check   *widget.Check
+	image   *canvas.Image
+	label   *widget.Label
+	frames  [2]image.Image
+	showing int // index into frames
+	stop    chan struct{}
+}
+
+func newBlinkComparator(app *VideoCompareApp) *blinkComparator {
+	return &blinkComparator{app: app, rate: defaultBlinkRate}
+}
+
+// controls returns the toggle and the rate picker.
+func (bc *blinkComparator) controls() (*widget.Check, *widget.Select) {
+	bc.check = widget.NewCheck("Blink", bc.setEnabled)
+	rate := widget.NewSelect(blinkRates, func(s string) {
+		bc.rate, _ = strconv.ParseFloat(strings.TrimSuffix(s, " Hz"), 64)
+		if bc.stop != nil {
+			bc.startTicker()
+		}
+	})
+	rate.SetSelected(fmt.Sprintf("%.0f Hz", bc.rate))
+	return bc.check, rate
+}
+
+// framesAligned reports whether both players show the same moment,
+// allowing for the sync lock's offset and half a frame of rounding.
+func (app *VideoCompareApp) framesAligned() bool {
+	l, r := app.leftPlayer, app.rightPlayer
+	offset := 0.0
+	if app.syncLocked {
+		offset = app.syncLockOffset
+	}
+	tolerance := 0.5 / math.Max(1, math.Max(l.stepFPS(), r.stepFPS()))
+	return math.Abs(r.currentTime-l.currentTime-offset) <= tolerance
+}
+
+// alignPlayers moves the right player to the left one's moment.
+func (bc *blinkComparator) alignPlayers() {
+	offset := 0.0
+	if bc.app.syncLocked {
+		offset = bc.app.syncLockOffset
+	}
+	bc.app.pauseAll()
+	bc.app.rightPlayer.seekTo(bc.app.leftPlayer.currentTime + offset)
+}
+
+// setEnabled opens or closes the blink window, first offering to line the
+// players up when they show different moments.
+func (bc *blinkComparator) setEnabled(enabled bool) {
+	if enabled == bc.enabled {
+		return
+	}
+	if !enabled {
+		bc.close()
+		return
+	}
+	if !bc.app.framesAligned() {
+		l, r := bc.app.leftPlayer, bc.app.rightPlayer
+		dialog.ShowConfirm("Frames Not Aligned",
+			fmt.Sprintf("%s is at %s but %s is at %s.\nBlinking only shows real differences between the same frame.\n\n"+
+				"Move %s to match %s and start blinking?",
+				l.title, formatTimecode(l.currentTime, l.fps), r.title, formatTimecode(r.currentTime, r.fps), r.title, l.title),
+			func(ok bool) {
+				if !ok {
+					bc.check.SetChecked(false)
+					return
+				}
+				bc.alignPlayers()
+				bc.open()
+			}, bc.app.window)
+		return
+	}
+	bc.open()
+}
+
+func (bc *blinkComparator) open() {
+	bc.enabled = true
+	if bc.window == nil {
+		bc.image = canvas.NewImageFromImage(nil)
+		bc.image.FillMode = canvas.ImageFillContain
+		bc.image.SetMinSize(fyne.NewSize(640, 360))
+		bc.label = widget.NewLabel("")
+		bc.window = fyne.CurrentApp().NewWindow("Blink Comparator")
+		bc.window.SetContent(container.NewBorder(bc.label, nil, nil, nil, bc.image))
+		bc.window.SetOnClosed(func() {
+			bc.window = nil
+			bc.check.SetChecked(false)
+		})
+	}
+	bc.window.Show()
+	bc.refresh()
+	bc.startTicker()
+}
+
+func (bc *blinkComparator) close() {
+	bc.enabled = false
+	bc.stopTicker()
+	bc.frames = [2]image.Image{}
+	if bc.window != nil {
+		bc.window.Hide()
+	}
+}
+
+// startTicker (re)starts alternating at the current rate.
+func (bc *blinkComparator) startTicker() {
+	bc.stopTicker()
+	stop := make(chan struct{})
+	bc.stop = stop
+	interval := time.Duration(float64(time.Second) / (2 * bc.rate))
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				fyne.Do(func() {
+					if bc.stop == stop {
+						bc.flip()
+					}
+				})
+			}
+		}
+	}()
+}
+
+func (bc *blinkComparator) stopTicker() {
+	if bc.stop != nil {
+		close(bc.stop)
+		bc.stop = nil
+	}
+}
+
+// flip shows the other player's frame.
+func (bc *blinkComparator) flip() {
+	bc.showing = 1 - bc.showing
+	bc.show()
+}
+
+func (bc *blinkComparator) show() {
+	frame := bc.frames[bc.showing]
+	if frame == nil || bc.image == nil {
+		return
+	}
+	vp := []*VideoPlayer{bc.app.leftPlayer, bc.app.rightPlayer}[bc.showing]
+	bc.image.Image = frame
+	bc.image.Refresh()
+	text := fmt.Sprintf("%s @ %s", vp.title, formatTimecode(vp.currentTime, vp.fps))
+	if !bc.app.framesAligned() {
+		text += " — not aligned"
+	}
+	bc.label.SetText(text)
+}
+
+// refresh grabs both current frames in the background once libvlc has
+// shown them. The right frame is scaled to the left one's size so the
+// pane doesn't jump between resolutions.
+func (bc *blinkComparator) refresh() {
+	if !bc.enabled {
+		return
+	}
+	bc.pending++
+	generation := bc.pending
+	bc.app.grabPair(func(left, right image.Image, err error) {
+		if generation != bc.pending || !bc.enabled {
+			return
+		}
+		if err != nil {
+			log.Printf("blink: %v", err)
+			bc.label.SetText(err.Error())
+			return
+		}
+		bc.frames = [2]image.Image{left, right}
+		bc.show()
+	})
+}
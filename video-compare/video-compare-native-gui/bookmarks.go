@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// bookmark marks an interesting position on the left player's timeline.
+type bookmark struct {
+	Time  float64       `json:"time"`
+	Label string        `json:"label"`
+	Left  []*annotation `json:"left_annotations,omitempty"`
+	Right []*annotation `json:"right_annotations,omitempty"`
+
+	// Region of interest for ROI metrics, nil when none was drawn
+	ROI *regionOfInterest `json:"roi,omitempty"`
+}
+
+// bookmarksPanel lists the session's bookmarks; selecting one seeks both
+// players there.
+type bookmarksPanel struct {
+	app       *VideoCompareApp
+	bookmarks []bookmark
+	selected  int
+
+	list      *widget.List
+	entry     *widget.Entry
+	deleteBtn *widget.Button
+}
+
+func newBookmarksPanel(app *VideoCompareApp) *bookmarksPanel {
+	return &bookmarksPanel{app: app, selected: -1}
+}
+
+func (bp *bookmarksPanel) content() fyne.CanvasObject {
+	bp.list = widget.NewList(
+		func() int { return len(bp.bookmarks) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			b := bp.bookmarks[id]
+			text := fmt.Sprintf("[%s] %s", formatTimecode(b.Time, bp.app.leftPlayer.fps), b.Label)
+			if b.ROI != nil {
+				text += " (ROI)"
+			}
+			obj.(*widget.Label).SetText(text)
+		},
+	)
+	bp.list.OnSelected = func(id widget.ListItemID) {
+		bp.selected = id
+		bp.deleteBtn.Enable()
+		b := bp.bookmarks[id]
+		bp.app.seekAll(b.Time)
+		bp.app.annotator.setAnnotations(b.Left, b.Right)
+		bp.app.roi.set(b.ROI)
+	}
+	bp.list.OnUnselected = func(widget.ListItemID) {
+		bp.selected = -1
+		bp.deleteBtn.Disable()
+	}
+
+	bp.entry = widget.NewEntry()
+	bp.entry.SetPlaceHolder("Bookmark label (optional)")
+	bp.entry.OnSubmitted = func(string) { bp.addBookmark() }
+
+	addBtn := widget.NewButtonWithIcon("Add Bookmark", theme.ContentAddIcon(), bp.addBookmark)
+	bp.deleteBtn = widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), bp.deleteBookmark)
+	bp.deleteBtn.Disable()
+
+	buttons := container.NewHBox(addBtn, bp.deleteBtn)
+	return container.NewBorder(container.NewBorder(nil, nil, nil, buttons, bp.entry), nil, nil, nil, bp.list)
+}
+
+// addBookmark bookmarks the left player's current position together with
+// any drawings currently shown.
+func (bp *bookmarksPanel) addBookmark() {
+	t := bp.app.leftPlayer.currentTime
+	label := strings.TrimSpace(bp.entry.Text)
+	if label == "" {
+		label = fmt.Sprintf("Bookmark %d", len(bp.bookmarks)+1)
+	}
+	bp.bookmarks = append(bp.bookmarks, bookmark{
+		Time:  t,
+		Label: label,
+		Left:  cloneAnnotations(bp.app.leftPlayer.annotations),
+		Right: cloneAnnotations(bp.app.rightPlayer.annotations),
+		ROI:   bp.app.roi.current(),
+	})
+	bp.sortBookmarks()
+	bp.entry.SetText("")
+	bp.list.Refresh()
+}
+
+func (bp *bookmarksPanel) deleteBookmark() {
+	if bp.selected < 0 || bp.selected >= len(bp.bookmarks) {
+		return
+	}
+	bp.bookmarks = append(bp.bookmarks[:bp.selected], bp.bookmarks[bp.selected+1:]...)
+	bp.list.UnselectAll()
+	bp.list.Refresh()
+}
+
+// storeAnnotations saves drawings onto the selected bookmark, if any.
+func (bp *bookmarksPanel) storeAnnotations(left, right []*annotation) {
+	if bp.selected < 0 || bp.selected >= len(bp.bookmarks) {
+		return
+	}
+	bp.bookmarks[bp.selected].Left = cloneAnnotations(left)
+	bp.bookmarks[bp.selected].Right = cloneAnnotations(right)
+}
+
+// storeROI saves the region of interest onto the selected bookmark, if any.
+func (bp *bookmarksPanel) storeROI(roi *regionOfInterest) {
+	if bp.selected < 0 || bp.selected >= len(bp.bookmarks) {
+		return
+	}
+	if roi != nil {
+		r := *roi
+		roi = &r
+	}
+	bp.bookmarks[bp.selected].ROI = roi
+	bp.list.RefreshItem(bp.selected)
+}
+
+// setBookmarks replaces all bookmarks, e.g. when a session is loaded.
+func (bp *bookmarksPanel) setBookmarks(bookmarks []bookmark) {
+	bp.bookmarks = append([]bookmark(nil), bookmarks...)
+	bp.sortBookmarks()
+	bp.list.UnselectAll()
+	bp.list.Refresh()
+}
+
+func (bp *bookmarksPanel) sortBookmarks() {
+	sort.SliceStable(bp.bookmarks, func(i, j int) bool { return bp.bookmarks[i].Time < bp.bookmarks[j].Time })
+}
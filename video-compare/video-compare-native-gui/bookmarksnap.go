@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// snapshotFileName names a bookmark's snapshot by its position in the list,
+// label and timecode, keeping only characters safe in file names.
+func snapshotFileName(index int, b bookmark, fps float64) string {
+	label := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, b.Label)
+	stamp := strings.ReplaceAll(formatTimecode(b.Time, fps), ":", "-")
+	return fmt.Sprintf("%02d-%s-%s%s", index+1, strings.Trim(label, "-"), stamp, defaultImageFormat().ext())
+}
+
+// exportBookmarkSnapshots asks for a folder and writes a side-by-side
+// snapshot at every bookmark there, with the bookmark's drawings. While the
+// sync lock is on, the right player is offset as it would be when scrubbing.
+func (app *VideoCompareApp) exportBookmarkSnapshots() {
+	bookmarks := append([]bookmark(nil), app.bookmarks.bookmarks...)
+	if len(bookmarks) == 0 {
+		dialog.ShowInformation("Export Bookmark Snapshots", "Add a bookmark first.", app.window)
+		return
+	}
+	if !app.bothLoaded() {
+		dialog.ShowInformation("Export Bookmark Snapshots", "Load a video on both sides first.", app.window)
+		return
+	}
+
+	dialog.ShowFolderOpen(func(folder fyne.ListableURI, err error) {
+		if err != nil || folder == nil {
+			return
+		}
+		dir := folder.Path()
+		app.pauseAll()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		bar := widget.NewProgressBar()
+		bar.Max = float64(len(bookmarks))
+		status := widget.NewLabel(fmt.Sprintf("Exporting %d snapshots…", len(bookmarks)))
+		progress := dialog.NewCustom("Exporting Bookmark Snapshots", "Cancel", container.NewVBox(status, bar), app.window)
+		progress.SetOnClosed(cancel)
+		progress.Show()
+
+		go func() {
+			written, err := app.writeBookmarkSnapshots(ctx, bookmarks, dir, func(done int) {
+				fyne.Do(func() { bar.SetValue(float64(done)) })
+			})
+			fyne.Do(func() {
+				cancelled := ctx.Err() != nil
+				progress.Hide()
+				switch {
+				case err != nil:
+					log.Printf("bookmark snapshot export: %v", err)
+					dialog.ShowError(err, app.window)
+				case !cancelled:
+					dialog.ShowInformation("Export Bookmark Snapshots",
+						fmt.Sprintf("Wrote %d snapshots to %s", written, dir), app.window)
+				}
+			})
+		}()
+	}, app.window)
+}
+
+// writeBookmarkSnapshots seeks to each bookmark in turn and writes its
+// snapshot to dir, then restores the positions and drawings shown before.
+// It runs in the background and returns how many files were written.
+func (app *VideoCompareApp) writeBookmarkSnapshots(ctx context.Context, bookmarks []bookmark, dir string, done func(int)) (int, error) {
+	var (
+		fps                       float64
+		offset                    float64
+		restoreLeft, restoreRight float64
+		leftDrawings              []*annotation
+		rightDrawings             []*annotation
+	)
+	fyne.DoAndWait(func() {
+		fps = app.leftPlayer.fps
+		if app.syncLocked {
+			offset = app.syncLockOffset
+		}
+		restoreLeft, restoreRight = app.leftPlayer.currentTime, app.rightPlayer.currentTime
+		leftDrawings = cloneAnnotations(app.leftPlayer.annotations)
+		rightDrawings = cloneAnnotations(app.rightPlayer.annotations)
+	})
+	defer fyne.DoAndWait(func() {
+		app.leftPlayer.seekTo(restoreLeft)
+		app.rightPlayer.seekTo(restoreRight)
+		app.annotator.setAnnotations(leftDrawings, rightDrawings)
+	})
+
+	for i, b := range bookmarks {
+		if ctx.Err() != nil {
+			return i, nil
+		}
+		fyne.DoAndWait(func() {
+			app.annotator.setAnnotations(b.Left, b.Right)
+			app.leftPlayer.seekTo(b.Time)
+			app.rightPlayer.seekTo(b.Time + offset)
+		})
+		time.Sleep(frameSettleDelay)
+
+		var export func() (*image.RGBA, error)
+		fyne.DoAndWait(func() { export = app.sideBySideExporter() })
+		img, err := export()
+		if err != nil {
+			return i, fmt.Errorf("capturing %q: %w", b.Label, err)
+		}
+		if err := writeImage(filepath.Join(dir, snapshotFileName(i, b, fps)), img); err != nil {
+			return i, err
+		}
+		done(i + 1)
+	}
+	return len(bookmarks), nil
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	cacheMaxSizePref    = "cache.maxSizeMB"
+	defaultCacheMaxSize = 1024 // MB
+)
+
+var cacheSizeChoices = []string{"256 MB", "512 MB", "1024 MB", "2048 MB", "4096 MB"}
+
+// cacheMu serializes writes and eviction; reads go straight to disk.
+var cacheMu sync.Mutex
+
+// cachePinned counts the holders of each cache file that must stay, such
+// as joined segments or a transcode a player is showing. Guarded by
+// cacheMu.
+var cachePinned = map[string]int{}
+
+// pinCacheFile keeps path out of eviction and clearing until the returned
+// function is called. Pins nest.
+func pinCacheFile(path string) (unpin func()) {
+	cacheMu.Lock()
+	cachePinned[path]++
+	cacheMu.Unlock()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cacheMu.Lock()
+			defer cacheMu.Unlock()
+			if cachePinned[path]--; cachePinned[path] <= 0 {
+				delete(cachePinned, path)
+			}
+		})
+	}
+}
+
+// cacheDir is where derived data such as extracted audio and frame
+// timestamps is kept across sessions, and where temporary images go.
+func cacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "video-compare")
+}
+
+// cacheMaxBytes is the size the cache is trimmed to after every write.
+func cacheMaxBytes() int64 {
+	mb := fyne.CurrentApp().Preferences().IntWithFallback(cacheMaxSizePref, defaultCacheMaxSize)
+	return int64(mb) << 20
+}
+
+// cacheEntryName names the entry holding kind data derived from source.
+// The prefix identifies the source and kind, the suffix the version of the
+// file it was derived from, so a changed file misses and its old entries
+// can be recognized and dropped.
+func cacheEntryName(kind, source string) (prefix, name string, err error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return "", "", err
+	}
+	if abs, err := filepath.Abs(source); err == nil {
+		source = abs
+	}
+	id := sha256.Sum256([]byte(kind + "\x00" + source))
+	version := sha256.Sum256([]byte(fmt.Sprintf("%d\x00%d", info.ModTime().UnixNano(), info.Size())))
+	prefix = hex.EncodeToString(id[:12]) + "-"
+	return prefix, prefix + hex.EncodeToString(version[:6]) + "." + kind, nil
+}
+
+// cacheGet returns the kind data cached for source, if any is cached for
+// its current version. It is safe to call from background goroutines.
+func cacheGet(kind, source string) ([]byte, bool) {
+	_, name, err := cacheEntryName(kind, source)
+	if err != nil {
+		return nil, false
+	}
+	path := filepath.Join(cacheDir(), name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	// The modification time orders entries for eviction
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return data, true
+}
+
+// cachePut stores kind data derived from source, replacing entries made
+// from earlier versions of the file, and trims the cache to its size
+// limit. Failures are logged; the cache is only an optimization.
+func cachePut(kind, source string, data []byte) {
+	prefix, name, err := cacheEntryName(kind, source)
+	if err != nil {
+		return
+	}
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	dir := cacheDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Printf("cache: %v", err)
+		return
+	}
+	stale, _ := filepath.Glob(filepath.Join(dir, prefix+"*"))
+	for _, path := range stale {
+		if filepath.Base(path) != name {
+			os.Remove(path)
+		}
+	}
+
+	tmp, err := os.CreateTemp(dir, "tmp-*")
+	if err != nil {
+		log.Printf("cache: %v", err)
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(dir, name))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		log.Printf("cache: writing %s: %v", name, err)
+		return
+	}
+	evictCache(dir, cacheMaxBytes())
+}
+
+// cacheTempFile creates a file in the cache directory for data that has to
+// outlive the call that made it, such as an image handed to the clipboard.
+// Call cacheTrim once it is written so it counts toward the size limit.
+func cacheTempFile(pattern string) (*os.File, error) {
+	dir := cacheDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return os.CreateTemp(dir, pattern)
+}
+
+// cacheTrim evicts the least recently used entries beyond the size limit.
+func cacheTrim() {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	evictCache(cacheDir(), cacheMaxBytes())
+}
+
+// pinCache keeps path in the cache while vp shows it, when it is a cache
+// file such as joined segments or a transcode, and releases the file vp
+// showed before.
+func (vp *VideoPlayer) pinCache(path string) {
+	if vp.unpinCache != nil {
+		vp.unpinCache()
+		vp.unpinCache = nil
+	}
+	if path != "" && filepath.Dir(path) == cacheDir() {
+		vp.unpinCache = pinCacheFile(path)
+	}
+}
+
+type cacheEntry struct {
+	path    string
+	size    int64
+	touched time.Time
+}
+
+func listCache(dir string) []cacheEntry {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var entries []cacheEntry
+	for _, e := range dirEntries {
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() || strings.HasPrefix(e.Name(), "tmp-") {
+			continue
+		}
+		entries = append(entries, cacheEntry{filepath.Join(dir, e.Name()), info.Size(), info.ModTime()})
+	}
+	return entries
+}
+
+// evictCache removes the least recently used entries until the cache fits
+// in limit bytes, skipping pinned files. The caller holds cacheMu.
+func evictCache(dir string, limit int64) {
+	entries := listCache(dir)
+	var total int64
+	for _, e := range entries {
+		total += e.size
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].touched.Before(entries[j].touched) })
+	for _, e := range entries {
+		if total <= limit {
+			break
+		}
+		if cachePinned[e.path] > 0 {
+			continue
+		}
+		if err := os.Remove(e.path); err == nil {
+			total -= e.size
+		}
+	}
+}
+
+// cacheUsage returns the size and number of the cached entries.
+func cacheUsage() (int64, int) {
+	entries := listCache(cacheDir())
+	var total int64
+	for _, e := range entries {
+		total += e.size
+	}
+	return total, len(entries)
+}
+
+// clearCache removes every cached entry but the pinned files.
+func clearCache() {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	for _, e := range listCache(cacheDir()) {
+		if cachePinned[e.path] == 0 {
+			os.Remove(e.path)
+		}
+	}
+}
+
+// formatSize renders a byte count in human readable units.
+func formatSize(bytes int64) string {
+	switch {
+	case bytes >= 1<<30:
+		return fmt.Sprintf("%.2f GB", float64(bytes)/(1<<30))
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%d KB", bytes>>10)
+	default:
+		return fmt.Sprintf("%d bytes", bytes)
+	}
+}
+
+// cacheDialog shows how much the cache holds and lets the user change its
+// size limit or clear it.
+func (app *VideoCompareApp) cacheDialog() {
+	usage := widget.NewLabel("")
+	refresh := func() {
+		size, count := cacheUsage()
+		usage.SetText(trf("%s in %d entries, limit %s\n%s",
+			formatSize(size), count, formatSize(cacheMaxBytes()), cacheDir()))
+	}
+	refresh()
+
+	limit := widget.NewSelect(cacheSizeChoices, func(choice string) {
+		var mb int
+		if _, err := fmt.Sscanf(choice, "%d MB", &mb); err != nil {
+			return
+		}
+		fyne.CurrentApp().Preferences().SetInt(cacheMaxSizePref, mb)
+		cacheTrim()
+		refresh()
+	})
+	limit.SetSelected(fmt.Sprintf("%d MB", cacheMaxBytes()>>20))
+
+	clearBtn := widget.NewButton(tr("Clear Cache"), func() {
+		clearCache()
+		refresh()
+	})
+
+	dialog.ShowCustom(tr("Cache"), tr("Close"), container.NewVBox(
+		usage,
+		widget.NewForm(widget.NewFormItem(tr("Size limit"), limit)),
+		clearBtn,
+	), app.window)
+}
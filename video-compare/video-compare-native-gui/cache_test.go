@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEvictCacheKeepsPinned(t *testing.T) {
+	dir := t.TempDir()
+	// Oldest first, so the joined file would go before the others
+	now := time.Now()
+	var paths []string
+	for i, name := range []string{"joined-a.mkv", "b.audio", "c.frametimes"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, make([]byte, 100), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		touched := now.Add(time.Duration(i-3) * time.Minute)
+		if err := os.Chtimes(path, touched, touched); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	unpin := pinCacheFile(paths[0])
+	cacheMu.Lock()
+	evictCache(dir, 150)
+	cacheMu.Unlock()
+	if _, err := os.Stat(paths[0]); err != nil {
+		t.Errorf("pinned file was evicted: %v", err)
+	}
+	if _, err := os.Stat(paths[1]); !os.IsNotExist(err) {
+		t.Errorf("least recently used unpinned file was kept")
+	}
+	if _, err := os.Stat(paths[2]); !os.IsNotExist(err) {
+		t.Errorf("cache still over its limit: %s was kept", filepath.Base(paths[2]))
+	}
+
+	// Once unpinned it is evicted like any other entry
+	unpin()
+	unpin()
+	cacheMu.Lock()
+	evictCache(dir, 0)
+	cacheMu.Unlock()
+	if _, err := os.Stat(paths[0]); !os.IsNotExist(err) {
+		t.Errorf("unpinned file was kept")
+	}
+	if len(cachePinned) != 0 {
+		t.Errorf("pins left after unpinning: %v", cachePinned)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"fyne.io/fyne/v2/dialog"
+)
+
+// minSyncPointSpan is how far apart, in seconds, two sync points must be
+// for the rate ratio between them to mean anything.
+const minSyncPointSpan = 1.0
+
+// calibrateSync takes the frames both players are paused on as matching.
+// The first sync point gives the offset the sync lock keeps; a second one,
+// further into the clips, gives the rate ratio content sync maps positions
+// by. Each is shown for confirmation before it is applied, and a point
+// set after both starts the calibration over.
+func (app *VideoCompareApp) calibrateSync() {
+	cs := app.contentSync
+	p := syncPoint{app.leftPlayer.currentTime, app.rightPlayer.currentTime}
+	if cs.start == nil || cs.end != nil {
+		offset := p.Right - p.Left
+		message := fmt.Sprintf("Left %s matches right %s.\n\nOffset: %s\n\nLock the players at this offset?\nA second sync point further into the clips also works out their rate ratio.",
+			formatMacroTime(p.Left), formatMacroTime(p.Right), formatSyncOffset(offset))
+		dialog.ShowConfirm("Set Sync Point", message, func(ok bool) {
+			if !ok {
+				return
+			}
+			cs.start, cs.end = &p, nil
+			cs.refresh()
+			app.lockSyncAt(offset)
+		}, app.window)
+		return
+	}
+
+	start, end := *cs.start, p
+	if end.Left < start.Left {
+		start, end = end, start
+	}
+	if end.Left-start.Left < minSyncPointSpan || end.Right <= start.Right {
+		dialog.ShowInformation("Set Sync Point",
+			fmt.Sprintf("The two sync points must be at least %.0f s apart, in the same order on both sides.", minSyncPointSpan), app.window)
+		return
+	}
+	ratio := (end.Right - start.Right) / (end.Left - start.Left)
+	message := fmt.Sprintf("First point: left %s ↔ right %s\nSecond point: left %s ↔ right %s\n\nOffset at the first point: %s\nRate ratio: %.5f (%s)\n\nTurn on content sync with these points?",
+		formatMacroTime(start.Left), formatMacroTime(start.Right),
+		formatMacroTime(end.Left), formatMacroTime(end.Right),
+		formatSyncOffset(start.Right-start.Left), ratio, describeRateRatio(ratio))
+	dialog.ShowConfirm("Set Sync Point", message, func(ok bool) {
+		if !ok {
+			return
+		}
+		cs.start, cs.end = &start, &end
+		cs.enable()
+	}, app.window)
+}
+
+// formatSyncOffset describes how far the right player is ahead of the
+// left one.
+func formatSyncOffset(offset float64) string {
+	switch {
+	case math.Abs(offset) < 0.0005:
+		return "none, both at the same time"
+	case offset > 0:
+		return fmt.Sprintf("right %.3f s ahead of left", offset)
+	}
+	return fmt.Sprintf("right %.3f s behind left", -offset)
+}
+
+// describeRateRatio puts a ratio of right to left durations in words.
+func describeRateRatio(ratio float64) string {
+	switch {
+	case math.Abs(ratio-1) < 0.0001:
+		return "same speed"
+	case ratio > 1:
+		return fmt.Sprintf("the right clip runs %.2f%% longer", (ratio-1)*100)
+	}
+	return fmt.Sprintf("the right clip runs %.2f%% shorter", (1-ratio)*100)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	"videocompare"
+)
+
+// capabilityTimeout bounds the startup check for ffmpeg and ffprobe.
+const capabilityTimeout = 10 * time.Second
+
+const (
+	needsFFmpeg  = videocompare.ToolFFmpeg
+	needsFFprobe = videocompare.ToolFFprobe
+)
+
+// toolFeatures are the features that need ffmpeg or ffprobe, for the
+// notice listing what a partial toolchain leaves out.
+var toolFeatures = []struct {
+	name  string
+	tools []string
+}{
+	{"exact frame stepping, frame counts, bitrate chart and format details", []string{needsFFprobe}},
+	{"cadence, loudness and A/V sync analysis", []string{needsFFmpeg}},
+	{"duplicate detection", []string{needsFFmpeg}},
+	{"decode error checks", []string{needsFFmpeg}},
+	{"caption comparison", []string{needsFFmpeg, needsFFprobe}},
+	{"ROI range metrics", []string{needsFFmpeg}},
+	{"worst frame scan", []string{needsFFmpeg}},
+	{"decode benchmark", []string{needsFFmpeg}},
+	{"wipe sweep export", []string{needsFFmpeg}},
+	{"WebP export", []string{needsFFmpeg}},
+	{"segment joining and aligned clip export", []string{needsFFmpeg, needsFFprobe}},
+	{"transcoding to compatible MP4", []string{needsFFmpeg, needsFFprobe}},
+}
+
+// detectTools checks which of ffmpeg and ffprobe are installed, once at
+// startup, so features needing a missing one start out disabled.
+func (app *VideoCompareApp) detectTools() {
+	ctx, cancel := context.WithTimeout(context.Background(), capabilityTimeout)
+	defer cancel()
+	app.tools = videocompare.DetectCapabilities(ctx)
+	if missing := app.tools.Missing(needsFFmpeg, needsFFprobe); len(missing) > 0 {
+		log.Printf("not found on the PATH: %s; features needing them are disabled", strings.Join(missing, ", "))
+	}
+}
+
+// unavailable says why a feature needing tools can't be used, or returns
+// "" when it can.
+func (app *VideoCompareApp) unavailable(tools ...string) string {
+	return tr(app.tools.Explain(tools...))
+}
+
+// requireTools disables item when a tool it needs is missing. Menus have
+// no tooltips, so the reason goes into its label.
+func (app *VideoCompareApp) requireTools(item *fyne.MenuItem, tools ...string) *fyne.MenuItem {
+	if reason := app.unavailable(tools...); reason != "" {
+		item.Label += " (" + reason + ")"
+		item.Disabled = true
+	}
+	return item
+}
+
+// newToolsNotice creates the notice naming the features the installed
+// tools can't provide, hidden when nothing is missing.
+func (app *VideoCompareApp) newToolsNotice() *widget.Label {
+	notice := widget.NewLabel("")
+	notice.Importance = widget.WarningImportance
+	notice.Wrapping = fyne.TextWrapWord
+	var lines []string
+	for _, f := range toolFeatures {
+		if reason := app.unavailable(f.tools...); reason != "" {
+			lines = append(lines, fmt.Sprintf("%s %s", tr(f.name), reason))
+		}
+	}
+	if len(lines) == 0 {
+		notice.Hide()
+		return notice
+	}
+	notice.SetText(trf("Unavailable: %s", strings.Join(lines, "; ")))
+	return notice
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font/basicfont"
+)
+
+const prefSnapshotCaption = "export.snapshotCaption"
+
+// captionBackground is the strip the caption is drawn on, dark enough for
+// white text and distinct from letterboxing.
+var captionBackground = color.RGBA{R: 32, G: 32, B: 32, A: 255}
+
+// captionEnabled reports whether single-player snapshots get a caption bar
+// rather than being saved clean.
+func captionEnabled() bool {
+	return fyne.CurrentApp().Preferences().Bool(prefSnapshotCaption)
+}
+
+// captionLines describes vp's file and current frame with the details
+// shown in its stats.
+func (vp *VideoPlayer) captionLines() []string {
+	bitrate := "unknown"
+	if vp.bitrate > 0 {
+		bitrate = formatBitrate(vp.bitrate)
+	}
+	codec := vp.codec
+	if codec == "" {
+		codec = "unknown"
+	}
+	return []string{
+		displayName(vp.path),
+		fmt.Sprintf("Time: %s   Resolution: %dx%d   Codec: %s   Bitrate: %s",
+			vp.timecode(), vp.width, vp.height, codec, bitrate),
+	}
+}
+
+// addCaptionBar returns img with a strip below it holding lines of text.
+// The bitmap font is scaled with the frame width so the caption stays
+// legible on large frames.
+func addCaptionBar(img *image.RGBA, lines []string) *image.RGBA {
+	b := img.Bounds()
+	scale := max(1, b.Dx()/640)
+	lineHeight := (basicfont.Face7x13.Height + 6) * scale
+	margin := 6 * scale
+	barHeight := len(lines)*lineHeight + 2*margin
+
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()+barHeight))
+	draw.Draw(out, image.Rect(0, 0, b.Dx(), b.Dy()), img, b.Min, draw.Src)
+	bar := image.Rect(0, b.Dy(), b.Dx(), b.Dy()+barHeight)
+	draw.Draw(out, bar, image.NewUniform(captionBackground), image.Point{}, draw.Src)
+	for i, line := range lines {
+		drawImageAt(out, renderText(line, captionBackground), margin, bar.Min.Y+margin+i*lineHeight, scale)
+	}
+	return out
+}
+
+// captionMenuItem toggles the caption bar on snapshots.
+func (app *VideoCompareApp) captionMenuItem() *fyne.MenuItem {
+	item := fyne.NewMenuItem(tr("Caption Snapshots"), nil)
+	item.Checked = captionEnabled()
+	item.Action = func() {
+		item.Checked = !item.Checked
+		fyne.CurrentApp().Preferences().SetBool(prefSnapshotCaption, item.Checked)
+		app.window.MainMenu().Refresh()
+	}
+	return item
+}
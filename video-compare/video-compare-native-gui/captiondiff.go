@@ -0,0 +1,421 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// captionRetimeTolerance is how far, in seconds, a caption may start or
+// end off its counterpart before it counts as retimed. It absorbs the
+// frame rounding of caption timing.
+const captionRetimeTolerance = 0.1
+
+var (
+	srtBlockSeparator = regexp.MustCompile(`\r?\n\s*\r?\n`)
+	srtTimingPattern  = regexp.MustCompile(`(\d+):(\d\d):(\d\d)[,.](\d\d\d)\s*-->\s*(\d+):(\d\d):(\d\d)[,.](\d\d\d)`)
+	captionTagPattern = regexp.MustCompile(`<[^>]*>|\{[^}]*\}`)
+)
+
+// textSubtitleCodecs are the subtitle codecs ffmpeg can convert to SRT.
+// Bitmap subtitles such as PGS or DVB would need OCR.
+var textSubtitleCodecs = map[string]bool{
+	"subrip": true, "srt": true, "ass": true, "ssa": true, "mov_text": true,
+	"webvtt": true, "text": true, "eia_608": true, "microdvd": true,
+}
+
+// captionCue is one caption shown from Start to End seconds.
+type captionCue struct {
+	Start, End float64
+	Text       string
+}
+
+// key is the cue's text as compared: without styling tags, line breaks or
+// repeated spaces.
+func (c captionCue) key() string {
+	return strings.Join(strings.Fields(captionTagPattern.ReplaceAllString(c.Text, " ")), " ")
+}
+
+// parseSRT reads the cues of an SRT document, skipping malformed blocks.
+func parseSRT(srt string) []captionCue {
+	var cues []captionCue
+	for _, block := range srtBlockSeparator.Split(strings.TrimSpace(srt), -1) {
+		lines := strings.Split(strings.ReplaceAll(block, "\r", ""), "\n")
+		for i, line := range lines {
+			m := srtTimingPattern.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			cue := captionCue{Start: srtSeconds(m[1:5]), End: srtSeconds(m[5:9])}
+			cue.Text = strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+			if cue.key() != "" {
+				cues = append(cues, cue)
+			}
+			break
+		}
+	}
+	return cues
+}
+
+// srtSeconds converts the hours, minutes, seconds and milliseconds of an
+// SRT timestamp to seconds.
+func srtSeconds(parts []string) float64 {
+	var v [4]int
+	for i, p := range parts {
+		v[i], _ = strconv.Atoi(p)
+	}
+	return float64(v[0]*3600+v[1]*60+v[2]) + float64(v[3])/1000
+}
+
+// filterPath escapes path for use as a filter option inside a filtergraph,
+// once for the option value and once for the graph.
+func filterPath(path string) string {
+	option := strings.NewReplacer(`\`, `\\`, `'`, `\'`, `:`, `\:`).Replace(path)
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`, `[`, `\[`, `]`, `\]`, `,`, `\,`, `;`, `\;`).Replace(option)
+}
+
+// extractCaptions reads path's captions as SRT: the first text subtitle
+// stream, or else the CEA-608/708 closed captions carried in the video
+// stream. It also says which of the two it used. The SRT is cached.
+func extractCaptions(ctx context.Context, path string) ([]captionCue, string, error) {
+	out, err := runFFprobe("-show_entries", "stream=index,codec_type,codec_name,closed_captions", path)
+	if err != nil {
+		return nil, "", err
+	}
+	var probe struct {
+		Streams []struct {
+			Index          int    `json:"index"`
+			CodecType      string `json:"codec_type"`
+			CodecName      string `json:"codec_name"`
+			ClosedCaptions int    `json:"closed_captions"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, "", fmt.Errorf("parsing ffprobe output: %w", err)
+	}
+
+	var args []string
+	var source string
+	for _, s := range probe.Streams {
+		if s.CodecType == "subtitle" && textSubtitleCodecs[s.CodecName] {
+			args = []string{"-i", path, "-map", fmt.Sprintf("0:%d", s.Index)}
+			source = fmt.Sprintf("subtitle stream #%d (%s)", s.Index, s.CodecName)
+			break
+		}
+	}
+	if args == nil {
+		for _, s := range probe.Streams {
+			if s.CodecType == "video" && s.ClosedCaptions > 0 {
+				args = []string{"-f", "lavfi", "-i", "movie=" + filterPath(path) + "[out0+subcc]", "-map", "0:s"}
+				source = "closed captions"
+				break
+			}
+		}
+	}
+	if args == nil {
+		return nil, "", fmt.Errorf("no text subtitles or closed captions")
+	}
+
+	kind := "captions"
+	srt, ok := cacheGet(kind, path)
+	if !ok {
+		srt, err = runFFmpeg(ctx, append(args, "-f", "srt", "-")...)
+		if err != nil {
+			return nil, "", err
+		}
+		cachePut(kind, path, srt)
+	}
+	return parseSRT(string(srt)), source, nil
+}
+
+// captionChange classifies a line of the caption diff.
+type captionChange int
+
+const (
+	captionSame captionChange = iota
+	captionRetimed
+	captionAdded
+	captionRemoved
+)
+
+func (c captionChange) String() string {
+	switch c {
+	case captionRetimed:
+		return "retimed"
+	case captionAdded:
+		return "added"
+	case captionRemoved:
+		return "removed"
+	}
+	return "same"
+}
+
+// captionDiffLine is one cue of either clip, or a pair of cues with the
+// same text. Left is nil for added cues and Right for removed ones.
+type captionDiffLine struct {
+	Change      captionChange
+	Left, Right *captionCue
+}
+
+// diffCaptions lines up the cues of both clips by text, keeping their
+// order, and marks pairs whose timing moved as retimed and the rest as
+// added or removed.
+func diffCaptions(left, right []captionCue) []captionDiffLine {
+	// lcs[i][j] is the longest common run of texts in left[i:] and right[j:]
+	lcs := make([][]int, len(left)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(right)+1)
+	}
+	for i := len(left) - 1; i >= 0; i-- {
+		for j := len(right) - 1; j >= 0; j-- {
+			if left[i].key() == right[j].key() {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []captionDiffLine
+	i, j := 0, 0
+	for i < len(left) || j < len(right) {
+		switch {
+		case i < len(left) && j < len(right) && left[i].key() == right[j].key():
+			change := captionSame
+			if math.Abs(left[i].Start-right[j].Start) > captionRetimeTolerance ||
+				math.Abs(left[i].End-right[j].End) > captionRetimeTolerance {
+				change = captionRetimed
+			}
+			lines = append(lines, captionDiffLine{Change: change, Left: &left[i], Right: &right[j]})
+			i++
+			j++
+		case i < len(left) && (j == len(right) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, captionDiffLine{Change: captionRemoved, Left: &left[i]})
+			i++
+		default:
+			lines = append(lines, captionDiffLine{Change: captionAdded, Right: &right[j]})
+			j++
+		}
+	}
+	return lines
+}
+
+// captionsPanel extracts both clips' captions and lists them side by side,
+// marking added, removed and retimed cues. Selecting a line seeks both
+// players to it.
+type captionsPanel struct {
+	app *VideoCompareApp
+
+	lines   []captionDiffLine
+	shown   []captionDiffLine
+	cancel  context.CancelFunc
+	sources [2]string
+
+	compareBtn  *widget.Button
+	cancelBtn   *widget.Button
+	changedOnly *widget.Check
+	statusLabel *widget.Label
+	list        *widget.List
+}
+
+func newCaptionsPanel(app *VideoCompareApp) *captionsPanel {
+	return &captionsPanel{app: app}
+}
+
+func (cp *captionsPanel) content() fyne.CanvasObject {
+	cp.compareBtn = widget.NewButtonWithIcon("Compare Captions", theme.SearchIcon(), cp.compare)
+	cp.cancelBtn = widget.NewButtonWithIcon("Cancel", theme.CancelIcon(), cp.stop)
+	cp.changedOnly = widget.NewCheck("Changes only", func(bool) { cp.filter() })
+	cp.statusLabel = widget.NewLabel("")
+	cp.statusLabel.Wrapping = fyne.TextWrapWord
+	if reason := cp.app.unavailable(needsFFmpeg, needsFFprobe); reason != "" {
+		cp.statusLabel.SetText("Caption comparison " + reason)
+	}
+
+	cp.list = widget.NewList(
+		func() int { return len(cp.shown) },
+		func() fyne.CanvasObject {
+			// Two lines: the timing, then the text on one line
+			left, right := widget.NewLabel("00:00:00.000\n"), widget.NewLabel("00:00:00.000\n")
+			left.Truncation, right.Truncation = fyne.TextTruncateEllipsis, fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, widget.NewLabel("retimed"), nil, container.NewGridWithColumns(2, left, right))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			line := cp.shown[id]
+			row := obj.(*fyne.Container)
+			cells := row.Objects[0].(*fyne.Container).Objects
+			mark := row.Objects[1].(*widget.Label)
+			mark.SetText(line.Change.String())
+			mark.Importance = captionImportance(line.Change)
+			mark.Refresh()
+			for i, cue := range []*captionCue{line.Left, line.Right} {
+				label := cells[i].(*widget.Label)
+				if cue == nil {
+					label.SetText("")
+					continue
+				}
+				label.SetText(fmt.Sprintf("%s → %s\n%s", formatMacroTime(cue.Start), formatMacroTime(cue.End),
+					strings.ReplaceAll(cue.Text, "\n", " / ")))
+			}
+		},
+	)
+	cp.list.OnSelected = func(id widget.ListItemID) {
+		cp.seek(cp.shown[id])
+	}
+
+	toolbar := container.NewHBox(cp.compareBtn, cp.cancelBtn, cp.changedOnly)
+	cp.refresh()
+	return container.NewBorder(container.NewVBox(toolbar, cp.statusLabel), nil, nil, nil, cp.list)
+}
+
+func captionImportance(c captionChange) widget.Importance {
+	switch c {
+	case captionAdded:
+		return widget.SuccessImportance
+	case captionRemoved:
+		return widget.DangerImportance
+	case captionRetimed:
+		return widget.WarningImportance
+	}
+	return widget.LowImportance
+}
+
+// seek moves each player to its cue of line, or both to the one cue an
+// added or removed line has.
+func (cp *captionsPanel) seek(line captionDiffLine) {
+	for i, vp := range []*VideoPlayer{cp.app.leftPlayer, cp.app.rightPlayer} {
+		cue := line.Left
+		if (i == 1 && line.Right != nil) || cue == nil {
+			cue = line.Right
+		}
+		if vp.canPlay() && cue.Start <= vp.duration {
+			vp.seekTo(cue.Start)
+		}
+	}
+}
+
+// compare extracts both clips' captions in the background and diffs them.
+func (cp *captionsPanel) compare() {
+	cp.stop()
+	l, r := cp.app.leftPlayer, cp.app.rightPlayer
+	for _, vp := range []*VideoPlayer{l, r} {
+		if vp.path == "" || vp.still != nil || isNetworkSource(vp.path) {
+			cp.setResult(nil, "Caption comparison needs a local video file in both players")
+			return
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cp.cancel = cancel
+	cp.setResult(nil, "Extracting captions…")
+
+	paths := [2]string{l.path, r.path}
+	go func() {
+		var cues [2][]captionCue
+		var sources [2]string
+		var err error
+		for i, path := range paths {
+			cues[i], sources[i], err = extractCaptions(ctx, path)
+			if err != nil {
+				err = fmt.Errorf("%s: %w", displayName(path), err)
+				break
+			}
+		}
+		fyne.Do(func() {
+			if ctx.Err() != nil || l.path != paths[0] || r.path != paths[1] {
+				return
+			}
+			cancel()
+			cp.cancel = nil
+			if err != nil {
+				log.Printf("caption comparison: %v", err)
+				cp.setResult(nil, fmt.Sprintf("Comparison failed: %v", err))
+				return
+			}
+			cp.sources = sources
+			lines := diffCaptions(cues[0], cues[1])
+			cp.setResult(lines, cp.summary(lines, cues))
+		})
+	}()
+}
+
+// summary counts the cues of each kind of change.
+func (cp *captionsPanel) summary(lines []captionDiffLine, cues [2][]captionCue) string {
+	var counts [4]int
+	for _, line := range lines {
+		counts[line.Change]++
+	}
+	return fmt.Sprintf("%s: %d cues from %s; %s: %d cues from %s\n%d added, %d removed, %d retimed, %d unchanged",
+		cp.app.leftPlayer.title, len(cues[0]), cp.sources[0],
+		cp.app.rightPlayer.title, len(cues[1]), cp.sources[1],
+		counts[captionAdded], counts[captionRemoved], counts[captionRetimed], counts[captionSame])
+}
+
+// stop cancels a running extraction.
+func (cp *captionsPanel) stop() {
+	if cp.cancel == nil {
+		return
+	}
+	cp.cancel()
+	cp.cancel = nil
+	cp.setResult(nil, "Comparison cancelled")
+}
+
+// reset drops the comparison when either file changes.
+func (cp *captionsPanel) reset() {
+	if cp.cancel != nil {
+		cp.cancel()
+		cp.cancel = nil
+	}
+	cp.setResult(nil, "")
+}
+
+func (cp *captionsPanel) setResult(lines []captionDiffLine, status string) {
+	cp.lines = lines
+	if cp.list != nil {
+		cp.list.UnselectAll()
+		cp.statusLabel.SetText(status)
+	}
+	cp.filter()
+}
+
+// filter applies the changes-only option to the listed lines.
+func (cp *captionsPanel) filter() {
+	cp.shown = cp.lines
+	if cp.changedOnly != nil && cp.changedOnly.Checked {
+		cp.shown = nil
+		for _, line := range cp.lines {
+			if line.Change != captionSame {
+				cp.shown = append(cp.shown, line)
+			}
+		}
+	}
+	cp.refresh()
+}
+
+func (cp *captionsPanel) refresh() {
+	if cp.list == nil {
+		return
+	}
+	if cp.cancel != nil {
+		cp.compareBtn.Disable()
+		cp.cancelBtn.Enable()
+	} else {
+		cp.compareBtn.Enable()
+		cp.cancelBtn.Disable()
+	}
+	if cp.app.unavailable(needsFFmpeg, needsFFprobe) != "" {
+		cp.compareBtn.Disable()
+	}
+	cp.list.Refresh()
+}
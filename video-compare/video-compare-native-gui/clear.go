@@ -0,0 +1,49 @@
+package main
+
+// unload stops vp and releases its file, returning it to the state it had
+// before anything was loaded.
+func (vp *VideoPlayer) unload() {
+	vp.cancelReconnect()
+	vp.stopParsing()
+	vp.cancelLoad()
+	vp.stop()
+	if vp.media != nil {
+		vp.media.Release()
+		vp.media = nil
+	}
+	vp.clearStill()
+	vp.dropGrabbedFrame()
+
+	vp.path = ""
+	vp.pinCache("")
+	vp.segments = nil
+	vp.transcoded = nil
+	vp.variants = nil
+	vp.variant = nil
+	vp.variantSelect.Hide()
+	vp.transform = ""
+	vp.rangeStart, vp.rangeEnd = 0, 0
+	vp.updateRangeLabel()
+	vp.resetMediaInfo()
+	vp.parseTimedOut = false
+	vp.frameCount, vp.streamDuration = 0, 0
+	vp.frameTimes = nil
+	vp.decoder = nil
+	vp.startTimecode = ""
+	vp.updateSeekHint()
+
+	vp.fileLabel.SetText(tr("No file selected"))
+	vp.updateTimeDisplay()
+	vp.updatingProgress = true
+	vp.progressBar.SetValue(0)
+	vp.updatingProgress = false
+	vp.statsLabel.SetText(tr("No video loaded"))
+	vp.updateVideoCanvas()
+	vp.updateControls()
+}
+
+// clearPlayer unloads vp and resets everything derived from its file.
+func (app *VideoCompareApp) clearPlayer(vp *VideoPlayer) {
+	vp.unload()
+	app.playerChanged(vp)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// copyImage puts img on the system clipboard. Fyne's clipboard only carries
+// text, so the image is handed to a platform tool; when none is available
+// the PNG stays in a temporary file and its path is copied instead.
+func (app *VideoCompareApp) copyImage(img image.Image, name string) {
+	path, err := writeTempPNG(img, name)
+	if err != nil {
+		dialog.ShowError(err, app.window)
+		return
+	}
+
+	// Output is not captured: xclip keeps running to own the selection and
+	// would hold a pipe open indefinitely.
+	if cmd := imageClipboardCommand(path); cmd != nil {
+		err := cmd.Run()
+		if err == nil {
+			return
+		}
+		log.Printf("image clipboard via %s failed: %v", cmd.Path, err)
+	}
+
+	fyne.CurrentApp().Clipboard().SetContent(path)
+	dialog.ShowInformation("Copied File Path",
+		fmt.Sprintf("Copying images is not supported on this system.\nThe frame was saved to:\n%s\n\nIts path has been copied to the clipboard.", path),
+		app.window)
+}
+
+// writeTempPNG writes img for the clipboard tools to read. It goes to the
+// cache rather than the temp directory, so old copies are evicted with the
+// rest of the cache instead of piling up.
+func writeTempPNG(img image.Image, name string) (string, error) {
+	f, err := cacheTempFile("clipboard-" + name + "-*.png")
+	if err != nil {
+		return "", err
+	}
+	err = png.Encode(f, img)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	cacheTrim()
+	return f.Name(), nil
+}
+
+// imageClipboardCommand returns a command that copies the PNG at path to the
+// clipboard as image data, or nil if no suitable tool is installed.
+func imageClipboardCommand(path string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf(`set the clipboard to (read (POSIX file %q) as «class PNGf»)`, path)
+		return exec.Command("osascript", "-e", script)
+	case "windows":
+		script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms,System.Drawing; `+
+			`[System.Windows.Forms.Clipboard]::SetImage([System.Drawing.Image]::FromFile('%s'))`, path)
+		return exec.Command("powershell", "-NoProfile", "-STA", "-Command", script)
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			if _, err := exec.LookPath("wl-copy"); err == nil {
+				return exec.Command("sh", "-c", `wl-copy --type image/png < "$1"`, "sh", path)
+			}
+		}
+		if tool, err := exec.LookPath("xclip"); err == nil {
+			return exec.Command(tool, "-selection", "clipboard", "-t", "image/png", "-i", path)
+		}
+	}
+	return nil
+}
+
+func (app *VideoCompareApp) copyFrame(vp *VideoPlayer) {
+	app.whenExported(app.frameExporter(vp), func(img *image.RGBA) { app.copyImage(img, "frame") })
+}
+
+func (app *VideoCompareApp) copySideBySide() {
+	app.whenExported(app.sideBySideExporter(), func(img *image.RGBA) { app.copyImage(img, "side-by-side") })
+}
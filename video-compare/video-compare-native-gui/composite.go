@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	defaultOverlayBlend = 0.5
+	maxOverlayGain      = 32
+)
+
+// overlayColormap colors the amplified differences painted over the blend.
+const overlayColormap = "Inferno"
+
+// overlayComposite shows both players' current frames laid over each other
+// in one pane, with the right one blended in at an adjustable opacity and
+// their difference, amplified by an adjustable gain, painted on top. Where
+// the frames match the blended picture shows through, so it is clear where
+// in the image the differences are.
+type overlayComposite struct {
+	app     *VideoCompareApp
+	enabled bool
+	blend   float64 // opacity of the right frame, 0 to 1
+	gain    float64 // difference amplification, 0 for none
+	pending int     // bumped on every refresh so stale results are dropped
+
+	window     fyne.Window
+	check      *widget.Check
+	image      *canvas.Image
+	label      *widget.Label
+	blendLabel *widget.Label
+	gainLabel  *widget.Label
+	saveBtn    *widget.Button
+	frames     [2]image.Image
+	composed   *image.RGBA
+}
+
+func newOverlayComposite(app *VideoCompareApp) *overlayComposite {
+	return &overlayComposite{app: app, blend: defaultOverlayBlend}
+}
+
+// control returns the toggle opening the composite window.
+func (oc *overlayComposite) control() *widget.Check {
+	oc.check = widget.NewCheck(tr("Overlay"), oc.setEnabled)
+	return oc.check
+}
+
+func (oc *overlayComposite) setEnabled(enabled bool) {
+	if enabled == oc.enabled {
+		return
+	}
+	if enabled {
+		oc.open()
+	} else {
+		oc.close()
+	}
+}
+
+func (oc *overlayComposite) open() {
+	oc.enabled = true
+	if oc.window == nil {
+		oc.image = canvas.NewImageFromImage(nil)
+		oc.image.FillMode = canvas.ImageFillContain
+		oc.image.SetMinSize(fyne.NewSize(640, 360))
+		oc.label = widget.NewLabel("")
+		oc.blendLabel = widget.NewLabel("")
+		oc.gainLabel = widget.NewLabel("")
+
+		// Recomposing a large frame takes a moment, so the sliders only
+		// update their labels while dragged and recompose on release
+		blend := widget.NewSlider(0, 100)
+		blend.SetValue(oc.blend * 100)
+		blend.OnChanged = func(v float64) {
+			oc.blend = v / 100
+			oc.updateLabels()
+		}
+		blend.OnChangeEnded = func(float64) { oc.compose() }
+		gain := widget.NewSlider(0, maxOverlayGain)
+		gain.SetValue(oc.gain)
+		gain.OnChanged = func(v float64) {
+			oc.gain = v
+			oc.updateLabels()
+		}
+		gain.OnChangeEnded = func(float64) { oc.compose() }
+		oc.saveBtn = widget.NewButtonWithIcon("Save Composite", theme.DocumentSaveIcon(), oc.save)
+		oc.saveBtn.Disable()
+		oc.updateLabels()
+
+		sliders := container.New(layout.NewFormLayout(),
+			oc.blendLabel, blend,
+			oc.gainLabel, gain,
+		)
+		bottom := container.NewBorder(nil, nil, nil, oc.saveBtn, sliders)
+		oc.window = fyne.CurrentApp().NewWindow("Overlay Composite")
+		oc.window.SetContent(container.NewBorder(oc.label, bottom, nil, nil, oc.image))
+		oc.window.SetOnClosed(func() {
+			oc.window = nil
+			oc.check.SetChecked(false)
+		})
+	}
+	oc.window.Show()
+	oc.refresh()
+}
+
+func (oc *overlayComposite) close() {
+	oc.enabled = false
+	oc.frames = [2]image.Image{}
+	oc.composed = nil
+	if oc.window != nil {
+		oc.window.Hide()
+	}
+}
+
+func (oc *overlayComposite) updateLabels() {
+	oc.blendLabel.SetText(fmt.Sprintf("Blend: %.0f%% right", oc.blend*100))
+	if oc.gain == 0 {
+		oc.gainLabel.SetText("Diff gain: off")
+	} else {
+		oc.gainLabel.SetText(fmt.Sprintf("Diff gain: %.0f×", oc.gain))
+	}
+}
+
+// refresh grabs both current frames and composes them.
+func (oc *overlayComposite) refresh() {
+	if !oc.enabled {
+		return
+	}
+	oc.pending++
+	generation := oc.pending
+	oc.app.grabPair(func(left, right image.Image, err error) {
+		if generation != oc.pending || !oc.enabled {
+			return
+		}
+		if err != nil {
+			log.Printf("overlay: %v", err)
+			oc.label.SetText(err.Error())
+			return
+		}
+		oc.frames = [2]image.Image{left, right}
+		oc.compose()
+	})
+}
+
+// compose redraws the pane from the grabbed frames at the current blend
+// and gain.
+func (oc *overlayComposite) compose() {
+	left, right := oc.frames[0], oc.frames[1]
+	if left == nil || oc.image == nil {
+		return
+	}
+	oc.composed = composeOverlay(left, right, oc.blend, oc.gain, heatmapDiffMode(), colormaps[overlayColormap])
+	oc.image.Image = oc.composed
+	oc.image.Refresh()
+	oc.saveBtn.Enable()
+
+	l, r := oc.app.leftPlayer, oc.app.rightPlayer
+	text := fmt.Sprintf("%s @ %s over %s @ %s", r.title, formatTimecode(r.currentTime, r.fps), l.title, formatTimecode(l.currentTime, l.fps))
+	if !oc.app.framesAligned() {
+		text += " — not aligned"
+	}
+	oc.label.SetText(text)
+}
+
+func (oc *overlayComposite) save() {
+	if oc.composed == nil {
+		return
+	}
+	oc.app.saveImage(oc.composed, fmt.Sprintf("overlay-%s.png", oc.app.leftPlayer.timecodeFileStamp()))
+}
+
+// composeOverlay blends right over left at opacity blend and paints their
+// difference on top. Each pixel's difference, measured as mode says and
+// multiplied by gain, picks a color from stops that covers the blend in
+// proportion, so matching areas keep showing the picture while
+// differences stand out. right must be the size of left.
+func composeOverlay(left, right image.Image, blend, gain float64, mode string, stops []color.RGBA) *image.RGBA {
+	a, c := toRGBA(left), toRGBA(right)
+	b := a.Bounds()
+
+	// Precompute the color and its coverage for every difference value
+	var ramp [256]color.RGBA
+	var cover [256]float64
+	for d := range ramp {
+		t := min(1, float64(d)*gain/255)
+		ramp[d], cover[d] = colormapAt(stops, t), t
+	}
+
+	diff := pixelDifference(mode)
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			i := a.PixOffset(b.Min.X+x, b.Min.Y+y)
+			j := c.PixOffset(c.Bounds().Min.X+x, c.Bounds().Min.Y+y)
+			k := out.PixOffset(x, y)
+			p, q := a.Pix[i:i+3], c.Pix[j:j+3]
+			d := 0
+			if gain > 0 {
+				d = min(255, int(diff(p, q)+0.5))
+			}
+			hl := [3]uint8{ramp[d].R, ramp[d].G, ramp[d].B}
+			for ch := 0; ch < 3; ch++ {
+				mixed := float64(p[ch]) + (float64(q[ch])-float64(p[ch]))*blend
+				out.Pix[k+ch] = uint8(mixed + (float64(hl[ch])-mixed)*cover[d] + 0.5)
+			}
+			out.Pix[k+3] = 0xff
+		}
+	}
+	return out
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// contentSyncTolerance is how far, in seconds, the right player may drift
+// from the position mapped from the left one during playback before it is
+// seeked back in line.
+const contentSyncTolerance = 0.25
+
+// syncPoint pairs the left and right positions showing the same content.
+type syncPoint struct {
+	Left  float64 `json:"left"`
+	Right float64 `json:"right"`
+}
+
+// contentSync maps positions between the players by content rather than
+// by time, for clips that run at different speeds such as a 24p film and
+// its 25p transfer. The mapping is the line through a start and an end
+// sync point, which default to the starts and ends of both files so that
+// positions map by proportion. While it is on, the right player plays at
+// the line's slope so matching frames stay together.
+type contentSync struct {
+	app        *VideoCompareApp
+	enabled    bool
+	start, end *syncPoint // nil for the files' starts and ends
+
+	check       *widget.Check
+	startBtn    *widget.Button
+	endBtn      *widget.Button
+	clearBtn    *widget.Button
+	statusLabel *widget.Label
+}
+
+func newContentSync(app *VideoCompareApp) *contentSync {
+	return &contentSync{app: app}
+}
+
+func (cs *contentSync) content() fyne.CanvasObject {
+	cs.check = widget.NewCheck("Content sync: match positions by content, not time", cs.setEnabled)
+	cs.startBtn = widget.NewButtonWithIcon("Set Start Point", theme.MediaSkipPreviousIcon(), func() { cs.setPoint(false) })
+	cs.endBtn = widget.NewButtonWithIcon("Set End Point", theme.MediaSkipNextIcon(), func() { cs.setPoint(true) })
+	cs.clearBtn = widget.NewButtonWithIcon("Clear Points", theme.ContentClearIcon(), cs.clear)
+	cs.statusLabel = widget.NewLabel("")
+	cs.statusLabel.Wrapping = fyne.TextWrapWord
+	cs.refresh()
+
+	toolbar := container.NewHBox(cs.check, cs.startBtn, cs.endBtn, cs.clearBtn)
+	help := widget.NewLabel("Line both players up on the same frame near the start and press Set Start Point, then again near the end for Set End Point.")
+	help.Importance = widget.LowImportance
+	help.Wrapping = fyne.TextWrapWord
+	return container.NewVBox(toolbar, cs.statusLabel, help)
+}
+
+// points returns the start and end sync points, filling in the files'
+// starts and ends for those not set.
+func (cs *contentSync) points() (syncPoint, syncPoint) {
+	start, end := syncPoint{}, syncPoint{cs.app.leftPlayer.duration, cs.app.rightPlayer.duration}
+	if cs.start != nil {
+		start = *cs.start
+	}
+	if cs.end != nil {
+		end = *cs.end
+	}
+	return start, end
+}
+
+// slope is how many seconds of the right clip match one second of the
+// left; ok is false until both files have a duration and the points are
+// in order.
+func (cs *contentSync) slope() (slope float64, ok bool) {
+	start, end := cs.points()
+	if end.Left <= start.Left || end.Right <= start.Right {
+		return 0, false
+	}
+	return (end.Right - start.Right) / (end.Left - start.Left), true
+}
+
+// active reports whether positions are being mapped by content.
+func (cs *contentSync) active() bool {
+	_, ok := cs.slope()
+	return cs.enabled && ok
+}
+
+// mapFrom returns the position in the other player showing the content vp
+// shows at seconds.
+func (cs *contentSync) mapFrom(vp *VideoPlayer, seconds float64) float64 {
+	slope, _ := cs.slope()
+	start, _ := cs.points()
+	if vp == cs.app.rightPlayer {
+		return start.Left + (seconds-start.Right)/slope
+	}
+	return start.Right + (seconds-start.Left)*slope
+}
+
+// rate is vp's playback rate relative to the other player's: the slope
+// for the right player while content sync is active, 1 otherwise.
+func (cs *contentSync) rate(vp *VideoPlayer) float64 {
+	if vp != cs.app.rightPlayer || !cs.active() {
+		return 1
+	}
+	slope, _ := cs.slope()
+	return slope
+}
+
+// applyRates sets both players' playback rates for the given speed.
+func (cs *contentSync) applyRates(speed float64) {
+	for _, vp := range []*VideoPlayer{cs.app.leftPlayer, cs.app.rightPlayer} {
+		if vp.canPlay() {
+			vp.setRate(float32(speed * cs.rate(vp)))
+		}
+	}
+}
+
+// align moves the right player to the content the left one shows.
+func (cs *contentSync) align() {
+	if cs.active() && cs.app.rightPlayer.canPlay() {
+		cs.app.rightPlayer.seekTo(cs.mapFrom(cs.app.leftPlayer, cs.app.leftPlayer.currentTime))
+	}
+}
+
+// follow pulls the right player back in line when it has drifted from the
+// left one during playback, as it slowly does at fractional rates.
+func (cs *contentSync) follow() {
+	l, r := cs.app.leftPlayer, cs.app.rightPlayer
+	if !cs.active() || !l.playing() || !r.playing() {
+		return
+	}
+	if target := cs.mapFrom(l, l.currentTime); math.Abs(r.currentTime-target) > contentSyncTolerance {
+		r.seekTo(target)
+	}
+}
+
+// shuttleSpeed is the forward speed the players currently run at.
+func (cs *contentSync) shuttleSpeed() float64 {
+	if cs.app.shuttle.speed > 0 {
+		return float64(cs.app.shuttle.speed)
+	}
+	return 1
+}
+
+func (cs *contentSync) setEnabled(on bool) {
+	cs.enabled = on
+	cs.align()
+	cs.applyRates(cs.shuttleSpeed())
+	cs.refresh()
+}
+
+// enable turns content sync on and ticks its check to match.
+func (cs *contentSync) enable() {
+	cs.check.SetChecked(true) // no callback when already ticked
+	cs.setEnabled(true)
+}
+
+// setPoint records the players' current positions as the start or end
+// sync point.
+func (cs *contentSync) setPoint(end bool) {
+	p := &syncPoint{cs.app.leftPlayer.currentTime, cs.app.rightPlayer.currentTime}
+	if end {
+		cs.end = p
+	} else {
+		cs.start = p
+	}
+	cs.applyRates(cs.shuttleSpeed())
+	cs.refresh()
+}
+
+func (cs *contentSync) clear() {
+	cs.start, cs.end = nil, nil
+	cs.applyRates(cs.shuttleSpeed())
+	cs.refresh()
+}
+
+// forget drops the sync points, which belong to the files they were set
+// on.
+func (cs *contentSync) forget() {
+	cs.start, cs.end = nil, nil
+	cs.refresh()
+}
+
+func (cs *contentSync) refresh() {
+	if cs.statusLabel == nil {
+		return
+	}
+	if cs.app.bothLoaded() {
+		cs.startBtn.Enable()
+		cs.endBtn.Enable()
+	} else {
+		cs.startBtn.Disable()
+		cs.endBtn.Disable()
+	}
+	if cs.start != nil || cs.end != nil {
+		cs.clearBtn.Enable()
+	} else {
+		cs.clearBtn.Disable()
+	}
+
+	start, end := cs.points()
+	describe := func(name string, p syncPoint, set bool) string {
+		origin := "file " + name
+		if set {
+			origin = "set"
+		}
+		return fmt.Sprintf("%s: left %s ↔ right %s (%s)", name, formatMacroTime(p.Left), formatMacroTime(p.Right), origin)
+	}
+	text := describe("start", start, cs.start != nil) + "\n" + describe("end", end, cs.end != nil)
+	switch slope, ok := cs.slope(); {
+	case !cs.app.bothLoaded():
+		text = "Load a video on both sides to sync them by content."
+	case !ok:
+		text += "\nThe end point must come after the start point on both sides."
+	case cs.enabled:
+		text += fmt.Sprintf("\nThe right player plays at %.4f× the left one's speed.", slope)
+	default:
+		text += fmt.Sprintf("\nThe right clip runs %.4f× as long as the left one here.", slope)
+	}
+	cs.statusLabel.SetText(text)
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"log"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// decodeCheckTimeout bounds decoding one frame to look for errors.
+const decodeCheckTimeout = 15 * time.Second
+
+// decoderLogPrefix matches the "[h264 @ 0x55d0c1e2a8c0]" context ffmpeg
+// puts before a component's log lines.
+var decoderLogPrefix = regexp.MustCompile(`^\[(\w+) @ 0x[0-9a-f]+\]\s*`)
+
+// decodeError is a position whose frame failed to decode cleanly.
+type decodeError struct {
+	side    string // title of the player it was found in
+	path    string
+	seconds float64
+	message string
+}
+
+func (e decodeError) String() string {
+	return fmt.Sprintf("%s  %s  %s — %s", e.side, formatMacroTime(e.seconds), displayName(e.path), e.message)
+}
+
+// checkFrameDecode decodes the frame at seconds with ffmpeg and returns the
+// first error it logged, or "" when the frame decoded cleanly. Errors in
+// the frames decoded on the way from the previous keyframe count too, as
+// they reach the frame through its references.
+func checkFrameDecode(ctx context.Context, path string, seconds float64) (string, error) {
+	out, err := runFFmpegLog(ctx, "-v", "error", "-ss", fmt.Sprintf("%.3f", seconds), "-i", path,
+		"-map", "0:v:0", "-frames:v", "1", "-f", "null", "-")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return decoderLogPrefix.ReplaceAllString(line, "$1: "), nil
+		}
+	}
+	return "", nil
+}
+
+// newDecodeErrorView creates the layer covering the video, garbage and
+// all, while its current frame is known not to decode.
+func (vp *VideoPlayer) newDecodeErrorView() *fyne.Container {
+	vp.decodeErrorTitle = canvas.NewText("", color.NRGBA{R: 255, G: 90, B: 90, A: 255})
+	vp.decodeErrorTitle.TextStyle = fyne.TextStyle{Bold: true}
+	vp.decodeErrorTitle.TextSize = theme.TextSize() * 1.5
+	vp.decodeErrorTitle.Alignment = fyne.TextAlignCenter
+	vp.decodeErrorDetail = canvas.NewText("", color.White)
+	vp.decodeErrorDetail.Alignment = fyne.TextAlignCenter
+	vp.decodeErrorView = container.NewStack(
+		canvas.NewRectangle(color.Black),
+		container.NewCenter(container.NewVBox(vp.decodeErrorTitle, vp.decodeErrorDetail)),
+	)
+	vp.decodeErrorView.Hide()
+	return vp.decodeErrorView
+}
+
+func (vp *VideoPlayer) showDecodeError(e decodeError) {
+	vp.decodeFault = &e
+	vp.decodeErrorTitle.Text = "Decode error at " + formatMacroTime(e.seconds)
+	vp.decodeErrorDetail.Text = e.message
+	vp.decodeErrorView.Show()
+	vp.decodeErrorView.Refresh()
+}
+
+func (vp *VideoPlayer) hideDecodeError() {
+	vp.decodeFault = nil
+	if vp.decodeErrorView != nil {
+		vp.decodeErrorView.Hide()
+	}
+}
+
+// decodeErrorHere returns the decode error at vp's current frame, nil if
+// none was found there.
+func (vp *VideoPlayer) decodeErrorHere() *decodeError {
+	if e := vp.decodeFault; e != nil && e.path == vp.path && e.seconds == vp.currentTime {
+		return e
+	}
+	return nil
+}
+
+// decodeErrorsPanel checks each frame a paused player lands on for decode
+// errors and logs the positions where one was found, for reporting which
+// frames of an encode are broken.
+type decodeErrorsPanel struct {
+	app    *VideoCompareApp
+	errors []decodeError
+
+	exportBtn   *widget.Button
+	clearBtn    *widget.Button
+	statusLabel *widget.Label
+	list        *widget.List
+}
+
+func newDecodeErrorsPanel(app *VideoCompareApp) *decodeErrorsPanel {
+	return &decodeErrorsPanel{app: app}
+}
+
+func (dp *decodeErrorsPanel) content() fyne.CanvasObject {
+	dp.exportBtn = widget.NewButtonWithIcon("Export…", theme.DocumentSaveIcon(), dp.export)
+	dp.clearBtn = widget.NewButtonWithIcon("Clear", theme.ContentClearIcon(), func() {
+		dp.errors = nil
+		dp.list.UnselectAll()
+		dp.refresh()
+	})
+	dp.statusLabel = widget.NewLabel("")
+	dp.statusLabel.Wrapping = fyne.TextWrapWord
+
+	dp.list = widget.NewList(
+		func() int { return len(dp.errors) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(dp.errors[id].String())
+		},
+	)
+	dp.list.OnSelected = func(id widget.ListItemID) {
+		dp.seek(dp.errors[id])
+	}
+
+	toolbar := container.NewHBox(dp.exportBtn, dp.clearBtn)
+	dp.refresh()
+	return container.NewBorder(container.NewVBox(toolbar, dp.statusLabel), nil, nil, nil, dp.list)
+}
+
+func (dp *decodeErrorsPanel) refresh() {
+	if dp.list == nil {
+		return
+	}
+	if len(dp.errors) > 0 {
+		dp.exportBtn.Enable()
+		dp.clearBtn.Enable()
+	} else {
+		dp.exportBtn.Disable()
+		dp.clearBtn.Disable()
+	}
+	switch reason := dp.app.unavailable(needsFFmpeg); {
+	case reason != "":
+		dp.statusLabel.SetText("Checking frames for decode errors " + reason)
+	case len(dp.errors) == 0:
+		dp.statusLabel.SetText("Each frame a paused player steps or seeks to is decoded with ffmpeg; frames that fail are listed here.")
+	default:
+		dp.statusLabel.SetText(fmt.Sprintf("%d frames failed to decode", len(dp.errors)))
+	}
+	dp.list.Refresh()
+}
+
+// stateChanged checks the frame a player pauses on and takes down the
+// error overlay once it plays on or stops.
+func (dp *decodeErrorsPanel) stateChanged(vp *VideoPlayer) {
+	if vp.state == statePaused {
+		dp.check(vp)
+	} else {
+		dp.forget(vp)
+	}
+}
+
+// check decodes vp's current frame in the background, covering the video
+// with an error overlay and logging the position if that fails.
+func (dp *decodeErrorsPanel) check(vp *VideoPlayer) {
+	dp.forget(vp)
+	if vp.playing() || vp.path == "" || vp.still != nil || isNetworkSource(vp.path) || dp.app.unavailable(needsFFmpeg) != "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), decodeCheckTimeout)
+	vp.decodeCheck = cancel
+	path, seconds := vp.path, vp.currentTime
+	go func() {
+		message, err := checkFrameDecode(ctx, path, seconds)
+		fyne.Do(func() {
+			if errors.Is(ctx.Err(), context.Canceled) || vp.path != path || vp.currentTime != seconds {
+				return
+			}
+			cancel()
+			vp.decodeCheck = nil
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				log.Printf("checking %s at %s for decode errors: timed out", path, formatMacroTime(seconds))
+				return
+			case err != nil:
+				// ffmpeg gave up on the file altogether
+				message = err.Error()
+			case message == "":
+				return
+			}
+			e := decodeError{side: vp.title, path: path, seconds: seconds, message: message}
+			vp.showDecodeError(e)
+			dp.add(e)
+		})
+	}()
+}
+
+// forget stops any check running for vp and removes its error overlay.
+// Logged errors stay listed.
+func (dp *decodeErrorsPanel) forget(vp *VideoPlayer) {
+	if vp.decodeCheck != nil {
+		vp.decodeCheck()
+		vp.decodeCheck = nil
+	}
+	vp.hideDecodeError()
+}
+
+// add logs e unless the same frame is already listed.
+func (dp *decodeErrorsPanel) add(e decodeError) {
+	for _, logged := range dp.errors {
+		if logged.side == e.side && logged.path == e.path && math.Abs(logged.seconds-e.seconds) < 0.001 {
+			return
+		}
+	}
+	dp.errors = append(dp.errors, e)
+	sort.SliceStable(dp.errors, func(i, j int) bool {
+		if dp.errors[i].side != dp.errors[j].side {
+			return dp.errors[i].side < dp.errors[j].side
+		}
+		return dp.errors[i].seconds < dp.errors[j].seconds
+	})
+	dp.refresh()
+}
+
+// seek pauses the player the error was found in on its frame, provided
+// the file is still loaded there.
+func (dp *decodeErrorsPanel) seek(e decodeError) {
+	for _, vp := range []*VideoPlayer{dp.app.leftPlayer, dp.app.rightPlayer} {
+		if vp.title == e.side && vp.path == e.path {
+			vp.pause()
+			vp.seekTo(e.seconds)
+		}
+	}
+}
+
+// export writes the logged errors as CSV.
+func (dp *decodeErrorsPanel) export() {
+	fd := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+		if err := writeDecodeErrorsCSV(writer, dp.errors); err != nil {
+			dialog.ShowError(err, dp.app.window)
+		}
+	}, dp.app.window)
+	fd.SetFileName("decode-errors.csv")
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{".csv"}))
+	fd.Show()
+}
+
+func writeDecodeErrorsCSV(w io.Writer, decodeErrors []decodeError) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"side", "file", "seconds", "time", "error"}); err != nil {
+		return err
+	}
+	for _, e := range decodeErrors {
+		record := []string{e.side, e.path, strconv.FormatFloat(e.seconds, 'f', 3, 64), formatMacroTime(e.seconds), e.message}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
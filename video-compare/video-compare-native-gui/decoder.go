@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"fyne.io/fyne/v2"
+)
+
+// decoderInfo describes how a file's video stream gets decoded on this
+// machine, as ffmpeg chooses with hardware acceleration allowed. libvlc
+// draws on the same hardware APIs, so a file ffmpeg can only decode in
+// software is one libvlc falls back to software for too.
+type decoderInfo struct {
+	Codec   string // codec name, e.g. H.264
+	Decoder string // ffmpeg decoder implementation, e.g. h264 or hevc_cuvid
+	HWAccel string // hardware API in use, "" when decoding in software
+}
+
+func (d decoderInfo) String() string {
+	switch {
+	case d.HWAccel != "":
+		return fmt.Sprintf("%s (hardware, %s)", d.Codec, d.HWAccel)
+	case strings.HasSuffix(d.Decoder, "_cuvid"), strings.HasSuffix(d.Decoder, "_qsv"),
+		strings.HasSuffix(d.Decoder, "_videotoolbox"), strings.HasSuffix(d.Decoder, "_mediacodec"):
+		return fmt.Sprintf("%s (hardware, %s)", d.Codec, d.Decoder)
+	case d.Decoder != "":
+		return fmt.Sprintf("%s (software, %s)", d.Codec, d.Decoder)
+	}
+	return d.Codec
+}
+
+var (
+	// Logged at verbose level once a hardware device is set up
+	hwaccelPattern = regexp.MustCompile(`Using auto hwaccel type (\w+)`)
+	// Stream #0:0 -> #0:0 (h264 (native) -> wrapped_avframe (native))
+	decoderPattern = regexp.MustCompile(`Stream #\d+:\d+ -> #\d+:\d+ \((\w+) \((\w+)\)`)
+)
+
+// probeDecoder names the video codec of path and decodes its first frame
+// with hardware acceleration allowed to see which decoder and hardware API
+// ffmpeg settles on.
+func probeDecoder(ctx context.Context, path string) (decoderInfo, error) {
+	var info decoderInfo
+	out, err := runFFprobe("-select_streams", "v:0", "-show_entries", "stream=codec_name,codec_long_name", path)
+	if err != nil {
+		return info, err
+	}
+	var probe struct {
+		Streams []struct {
+			Name     string `json:"codec_name"`
+			LongName string `json:"codec_long_name"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return info, fmt.Errorf("parsing ffprobe output: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return info, fmt.Errorf("no video stream")
+	}
+	// "H.264 / AVC / MPEG-4 AVC / MPEG-4 part 10" reads as H.264
+	s := probe.Streams[0]
+	info.Codec, _, _ = strings.Cut(s.LongName, " / ")
+	info.Codec, _, _ = strings.Cut(info.Codec, " (")
+	if info.Codec == "" {
+		info.Codec = strings.ToUpper(s.Name)
+	}
+
+	logOutput, err := runFFmpegLog(ctx, "-v", "verbose", "-hwaccel", "auto",
+		"-i", path, "-map", "0:v:0", "-frames:v", "1", "-f", "null", "-")
+	if err != nil {
+		return info, err
+	}
+	if m := decoderPattern.FindStringSubmatch(logOutput); m != nil {
+		info.Decoder = m[1]
+		if m[2] != "native" {
+			info.Decoder = m[2]
+		}
+	}
+	if m := hwaccelPattern.FindStringSubmatch(logOutput); m != nil {
+		info.HWAccel = m[1]
+	}
+	return info, nil
+}
+
+// analyzeDecoder probes which decoder vp's file uses in the background and
+// shows it in the stats and metadata.
+func (app *VideoCompareApp) analyzeDecoder(vp *VideoPlayer) {
+	vp.decoder = nil
+	if vp.path == "" || vp.still != nil || isNetworkSource(vp.path) {
+		return
+	}
+	path := vp.path
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+		defer cancel()
+		info, err := probeDecoder(ctx, path)
+		fyne.Do(func() {
+			if vp.path != path {
+				return
+			}
+			if err != nil {
+				log.Printf("probing decoder of %s: %v", path, err)
+				return
+			}
+			vp.decoder = &info
+			vp.codec = info.Codec
+			vp.updateStats()
+			app.updateStats()
+		})
+	}()
+}
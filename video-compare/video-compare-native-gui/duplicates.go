@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// duplicateProgressInterval limits how often a running scan reports progress.
+const duplicateProgressInterval = 250 * time.Millisecond
+
+var (
+	duplicateTick     = color.RGBA{R: 0xff, G: 0x90, B: 0x20, A: 0xff}
+	framemd5TBPattern = regexp.MustCompile(`^#tb 0: (\d+)/(\d+)`)
+)
+
+// duplicateRun is a frame followed by one or more identical frames.
+type duplicateRun struct {
+	Start   float64 // time of the original frame
+	End     float64 // time of the last repeat
+	Repeats int
+}
+
+// scanDuplicates hashes every decoded frame of path's first video stream
+// and returns the runs of consecutive identical frames. progress receives
+// the position reached in seconds.
+func scanDuplicates(ctx context.Context, path string, progress func(float64)) ([]duplicateRun, error) {
+	var (
+		runs     []duplicateRun
+		timeBase = 1.0
+		lastHash string
+		lastTime float64
+		parseErr error
+	)
+	err := streamFFmpeg(ctx, func(line string) {
+		if strings.HasPrefix(line, "#") {
+			if m := framemd5TBPattern.FindStringSubmatch(line); m != nil {
+				num, _ := strconv.ParseFloat(m[1], 64)
+				den, _ := strconv.ParseFloat(m[2], 64)
+				if den > 0 {
+					timeBase = num / den
+				}
+			}
+			return
+		}
+		// stream, dts, pts, duration, size, hash
+		fields := strings.Split(line, ",")
+		if len(fields) != 6 {
+			return
+		}
+		pts, err := strconv.ParseInt(strings.TrimSpace(fields[2]), 10, 64)
+		if err != nil {
+			parseErr = fmt.Errorf("unexpected framemd5 line %q", line)
+			return
+		}
+		t := float64(pts) * timeBase
+		hash := strings.TrimSpace(fields[5])
+		if hash == lastHash {
+			if n := len(runs); n > 0 && runs[n-1].End == lastTime {
+				runs[n-1].End = t
+				runs[n-1].Repeats++
+			} else {
+				runs = append(runs, duplicateRun{Start: lastTime, End: t, Repeats: 1})
+			}
+		}
+		lastHash, lastTime = hash, t
+		progress(t)
+	}, "-i", path, "-map", "0:v:0", "-fps_mode", "passthrough", "-f", "framemd5", "-")
+	if err != nil {
+		return nil, err
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	return runs, nil
+}
+
+// duplicatesPanel runs the duplicate-frame scan for either player, lists
+// the runs found and marks them under the player's progress bar.
+type duplicatesPanel struct {
+	app *VideoCompareApp
+
+	runs     map[*VideoPlayer][]duplicateRun
+	cancels  map[*VideoPlayer]context.CancelFunc
+	progress map[*VideoPlayer]float64
+	status   map[*VideoPlayer]string
+
+	playerSelect *widget.Select
+	scanBtn      *widget.Button
+	cancelBtn    *widget.Button
+	progressBar  *widget.ProgressBar
+	statusLabel  *widget.Label
+	list         *widget.List
+}
+
+func newDuplicatesPanel(app *VideoCompareApp) *duplicatesPanel {
+	return &duplicatesPanel{
+		app:      app,
+		runs:     make(map[*VideoPlayer][]duplicateRun),
+		cancels:  make(map[*VideoPlayer]context.CancelFunc),
+		progress: make(map[*VideoPlayer]float64),
+		status:   make(map[*VideoPlayer]string),
+	}
+}
+
+// selected is the player whose results the panel shows.
+func (dp *duplicatesPanel) selected() *VideoPlayer {
+	if dp.playerSelect.Selected == dp.app.rightPlayer.title {
+		return dp.app.rightPlayer
+	}
+	return dp.app.leftPlayer
+}
+
+func (dp *duplicatesPanel) content() fyne.CanvasObject {
+	dp.playerSelect = widget.NewSelect([]string{dp.app.leftPlayer.title, dp.app.rightPlayer.title}, func(string) {
+		if dp.list != nil {
+			dp.list.UnselectAll()
+			dp.refresh()
+		}
+	})
+	dp.playerSelect.SetSelected(dp.app.leftPlayer.title)
+	dp.scanBtn = widget.NewButtonWithIcon("Scan for Duplicates", theme.SearchIcon(), func() { dp.scan(dp.selected()) })
+	dp.cancelBtn = widget.NewButtonWithIcon("Cancel", theme.CancelIcon(), func() { dp.cancel(dp.selected()) })
+	dp.progressBar = widget.NewProgressBar()
+	dp.statusLabel = widget.NewLabel("")
+
+	dp.list = widget.NewList(
+		func() int { return len(dp.runs[dp.selected()]) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			vp := dp.selected()
+			r := dp.runs[vp][id]
+			obj.(*widget.Label).SetText(fmt.Sprintf("%s – %s: %d repeated frame(s)",
+				formatTimecode(r.Start, vp.fps), formatTimecode(r.End, vp.fps), r.Repeats))
+		},
+	)
+	dp.list.OnSelected = func(id widget.ListItemID) {
+		vp := dp.selected()
+		vp.seekTo(dp.runs[vp][id].Start)
+	}
+
+	toolbar := container.NewHBox(dp.playerSelect, dp.scanBtn, dp.cancelBtn)
+	top := container.NewVBox(toolbar, dp.progressBar, dp.statusLabel)
+	dp.refresh()
+	return container.NewBorder(top, nil, nil, nil, dp.list)
+}
+
+// ticks marks the duplicate runs found in vp on its timeline.
+func (dp *duplicatesPanel) ticks(vp *VideoPlayer) []timelineTick {
+	ticks := make([]timelineTick, len(dp.runs[vp]))
+	for i, r := range dp.runs[vp] {
+		ticks[i] = timelineTick{start: r.Start, end: r.End, color: duplicateTick}
+	}
+	return ticks
+}
+
+// scan starts a background duplicate-frame scan of vp, replacing any
+// earlier results.
+func (dp *duplicatesPanel) scan(vp *VideoPlayer) {
+	dp.cancel(vp)
+	delete(dp.runs, vp)
+	if vp.path == "" || vp.still != nil || isNetworkSource(vp.path) {
+		dp.status[vp] = "Duplicate detection needs a local video file"
+		dp.refresh()
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dp.cancels[vp] = cancel
+	dp.progress[vp] = 0
+	dp.status[vp] = "Scanning " + displayName(vp.path) + "…"
+	dp.refresh()
+
+	path, duration := vp.path, vp.duration
+	go func() {
+		var lastReport time.Time
+		runs, err := scanDuplicates(ctx, path, func(t float64) {
+			if duration <= 0 || time.Since(lastReport) < duplicateProgressInterval {
+				return
+			}
+			lastReport = time.Now()
+			fyne.Do(func() {
+				if ctx.Err() != nil || vp.path != path {
+					return
+				}
+				dp.progress[vp] = min(1, t/duration)
+				dp.refresh()
+			})
+		})
+		fyne.Do(func() {
+			if ctx.Err() != nil || vp.path != path {
+				return
+			}
+			cancel()
+			delete(dp.cancels, vp)
+			if err != nil {
+				log.Printf("duplicate detection for %s: %v", path, err)
+				dp.status[vp] = fmt.Sprintf("Scan failed: %v", err)
+				dp.refresh()
+				return
+			}
+			repeats := 0
+			for _, r := range runs {
+				repeats += r.Repeats
+			}
+			dp.runs[vp] = runs
+			dp.progress[vp] = 1
+			dp.status[vp] = fmt.Sprintf("%d run(s) of duplicate frames, %d repeated frame(s) in total", len(runs), repeats)
+			dp.refresh()
+		})
+	}()
+}
+
+// cancel stops a running scan of vp.
+func (dp *duplicatesPanel) cancel(vp *VideoPlayer) {
+	cancel := dp.cancels[vp]
+	if cancel == nil {
+		return
+	}
+	cancel()
+	delete(dp.cancels, vp)
+	dp.progress[vp] = 0
+	dp.status[vp] = "Scan cancelled"
+	dp.refresh()
+}
+
+// reset drops vp's results when a new file is loaded into it.
+func (dp *duplicatesPanel) reset(vp *VideoPlayer) {
+	if cancel := dp.cancels[vp]; cancel != nil {
+		cancel()
+		delete(dp.cancels, vp)
+	}
+	delete(dp.runs, vp)
+	delete(dp.progress, vp)
+	delete(dp.status, vp)
+	dp.refresh()
+}
+
+// refresh updates the controls for the selected player and redraws the
+// timeline ticks.
+func (dp *duplicatesPanel) refresh() {
+	dp.app.refreshTimelineTicks()
+	if dp.list == nil {
+		return
+	}
+	vp := dp.selected()
+	if dp.cancels[vp] != nil {
+		dp.scanBtn.Disable()
+		dp.cancelBtn.Enable()
+	} else {
+		dp.scanBtn.Enable()
+		dp.cancelBtn.Disable()
+	}
+	dp.progressBar.SetValue(dp.progress[vp])
+	dp.statusLabel.SetText(dp.status[vp])
+	if reason := dp.app.unavailable(needsFFmpeg); reason != "" {
+		dp.scanBtn.Disable()
+		dp.statusLabel.SetText("Duplicate detection " + reason)
+	}
+	dp.list.Refresh()
+}
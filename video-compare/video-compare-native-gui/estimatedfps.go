@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"fyne.io/fyne/v2"
+	"videocompare"
+)
+
+// estimatedFPS is the frame rate frame stepping assumes when neither
+// libvlc nor ffprobe report one, so stepping still moves by about a frame.
+const estimatedFPS = 25.0
+
+// analyzeFPS asks ffprobe for vp's frame rate when libvlc's track info
+// left it at zero, which happens with some raw and badly muxed streams.
+func (app *VideoCompareApp) analyzeFPS(vp *VideoPlayer) {
+	if vp.path == "" || vp.still != nil || isNetworkSource(vp.path) {
+		return
+	}
+	path := vp.path
+	vp.whenParsed(func() {
+		if vp.path != path || vp.fps > 0 {
+			return
+		}
+		go func() {
+			fps, err := videocompare.ProbeFrameRate(context.Background(), path)
+			fyne.Do(func() {
+				if vp.path != path || vp.fps > 0 {
+					return
+				}
+				if err != nil {
+					log.Printf("reading frame rate of %s: %v", path, err)
+					return
+				}
+				vp.fps = fps
+				vp.updateTimeDisplay()
+				vp.updateSeekHint()
+				app.updateTimecodeAlign()
+				app.measuredFPSChanged(vp)
+			})
+		}()
+	})
+}
+
+// fpsEstimated reports whether frame stepping falls back to estimatedFPS
+// because vp's frame rate is unknown.
+func (vp *VideoPlayer) fpsEstimated() bool {
+	return vp.fps <= 0 && vp.measuredFPS() <= 0
+}
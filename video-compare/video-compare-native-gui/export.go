@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// frameExporter returns a function grabbing vp's current frame with the
+// overlays that should be burned into exported images. Like frameGrabber
+// it must be called on the UI goroutine, which also takes the overlays as
+// they are now, and the function it returns may run on any goroutine.
+func (app *VideoCompareApp) frameExporter(vp *VideoPlayer) func() (*image.RGBA, error) {
+	grab := vp.frameGrabber()
+	aspect := vp.sampleAspect()
+	var annotations []*annotation
+	for _, a := range vp.annotations {
+		copied := *a
+		annotations = append(annotations, &copied)
+	}
+	height := vp.height
+	burnIn, timecode := app.burnIn, vp.timecode()
+	labels, side := app.labels, app.side(vp)
+	return func() (*image.RGBA, error) {
+		frame, err := grab()
+		if err != nil {
+			return nil, err
+		}
+		out := applyDisplayAspect(toRGBA(frame), aspect)
+		if len(annotations) > 0 && height > 0 {
+			drawAnnotations(out, annotations, float64(out.Bounds().Dy())/float64(height))
+		}
+		if burnIn.enabled {
+			drawTextBox(out, timecode, burnIn.corner)
+		}
+		labels.draw(out, out.Bounds(), side)
+		return out, nil
+	}
+}
+
+// sideBySideExporter returns a function grabbing both players' frames and
+// composing them next to each other, with the same rules as frameExporter.
+func (app *VideoCompareApp) sideBySideExporter() func() (*image.RGBA, error) {
+	exportLeft, exportRight := app.frameExporter(app.leftPlayer), app.frameExporter(app.rightPlayer)
+	caption := app.provenance.captionLines()
+	return func() (*image.RGBA, error) {
+		left, err := exportLeft()
+		if err != nil {
+			return nil, err
+		}
+		right, err := exportRight()
+		if err != nil {
+			return nil, err
+		}
+		img := composeSideBySide(left, right)
+		if caption != nil {
+			img = addCaptionBar(img, caption)
+		}
+		return img, nil
+	}
+}
+
+// whenExported runs export in the background and passes the image to done
+// on the UI goroutine, or shows why it failed.
+func (app *VideoCompareApp) whenExported(export func() (*image.RGBA, error), done func(*image.RGBA)) {
+	go func() {
+		img, err := export()
+		fyne.Do(func() {
+			if err != nil {
+				dialog.ShowError(err, app.window)
+				return
+			}
+			done(img)
+		})
+	}()
+}
+
+// composeSideBySide places left and right next to each other on a black
+// background, vertically centring the shorter image.
+func composeSideBySide(left, right image.Image) *image.RGBA {
+	lb, rb := left.Bounds(), right.Bounds()
+	height := max(lb.Dy(), rb.Dy())
+	out := image.NewRGBA(image.Rect(0, 0, lb.Dx()+rb.Dx(), height))
+	draw.Draw(out, out.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+
+	leftRect := image.Rect(0, (height-lb.Dy())/2, lb.Dx(), (height-lb.Dy())/2+lb.Dy())
+	draw.Draw(out, leftRect, left, lb.Min, draw.Src)
+
+	rightRect := image.Rect(lb.Dx(), (height-rb.Dy())/2, lb.Dx()+rb.Dx(), (height-rb.Dy())/2+rb.Dy())
+	draw.Draw(out, rightRect, right, rb.Min, draw.Src)
+	return out
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(out, out.Bounds(), img, b.Min, draw.Src)
+	return out
+}
+
+// drawTextBox renders text on a translucent box in the given corner of dst.
+// The bitmap font is scaled with the frame height so the text stays legible
+// on high resolution frames.
+func drawTextBox(dst *image.RGBA, text string, corner overlayCorner) {
+	box := renderTextBox(text)
+	scale := max(1, dst.Bounds().Dy()/360)
+	w, h := box.Bounds().Dx()*scale, box.Bounds().Dy()*scale
+	margin := 8 * scale
+	b := dst.Bounds()
+
+	x, y := b.Min.X+margin, b.Min.Y+margin
+	if corner == cornerTopRight || corner == cornerBottomRight {
+		x = b.Max.X - margin - w
+	}
+	if corner == cornerBottomLeft || corner == cornerBottomRight {
+		y = b.Max.Y - margin - h
+	}
+	drawImageAt(dst, box, x, y, scale)
+}
+
+// renderTextBox renders white text on a translucent black box.
+func renderTextBox(text string) *image.RGBA {
+	return renderText(text, color.RGBA{A: 170})
+}
+
+// renderText renders white text on a box filled with bg.
+func renderText(text string, bg color.Color) *image.RGBA {
+	face := basicfont.Face7x13
+	const pad = 3
+	textWidth := font.MeasureString(face, text).Ceil()
+	box := image.NewRGBA(image.Rect(0, 0, textWidth+2*pad, face.Height+2*pad))
+	draw.Draw(box, box.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+
+	d := &font.Drawer{
+		Dst:  box,
+		Src:  image.NewUniform(color.White),
+		Face: face,
+		Dot:  fixed.P(pad, pad+face.Ascent),
+	}
+	d.DrawString(text)
+	return box
+}
+
+// drawImageAt composites src onto dst with its top-left corner at (x, y),
+// enlarged by an integer scale factor.
+func drawImageAt(dst *image.RGBA, src image.Image, x, y, scale int) {
+	sb := src.Bounds()
+	rect := image.Rect(x, y, x+sb.Dx()*scale, y+sb.Dy()*scale)
+	draw.NearestNeighbor.Scale(dst, rect, src, sb, draw.Over, nil)
+}
+
+// saveImage asks for a destination and writes img there in the default
+// image format, or the one named by the extension the user chose.
+func (app *VideoCompareApp) saveImage(img image.Image, suggestedName string) {
+	fd := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+		if err := encodeImage(writer, img, formatForPath(writer.URI().Path()), imageQuality()); err != nil {
+			log.Printf("failed to write %s: %v", writer.URI().Path(), err)
+			dialog.ShowError(err, app.window)
+		}
+	}, app.window)
+	fd.SetFileName(withImageExt(suggestedName))
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{".png", ".jpg", ".jpeg", ".webp"}))
+	fd.Show()
+}
+
+func (app *VideoCompareApp) saveSnapshot(vp *VideoPlayer) {
+	var caption []string
+	if captionEnabled() {
+		caption = vp.captionLines()
+	}
+	name := fmt.Sprintf("snapshot-%s.png", vp.timecodeFileStamp())
+	app.macro.recordSide(vp, macroCommand{name: "snapshot"})
+	app.whenExported(app.frameExporter(vp), func(img *image.RGBA) {
+		if caption != nil {
+			img = addCaptionBar(img, caption)
+		}
+		app.saveImage(img, name)
+	})
+}
+
+func (app *VideoCompareApp) saveSideBySide() {
+	name := fmt.Sprintf("side-by-side-%s.png", app.leftPlayer.timecodeFileStamp())
+	app.macro.record(macroCommand{name: "snapshot"})
+	app.whenExported(app.sideBySideExporter(), func(img *image.RGBA) {
+		app.saveImage(img, name)
+	})
+}
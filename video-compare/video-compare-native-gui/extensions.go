@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const prefSupportedFormats = "formats.extensions"
+
+// defaultFormats are the extensions offered by the open dialog out of the
+// box: common video containers plus the still reference image formats.
+var defaultFormats = []string{
+	".mp4", ".mkv", ".avi", ".mov", ".webm", ".flv", ".wmv", ".m4v", ".3gp", ".ogv", ".ts", ".mts", ".m2ts",
+	".png", ".jpg", ".jpeg",
+}
+
+var extensionPattern = regexp.MustCompile(`^\.[a-z0-9][a-z0-9_+-]*$`)
+
+// supportedFormats returns the customized extension list, or the defaults
+// when none was saved.
+func supportedFormats() []string {
+	exts := fyne.CurrentApp().Preferences().StringList(prefSupportedFormats)
+	if len(exts) == 0 {
+		return slices.Clone(defaultFormats)
+	}
+	return exts
+}
+
+// parseExtensions splits a whitespace or comma separated list and checks
+// every entry is a lowercase, dot-prefixed extension.
+func parseExtensions(s string) ([]string, error) {
+	var exts []string
+	for _, ext := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\n' || r == '\t' }) {
+		if !extensionPattern.MatchString(ext) {
+			return nil, fmt.Errorf("invalid extension %q: use a lowercase extension starting with a dot, like .mp4", ext)
+		}
+		if !slices.Contains(exts, ext) {
+			exts = append(exts, ext)
+		}
+	}
+	if len(exts) == 0 {
+		return nil, fmt.Errorf("at least one extension is required")
+	}
+	return exts, nil
+}
+
+// supportedFormatsDialog edits the extensions accepted by the open dialog,
+// for containers libvlc can play but aren't listed by default.
+func (app *VideoCompareApp) supportedFormatsDialog() {
+	entry := widget.NewMultiLineEntry()
+	entry.Wrapping = fyne.TextWrapWord
+	entry.SetText(strings.Join(supportedFormats(), " "))
+	entry.SetMinRowsVisible(4)
+	reset := widget.NewButtonWithIcon("Reset to Defaults", theme.ViewRefreshIcon(), func() {
+		entry.SetText(strings.Join(defaultFormats, " "))
+	})
+	content := container.NewBorder(
+		widget.NewLabel("Extensions accepted when opening files, separated by spaces or commas:"),
+		container.NewHBox(reset), nil, nil, entry)
+
+	d := dialog.NewCustomConfirm("Supported Formats", "Save", "Cancel", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		exts, err := parseExtensions(entry.Text)
+		if err != nil {
+			dialog.ShowError(err, app.window)
+			return
+		}
+		prefs := fyne.CurrentApp().Preferences()
+		if slices.Equal(exts, defaultFormats) {
+			prefs.RemoveValue(prefSupportedFormats)
+		} else {
+			prefs.SetStringList(prefSupportedFormats, exts)
+		}
+	}, app.window)
+	d.Resize(fyne.NewSize(520, 0))
+	d.Show()
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"image"
+	"log"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/widget"
+	libvlc "github.com/adrg/libvlc-go/v3"
+)
+
+// Field display modes. Anything but fieldsFrame shows the fields of the
+// current frame instead of the video, which is mutually exclusive with
+// deinterlacing since that merges the fields.
+const (
+	fieldsFrame  = "Full Frame"
+	fieldsTop    = "Top Field"
+	fieldsBottom = "Bottom Field"
+	fieldsBoth   = "Both Fields"
+)
+
+var fieldModes = []string{fieldsFrame, fieldsTop, fieldsBottom, fieldsBoth}
+
+// newFieldView creates the hidden image that shows the separated fields
+// over the video.
+func (vp *VideoPlayer) newFieldView() *canvas.Image {
+	vp.fieldView = canvas.NewImageFromImage(nil)
+	vp.fieldView.FillMode = canvas.ImageFillContain
+	vp.fieldView.ScaleMode = canvas.ImageScalePixels // keep field lines crisp
+	vp.fieldView.Hide()
+	return vp.fieldView
+}
+
+// fieldPanel shows one or both fields of each player's current frame, to
+// diagnose field order mismatches between interlaced encodes.
+type fieldPanel struct {
+	app     *VideoCompareApp
+	mode    string
+	pending int // bumped on every refresh so stale results are dropped
+	sel     *widget.Select
+}
+
+func newFieldPanel(app *VideoCompareApp) *fieldPanel {
+	return &fieldPanel{app: app, mode: fieldsFrame}
+}
+
+func (fp *fieldPanel) active() bool {
+	return fp.mode != fieldsFrame
+}
+
+func (fp *fieldPanel) selector() fyne.CanvasObject {
+	fp.sel = widget.NewSelect(fieldModes, fp.setMode)
+	fp.sel.SetSelected(fieldsFrame)
+	return fp.sel
+}
+
+// setMode switches the field display. Showing fields turns inverse
+// telecine off and disables libvlc's deinterlacer, so the grabbed frames
+// still carry both fields.
+func (fp *fieldPanel) setMode(mode string) {
+	fp.mode = mode
+	if fp.active() {
+		if fp.app.ivtcCheck.Checked {
+			fp.app.ivtcCheck.SetChecked(false)
+		}
+		for _, vp := range []*VideoPlayer{fp.app.leftPlayer, fp.app.rightPlayer} {
+			if err := vp.player.SetDeinterlaceMode(libvlc.DeinterlaceModeDisable); err != nil {
+				log.Printf("%s: disabling deinterlacing: %v", vp.title, err)
+			}
+			vp.dropGrabbedFrame()
+		}
+	}
+	fp.refresh()
+}
+
+// deactivate returns to full frame display, used when deinterlacing is
+// turned on.
+func (fp *fieldPanel) deactivate() {
+	if fp.active() {
+		fp.sel.SetSelected(fieldsFrame)
+	}
+}
+
+// refresh grabs each player's current frame and shows its fields.
+func (fp *fieldPanel) refresh() {
+	fp.pending++
+	players := []*VideoPlayer{fp.app.leftPlayer, fp.app.rightPlayer}
+	if !fp.active() {
+		for _, vp := range players {
+			vp.fieldView.Hide()
+			vp.fieldView.Image = nil
+		}
+		return
+	}
+
+	generation, mode := fp.pending, fp.mode
+	for _, vp := range players {
+		if vp.path == "" {
+			vp.fieldView.Hide()
+			continue
+		}
+		grab, path := vp.frameGrabber(), vp.path
+		go func() {
+			time.Sleep(frameSettleDelay)
+			frame, err := grab()
+			var img *image.RGBA
+			if err == nil {
+				img = separateFields(frame, mode)
+			}
+			fyne.Do(func() {
+				if generation != fp.pending || vp.path != path {
+					return
+				}
+				if err != nil {
+					log.Printf("%s: grabbing frame for field view: %v", vp.title, err)
+					return
+				}
+				vp.fieldView.Image = img
+				vp.fieldView.Show()
+				vp.fieldView.Refresh()
+			})
+		}()
+	}
+}
+
+// separateFields extracts the fields of an interlaced frame. A single
+// field is line-doubled back to the frame height; both fields are stacked
+// with the top field above the bottom one, each at half height.
+func separateFields(frame image.Image, mode string) *image.RGBA {
+	src := toRGBA(frame)
+	b := src.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	rowBytes := b.Dx() * 4
+	copyRow := func(dstY, srcY int) {
+		d := out.PixOffset(0, dstY)
+		s := src.PixOffset(b.Min.X, b.Min.Y+srcY)
+		copy(out.Pix[d:d+rowBytes], src.Pix[s:s+rowBytes])
+	}
+
+	switch mode {
+	case fieldsTop, fieldsBottom:
+		parity := 0
+		if mode == fieldsBottom {
+			parity = 1
+		}
+		for y := 0; y < b.Dy(); y++ {
+			copyRow(y, min(y&^1+parity, b.Dy()-1))
+		}
+	default:
+		top := (b.Dy() + 1) / 2
+		for y := 0; y < b.Dy(); y++ {
+			if y < top {
+				copyRow(y, 2*y)
+			} else {
+				copyRow(y, 2*(y-top)+1)
+			}
+		}
+	}
+	return out
+}
+
+// forget hides vp's field view until the new file is grabbed.
+func (fp *fieldPanel) forget(vp *VideoPlayer) {
+	vp.fieldView.Hide()
+	vp.fieldView.Image = nil
+	fp.refresh()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// newPathLabel creates the subdued line under vp's file label holding the
+// full path of its file, selectable so it can be copied.
+func (vp *VideoPlayer) newPathLabel() *widget.Label {
+	vp.pathLabel = widget.NewLabel("")
+	vp.pathLabel.Importance = widget.LowImportance
+	vp.pathLabel.SizeName = theme.SizeNameCaptionText
+	vp.pathLabel.Truncation = fyne.TextTruncateEllipsis
+	vp.pathLabel.Selectable = true
+	vp.pathLabel.Hide()
+	return vp.pathLabel
+}
+
+// labelPath is the file vp's label names: the original of a transcoded
+// file or the first of joined segments rather than the file in the cache.
+func (vp *VideoPlayer) labelPath() string {
+	switch {
+	case vp.transcoded != nil:
+		return vp.transcoded.path
+	case len(vp.segments) > 1:
+		return vp.segments[0].path
+	}
+	return vp.path
+}
+
+// shortName is path's base name with vp.nameDirs of its parent
+// directories, for telling apart files of the same name.
+func (vp *VideoPlayer) shortName(path string) string {
+	if vp.nameDirs == 0 || isNetworkSource(path) {
+		return displayName(path)
+	}
+	parts := strings.Split(filepath.ToSlash(filepath.Clean(absPath(path))), "/")
+	if keep := vp.nameDirs + 1; keep < len(parts) {
+		return "…/" + strings.Join(parts[len(parts)-keep:], "/")
+	}
+	return filepath.ToSlash(absPath(path))
+}
+
+// disambiguatingDirs returns how many parent directories the names of a
+// and b need to differ: 0 when their base names already do.
+func disambiguatingDirs(a, b string) int {
+	if a == "" || b == "" || isNetworkSource(a) || isNetworkSource(b) {
+		return 0
+	}
+	pa := strings.Split(filepath.ToSlash(filepath.Clean(absPath(a))), "/")
+	pb := strings.Split(filepath.ToSlash(filepath.Clean(absPath(b))), "/")
+	for n := 0; n < min(len(pa), len(pb)); n++ {
+		if pa[len(pa)-1-n] != pb[len(pb)-1-n] {
+			return n
+		}
+	}
+	// The same file, or one path is a suffix of the other
+	return 0
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// refreshFileLabel names vp's file in its label, with what is under way.
+func (vp *VideoPlayer) refreshFileLabel() {
+	switch {
+	case vp.path == "":
+		vp.fileLabel.SetText(tr("No file selected"))
+	case vp.opening:
+		vp.fileLabel.SetText(vp.sourceName() + " — opening…")
+	case vp.parsing:
+		vp.fileLabel.SetText(vp.sourceName() + " — analyzing…")
+	default:
+		vp.fileLabel.SetText(vp.sourceName())
+	}
+}
+
+// updateFileLabels shows each player's full path under its file label and
+// adds parent directories to the names when both files share a base name,
+// so two renditions called the same aren't mixed up.
+func (app *VideoCompareApp) updateFileLabels() {
+	left, right := app.leftPlayer.labelPath(), app.rightPlayer.labelPath()
+	dirs := 0
+	if displayName(left) == displayName(right) {
+		dirs = disambiguatingDirs(left, right)
+	}
+	for _, vp := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		path := vp.labelPath()
+		if vp.nameDirs != dirs {
+			vp.nameDirs = dirs
+			// Streams show their own connection status
+			if !isNetworkSource(path) {
+				vp.refreshFileLabel()
+			}
+		}
+		if path == "" || isNetworkSource(path) {
+			vp.pathLabel.Hide()
+			continue
+		}
+		vp.pathLabel.SetText(absPath(path))
+		vp.pathLabel.Show()
+	}
+}
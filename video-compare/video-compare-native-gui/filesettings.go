@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// fileSettingsFile is kept next to the comparison history in the app's
+// storage.
+const fileSettingsFile = "file-settings.json"
+
+// fileSettings are the display choices made for one file, reapplied when
+// it is opened again.
+type fileSettings struct {
+	Transform       string           `json:"transform,omitempty"` // libvlc transform filter type
+	Levels          levelsConversion `json:"levels,omitempty"`
+	AudioTrack      int              `json:"audio_track,omitempty"`
+	InverseTelecine bool             `json:"inverse_telecine,omitempty"`
+}
+
+// fileSettingsStore remembers display settings keyed by file path.
+type fileSettingsStore struct {
+	app       *VideoCompareApp
+	path      string
+	files     map[string]fileSettings
+	restoring bool // set while reapplying, so the changes aren't recorded again
+}
+
+func newFileSettingsStore(app *VideoCompareApp) *fileSettingsStore {
+	fs := &fileSettingsStore{app: app, files: make(map[string]fileSettings)}
+	root := fyne.CurrentApp().Storage().RootURI()
+	if root == nil {
+		return fs
+	}
+	fs.path = filepath.Join(root.Path(), fileSettingsFile)
+	data, err := os.ReadFile(fs.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("reading file settings: %v", err)
+		}
+		return fs
+	}
+	if err := json.Unmarshal(data, &fs.files); err != nil {
+		log.Printf("parsing file settings: %v", err)
+	}
+	return fs
+}
+
+func (fs *fileSettingsStore) save() {
+	if fs.path == "" {
+		return
+	}
+	data, err := json.MarshalIndent(fs.files, "", "  ")
+	if err == nil {
+		err = os.WriteFile(fs.path, data, 0o644)
+	}
+	if err != nil {
+		log.Printf("saving file settings: %v", err)
+	}
+}
+
+// remember records vp's current display settings for its file, dropping
+// the entry once everything is back to the defaults.
+func (fs *fileSettingsStore) remember(vp *VideoPlayer) {
+	path := vp.originalPath()
+	if fs.restoring || path == "" || vp.still != nil || isNetworkSource(path) {
+		return
+	}
+	s := fileSettings{
+		Transform:       vp.transform,
+		AudioTrack:      vp.audioTrack,
+		InverseTelecine: vp.ivtc,
+	}
+	if vp.levels != levelsAsEncoded {
+		s.Levels = vp.levels
+	}
+	if s == (fileSettings{}) {
+		if _, ok := fs.files[path]; !ok {
+			return
+		}
+		delete(fs.files, path)
+	} else {
+		fs.files[path] = s
+	}
+	fs.save()
+}
+
+// restore reapplies what was remembered for the file just loaded into vp.
+// The transform needs the media reloaded, which is done right away; the
+// audio track can only be picked once the tracks are known.
+func (fs *fileSettingsStore) restore(vp *VideoPlayer) {
+	s, ok := fs.files[vp.originalPath()]
+	if !ok || vp.media == nil {
+		return
+	}
+	fs.restoring = true
+	defer func() { fs.restoring = false }()
+
+	if s.Transform != "" {
+		vp.transform = s.Transform
+		vp.reloadMedia()
+	}
+	if s.Levels != "" {
+		vp.setLevels(s.Levels)
+	}
+	if s.InverseTelecine && !fs.app.ivtc {
+		// Applied to the clip once its cadence is detected as pulldown
+		fs.app.ivtcCheck.SetChecked(true)
+	}
+	vp.whenParsed(func() {
+		if s.AudioTrack > 0 && s.AudioTrack < len(vp.audioTracks) {
+			fs.restoring = true
+			vp.audioSelect.SetSelectedIndex(s.AudioTrack)
+			fs.restoring = false
+		}
+	})
+}
+
+// forget drops the remembered settings of path.
+func (fs *fileSettingsStore) forget(path string) {
+	delete(fs.files, path)
+	fs.save()
+}
+
+// forgetDialog lets the user pick a file whose remembered settings are
+// cleared, the loaded files first.
+func (fs *fileSettingsStore) forgetDialog() {
+	var paths []string
+	for path := range fs.files {
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		dialog.ShowInformation("Forget File Settings", "No file settings are remembered.", fs.app.window)
+		return
+	}
+	loaded := []string{fs.app.leftPlayer.originalPath(), fs.app.rightPlayer.originalPath()}
+	sort.Slice(paths, func(i, j int) bool {
+		li, lj := slices.Contains(loaded, paths[i]), slices.Contains(loaded, paths[j])
+		if li != lj {
+			return li
+		}
+		return paths[i] < paths[j]
+	})
+
+	choice := widget.NewSelect(paths, nil)
+	choice.SetSelectedIndex(0)
+	dialog.ShowForm("Forget File Settings", "Forget", "Cancel",
+		[]*widget.FormItem{widget.NewFormItem("File", choice)},
+		func(ok bool) {
+			if ok && choice.Selected != "" {
+				fs.forget(choice.Selected)
+			}
+		}, fs.app.window)
+}
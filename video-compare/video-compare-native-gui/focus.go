@@ -0,0 +1,91 @@
+package main
+
+import "fyne.io/fyne/v2"
+
+const (
+	prefPauseOnFocusLoss = "playback.pauseOnFocusLoss"
+	prefResumeOnFocus    = "playback.resumeOnFocus"
+)
+
+// focusPause pauses playback while the window is in the background, when
+// the preference is on, and can resume what it paused on return.
+type focusPause struct {
+	app *VideoCompareApp
+
+	// Players paused by losing focus, with the file each had then, so a
+	// player the user stopped or reloaded meanwhile isn't restarted
+	paused map[*VideoPlayer]string
+
+	resumeItem *fyne.MenuItem
+}
+
+func newFocusPause(app *VideoCompareApp) *focusPause {
+	fp := &focusPause{app: app, paused: make(map[*VideoPlayer]string)}
+	lifecycle := fyne.CurrentApp().Lifecycle()
+	lifecycle.SetOnExitedForeground(fp.focusLost)
+	lifecycle.SetOnEnteredForeground(fp.focusGained)
+	return fp
+}
+
+// focusLost pauses both players, together so they stay in step, and
+// remembers which were playing.
+func (fp *focusPause) focusLost() {
+	if !fyne.CurrentApp().Preferences().Bool(prefPauseOnFocusLoss) {
+		return
+	}
+	clear(fp.paused)
+	playing := false
+	for _, vp := range []*VideoPlayer{fp.app.leftPlayer, fp.app.rightPlayer} {
+		if vp.playing() {
+			fp.paused[vp] = vp.path
+			playing = true
+		}
+	}
+	if playing {
+		fp.app.pauseAll()
+	}
+}
+
+// focusGained resumes the players focusLost paused, if the user wants
+// that and left them paused on the same file.
+func (fp *focusPause) focusGained() {
+	paused := fp.paused
+	fp.paused = make(map[*VideoPlayer]string)
+	if !fyne.CurrentApp().Preferences().Bool(prefResumeOnFocus) {
+		return
+	}
+	for vp, path := range paused {
+		if vp.path == path && vp.state == statePaused && vp.canPlay() {
+			vp.play()
+		}
+	}
+}
+
+// pauseMenuItem toggles pausing while the window is in the background.
+func (fp *focusPause) pauseMenuItem() *fyne.MenuItem {
+	prefs := fyne.CurrentApp().Preferences()
+	item := fyne.NewMenuItem(tr("Pause When Window Loses Focus"), nil)
+	item.Checked = prefs.Bool(prefPauseOnFocusLoss)
+	item.Action = func() {
+		item.Checked = !item.Checked
+		prefs.SetBool(prefPauseOnFocusLoss, item.Checked)
+		fp.resumeItem.Disabled = !item.Checked
+		fp.app.window.MainMenu().Refresh()
+	}
+	return item
+}
+
+// resumeMenuItem toggles resuming on return what losing focus paused. It
+// only applies while pausing on focus loss is on.
+func (fp *focusPause) resumeMenuItem() *fyne.MenuItem {
+	prefs := fyne.CurrentApp().Preferences()
+	fp.resumeItem = fyne.NewMenuItem(tr("Resume When Window Regains Focus"), nil)
+	fp.resumeItem.Checked = prefs.Bool(prefResumeOnFocus)
+	fp.resumeItem.Disabled = !prefs.Bool(prefPauseOnFocusLoss)
+	fp.resumeItem.Action = func() {
+		fp.resumeItem.Checked = !fp.resumeItem.Checked
+		prefs.SetBool(prefResumeOnFocus, fp.resumeItem.Checked)
+		fp.app.window.MainMenu().Refresh()
+	}
+	return fp.resumeItem
+}
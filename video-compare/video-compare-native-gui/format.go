@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"golang.org/x/image/draw"
+	"videocompare"
+)
+
+// videoFormat describes how a clip's pixels are stored.
+type videoFormat struct {
+	PixelFormat string
+	BitDepth    int
+	Chroma      string // chroma subsampling, e.g. 4:2:0
+	ColorRange  string // tv (limited) or pc (full)
+
+	// Sample (pixel) and display aspect ratios; anamorphic sources have
+	// non-square pixels
+	SAR          string
+	DAR          string
+	SampleAspect float64 // pixel width relative to its height
+}
+
+// probeFormat reads the pixel format and color range of path's first
+// video stream.
+func probeFormat(path string) (videoFormat, error) {
+	out, err := runFFprobe("-select_streams", "v:0",
+		"-show_entries", "stream=pix_fmt,bits_per_raw_sample,color_range,sample_aspect_ratio,display_aspect_ratio", path)
+	if err != nil {
+		return videoFormat{}, err
+	}
+	var probe struct {
+		Streams []struct {
+			PixFmt     string `json:"pix_fmt"`
+			BitsPerRaw string `json:"bits_per_raw_sample"`
+			ColorRange string `json:"color_range"`
+			SAR        string `json:"sample_aspect_ratio"`
+			DAR        string `json:"display_aspect_ratio"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return videoFormat{}, fmt.Errorf("parsing ffprobe output: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return videoFormat{}, fmt.Errorf("no video stream")
+	}
+
+	s := probe.Streams[0]
+	f := videoFormat{PixelFormat: s.PixFmt, ColorRange: s.ColorRange}
+	f.BitDepth, f.Chroma = videocompare.ParsePixelFormat(s.PixFmt)
+	if bits, err := strconv.Atoi(s.BitsPerRaw); err == nil && bits > 0 {
+		f.BitDepth = bits
+	}
+	if f.ColorRange == "" || f.ColorRange == "unknown" {
+		f.ColorRange = "unspecified"
+	}
+	f.SAR, f.DAR, f.SampleAspect = "1:1", s.DAR, 1
+	if sar, ok := parseRatio(s.SAR); ok {
+		f.SAR, f.SampleAspect = s.SAR, sar
+	}
+	if _, ok := parseRatio(f.DAR); !ok {
+		f.DAR = "unknown"
+	}
+	return f, nil
+}
+
+// parseRatio parses an ffprobe aspect ratio such as 64:45. ffprobe reports
+// 0:1 or N/A when the ratio is unknown.
+func parseRatio(s string) (float64, bool) {
+	num, den, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, false
+	}
+	n, err1 := strconv.ParseFloat(num, 64)
+	d, err2 := strconv.ParseFloat(den, 64)
+	if err1 != nil || err2 != nil || n <= 0 || d <= 0 {
+		return 0, false
+	}
+	return n / d, true
+}
+
+// sampleAspect is the width of vp's pixels relative to their height, 1
+// until probed.
+func (vp *VideoPlayer) sampleAspect() float64 {
+	if vp.format == nil || vp.format.SampleAspect <= 0 {
+		return 1
+	}
+	return vp.format.SampleAspect
+}
+
+// displaySize is vp's frame size with non-square pixels stretched to
+// their display aspect.
+func (vp *VideoPlayer) displaySize() (int, int) {
+	return int(math.Round(float64(vp.width) * vp.sampleAspect())), vp.height
+}
+
+// applyDisplayAspect stretches img horizontally by the sample aspect ratio
+// so anamorphic frames come out with their intended shape.
+func applyDisplayAspect(img *image.RGBA, sampleAspect float64) *image.RGBA {
+	if sampleAspect <= 0 || math.Abs(sampleAspect-1) < 1e-3 {
+		return img
+	}
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, int(math.Round(float64(b.Dx())*sampleAspect)), b.Dy()))
+	draw.BiLinear.Scale(out, out.Bounds(), img, b, draw.Src, nil)
+	return out
+}
+
+// analyzeFormat probes vp's pixel format in the background and warns when
+// both clips are loaded with different formats.
+func (app *VideoCompareApp) analyzeFormat(vp *VideoPlayer) {
+	vp.format = nil
+	if vp.path == "" || vp.still != nil || isNetworkSource(vp.path) {
+		return
+	}
+	path := vp.path
+	go func() {
+		f, err := probeFormat(path)
+		fyne.Do(func() {
+			if vp.path != path {
+				return
+			}
+			if err != nil {
+				log.Printf("probing format of %s: %v", path, err)
+				return
+			}
+			vp.format = &f
+			vp.updateVideoCanvas()
+			app.updateStats()
+			app.warnFormatMismatch()
+		})
+	}()
+}
+
+// warnFormatMismatch tells the user when bit depth, chroma subsampling,
+// color range or pixel aspect differ, since pixel differences then partly
+// reflect the format rather than the encode.
+func (app *VideoCompareApp) warnFormatMismatch() {
+	l, r := app.leftPlayer.format, app.rightPlayer.format
+	if l == nil || r == nil {
+		return
+	}
+	var diffs []string
+	if l.BitDepth != r.BitDepth {
+		diffs = append(diffs, fmt.Sprintf("bit depth: %d-bit vs %d-bit", l.BitDepth, r.BitDepth))
+	}
+	if l.Chroma != r.Chroma {
+		diffs = append(diffs, fmt.Sprintf("chroma subsampling: %s vs %s", l.Chroma, r.Chroma))
+	}
+	if l.ColorRange != r.ColorRange {
+		diffs = append(diffs, fmt.Sprintf("color range: %s vs %s (Adjust > Levels converts one side)", l.ColorRange, r.ColorRange))
+	}
+	if l.SAR != r.SAR {
+		diffs = append(diffs, fmt.Sprintf("sample aspect ratio: %s vs %s", l.SAR, r.SAR))
+	}
+	if len(diffs) == 0 {
+		return
+	}
+	dialog.ShowInformation("Pixel Formats Differ",
+		"The clips are stored differently:\n\n"+strings.Join(diffs, "\n")+
+			"\n\nSome visible differences may come from the format rather than the encode.",
+		app.window)
+}
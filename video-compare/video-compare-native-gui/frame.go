@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"os"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+	libvlc "github.com/adrg/libvlc-go/v3"
+)
+
+// minVideoSize is the smallest the video area shrinks to, so the controls
+// around it get the space on small windows.
+var minVideoSize = fyne.NewSize(320, 180)
+
+// videoFitLayout centres the video canvas in the area at the largest size
+// with the video's display aspect ratio. Fyne lays it out again whenever
+// the window or split is resized, so the canvas tracks the window; playback
+// position and zoom are player state and are left alone.
+type videoFitLayout struct {
+	player *VideoPlayer
+}
+
+func (l *videoFitLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	fitted := size
+	if w, h := l.player.displaySize(); w > 0 && h > 0 {
+		scale := math.Min(float64(size.Width)/float64(w), float64(size.Height)/float64(h))
+		fitted = fyne.NewSize(float32(float64(w)*scale), float32(float64(h)*scale))
+	}
+	pos := fyne.NewPos((size.Width-fitted.Width)/2, (size.Height-fitted.Height)/2)
+	for _, o := range objects {
+		o.Resize(fitted)
+		o.Move(pos)
+	}
+}
+
+func (l *videoFitLayout) MinSize([]fyne.CanvasObject) fyne.Size {
+	return minVideoSize
+}
+
+// videoArea wraps a player's display canvas so pointer movement over the
+// video can be mapped back to source pixels.
+type videoArea struct {
+	widget.BaseWidget
+
+	player  *VideoPlayer
+	content fyne.CanvasObject
+
+	onHover   func(vp *VideoPlayer, pos fyne.Position)
+	onLeave   func(vp *VideoPlayer)
+	onDrag    func(vp *VideoPlayer, ev *fyne.DragEvent)
+	onDragEnd func(vp *VideoPlayer)
+	onTap     func(vp *VideoPlayer, ev *fyne.PointEvent)
+	onScroll  func(vp *VideoPlayer, ev *fyne.ScrollEvent)
+}
+
+func newVideoArea(player *VideoPlayer, content fyne.CanvasObject) *videoArea {
+	va := &videoArea{player: player, content: content}
+	va.ExtendBaseWidget(va)
+	return va
+}
+
+func (va *videoArea) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(va.content)
+}
+
+func (va *videoArea) MouseIn(ev *desktop.MouseEvent) {
+	va.MouseMoved(ev)
+}
+
+func (va *videoArea) MouseMoved(ev *desktop.MouseEvent) {
+	if va.onHover != nil {
+		va.onHover(va.player, ev.Position)
+	}
+}
+
+func (va *videoArea) MouseOut() {
+	if va.onLeave != nil {
+		va.onLeave(va.player)
+	}
+}
+
+func (va *videoArea) Dragged(ev *fyne.DragEvent) {
+	if va.onDrag != nil {
+		va.onDrag(va.player, ev)
+	}
+}
+
+func (va *videoArea) DragEnd() {
+	if va.onDragEnd != nil {
+		va.onDragEnd(va.player)
+	}
+}
+
+func (va *videoArea) Tapped(ev *fyne.PointEvent) {
+	if va.onTap != nil {
+		va.onTap(va.player, ev)
+	}
+}
+
+func (va *videoArea) Scrolled(ev *fyne.ScrollEvent) {
+	if va.onScroll != nil {
+		va.onScroll(va.player, ev)
+	}
+}
+
+// clampedPosition is like normalizedPosition but pins positions outside the
+// area to its nearest edge.
+func (va *videoArea) clampedPosition(pos fyne.Position) [2]float64 {
+	size := va.Size()
+	if size.Width <= 0 || size.Height <= 0 {
+		return [2]float64{}
+	}
+	nx := math.Min(1, math.Max(0, float64(pos.X/size.Width)))
+	ny := math.Min(1, math.Max(0, float64(pos.Y/size.Height)))
+	return [2]float64{nx, ny}
+}
+
+// normalizedPosition converts a position inside the video area into
+// resolution-independent coordinates in the range [0, 1].
+func (va *videoArea) normalizedPosition(pos fyne.Position) (float64, float64, bool) {
+	size := va.Size()
+	if size.Width <= 0 || size.Height <= 0 {
+		return 0, 0, false
+	}
+	nx := float64(pos.X / size.Width)
+	ny := float64(pos.Y / size.Height)
+	if nx < 0 || nx > 1 || ny < 0 || ny > 1 {
+		return 0, 0, false
+	}
+	return nx, ny, true
+}
+
+// sourcePixel maps normalized frame coordinates onto a pixel of the
+// player's source frame.
+func (vp *VideoPlayer) sourcePixel(nx, ny float64) (int, int, bool) {
+	if vp.width <= 0 || vp.height <= 0 {
+		return 0, 0, false
+	}
+	x := clampInt(int(nx*float64(vp.width)), 0, vp.width-1)
+	y := clampInt(int(ny*float64(vp.height)), 0, vp.height-1)
+	return x, y, true
+}
+
+// snapshotFrame asks libvlc to write the frame player is displaying to a
+// temporary PNG and decodes it; title names the player in errors. It only
+// talks to libvlc, so it is safe to call from background goroutines with
+// a player taken on the UI goroutine.
+func snapshotFrame(player *libvlc.Player, title string) (image.Image, error) {
+	if player == nil {
+		return nil, fmt.Errorf("%s: no video loaded", title)
+	}
+
+	tmp, err := os.CreateTemp("", "video-compare-*.png")
+	if err != nil {
+		return nil, err
+	}
+	path := tmp.Name()
+	tmp.Close()
+	defer os.Remove(path)
+
+	// Zero width and height keep the source resolution
+	if err := player.TakeSnapshot(path, 0, 0); err != nil {
+		return nil, fmt.Errorf("%s: snapshot failed: %w", title, err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: decoding snapshot: %w", title, err)
+	}
+	return img, nil
+}
+
+// cropRegion returns the size×size region of img centred on (cx, cy),
+// shifted as needed so it stays inside the image bounds.
+func cropRegion(img image.Image, cx, cy, size int) image.Image {
+	b := img.Bounds()
+	x0 := clampInt(b.Min.X+cx-size/2, b.Min.X, max(b.Min.X, b.Max.X-size))
+	y0 := clampInt(b.Min.Y+cy-size/2, b.Min.Y, max(b.Min.Y, b.Max.Y-size))
+	rect := image.Rect(x0, y0, x0+size, y0+size).Intersect(b)
+
+	out := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	for y := 0; y < rect.Dy(); y++ {
+		for x := 0; x < rect.Dx(); x++ {
+			out.Set(x, y, img.At(rect.Min.X+x, rect.Min.Y+y))
+		}
+	}
+	return out
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+)
+
+// probeFrameCount reads nb_frames from the stream header, falling back to
+// counting packets for containers such as Matroska that don't store it. It
+// also returns the stream's duration in seconds, 0 when not recorded.
+func probeFrameCount(path string) (int, float64, error) {
+	type stream struct {
+		NbFrames  string `json:"nb_frames"`
+		NbPackets string `json:"nb_read_packets"`
+		Duration  string `json:"duration"`
+	}
+	probe := func(args ...string) (stream, error) {
+		out, err := runFFprobe(append([]string{"-select_streams", "v:0"}, append(args, path)...)...)
+		if err != nil {
+			return stream{}, err
+		}
+		var p struct {
+			Streams []stream `json:"streams"`
+		}
+		if err := json.Unmarshal(out, &p); err != nil {
+			return stream{}, fmt.Errorf("parsing ffprobe output: %w", err)
+		}
+		if len(p.Streams) == 0 {
+			return stream{}, fmt.Errorf("no video stream")
+		}
+		return p.Streams[0], nil
+	}
+
+	s, err := probe("-show_entries", "stream=nb_frames,duration")
+	if err != nil {
+		return 0, 0, err
+	}
+	duration, _ := strconv.ParseFloat(s.Duration, 64)
+	if n, err := strconv.Atoi(s.NbFrames); err == nil && n > 0 {
+		return n, duration, nil
+	}
+	s, err = probe("-count_packets", "-show_entries", "stream=nb_read_packets")
+	if err != nil {
+		return 0, 0, err
+	}
+	n, err := strconv.Atoi(s.NbPackets)
+	if err != nil {
+		return 0, 0, fmt.Errorf("no frame count for %s", path)
+	}
+	return n, duration, nil
+}
+
+// analyzeFrameCount probes vp's frame count in the background for the
+// metadata table and the measured frame rate.
+func (app *VideoCompareApp) analyzeFrameCount(vp *VideoPlayer) {
+	vp.frameCount, vp.streamDuration = 0, 0
+	if vp.path == "" || vp.still != nil || isNetworkSource(vp.path) {
+		return
+	}
+	path := vp.path
+	go func() {
+		c, d, err := probeFrameCount(path)
+		fyne.Do(func() {
+			if vp.path != path {
+				return
+			}
+			if err != nil {
+				log.Printf("counting frames of %s: %v", path, err)
+				return
+			}
+			vp.frameCount, vp.streamDuration = c, d
+			app.measuredFPSChanged(vp)
+		})
+	}()
+}
+
+// frameCountRow compares the frame counts, giving the right clip's count
+// with its exact difference from the left one when they disagree.
+func (app *VideoCompareApp) frameCountRow() metadataRow {
+	l, r := app.leftPlayer, app.rightPlayer
+	value := func(vp *VideoPlayer) string {
+		if vp.frameCount == 0 {
+			return "unknown"
+		}
+		return strconv.Itoa(vp.frameCount)
+	}
+	row := metadataRow{Name: "Frame Count", Left: loadedValue(l, value), Right: loadedValue(r, value)}
+	if l.frameCount > 0 && r.frameCount > 0 && l.path != "" && r.path != "" {
+		if delta := r.frameCount - l.frameCount; delta != 0 {
+			row.Right += fmt.Sprintf(" (%+d frames)", delta)
+		}
+	}
+	return row
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+const prefFrameInfo = "timecode.advanced"
+
+// probedFrame is what ffprobe reports about one decoded video frame. The
+// timestamps are kept as ffprobe prints them, as they may be "N/A".
+type probedFrame struct {
+	PTS      string `json:"pts"`
+	PTSTime  string `json:"pts_time"`
+	DTS      string `json:"pkt_dts"`
+	DTSTime  string `json:"pkt_dts_time"`
+	KeyFrame int    `json:"key_frame"`
+	PictType string `json:"pict_type"`
+}
+
+// probeFrameAt decodes path's video frames from the keyframe before
+// seconds up to a couple of frames past it and returns the one shown at
+// seconds: the last one presented at or before it.
+func probeFrameAt(path string, seconds, fps float64) (probedFrame, error) {
+	interval := fmt.Sprintf("%.6f%%%.6f", seconds, seconds+2/fps)
+	out, err := runFFprobe("-select_streams", "v:0", "-read_intervals", interval,
+		"-show_entries", "frame=pts,pts_time,pkt_dts,pkt_dts_time,key_frame,pict_type", path)
+	if err != nil {
+		return probedFrame{}, err
+	}
+	var probe struct {
+		Frames []probedFrame `json:"frames"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return probedFrame{}, fmt.Errorf("parsing ffprobe output: %w", err)
+	}
+	if len(probe.Frames) == 0 {
+		return probedFrame{}, fmt.Errorf("no frame at %s", formatTime(seconds))
+	}
+	shown := probe.Frames[0]
+	for _, f := range probe.Frames {
+		if t, err := strconv.ParseFloat(f.PTSTime, 64); err == nil && t <= seconds+2*frameTimeBias {
+			shown = f
+		}
+	}
+	return shown, nil
+}
+
+func (f probedFrame) String() string {
+	timestamp := func(name, ts, seconds string) string {
+		if ts == "" || ts == "N/A" {
+			return name + " N/A"
+		}
+		return fmt.Sprintf("%s %s (%s s)", name, ts, seconds)
+	}
+	kind := "non-key frame"
+	if f.KeyFrame == 1 {
+		kind = "key frame"
+	}
+	return fmt.Sprintf("%s · %s · %s · type %s", timestamp("PTS", f.PTS, f.PTSTime), timestamp("DTS", f.DTS, f.DTSTime), kind, f.PictType)
+}
+
+// frameInfoReadout shows the PTS, DTS, keyframe flag and picture type of
+// each player's current frame, for diagnosing why two muxes of the same
+// content seek differently. It is an advanced option, off by default, and
+// only reads the frame while paused, as every read runs ffprobe.
+type frameInfoReadout struct {
+	app     *VideoCompareApp
+	enabled bool
+	labels  map[*VideoPlayer]*widget.Label
+	pending map[*VideoPlayer]int // bumped on every read so stale results are dropped
+}
+
+func newFrameInfoReadout(app *VideoCompareApp) *frameInfoReadout {
+	return &frameInfoReadout{
+		app:     app,
+		enabled: fyne.CurrentApp().Preferences().Bool(prefFrameInfo),
+		labels:  make(map[*VideoPlayer]*widget.Label),
+		pending: make(map[*VideoPlayer]int),
+	}
+}
+
+// label creates vp's readout, hidden while the option is off.
+func (fr *frameInfoReadout) label(vp *VideoPlayer) *widget.Label {
+	label := widget.NewLabel("")
+	label.TextStyle = fyne.TextStyle{Monospace: true}
+	label.Wrapping = fyne.TextWrapWord
+	if !fr.enabled {
+		label.Hide()
+	}
+	fr.labels[vp] = label
+	return label
+}
+
+// menuItem toggles the readout and remembers the choice.
+func (fr *frameInfoReadout) menuItem() *fyne.MenuItem {
+	item := fyne.NewMenuItem(tr("Show Frame Timestamps (PTS/DTS)"), nil)
+	item.Checked = fr.enabled
+	item.Action = func() {
+		item.Checked = !item.Checked
+		fyne.CurrentApp().Preferences().SetBool(prefFrameInfo, item.Checked)
+		fr.enabled = item.Checked
+		for vp, label := range fr.labels {
+			if fr.enabled {
+				label.Show()
+				fr.refresh(vp)
+			} else {
+				label.Hide()
+			}
+		}
+		fr.app.window.MainMenu().Refresh()
+	}
+	return item
+}
+
+// refresh reads the frame vp shows in the background.
+func (fr *frameInfoReadout) refresh(vp *VideoPlayer) {
+	label := fr.labels[vp]
+	if !fr.enabled || label == nil {
+		return
+	}
+	fr.pending[vp]++
+	switch {
+	case vp.path == "" || vp.still != nil || isNetworkSource(vp.path) || !vp.hasVideo:
+		label.SetText("")
+		return
+	case fr.app.unavailable(needsFFprobe) != "":
+		label.SetText("Frame timestamps " + fr.app.unavailable(needsFFprobe))
+		return
+	case vp.playing():
+		label.SetText("Frame timestamps are read while paused")
+		return
+	}
+
+	seq, path, seconds := fr.pending[vp], vp.path, vp.currentTime
+	fps := vp.fps
+	if fps <= 0 {
+		fps = estimatedFPS
+	}
+	go func() {
+		frame, err := probeFrameAt(path, seconds, fps)
+		fyne.Do(func() {
+			if fr.pending[vp] != seq || vp.path != path {
+				return
+			}
+			if err != nil {
+				log.Printf("reading the frame of %s at %s: %v", path, formatTime(seconds), err)
+				label.SetText(fmt.Sprintf("Frame timestamps unavailable: %v", err))
+				return
+			}
+			label.SetText(frame.String())
+		})
+	}()
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"videocompare"
+)
+
+// frameTimeBias is added to a frame's timestamp when seeking to it. libvlc
+// seeks in whole milliseconds and would otherwise round down onto the end
+// of the previous frame.
+const frameTimeBias = 0.001
+
+// probeFrameTimes lists the presentation timestamps of path's video frames
+// in display order, read from the packet headers without decoding. The
+// probe output is cached, as reading every packet of a long file is slow.
+// ffprobe reports the file's own timestamps, which libvlc positions start
+// from the container start time; the times returned are relative to it.
+func probeFrameTimes(path string) ([]float64, error) {
+	out, ok := cacheGet("pts", path)
+	if !ok {
+		var err error
+		out, err = runFFprobe("-select_streams", "v:0", "-show_entries", "packet=pts_time", path)
+		if err != nil {
+			return nil, err
+		}
+		cachePut("pts", path, out)
+	}
+	var probe struct {
+		Packets []struct {
+			PTS string `json:"pts_time"`
+		} `json:"packets"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, fmt.Errorf("parsing ffprobe output: %w", err)
+	}
+	start, err := videocompare.ProbeStartTime(context.Background(), path)
+	if err != nil {
+		return nil, err
+	}
+	times := make([]float64, 0, len(probe.Packets))
+	for _, p := range probe.Packets {
+		if t, err := strconv.ParseFloat(p.PTS, 64); err == nil {
+			times = append(times, t-start)
+		}
+	}
+	if len(times) == 0 {
+		return nil, fmt.Errorf("no frame timestamps for %s", path)
+	}
+	// Packets come in decode order, which differs with B-frames
+	sort.Float64s(times)
+	return times, nil
+}
+
+// analyzeFrameTimes reads vp's frame timestamps in the background so frame
+// stepping lands on real frames, which matters for variable frame rate
+// files. They are kept for as long as the file stays loaded.
+func (app *VideoCompareApp) analyzeFrameTimes(vp *VideoPlayer) {
+	vp.frameTimes = nil
+	if vp.path == "" || vp.still != nil || isNetworkSource(vp.path) {
+		return
+	}
+	path := vp.path
+	go func() {
+		times, err := probeFrameTimes(path)
+		fyne.Do(func() {
+			if vp.path != path {
+				return
+			}
+			if err != nil {
+				log.Printf("reading frame timestamps of %s: %v", path, err)
+				return
+			}
+			vp.frameTimes = times
+			app.updateFrameControls()
+		})
+	}()
+}
+
+// frameTimeAt returns the timestamp of the frame delta frames away from
+// the one vp shows, and false when the timestamps aren't known or the step
+// leaves the clip.
+func (vp *VideoPlayer) frameTimeAt(delta int) (float64, bool) {
+	times := vp.frameTimes
+	if len(times) == 0 {
+		return 0, false
+	}
+	// The frame shown is the last one starting at or before the position
+	current := sort.SearchFloat64s(times, vp.currentTime+2*frameTimeBias) - 1
+	target := max(0, current) + delta
+	if current < 0 && delta > 0 {
+		target = delta - 1 // before the first frame, the next one is the first
+	}
+	if target < 0 || target >= len(times) {
+		return 0, false
+	}
+	return times[target], true
+}
+
+// stepFrame moves vp delta frames forward or back: to the exact timestamp
+// of that frame when known, otherwise by the nominal frame duration. While
+// inverse telecine is active the recovered film rate is used instead, as
+// the timestamps are those of the telecined frames.
+func (vp *VideoPlayer) stepFrame(delta int) {
+	if !vp.ivtc {
+		if t, ok := vp.frameTimeAt(delta); ok {
+			vp.seekTo(t + frameTimeBias)
+			return
+		}
+	}
+	fps := vp.stepFPS()
+	if fps <= 0 {
+		return
+	}
+	newTime := vp.currentTime + float64(delta)/fps
+	if newTime >= 0 {
+		vp.seekTo(newTime)
+	}
+}
@@ -5,6 +5,9 @@ go 1.23.0
 require (
 	fyne.io/fyne/v2 v2.6.1
 	github.com/adrg/libvlc-go/v3 v3.1.6
+	golang.org/x/image v0.24.0
+	golang.org/x/text v0.22.0
+	videocompare v0.0.0
 )
 
 require (
@@ -35,9 +38,9 @@ require (
 	github.com/srwiley/rasterx v0.0.0-20220730225603-2ab79fcdd4ef // indirect
 	github.com/stretchr/testify v1.10.0 // indirect
 	github.com/yuin/goldmark v1.7.8 // indirect
-	golang.org/x/image v0.24.0 // indirect
 	golang.org/x/net v0.35.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
-	golang.org/x/text v0.22.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace videocompare => ../videocompare
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"golang.org/x/image/draw"
+)
+
+// grabbedFrame is a frame grabbed from a player with the position it shows.
+type grabbedFrame struct {
+	path    string
+	seconds float64
+	img     image.Image
+}
+
+// grabFrameThen grabs the frame vp is showing, be it video or still, and
+// passes it to done on the UI goroutine. A frame still cached from the
+// last grab is passed right away, so tools reading pixels on every
+// pointer move don't snapshot each time; otherwise the grab runs in the
+// background, as decoding an adjusted player's frame runs ffmpeg. It must
+// be called on the UI goroutine.
+func (vp *VideoPlayer) grabFrameThen(done func(image.Image, error)) {
+	if vp.still != nil {
+		done(vp.still, nil)
+		return
+	}
+	if img := vp.cachedFrame(vp.path, vp.currentTime); img != nil {
+		done(img, nil)
+		return
+	}
+	grab := vp.frameGrabber()
+	go func() {
+		img, err := grab()
+		fyne.Do(func() { done(img, err) })
+	}()
+}
+
+// frameGrabber returns a function grabbing vp's current frame that may be
+// called from any goroutine, and should be whenever the grab may decode
+// with ffmpeg. It must itself be called on the UI goroutine. Grabs never
+// include the preview adjustments.
+func (vp *VideoPlayer) frameGrabber() func() (image.Image, error) {
+	if still := vp.still; still != nil {
+		return func() (image.Image, error) { return still, nil }
+	}
+	if err := vp.grabError(); err != nil {
+		return func() (image.Image, error) { return nil, err }
+	}
+
+	path, seconds := vp.path, vp.currentTime
+	player, title := vp.player, vp.title
+	grab := func() (image.Image, error) { return snapshotFrame(player, title) }
+	if vp.adjusted() && !isNetworkSource(path) {
+		grab = func() (image.Image, error) { return decodeFrame(path, seconds) }
+	}
+	return vp.cachedGrab(path, seconds, grab)
+}
+
+// cachedGrab wraps grab, which grabs the frame of path at seconds, so it
+// reuses vp's last grab when that was of the same frame and remembers what
+// it grabs otherwise. The cache lasts until the position changes or
+// dropGrabbedFrame clears it.
+func (vp *VideoPlayer) cachedGrab(path string, seconds float64, grab func() (image.Image, error)) func() (image.Image, error) {
+	return func() (image.Image, error) {
+		if img := vp.cachedFrame(path, seconds); img != nil {
+			return img, nil
+		}
+		img, err := grab()
+		if err != nil {
+			return nil, err
+		}
+		vp.grabMu.Lock()
+		vp.lastGrab = grabbedFrame{path: path, seconds: seconds, img: img}
+		vp.grabMu.Unlock()
+		return img, nil
+	}
+}
+
+// cachedFrame returns the last grab when it was of path at seconds, or nil.
+func (vp *VideoPlayer) cachedFrame(path string, seconds float64) image.Image {
+	vp.grabMu.Lock()
+	defer vp.grabMu.Unlock()
+	if last := vp.lastGrab; last.img != nil && last.path == path && last.seconds == seconds {
+		return last.img
+	}
+	return nil
+}
+
+// grabError explains why vp has no frame to grab, or returns nil.
+func (vp *VideoPlayer) grabError() error {
+	switch {
+	case vp.path == "" || vp.media == nil:
+		return fmt.Errorf("%s: no video loaded", vp.title)
+	case !vp.hasVideo && vp.hasAudio && !isNetworkSource(vp.path):
+		return fmt.Errorf("%s: %s has no video track", vp.title, displayName(vp.path))
+	case vp.decodeErrorHere() != nil:
+		e := vp.decodeErrorHere()
+		return fmt.Errorf("%s: decode error at %s: %s", vp.title, formatMacroTime(e.seconds), e.message)
+	}
+	return nil
+}
+
+// dropGrabbedFrame forgets the cached frame, for changes that alter the
+// picture without moving the position, such as deinterlacing.
+func (vp *VideoPlayer) dropGrabbedFrame() {
+	vp.grabMu.Lock()
+	vp.lastGrab = grabbedFrame{}
+	vp.grabMu.Unlock()
+}
+
+// grabPair grabs both players' current frames in the background once
+// libvlc has shown them and passes them to done on the UI goroutine. The
+// right frame is scaled to the left one's size so the two line up pixel
+// for pixel. It must be called on the UI goroutine.
+func (app *VideoCompareApp) grabPair(done func(left, right image.Image, err error)) {
+	grabLeft, grabRight := app.leftPlayer.frameGrabber(), app.rightPlayer.frameGrabber()
+	go func() {
+		time.Sleep(frameSettleDelay)
+		left, err := grabLeft()
+		var right image.Image
+		if err == nil {
+			right, err = grabRight()
+		}
+		if err == nil && right.Bounds().Size() != left.Bounds().Size() {
+			scaled := image.NewRGBA(image.Rect(0, 0, left.Bounds().Dx(), left.Bounds().Dy()))
+			draw.BiLinear.Scale(scaled, scaled.Bounds(), right, right.Bounds(), draw.Src, nil)
+			right = scaled
+		}
+		fyne.Do(func() { done(left, right, err) })
+	}()
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"image"
+	"strings"
+	"sync"
+	"testing"
+
+	libvlc "github.com/adrg/libvlc-go/v3"
+)
+
+// countingGrab returns a grab function counting its calls, each returning
+// a new image.
+func countingGrab(calls *int) func() (image.Image, error) {
+	return func() (image.Image, error) {
+		*calls++
+		return image.NewRGBA(image.Rect(0, 0, 2, 2)), nil
+	}
+}
+
+func TestCachedGrab(t *testing.T) {
+	vp := &VideoPlayer{}
+	calls := 0
+	grab := countingGrab(&calls)
+
+	first, err := vp.cachedGrab("a.mp4", 1.5, grab)()
+	if err != nil || calls != 1 {
+		t.Fatalf("first grab: %v, %d calls, want nil, 1", err, calls)
+	}
+	// The same frame again hits the cache, through a new grabber too
+	again, _ := vp.cachedGrab("a.mp4", 1.5, grab)()
+	if calls != 1 || again != first {
+		t.Errorf("same path and seconds: %d calls, same image %v, want 1 call and the cached image", calls, again == first)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		seconds float64
+	}{
+		{"seek", "a.mp4", 1.54},
+		{"other file", "b.mp4", 1.54},
+	}
+	for _, tt := range tests {
+		before := calls
+		if _, err := vp.cachedGrab(tt.path, tt.seconds, grab)(); err != nil || calls != before+1 {
+			t.Errorf("%s: %v, %d calls, want a fresh grab", tt.name, err, calls-before)
+		}
+	}
+
+	vp.dropGrabbedFrame()
+	before := calls
+	vp.cachedGrab("b.mp4", 1.54, grab)()
+	if calls != before+1 {
+		t.Errorf("after dropGrabbedFrame: %d grabs, want 1", calls-before)
+	}
+	if img := vp.cachedFrame("b.mp4", 1.54); img == nil {
+		t.Error("cachedFrame after a grab = nil")
+	}
+}
+
+func TestCachedGrabError(t *testing.T) {
+	vp := &VideoPlayer{}
+	failed := errors.New("snapshot failed")
+	if _, err := vp.cachedGrab("a.mp4", 0, func() (image.Image, error) { return nil, failed })(); err != failed {
+		t.Fatalf("grab error = %v, want %v", err, failed)
+	}
+	// A failed grab isn't cached
+	calls := 0
+	vp.cachedGrab("a.mp4", 0, countingGrab(&calls))()
+	if calls != 1 {
+		t.Errorf("grab after a failure: %d calls, want 1", calls)
+	}
+}
+
+func TestGrabError(t *testing.T) {
+	media := &libvlc.Media{}
+	tests := []struct {
+		name string
+		vp   *VideoPlayer
+		want string // substring of the error, "" for none
+	}{
+		{"nothing loaded", &VideoPlayer{title: "Left"}, "no video loaded"},
+		{"not opened", &VideoPlayer{title: "Left", path: "a.mp4"}, "no video loaded"},
+		{"audio only", &VideoPlayer{title: "Left", path: "/x/a.wav", media: media, hasAudio: true}, "a.wav has no video track"},
+		{"audio only stream", &VideoPlayer{title: "Left", path: "https://example.com/live.m3u8", media: media, hasAudio: true}, ""},
+		{"decode error here", &VideoPlayer{title: "Left", path: "a.mp4", media: media, hasVideo: true, currentTime: 2,
+			decodeFault: &decodeError{path: "a.mp4", seconds: 2, message: "h264: corrupt slice"}}, "h264: corrupt slice"},
+		{"decode error elsewhere", &VideoPlayer{title: "Left", path: "a.mp4", media: media, hasVideo: true, currentTime: 3,
+			decodeFault: &decodeError{path: "a.mp4", seconds: 2, message: "h264: corrupt slice"}}, ""},
+		{"video", &VideoPlayer{title: "Left", path: "a.mp4", media: media, hasVideo: true}, ""},
+	}
+	for _, tt := range tests {
+		err := tt.vp.grabError()
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s: grabError = %v, want nil", tt.name, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%s: grabError = %v, want one mentioning %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+// TestGrabberRace runs grabbers in the background while the UI goroutine,
+// here the test's, moves and reloads the player, as seeking during a
+// heatmap or loupe grab does. Run with -race: a grabber must only read
+// what frameGrabber captured, and the cache only under grabMu.
+func TestGrabberRace(t *testing.T) {
+	vp := &VideoPlayer{title: "Left", path: "a.mp4", media: &libvlc.Media{}, hasVideo: true,
+		adjust: defaultAdjust, levels: levelsAsEncoded}
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		grab := vp.frameGrabber()
+		cached := vp.cachedGrab(vp.path, vp.currentTime, func() (image.Image, error) {
+			return image.NewRGBA(image.Rect(0, 0, 2, 2)), nil
+		})
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			// No libvlc player is loaded, so the snapshot fails
+			if _, err := grab(); err == nil {
+				t.Error("grab without a player succeeded")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			cached()
+		}()
+
+		vp.currentTime = float64(i) / 25
+		vp.path = []string{"a.mp4", "b.mp4"}[i%2]
+		vp.dropGrabbedFrame()
+		vp.cachedFrame(vp.path, vp.currentTime)
+	}
+	wg.Wait()
+}
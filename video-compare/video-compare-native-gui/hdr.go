@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// masteringDisplay is the SMPTE ST 2086 description of the display a clip
+// was graded on: CIE 1931 xy chromaticities and luminance in cd/m².
+type masteringDisplay struct {
+	Red, Green, Blue, WhitePoint [2]float64
+	MinLuminance, MaxLuminance   float64
+}
+
+// hdrMetadata is the HDR signalling of a clip's first video stream.
+type hdrMetadata struct {
+	Transfer  string
+	Mastering *masteringDisplay
+	MaxCLL    int // maximum content light level, cd/m²
+	MaxFALL   int // maximum frame-average light level, cd/m²
+	HasCLL    bool
+}
+
+// hasMetadata reports whether the clip carries static HDR metadata.
+func (h *hdrMetadata) hasMetadata() bool {
+	return h.Mastering != nil || h.HasCLL
+}
+
+var transferNames = map[string]string{
+	"smpte2084":    "PQ (smpte2084)",
+	"arib-std-b67": "HLG (arib-std-b67)",
+}
+
+// knownPrimaries are the gamuts mastering displays usually report.
+var knownPrimaries = []struct {
+	name             string
+	red, green, blue [2]float64
+}{
+	{"BT.2020", [2]float64{0.708, 0.292}, [2]float64{0.170, 0.797}, [2]float64{0.131, 0.046}},
+	{"Display P3", [2]float64{0.680, 0.320}, [2]float64{0.265, 0.690}, [2]float64{0.150, 0.060}},
+	{"BT.709", [2]float64{0.640, 0.330}, [2]float64{0.300, 0.600}, [2]float64{0.150, 0.060}},
+}
+
+func nearXY(a, b [2]float64) bool {
+	return math.Abs(a[0]-b[0]) < 0.005 && math.Abs(a[1]-b[1]) < 0.005
+}
+
+// primariesLabel names the mastering display gamut, falling back to the
+// raw chromaticities.
+func (m *masteringDisplay) primariesLabel() string {
+	for _, p := range knownPrimaries {
+		if nearXY(m.Red, p.red) && nearXY(m.Green, p.green) && nearXY(m.Blue, p.blue) {
+			return fmt.Sprintf("%s, white %.4f,%.4f", p.name, m.WhitePoint[0], m.WhitePoint[1])
+		}
+	}
+	return fmt.Sprintf("R %.4f,%.4f G %.4f,%.4f B %.4f,%.4f, white %.4f,%.4f",
+		m.Red[0], m.Red[1], m.Green[0], m.Green[1], m.Blue[0], m.Blue[1], m.WhitePoint[0], m.WhitePoint[1])
+}
+
+func (m *masteringDisplay) luminanceLabel() string {
+	return fmt.Sprintf("%.4g–%.4g cd/m²", m.MinLuminance, m.MaxLuminance)
+}
+
+// sideDataValue converts an ffprobe side data value, which is either a
+// number or a rational such as "35400/50000".
+func sideDataValue(v any) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case string:
+		if num, den, ok := strings.Cut(v, "/"); ok {
+			n, err1 := strconv.ParseFloat(num, 64)
+			d, err2 := strconv.ParseFloat(den, 64)
+			if err1 != nil || err2 != nil || d == 0 {
+				return 0
+			}
+			return n / d
+		}
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	}
+	return 0
+}
+
+// applySideData fills h from one ffprobe side data entry.
+func (h *hdrMetadata) applySideData(sd map[string]any) {
+	xy := func(prefix string) [2]float64 {
+		return [2]float64{sideDataValue(sd[prefix+"_x"]), sideDataValue(sd[prefix+"_y"])}
+	}
+	switch sd["side_data_type"] {
+	case "Mastering display metadata":
+		if _, ok := sd["red_x"]; !ok {
+			return
+		}
+		h.Mastering = &masteringDisplay{
+			Red:          xy("red"),
+			Green:        xy("green"),
+			Blue:         xy("blue"),
+			WhitePoint:   xy("white_point"),
+			MinLuminance: sideDataValue(sd["min_luminance"]),
+			MaxLuminance: sideDataValue(sd["max_luminance"]),
+		}
+	case "Content light level metadata":
+		h.MaxCLL = int(sideDataValue(sd["max_content"]))
+		h.MaxFALL = int(sideDataValue(sd["max_average"]))
+		h.HasCLL = true
+	}
+}
+
+// probeHDR reads the transfer characteristics and static HDR metadata of
+// path's first video stream. The metadata is taken from the stream's side
+// data where ffprobe reports it there, and from the first frame otherwise.
+func probeHDR(path string) (hdrMetadata, error) {
+	out, err := runFFprobe("-select_streams", "v:0", "-read_intervals", "%+#1",
+		"-show_entries", "stream=color_transfer,side_data_list:frame=side_data_list", path)
+	if err != nil {
+		return hdrMetadata{}, err
+	}
+	var probe struct {
+		Streams []struct {
+			ColorTransfer string           `json:"color_transfer"`
+			SideData      []map[string]any `json:"side_data_list"`
+		} `json:"streams"`
+		Frames []struct {
+			SideData []map[string]any `json:"side_data_list"`
+		} `json:"frames"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return hdrMetadata{}, fmt.Errorf("parsing ffprobe output: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return hdrMetadata{}, fmt.Errorf("no video stream")
+	}
+
+	s := probe.Streams[0]
+	h := hdrMetadata{Transfer: s.ColorTransfer}
+	if h.Transfer == "" || h.Transfer == "unknown" {
+		h.Transfer = "unspecified"
+	}
+	for _, f := range probe.Frames {
+		for _, sd := range f.SideData {
+			h.applySideData(sd)
+		}
+	}
+	for _, sd := range s.SideData {
+		h.applySideData(sd)
+	}
+	return h, nil
+}
+
+// hdrValue reads a property of the probed HDR metadata, "unknown" until
+// the probe has finished and "none" when the clip doesn't carry it.
+func hdrValue(value func(h *hdrMetadata) string) func(vp *VideoPlayer) string {
+	return func(vp *VideoPlayer) string {
+		if vp.hdr == nil {
+			return "unknown"
+		}
+		if v := value(vp.hdr); v != "" {
+			return v
+		}
+		return "none"
+	}
+}
+
+// hdrRows are the metadata table rows comparing the clips' HDR signalling.
+func hdrRows(row func(name string, value func(vp *VideoPlayer) string) metadataRow) []metadataRow {
+	return []metadataRow{
+		row("Transfer", hdrValue(func(h *hdrMetadata) string {
+			if name, ok := transferNames[h.Transfer]; ok {
+				return name
+			}
+			return h.Transfer
+		})),
+		row("Mastering Primaries", hdrValue(func(h *hdrMetadata) string {
+			if h.Mastering == nil {
+				return ""
+			}
+			return h.Mastering.primariesLabel()
+		})),
+		row("Mastering Luminance", hdrValue(func(h *hdrMetadata) string {
+			if h.Mastering == nil {
+				return ""
+			}
+			return h.Mastering.luminanceLabel()
+		})),
+		row("MaxCLL", hdrValue(func(h *hdrMetadata) string {
+			if !h.HasCLL {
+				return ""
+			}
+			return fmt.Sprintf("%d cd/m²", h.MaxCLL)
+		})),
+		row("MaxFALL", hdrValue(func(h *hdrMetadata) string {
+			if !h.HasCLL {
+				return ""
+			}
+			return fmt.Sprintf("%d cd/m²", h.MaxFALL)
+		})),
+	}
+}
+
+// analyzeHDR probes vp's HDR metadata in the background and warns when only
+// one of the loaded clips carries it.
+func (app *VideoCompareApp) analyzeHDR(vp *VideoPlayer) {
+	vp.hdr = nil
+	if vp.path == "" || vp.still != nil || isNetworkSource(vp.path) {
+		return
+	}
+	path := vp.path
+	go func() {
+		h, err := probeHDR(path)
+		fyne.Do(func() {
+			if vp.path != path {
+				return
+			}
+			if err != nil {
+				log.Printf("probing HDR metadata of %s: %v", path, err)
+				return
+			}
+			vp.hdr = &h
+			app.refreshMetadataTable()
+			app.warnHDRMismatch()
+		})
+	}()
+}
+
+// warnHDRMismatch tells the user when one clip carries static HDR metadata
+// and the other doesn't, since the players will then map their brightness
+// differently.
+func (app *VideoCompareApp) warnHDRMismatch() {
+	l, r := app.leftPlayer, app.rightPlayer
+	if l.hdr == nil || r.hdr == nil || l.hdr.hasMetadata() == r.hdr.hasMetadata() {
+		return
+	}
+	with, without := l, r
+	if r.hdr.hasMetadata() {
+		with, without = r, l
+	}
+	dialog.ShowInformation("HDR Metadata Differs",
+		fmt.Sprintf("%s (%s) carries HDR mastering display or content light level metadata, "+
+			"but %s (%s) doesn't.\n\nTone mapping and brightness may differ for that reason alone.",
+			with.title, displayName(with.path), without.title, displayName(without.path)),
+		app.window)
+}
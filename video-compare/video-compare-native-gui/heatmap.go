@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"golang.org/x/image/draw"
+)
+
+// colormaps are sampled at evenly spaced stops and interpolated linearly.
+var colormaps = map[string][]color.RGBA{
+	"Viridis": {
+		{0x44, 0x01, 0x54, 0xff}, {0x48, 0x28, 0x78, 0xff}, {0x3e, 0x4a, 0x89, 0xff},
+		{0x31, 0x68, 0x8e, 0xff}, {0x26, 0x82, 0x8e, 0xff}, {0x1f, 0x9e, 0x89, 0xff},
+		{0x35, 0xb7, 0x79, 0xff}, {0x6d, 0xcd, 0x59, 0xff}, {0xb4, 0xde, 0x2c, 0xff},
+		{0xfd, 0xe7, 0x25, 0xff},
+	},
+	"Inferno": {
+		{0x00, 0x00, 0x04, 0xff}, {0x1b, 0x0c, 0x41, 0xff}, {0x4a, 0x0c, 0x6b, 0xff},
+		{0x78, 0x1c, 0x6d, 0xff}, {0xa5, 0x2c, 0x60, 0xff}, {0xcf, 0x44, 0x46, 0xff},
+		{0xed, 0x69, 0x25, 0xff}, {0xfb, 0x9b, 0x06, 0xff}, {0xf7, 0xd1, 0x3d, 0xff},
+		{0xfc, 0xff, 0xa4, 0xff},
+	},
+	"Grayscale": {
+		{0x00, 0x00, 0x00, 0xff}, {0xff, 0xff, 0xff, 0xff},
+	},
+}
+
+var colormapNames = []string{"Viridis", "Inferno", "Grayscale"}
+
+var heatmapAmplifications = []string{"1×", "2×", "4×", "8×", "16×", "32×"}
+
+// colormapAt maps t in [0, 1] onto the colormap.
+func colormapAt(stops []color.RGBA, t float64) color.RGBA {
+	if t <= 0 {
+		return stops[0]
+	}
+	if t >= 1 {
+		return stops[len(stops)-1]
+	}
+	pos := t * float64(len(stops)-1)
+	i := int(pos)
+	f := pos - float64(i)
+	a, b := stops[i], stops[i+1]
+	lerp := func(x, y uint8) uint8 { return uint8(float64(x) + (float64(y)-float64(x))*f + 0.5) }
+	return color.RGBA{lerp(a.R, b.R), lerp(a.G, b.G), lerp(a.B, b.B), 0xff}
+}
+
+// diffHeatmap maps the per-pixel difference between left and right onto a
+// colormap. right is resampled to left's size; the difference is measured
+// as mode says, multiplied by amplification.
+func diffHeatmap(left, right image.Image, mode string, amplification float64, stops []color.RGBA) *image.RGBA {
+	a := toRGBA(left)
+	b := a.Bounds()
+	scaled := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.BiLinear.Scale(scaled, scaled.Bounds(), right, right.Bounds(), draw.Src, nil)
+
+	// Precompute the ramp for every possible difference value
+	var ramp [256]color.RGBA
+	for d := range ramp {
+		ramp[d] = colormapAt(stops, float64(d)*amplification/255)
+	}
+
+	diff := pixelDifference(mode)
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			i := a.PixOffset(b.Min.X+x, b.Min.Y+y)
+			j := scaled.PixOffset(x, y)
+			d := min(255, int(diff(a.Pix[i:i+3], scaled.Pix[j:j+3])+0.5))
+			out.SetRGBA(x, y, ramp[d])
+		}
+	}
+	return out
+}
+
+func absDiff(x, y uint8) int {
+	if x > y {
+		return int(x - y)
+	}
+	return int(y - x)
+}
+
+// exportDiffHeatmap asks for the difference measure, amplification and
+// colormap, then saves the heatmap of the two current frames.
+func (app *VideoCompareApp) exportDiffHeatmap() {
+	mode := widget.NewSelect(diffModes, nil)
+	mode.SetSelected(heatmapDiffMode())
+	amplification := widget.NewSelect(heatmapAmplifications, nil)
+	amplification.SetSelected(heatmapAmplifications[2])
+	colormap := widget.NewSelect(colormapNames, nil)
+	colormap.SetSelected(colormapNames[0])
+
+	dialog.ShowForm("Export Diff Heatmap", "Export", "Cancel",
+		[]*widget.FormItem{
+			widget.NewFormItem("Difference", mode),
+			widget.NewFormItem("Amplification", amplification),
+			widget.NewFormItem("Colormap", colormap),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			fyne.CurrentApp().Preferences().SetString(diffModePref, mode.Selected)
+			app.grabPair(func(left, right image.Image, err error) {
+				if err != nil {
+					dialog.ShowError(err, app.window)
+					return
+				}
+				amp, _ := strconv.ParseFloat(strings.TrimSuffix(amplification.Selected, "×"), 64)
+				left, right = app.leftPlayer.metricLevels().apply(left), app.rightPlayer.metricLevels().apply(right)
+				img := diffHeatmap(left, right, mode.Selected, max(1, amp), colormaps[colormap.Selected])
+				b := img.Bounds()
+				mid := b.Min.X + b.Dx()/2
+				app.labels.draw(img, image.Rect(b.Min.X, b.Min.Y, mid, b.Max.Y), sideLeft)
+				app.labels.draw(img, image.Rect(mid, b.Min.Y, b.Max.X, b.Max.Y), sideRight)
+				app.saveImage(img, fmt.Sprintf("diff-heatmap-%s.png", app.leftPlayer.timecodeFileStamp()))
+			})
+		}, app.window)
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const historyFile = "history.json"
+
+// historyEntry records one comparison: the file pair, when it was made,
+// the user's tags and a few key figures at the time.
+type historyEntry struct {
+	Time    time.Time         `json:"time"`
+	Left    string            `json:"left"`
+	Right   string            `json:"right"`
+	Session string            `json:"session,omitempty"` // last .vcompare saved for it
+	Tags    []string          `json:"tags,omitempty"`
+	Metrics map[string]string `json:"metrics,omitempty"`
+}
+
+// matches reports whether every word of query occurs in the file names,
+// session or tags. A word of the form tag:x must match a tag exactly.
+func (e historyEntry) matches(query string) bool {
+	haystack := strings.ToLower(strings.Join([]string{e.Left, e.Right, e.Session, strings.Join(e.Tags, " ")}, " "))
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if tag, ok := strings.CutPrefix(word, "tag:"); ok {
+			found := false
+			for _, t := range e.Tags {
+				found = found || strings.EqualFold(t, tag)
+			}
+			if !found {
+				return false
+			}
+		} else if !strings.Contains(haystack, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseTags splits a comma separated tag list, dropping blanks and
+// duplicates.
+func parseTags(s string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimSpace(t)
+		if t != "" && !seen[strings.ToLower(t)] {
+			seen[strings.ToLower(t)] = true
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// historyPanel keeps a local log of comparisons in the app's storage and
+// lists it with a search box, so past sessions can be found and reopened.
+type historyPanel struct {
+	app     *VideoCompareApp
+	path    string
+	entries []historyEntry // newest first
+	current int            // entry of the pair currently loaded, -1 if none
+	visible []int          // indices of entries matching the search
+
+	search    *widget.Entry
+	list      *widget.List
+	openBtn   *widget.Button
+	tagsBtn   *widget.Button
+	deleteBtn *widget.Button
+	selected  int
+}
+
+func newHistoryPanel(app *VideoCompareApp) *historyPanel {
+	hp := &historyPanel{app: app, current: -1, selected: -1}
+	root := fyne.CurrentApp().Storage().RootURI()
+	if root == nil {
+		return hp
+	}
+	hp.path = filepath.Join(root.Path(), historyFile)
+	data, err := os.ReadFile(hp.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("reading comparison history: %v", err)
+		}
+		return hp
+	}
+	if err := json.Unmarshal(data, &hp.entries); err != nil {
+		log.Printf("parsing comparison history: %v", err)
+	}
+	return hp
+}
+
+func (hp *historyPanel) save() {
+	if hp.path == "" {
+		return
+	}
+	data, err := json.MarshalIndent(hp.entries, "", "  ")
+	if err == nil {
+		err = os.WriteFile(hp.path, data, 0o644)
+	}
+	if err != nil {
+		log.Printf("saving comparison history: %v", err)
+	}
+}
+
+func (hp *historyPanel) content() fyne.CanvasObject {
+	hp.search = widget.NewEntry()
+	hp.search.SetPlaceHolder("Search files and tags (tag:name for an exact tag)…")
+	hp.search.OnChanged = func(string) { hp.refresh() }
+
+	hp.list = widget.NewList(
+		func() int { return len(hp.visible) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			e := hp.entries[hp.visible[id]]
+			text := fmt.Sprintf("%s  %s  vs  %s", e.Time.Format("2006-01-02 15:04"), displayName(e.Left), displayName(e.Right))
+			if len(e.Tags) > 0 {
+				text += "  [" + strings.Join(e.Tags, ", ") + "]"
+			}
+			if summary := e.Metrics["summary"]; summary != "" {
+				text += "  — " + summary
+			}
+			obj.(*widget.Label).SetText(text)
+		},
+	)
+	hp.list.OnSelected = func(id widget.ListItemID) {
+		hp.selected = hp.visible[id]
+		hp.openBtn.Enable()
+		hp.tagsBtn.Enable()
+		hp.deleteBtn.Enable()
+	}
+	hp.list.OnUnselected = func(widget.ListItemID) { hp.clearSelection() }
+
+	hp.openBtn = widget.NewButtonWithIcon("Open", theme.FolderOpenIcon(), hp.open)
+	hp.tagsBtn = widget.NewButtonWithIcon("Edit Tags", theme.DocumentCreateIcon(), hp.editTags)
+	hp.deleteBtn = widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), hp.delete)
+	hp.clearSelection()
+	hp.refresh()
+
+	buttons := container.NewHBox(hp.openBtn, hp.tagsBtn, hp.deleteBtn)
+	return container.NewBorder(container.NewBorder(nil, nil, nil, buttons, hp.search), nil, nil, nil, hp.list)
+}
+
+func (hp *historyPanel) clearSelection() {
+	hp.selected = -1
+	if hp.openBtn != nil {
+		hp.openBtn.Disable()
+		hp.tagsBtn.Disable()
+		hp.deleteBtn.Disable()
+	}
+}
+
+// refresh re-applies the search to the list.
+func (hp *historyPanel) refresh() {
+	if hp.list == nil {
+		return
+	}
+	hp.visible = hp.visible[:0]
+	for i, e := range hp.entries {
+		if e.matches(hp.search.Text) {
+			hp.visible = append(hp.visible, i)
+		}
+	}
+	hp.list.UnselectAll()
+	hp.clearSelection()
+	hp.list.Refresh()
+}
+
+// record logs the loaded pair once both sides have a file, updating the
+// entry for the same pair within this run instead of adding another.
+func (hp *historyPanel) record() {
+	l, r := hp.app.leftPlayer.originalPath(), hp.app.rightPlayer.originalPath()
+	if l == "" || r == "" {
+		return
+	}
+	if hp.current < 0 || hp.entries[hp.current].Left != l || hp.entries[hp.current].Right != r {
+		hp.entries = append([]historyEntry{{Left: l, Right: r}}, hp.entries...)
+		hp.current = 0
+	}
+	e := &hp.entries[hp.current]
+	e.Time = time.Now()
+	e.Metrics = hp.app.historyMetrics()
+	hp.save()
+	hp.refresh()
+}
+
+// updateMetrics refreshes the figures of the current entry as probes
+// finish. It writes the log only when something changed.
+func (hp *historyPanel) updateMetrics() {
+	if hp.current < 0 {
+		return
+	}
+	metrics := hp.app.historyMetrics()
+	if maps.Equal(metrics, hp.entries[hp.current].Metrics) {
+		return
+	}
+	hp.entries[hp.current].Metrics = metrics
+	hp.save()
+	if hp.list != nil {
+		hp.list.Refresh()
+	}
+}
+
+// sessionSaved remembers where the current comparison was saved so it can
+// be reopened with its notes and bookmarks.
+func (hp *historyPanel) sessionSaved(path string) {
+	hp.record()
+	if hp.current >= 0 {
+		hp.entries[hp.current].Session = path
+		hp.save()
+		hp.refresh()
+	}
+}
+
+// historyMetrics summarises the current comparison for the history log.
+func (app *VideoCompareApp) historyMetrics() map[string]string {
+	metrics := make(map[string]string)
+	var differing []string
+	for _, row := range app.metadataDiff() {
+		if row.Differs() && row.Name != "File" {
+			differing = append(differing, row.Name)
+		}
+	}
+	if len(differing) > 0 {
+		metrics["summary"] = "differs in " + strings.Join(differing, ", ")
+	} else {
+		metrics["summary"] = "metadata matches"
+	}
+	if app.stillMetricsLabel.Visible() {
+		metrics["still_reference"] = app.stillMetricsLabel.Text
+	}
+	metrics["notes"] = fmt.Sprint(len(app.notes.notes))
+	metrics["bookmarks"] = fmt.Sprint(len(app.bookmarks.bookmarks))
+	return metrics
+}
+
+// open reopens the selected comparison from its saved session, or loads
+// the file pair when it was never saved.
+func (hp *historyPanel) open() {
+	if hp.selected < 0 {
+		return
+	}
+	e := hp.entries[hp.selected]
+	if e.Session != "" {
+		f, err := os.Open(e.Session)
+		if err == nil {
+			defer f.Close()
+			s, err := readSession(f)
+			if err != nil {
+				dialog.ShowError(err, hp.app.window)
+				return
+			}
+			hp.app.applySession(s)
+			return
+		}
+		log.Printf("opening saved session: %v", err)
+	}
+	hp.app.loadVideo(hp.app.leftPlayer, e.Left)
+	hp.app.loadVideo(hp.app.rightPlayer, e.Right)
+}
+
+func (hp *historyPanel) editTags() {
+	if hp.selected < 0 {
+		return
+	}
+	index := hp.selected
+	entry := widget.NewEntry()
+	entry.SetText(strings.Join(hp.entries[index].Tags, ", "))
+	entry.SetPlaceHolder("encoder-x, crf23, nightly")
+	item := widget.NewFormItem("Tags", entry)
+	item.HintText = "Comma separated"
+	dialog.ShowForm("Edit Tags", "Save", "Cancel", []*widget.FormItem{item},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			hp.entries[index].Tags = parseTags(entry.Text)
+			hp.save()
+			hp.refresh()
+		}, hp.app.window)
+}
+
+func (hp *historyPanel) delete() {
+	if hp.selected < 0 {
+		return
+	}
+	switch {
+	case hp.selected == hp.current:
+		hp.current = -1
+	case hp.selected < hp.current:
+		hp.current--
+	}
+	hp.entries = append(hp.entries[:hp.selected], hp.entries[hp.selected+1:]...)
+	hp.save()
+	hp.refresh()
+}
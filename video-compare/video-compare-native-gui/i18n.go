@@ -0,0 +1,298 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const uiLanguagePref = "ui.language"
+
+// uiLanguage is a language the interface can be shown in. An empty code
+// follows the system locale.
+type uiLanguage struct {
+	code string
+	name string
+}
+
+var uiLanguages = []uiLanguage{
+	{"", "System Default"},
+	{"en", "English"},
+	{"de", "Deutsch"},
+}
+
+// catalog holds the translations of the user-facing strings, keyed by
+// language and then by the English text. Strings missing from a language
+// are shown in English.
+var catalog = map[string]map[string]string{
+	"de": {
+		"Video Compare - Advanced Side-by-Side Comparison": "Video Compare – Erweiterter Side-by-Side-Vergleich",
+
+		// Players
+		"Left Video":                            "Linkes Video",
+		"Right Video":                           "Rechtes Video",
+		"No file selected":                      "Keine Datei ausgewählt",
+		"No video loaded":                       "Kein Video geladen",
+		"Cancel Reconnect":                      "Neuverbindung abbrechen",
+		"Select variant":                        "Variante wählen",
+		"Choose Left Video":                     "Linkes Video wählen",
+		"Choose Right Video":                    "Rechtes Video wählen",
+		"Open URL":                              "URL öffnen",
+		"Clear":                                 "Leeren",
+		"Play":                                  "Abspielen",
+		"Pause":                                 "Pause",
+		"Stop":                                  "Stopp",
+		"Seek":                                  "Springen",
+		"Snapshot":                              "Schnappschuss",
+		"Copy Frame":                            "Bild kopieren",
+		"Adjust":                                "Anpassen",
+		"Resolution: %s\nFPS: %s\nDuration: %s": "Auflösung: %s\nFPS: %s\nDauer: %s",
+		"\nVariant: %s":                         "\nVariante: %s",
+		"\nDecoder: %s":                         "\nDecoder: %s",
+		"\nStart timecode: %s":                  "\nStart-Timecode: %s",
+		"\nCadence: %s":                         "\nKadenz: %s",
+		"\nA/V sync: %s":                        "\nA/V-Versatz: %s",
+		"\nPreview adjusted: %s":                "\nVorschau angepasst: %s",
+		"\nLevels: %s":                          "\nPegel: %s",
+		"Could not open this file":              "Diese Datei konnte nicht geöffnet werden",
+		"libvlc reported a playback error":      "libvlc meldete einen Wiedergabefehler",
+		"Live stream — seeking and frame stepping are unavailable":                  "Livestream – Springen und Einzelbildschritte sind nicht verfügbar",
+		"No playable tracks — libvlc could not parse this file":                     "Keine abspielbaren Spuren – libvlc konnte diese Datei nicht analysieren",
+		"Audio only — no video track":                                               "Nur Audio – keine Videospur",
+		"Could not determine duration — seeking and frame stepping are unavailable": "Dauer nicht ermittelbar – Springen und Einzelbildschritte sind nicht verfügbar",
+		"Analyzing timed out after %s — track information may be incomplete":        "Analyse nach %s abgebrochen – Spurinformationen sind eventuell unvollständig",
+		"Load a video on both sides to sync, export and compare frames.":            "Auf beiden Seiten ein Video laden, um Bilder zu synchronisieren, zu exportieren und zu vergleichen.",
+		"Load a video on the right side to sync, export and compare frames.":        "Rechts ein Video laden, um Bilder zu synchronisieren, zu exportieren und zu vergleichen.",
+		"Load a video on the left side to sync, export and compare frames.":         "Links ein Video laden, um Bilder zu synchronisieren, zu exportieren und zu vergleichen.",
+		"Wipe sweep export %s.": "Der Wischblenden-Export %s.",
+
+		// Streams
+		"rtsp://host/stream or https://host/video.mp4": "rtsp://host/stream oder https://host/video.mp4",
+		"unsupported URL (expected one of: %s)":        "nicht unterstützte URL (erwartet: %s)",
+		"Open":                                         "Öffnen",
+		"URL":                                          "URL",
+		"reconnecting… (attempt %d/%d)":                "Neuverbindung… (Versuch %d/%d)",
+		"connection lost":                              "Verbindung verloren",
+		"reconnect cancelled":                          "Neuverbindung abgebrochen",
+
+		// Zoom
+		"Zoom:":              "Zoom:",
+		"Step:":              "Schritt:",
+		"Reset Registration": "Ausrichtung zurücksetzen",
+		"Offset L %+.2f,%+.2f px  R %+.2f,%+.2f px": "Versatz L %+.2f,%+.2f px  R %+.2f,%+.2f px",
+
+		// Common controls
+		"Sync Videos":         "Videos synchronisieren",
+		"Set Sync Point":      "Sync-Punkt setzen",
+		"Play All":            "Alle abspielen",
+		"Pause All":           "Alle pausieren",
+		"Stop All":            "Alle stoppen",
+		"Previous Frame":      "Vorheriges Bild",
+		"Next Frame":          "Nächstes Bild",
+		"Loupe":               "Lupe",
+		"Overlay":             "Überlagerung",
+		"Scopes":              "Scopes",
+		"Inverse Telecine":    "Inverses Telecine",
+		"Timecode":            "Timecode",
+		"Source Timecode":     "Quell-Timecode",
+		"Align by Timecode":   "Nach Timecode ausrichten",
+		"Save Side-by-Side":   "Side-by-Side speichern",
+		"Copy Side-by-Side":   "Side-by-Side kopieren",
+		"Export Diff Heatmap": "Differenz-Heatmap exportieren",
+		"Export Wipe Sweep":   "Wischblende exportieren",
+		"Keep both players on the same audio track": "Beide Player auf derselben Tonspur halten",
+
+		// Statistics
+		"Video Statistics\n\nLeft:\n%s\n\nRight:\n%s": "Videostatistik\n\nLinks:\n%s\n\nRechts:\n%s",
+		"File: %s\nResolution: %s\nFPS: %.2f":         "Datei: %s\nAuflösung: %s\nFPS: %.2f",
+
+		// Tabs
+		"Statistics":    "Statistik",
+		"Metadata":      "Metadaten",
+		"Audio":         "Audio",
+		"Bitrate":       "Bitrate",
+		"Duplicates":    "Duplikate",
+		"Captions":      "Untertitel",
+		"Benchmark":     "Benchmark",
+		"Notes":         "Notizen",
+		"Macro":         "Makro",
+		"ROI":           "ROI",
+		"Content Sync":  "Inhaltssynchronisierung",
+		"Decode Errors": "Dekodierfehler",
+		"Worst Frames":  "Schlechteste Frames",
+		"Provenance":    "Herkunft",
+		"Bookmarks":     "Lesezeichen",
+		"History":       "Verlauf",
+
+		// File menu
+		"File":                                               "Datei",
+		"Open Session…":                                      "Sitzung öffnen…",
+		"Save Session…":                                      "Sitzung speichern…",
+		"Generate Report…":                                   "Bericht erstellen…",
+		"Export Labels…":                                     "Beschriftungen exportieren…",
+		"Open Segments in Left…":                             "Segmente links öffnen…",
+		"Open Segments in Right…":                            "Segmente rechts öffnen…",
+		"Transcode Left to Compatible MP4":                   "Links in kompatibles MP4 umwandeln",
+		"Transcode Right to Compatible MP4":                  "Rechts in kompatibles MP4 umwandeln",
+		"Transcode to Compatible MP4":                        "In kompatibles MP4 umwandeln",
+		"\nTranscoded from: %s (original time = shown + %s)": "\nUmgewandelt aus: %s (Originalzeit = angezeigt + %s)",
+		"Export Aligned Clips…":                              "Ausgerichtete Clips exportieren…",
+		"Export All Bookmark Snapshots…":                     "Schnappschüsse aller Lesezeichen exportieren…",
+		"Supported Formats…":                                 "Unterstützte Formate…",
+		"Audio-Only Mode":                                    "Nur-Audio-Modus",
+		"Forget File Settings…":                              "Dateieinstellungen vergessen…",
+		"Cache…":                                             "Zwischenspeicher…",
+		"Background Jobs…":                                   "Hintergrundaufträge…",
+		"Language":                                           "Sprache",
+		"System Default":                                     "Systemstandard",
+		"The language changes the next time Video Compare starts.": "Die Sprache wird beim nächsten Start von Video Compare umgestellt.",
+
+		// Preference menus
+		"Auto-play on Open":                              "Beim Öffnen abspielen",
+		"Caption Snapshots":                              "Schnappschüsse beschriften",
+		"Pause When Window Loses Focus":                  "Pausieren, wenn das Fenster den Fokus verliert",
+		"Resume When Window Regains Focus":               "Fortsetzen, wenn das Fenster den Fokus zurückerhält",
+		"Show Frame Timestamps (PTS/DTS)":                "Frame-Zeitstempel anzeigen (PTS/DTS)",
+		"Image Export Format":                            "Bildexportformat",
+		"Lossy Quality…":                                 "Verlustbehaftete Qualität…",
+		"Lossy Image Quality":                            "Verlustbehaftete Bildqualität",
+		"Quality":                                        "Qualität",
+		"Used for JPEG and WebP; PNG is always lossless": "Gilt für JPEG und WebP; PNG ist immer verlustfrei",
+		"Normalize Color Range in Metrics":               "Farbbereich in Metriken normalisieren",
+		"Step Frames at Measured FPS":                    "Bilder mit gemessener FPS schrittweise anzeigen",
+		"Shortcuts":                                      "Tastenkürzel",
+		"Arrow Key Nudge":                                "Pfeiltastenschritt",
+		"Track Click and Page Up/Down":                   "Klick auf Leiste und Bild auf/ab",
+		"Scrub One Side With":                            "Eine Seite allein scrubben mit",
+
+		// Dialogs
+		"Save":                           "Speichern",
+		"Cancel":                         "Abbrechen",
+		"Close":                          "Schließen",
+		"Cache":                          "Zwischenspeicher",
+		"%s in %d entries, limit %s\n%s": "%s in %d Einträgen, Grenze %s\n%s",
+		"Size limit":                     "Größenbegrenzung",
+		"Clear Cache":                    "Zwischenspeicher leeren",
+		"Background Jobs":                "Hintergrundaufträge",
+		"Background jobs: %d running, %d queued (limit %d)": "Hintergrundaufträge: %d laufen, %d warten (Grenze %d)",
+		"Default (%d, one per CPU)":                         "Standard (%d, einer pro CPU)",
+		"Most ffmpeg and ffprobe processes the analyses, scans\nand exports run at once; the rest wait their turn:": "Höchstzahl gleichzeitiger ffmpeg- und ffprobe-Prozesse für Analysen, Scans\nund Exporte; die übrigen warten, bis sie an der Reihe sind:",
+		"Transcoding":              "Umwandlung",
+		"Re-encoding %s to H.264…": "%s wird in H.264 umkodiert…",
+		"Only single local video files can be transcoded.": "Nur einzelne lokale Videodateien können umgewandelt werden.",
+
+		// Missing tools
+		"needs an ffmpeg built with libvmaf":                        "benötigt ein mit libvmaf gebautes ffmpeg",
+		"needs ffmpeg, which wasn't found on the PATH":              "benötigt ffmpeg, das nicht im PATH gefunden wurde",
+		"needs ffprobe, which wasn't found on the PATH":             "benötigt ffprobe, das nicht im PATH gefunden wurde",
+		"needs ffmpeg and ffprobe, which weren't found on the PATH": "benötigt ffmpeg und ffprobe, die nicht im PATH gefunden wurden",
+		"Unavailable: %s": "Nicht verfügbar: %s",
+		"exact frame stepping, frame counts, bitrate chart and format details": "exakte Einzelbildschritte, Bildzahlen, Bitratendiagramm und Formatdetails",
+		"cadence, loudness and A/V sync analysis":                              "Kadenz-, Lautheits- und A/V-Versatzanalyse",
+		"duplicate detection":                     "Duplikaterkennung",
+		"decode error checks":                     "Dekodierfehlerprüfung",
+		"caption comparison":                      "Untertitelvergleich",
+		"ROI range metrics":                       "ROI-Bereichsmetriken",
+		"worst frame scan":                        "Suche nach schlechtesten Frames",
+		"decode benchmark":                        "Dekodier-Benchmark",
+		"wipe sweep export":                       "Wischblenden-Export",
+		"WebP export":                             "WebP-Export",
+		"segment joining and aligned clip export": "Segmentverkettung und Export ausgerichteter Clips",
+		"transcoding to compatible MP4":           "Umwandlung in kompatibles MP4",
+
+		// Benchmark
+		"Only the in/out range": "Nur der In/Out-Bereich",
+		"Run Benchmark":         "Benchmark starten",
+		"Decodes each file's video as fast as possible with ffmpeg": "Dekodiert das Video jeder Datei so schnell wie möglich mit ffmpeg",
+		"The benchmark %s":                     "Der Benchmark %s",
+		"Left":                                 "Links",
+		"Right":                                "Rechts",
+		"Right / Left":                         "Rechts / Links",
+		"Frames":                               "Frames",
+		"Decode Time":                          "Dekodierzeit",
+		"Average FPS":                          "Mittlere FPS",
+		"CPU Time":                             "CPU-Zeit",
+		"Peak Memory":                          "Spitzenspeicher",
+		"Load a local video file to benchmark": "Eine lokale Videodatei für den Benchmark laden",
+		"Decoding %s…":                         "%s wird dekodiert…",
+		"Benchmark finished":                   "Benchmark abgeschlossen",
+		"Benchmark cancelled":                  "Benchmark abgebrochen",
+		"Benchmark failed: %v":                 "Benchmark fehlgeschlagen: %v",
+
+		// Audio
+		"%s: no audio":                          "%s: kein Ton",
+		"%s: no audio waveform for this source": "%s: keine Wellenform für diese Quelle",
+		"%s: extracting audio…":                 "%s: Ton wird extrahiert…",
+		"Fit":                                   "Einpassen",
+		"Zoom %.0f× — %.3f s visible":           "Zoom %.0f× – %.3f s sichtbar",
+		"Spectrograms %s":                       "Spektrogramme: %s",
+		"Computing spectrograms…":               "Spektrogramme werden berechnet…",
+		"Mean spectral difference at the current offset: %.1f%%": "Mittlere spektrale Differenz beim aktuellen Versatz: %.1f%%",
+		"The clips don't overlap at the current offset":          "Die Clips überlappen beim aktuellen Versatz nicht",
+	},
+}
+
+// activeLanguage is the language chosen at startup, "en" when the system
+// locale or setting names one without translations.
+var activeLanguage = "en"
+
+// setupLanguage picks the interface language from the setting, falling
+// back to the system locale. Widgets are built with the translated text,
+// so a change applies on the next start.
+func setupLanguage(prefs fyne.Preferences) {
+	code := prefs.String(uiLanguagePref)
+	if code == "" {
+		code, _, _ = strings.Cut(lang.SystemLocale().LanguageString(), "-")
+	}
+	activeLanguage = "en"
+	if _, ok := catalog[code]; ok {
+		activeLanguage = code
+	}
+}
+
+// tr translates a user-facing string into the interface language.
+func tr(s string) string {
+	if t, ok := catalog[activeLanguage][s]; ok {
+		return t
+	}
+	return s
+}
+
+// trf translates format and formats it with the language's number
+// conventions, so 23.98 reads 23,98 in German. Values that aren't plain
+// numbers, such as resolutions or timecodes, should be passed as strings
+// to keep them free of digit grouping.
+func trf(format string, args ...any) string {
+	return message.NewPrinter(language.Make(activeLanguage)).Sprintf(tr(format), args...)
+}
+
+// resolution formats a frame size for display.
+func resolution(width, height int) string {
+	return fmt.Sprintf("%dx%d", width, height)
+}
+
+// languageMenuItem is the File menu's interface language choice.
+func (app *VideoCompareApp) languageMenuItem() *fyne.MenuItem {
+	item := fyne.NewMenuItem(tr("Language"), nil)
+	menu := fyne.NewMenu("")
+	for _, l := range uiLanguages {
+		choice := fyne.NewMenuItem(tr(l.name), nil)
+		choice.Checked = fyne.CurrentApp().Preferences().String(uiLanguagePref) == l.code
+		choice.Action = func() {
+			fyne.CurrentApp().Preferences().SetString(uiLanguagePref, l.code)
+			for _, other := range menu.Items {
+				other.Checked = other == choice
+			}
+			app.window.MainMenu().Refresh()
+			dialog.ShowInformation(tr("Language"), tr("The language changes the next time Video Compare starts."), app.window)
+		}
+		menu.Items = append(menu.Items, choice)
+	}
+	item.ChildMenu = menu
+	return item
+}
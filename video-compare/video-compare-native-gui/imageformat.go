@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// imageFormat is a file format exported frames are written in.
+type imageFormat string
+
+const (
+	imagePNG  imageFormat = "PNG"
+	imageJPEG imageFormat = "JPEG"
+	imageWebP imageFormat = "WebP"
+)
+
+var imageFormats = []imageFormat{imagePNG, imageJPEG, imageWebP}
+
+const (
+	prefImageFormat  = "export.imageFormat"
+	prefImageQuality = "export.imageQuality"
+
+	defaultImageQuality = 90
+)
+
+// ext is the file extension written for f.
+func (f imageFormat) ext() string {
+	switch f {
+	case imageJPEG:
+		return ".jpg"
+	case imageWebP:
+		return ".webp"
+	}
+	return ".png"
+}
+
+// lossy reports whether f takes a quality setting.
+func (f imageFormat) lossy() bool {
+	return f == imageJPEG || f == imageWebP
+}
+
+// defaultImageFormat is the format exports use unless the file name says
+// otherwise. PNG keeps frames exact.
+func defaultImageFormat() imageFormat {
+	f := imageFormat(fyne.CurrentApp().Preferences().StringWithFallback(prefImageFormat, string(imagePNG)))
+	for _, known := range imageFormats {
+		if f == known {
+			return f
+		}
+	}
+	return imagePNG
+}
+
+// imageQuality is the 1–100 quality lossy formats are written at.
+func imageQuality() int {
+	q := fyne.CurrentApp().Preferences().IntWithFallback(prefImageQuality, defaultImageQuality)
+	return min(100, max(1, q))
+}
+
+// formatForPath picks the format from path's extension, so typing another
+// extension in a save dialog overrides the default for that export.
+func formatForPath(path string) imageFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		return imagePNG
+	case ".jpg", ".jpeg":
+		return imageJPEG
+	case ".webp":
+		return imageWebP
+	}
+	return defaultImageFormat()
+}
+
+// withImageExt gives a suggested file name the default format's extension.
+func withImageExt(name string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name)) + defaultImageFormat().ext()
+}
+
+// encodeImage writes img to w in format f. Go has no WebP encoder, so WebP
+// goes through ffmpeg's libwebp.
+func encodeImage(w io.Writer, img image.Image, f imageFormat, quality int) error {
+	switch f {
+	case imageJPEG:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	case imageWebP:
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return err
+		}
+		out, err := runFFmpegInput(context.Background(), &buf, "-f", "png_pipe", "-i", "-",
+			"-c:v", "libwebp", "-quality", strconv.Itoa(quality), "-f", "webp", "-")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// writeImage encodes img to a new file at path in the format its
+// extension names.
+func writeImage(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := encodeImage(f, img, formatForPath(path), imageQuality()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// imageFormatMenuItem chooses the default export format and the quality
+// of lossy formats.
+func (app *VideoCompareApp) imageFormatMenuItem() *fyne.MenuItem {
+	item := fyne.NewMenuItem(tr("Image Export Format"), nil)
+	menu := fyne.NewMenu("")
+	current := defaultImageFormat()
+	var choices []*fyne.MenuItem
+	for _, f := range imageFormats {
+		choice := fyne.NewMenuItem(string(f), nil)
+		choice.Checked = f == current
+		choice.Action = func() {
+			fyne.CurrentApp().Preferences().SetString(prefImageFormat, string(f))
+			for _, other := range choices {
+				other.Checked = other == choice
+			}
+			app.window.MainMenu().Refresh()
+		}
+		if f == imageWebP {
+			app.requireTools(choice, needsFFmpeg)
+		}
+		choices = append(choices, choice)
+	}
+	menu.Items = append(choices, fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(tr("Lossy Quality…"), app.imageQualityDialog))
+	item.ChildMenu = menu
+	return item
+}
+
+// imageQualityDialog sets the quality JPEG and WebP exports are written at.
+func (app *VideoCompareApp) imageQualityDialog() {
+	slider := widget.NewSlider(1, 100)
+	slider.SetValue(float64(imageQuality()))
+	value := widget.NewLabel("")
+	slider.OnChanged = func(v float64) { value.SetText(fmt.Sprintf("%.0f", v)) }
+	slider.OnChanged(slider.Value)
+	qualityItem := widget.NewFormItem(tr("Quality"), container.NewBorder(nil, nil, nil, value, slider))
+	qualityItem.HintText = tr("Used for JPEG and WebP; PNG is always lossless")
+
+	dialog.ShowForm(tr("Lossy Image Quality"), tr("Save"), tr("Cancel"), []*widget.FormItem{qualityItem},
+		func(ok bool) {
+			if ok {
+				fyne.CurrentApp().Preferences().SetInt(prefImageQuality, int(slider.Value))
+			}
+		}, app.window)
+}
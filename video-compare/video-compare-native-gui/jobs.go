@@ -0,0 +1,72 @@
+package main
+
+import (
+	"runtime"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"videocompare"
+)
+
+// prefJobLimit is how many ffmpeg and ffprobe processes run at once; 0
+// keeps videocompare.DefaultJobLimit.
+const prefJobLimit = "jobs.limit"
+
+// applyJobLimit sets the saved background job limit, at startup.
+func applyJobLimit(prefs fyne.Preferences) {
+	videocompare.SetJobLimit(prefs.Int(prefJobLimit))
+}
+
+// newJobsIndicator creates the label counting the background ffmpeg and
+// ffprobe jobs running and queued under the limit, hidden while idle.
+func (app *VideoCompareApp) newJobsIndicator() *widget.Label {
+	label := widget.NewLabel("")
+	label.Importance = widget.LowImportance
+	label.Hide()
+	videocompare.OnJobsChanged(func(s videocompare.JobStats) {
+		fyne.Do(func() {
+			if s.Running+s.Waiting == 0 {
+				label.Hide()
+				return
+			}
+			label.SetText(trf("Background jobs: %d running, %d queued (limit %d)", s.Running, s.Waiting, s.Limit))
+			label.Show()
+		})
+	})
+	return label
+}
+
+// jobLimitDialog edits how many background jobs may run at once.
+func (app *VideoCompareApp) jobLimitDialog() {
+	defaultOption := trf("Default (%d, one per CPU)", videocompare.DefaultJobLimit())
+	options := []string{defaultOption}
+	for n := 1; n <= max(8, 2*runtime.NumCPU()); n++ {
+		options = append(options, strconv.Itoa(n))
+	}
+	limitSelect := widget.NewSelect(options, nil)
+	if n := fyne.CurrentApp().Preferences().Int(prefJobLimit); n > 0 {
+		limitSelect.SetSelected(strconv.Itoa(n))
+	} else {
+		limitSelect.SetSelected(defaultOption)
+	}
+	content := container.NewVBox(
+		widget.NewLabel(tr("Most ffmpeg and ffprobe processes the analyses, scans\nand exports run at once; the rest wait their turn:")),
+		limitSelect)
+
+	dialog.ShowCustomConfirm(tr("Background Jobs"), tr("Save"), tr("Cancel"), content, func(ok bool) {
+		if !ok {
+			return
+		}
+		n, _ := strconv.Atoi(limitSelect.Selected) // 0 for the default
+		prefs := fyne.CurrentApp().Preferences()
+		if n > 0 {
+			prefs.SetInt(prefJobLimit, n)
+		} else {
+			prefs.RemoveValue(prefJobLimit)
+		}
+		videocompare.SetJobLimit(n)
+	}, app.window)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"image"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"golang.org/x/image/draw"
+)
+
+const (
+	sideLeft = iota
+	sideRight
+)
+
+var labelColors = map[string]color.RGBA{
+	"Blue":   {R: 0x1f, G: 0x77, B: 0xb4, A: 0xff},
+	"Orange": {R: 0xff, G: 0x7f, B: 0x0e, A: 0xff},
+	"Green":  {R: 0x2c, G: 0xa0, B: 0x2c, A: 0xff},
+	"Red":    {R: 0xd6, G: 0x27, B: 0x28, A: 0xff},
+	"Purple": {R: 0x94, G: 0x67, B: 0xbd, A: 0xff},
+	"Gray":   {R: 0x7f, G: 0x7f, B: 0x7f, A: 0xff},
+}
+
+var (
+	labelColorNames    = []string{"Blue", "Orange", "Green", "Red", "Purple", "Gray"}
+	labelPositionNames = []string{"Top", "Bottom"}
+)
+
+// exportLabels marks which side every exported image came from: a band in
+// the side's color along one edge and the side's name in its outer corner.
+// The settings are kept in the app preferences so a batch of exports looks
+// the same across runs.
+type exportLabels struct {
+	enabled bool
+	names   [2]string
+	colors  [2]string
+	bottom  bool
+}
+
+const (
+	prefLabelsEnabled = "labels.enabled"
+	prefLabelsBottom  = "labels.bottom"
+)
+
+var (
+	prefLabelNames  = [2]string{"labels.left.name", "labels.right.name"}
+	prefLabelColors = [2]string{"labels.left.color", "labels.right.color"}
+)
+
+func loadExportLabels(prefs fyne.Preferences) exportLabels {
+	return exportLabels{
+		enabled: prefs.BoolWithFallback(prefLabelsEnabled, true),
+		names: [2]string{
+			prefs.StringWithFallback(prefLabelNames[sideLeft], "REF"),
+			prefs.StringWithFallback(prefLabelNames[sideRight], "TEST"),
+		},
+		colors: [2]string{
+			prefs.StringWithFallback(prefLabelColors[sideLeft], "Blue"),
+			prefs.StringWithFallback(prefLabelColors[sideRight], "Orange"),
+		},
+		bottom: prefs.BoolWithFallback(prefLabelsBottom, false),
+	}
+}
+
+func (l exportLabels) save(prefs fyne.Preferences) {
+	prefs.SetBool(prefLabelsEnabled, l.enabled)
+	prefs.SetBool(prefLabelsBottom, l.bottom)
+	for side := range l.names {
+		prefs.SetString(prefLabelNames[side], l.names[side])
+		prefs.SetString(prefLabelColors[side], l.colors[side])
+	}
+}
+
+func (l exportLabels) color(side int) color.RGBA {
+	if c, ok := labelColors[l.colors[side]]; ok {
+		return c
+	}
+	return labelColors["Gray"]
+}
+
+// draw labels region of dst as coming from side. The name goes in the
+// region's left corner for the left side and its right corner for the
+// right side, so both stay visible when the two are composed together.
+func (l exportLabels) draw(dst *image.RGBA, region image.Rectangle, side int) {
+	if !l.enabled {
+		return
+	}
+	c := l.color(side)
+	scale := max(1, region.Dy()/360)
+	band := 4 * scale
+	margin := 8 * scale
+
+	bandRect := image.Rect(region.Min.X, region.Min.Y, region.Max.X, region.Min.Y+band)
+	if l.bottom {
+		bandRect = image.Rect(region.Min.X, region.Max.Y-band, region.Max.X, region.Max.Y)
+	}
+	draw.Draw(dst, bandRect.Intersect(dst.Bounds()), image.NewUniform(c), image.Point{}, draw.Src)
+
+	if l.names[side] == "" {
+		return
+	}
+	box := renderText(l.names[side], c)
+	w, h := box.Bounds().Dx()*scale, box.Bounds().Dy()*scale
+	x := region.Min.X + margin
+	if side == sideRight {
+		x = region.Max.X - margin - w
+	}
+	y := region.Min.Y + band + margin
+	if l.bottom {
+		y = region.Max.Y - band - margin - h
+	}
+	drawImageAt(dst, box, x, y, scale)
+}
+
+// side is the label index of vp.
+func (app *VideoCompareApp) side(vp *VideoPlayer) int {
+	if vp == app.rightPlayer {
+		return sideRight
+	}
+	return sideLeft
+}
+
+// exportLabelsDialog edits the side labels drawn on exports.
+func (app *VideoCompareApp) exportLabelsDialog() {
+	l := app.labels
+	enabled := widget.NewCheck("Label exported images", nil)
+	enabled.SetChecked(l.enabled)
+	position := widget.NewSelect(labelPositionNames, nil)
+	position.SetSelected(labelPositionNames[0])
+	if l.bottom {
+		position.SetSelected(labelPositionNames[1])
+	}
+
+	var names [2]*widget.Entry
+	var colors [2]*widget.Select
+	items := []*widget.FormItem{widget.NewFormItem("", enabled)}
+	for side, vp := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		names[side] = widget.NewEntry()
+		names[side].SetText(l.names[side])
+		colors[side] = widget.NewSelect(labelColorNames, nil)
+		colors[side].SetSelected(l.colors[side])
+		items = append(items,
+			widget.NewFormItem(vp.title+" Name", names[side]),
+			widget.NewFormItem(vp.title+" Color", colors[side]))
+	}
+	items = append(items, widget.NewFormItem("Position", position))
+
+	dialog.ShowForm("Export Labels", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		l.enabled = enabled.Checked
+		l.bottom = position.Selected == labelPositionNames[1]
+		for side := range l.names {
+			l.names[side] = names[side].Text
+			l.colors[side] = colors[side].Selected
+		}
+		app.labels = l
+		l.save(fyne.CurrentApp().Preferences())
+	}, app.window)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"fyne.io/fyne/v2"
+)
+
+// levelsConversion maps a clip between limited (16–235) and full (0–255)
+// range in the preview, for comparing clips whose color range differs or
+// is tagged wrongly.
+type levelsConversion string
+
+const (
+	levelsAsEncoded levelsConversion = "As Encoded"
+	levelsExpand    levelsConversion = "Limited → Full"
+	levelsCompress  levelsConversion = "Full → Limited"
+)
+
+var levelsConversions = []string{string(levelsAsEncoded), string(levelsExpand), string(levelsCompress)}
+
+const prefNormalizeRange = "metrics.normalizeRange"
+
+// adjustFactors returns the changes to libvlc's adjust filter that perform
+// the conversion. The filter scales luma by contrast around mid grey and
+// adds (brightness-1)×255, so the offset moves black to the target level;
+// saturation scales chroma the same way.
+func (c levelsConversion) adjustFactors() (contrast, brightness, saturation float64) {
+	switch c {
+	case levelsExpand:
+		return 255.0 / 219, (255.0/219*112 - 128) / 255, 255.0 / 224
+	case levelsCompress:
+		return 219.0 / 255, (16 + 219.0/255*128 - 128) / 255, 224.0 / 255
+	}
+	return 1, 0, 1
+}
+
+// apply converts the levels of every RGB sample in img.
+func (c levelsConversion) apply(img image.Image) image.Image {
+	if c != levelsExpand && c != levelsCompress {
+		return img
+	}
+	var lut [256]uint8
+	for v := range lut {
+		var out float64
+		if c == levelsExpand {
+			out = (float64(v) - 16) * 255 / 219
+		} else {
+			out = 16 + float64(v)*219/255
+		}
+		lut[v] = uint8(math.Round(math.Min(255, math.Max(0, out))))
+	}
+	src := toRGBA(img)
+	out := image.NewRGBA(src.Rect)
+	for i := 0; i < len(src.Pix); i += 4 {
+		out.Pix[i] = lut[src.Pix[i]]
+		out.Pix[i+1] = lut[src.Pix[i+1]]
+		out.Pix[i+2] = lut[src.Pix[i+2]]
+		out.Pix[i+3] = src.Pix[i+3]
+	}
+	return out
+}
+
+// setLevels converts vp's preview levels on top of its adjustments.
+func (vp *VideoPlayer) setLevels(c levelsConversion) {
+	vp.levels = c
+	vp.setAdjust(vp.adjust)
+}
+
+// normalizeRangeEnabled reports whether frame metrics apply each player's
+// levels conversion first, so a range tag mismatch doesn't show up as a
+// difference.
+func normalizeRangeEnabled() bool {
+	return fyne.CurrentApp().Preferences().Bool(prefNormalizeRange)
+}
+
+// metricLevels returns the conversion metrics apply to vp's frames.
+func (vp *VideoPlayer) metricLevels() levelsConversion {
+	if !normalizeRangeEnabled() {
+		return levelsAsEncoded
+	}
+	return vp.levels
+}
+
+// normalizeRangeMenuItem toggles normalizing color range in metrics.
+func (app *VideoCompareApp) normalizeRangeMenuItem() *fyne.MenuItem {
+	item := fyne.NewMenuItem(tr("Normalize Color Range in Metrics"), nil)
+	item.Checked = normalizeRangeEnabled()
+	item.Action = func() {
+		item.Checked = !item.Checked
+		fyne.CurrentApp().Preferences().SetBool(prefNormalizeRange, item.Checked)
+		app.window.MainMenu().Refresh()
+		app.refreshStillMetrics()
+	}
+	return item
+}
+
+// colorRangeRow compares the color ranges, pointing at the levels
+// conversion when they differ and noting any conversion in use.
+func (app *VideoCompareApp) colorRangeRow() metadataRow {
+	l, r := app.leftPlayer, app.rightPlayer
+	value := formatValue(func(f *videoFormat) string { return f.ColorRange })
+	shown := func(vp *VideoPlayer) string {
+		v := value(vp)
+		if vp.levels != levelsAsEncoded {
+			v += fmt.Sprintf(" (previewed %s)", vp.levels)
+		}
+		return v
+	}
+	row := metadataRow{Name: "Color Range", Left: loadedValue(l, shown), Right: loadedValue(r, shown)}
+	if l.format != nil && r.format != nil && l.format.ColorRange != r.format.ColorRange &&
+		l.levels == levelsAsEncoded && r.levels == levelsAsEncoded {
+		row.Right += " — levels differ, convert one side under Adjust"
+	}
+	return row
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// newLoopCheck creates the toggle that makes vp start over whenever it
+// reaches the end of its clip or range. It only affects this player.
+func (vp *VideoPlayer) newLoopCheck() *widget.Check {
+	return widget.NewCheck("Loop", func(on bool) { vp.loop = on })
+}
+
+// loopAtRangeEnd jumps back to the in point once a looping player passes
+// its out point, and reports whether it did.
+func (vp *VideoPlayer) loopAtRangeEnd() bool {
+	start, end := vp.playRange()
+	if !vp.loop || !vp.playing() || vp.rangeEnd <= 0 || vp.currentTime < end {
+		return false
+	}
+	vp.seekTo(start)
+	return true
+}
+
+// restartLoop plays vp again from its in point after libvlc reported the
+// end of the clip. An ended player has to be stopped before it plays again
+// and only accepts a seek once playback is under way. The stop briefly
+// reports the player idle, so only a pause in the meantime cancels the seek.
+func (vp *VideoPlayer) restartLoop() {
+	start, _ := vp.playRange()
+	vp.player.Stop()
+	vp.currentTime = 0
+	vp.play()
+	if start <= 0 {
+		return
+	}
+	path := vp.path
+	go func() {
+		time.Sleep(frameSettleDelay)
+		fyne.Do(func() {
+			if vp.path == path && vp.loop && vp.state != statePaused {
+				vp.seekTo(start)
+			}
+		})
+	}()
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxVolume is libvlc's volume ceiling in percent; 100 leaves the level
+// untouched and 200 amplifies by about 6 dB.
+const maxVolume = 200
+
+var (
+	loudnessTargets       = []string{"-14 LUFS", "-16 LUFS", "-23 LUFS", "-24 LUFS"}
+	integratedLoudPattern = regexp.MustCompile(`I:\s+(-?[\d.]+|-inf) LUFS`)
+)
+
+// measureLoudness returns path's EBU R128 integrated loudness in LUFS.
+func measureLoudness(ctx context.Context, path string) (float64, error) {
+	out, err := runFFmpegLog(ctx, "-i", path, "-vn", "-af", "ebur128=framelog=quiet", "-f", "null", "-")
+	if err != nil {
+		return 0, err
+	}
+	m := integratedLoudPattern.FindAllStringSubmatch(out, -1)
+	if len(m) == 0 {
+		return 0, fmt.Errorf("no loudness summary in ffmpeg output")
+	}
+	lufs, err := strconv.ParseFloat(m[len(m)-1][1], 64)
+	if err != nil || math.IsInf(lufs, 0) {
+		return 0, fmt.Errorf("no audible audio")
+	}
+	return lufs, nil
+}
+
+// gainVolume converts a gain in dB to a libvlc volume percentage, and
+// reports whether the gain had to be limited.
+func gainVolume(gain float64) (int, bool) {
+	volume := 100 * math.Pow(10, gain/20)
+	if volume > maxVolume {
+		return maxVolume, true
+	}
+	return int(math.Round(volume)), false
+}
+
+// loudnessPanel measures both clips' integrated loudness and, when enabled,
+// sets each player's volume so both play at the same target loudness.
+type loudnessPanel struct {
+	app     *VideoCompareApp
+	enabled bool
+	target  float64 // LUFS
+
+	loudness map[*VideoPlayer]float64
+	cancels  map[*VideoPlayer]context.CancelFunc
+	labels   map[*VideoPlayer]*widget.Label
+}
+
+func newLoudnessPanel(app *VideoCompareApp) *loudnessPanel {
+	return &loudnessPanel{
+		app:      app,
+		target:   -23,
+		loudness: make(map[*VideoPlayer]float64),
+		cancels:  make(map[*VideoPlayer]context.CancelFunc),
+		labels:   make(map[*VideoPlayer]*widget.Label),
+	}
+}
+
+func (lp *loudnessPanel) content() fyne.CanvasObject {
+	check := widget.NewCheck("Loudness Normalize", func(enabled bool) {
+		lp.enabled = enabled
+		lp.apply()
+	})
+	targetSelect := widget.NewSelect(loudnessTargets, func(s string) {
+		lp.target, _ = strconv.ParseFloat(strings.TrimSuffix(s, " LUFS"), 64)
+		lp.apply()
+	})
+	targetSelect.SetSelected(fmt.Sprintf("%.0f LUFS", lp.target))
+
+	row := container.NewHBox(check, widget.NewLabel("Target:"), targetSelect)
+	for _, vp := range []*VideoPlayer{lp.app.leftPlayer, lp.app.rightPlayer} {
+		lp.labels[vp] = widget.NewLabel("")
+		row.Add(widget.NewSeparator())
+		row.Add(lp.labels[vp])
+	}
+	lp.updateLabels()
+	return row
+}
+
+// load measures vp's loudness in the background, replacing any
+// measurement still running for a previously loaded file.
+func (lp *loudnessPanel) load(vp *VideoPlayer) {
+	if cancel := lp.cancels[vp]; cancel != nil {
+		cancel()
+		delete(lp.cancels, vp)
+	}
+	delete(lp.loudness, vp)
+	lp.apply()
+	if !vp.hasAudio || isNetworkSource(vp.path) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lp.cancels[vp] = cancel
+	path := vp.path
+	lp.labels[vp].SetText(vp.title + ": measuring loudness…")
+
+	go func() {
+		lufs, err := measureLoudness(ctx, path)
+		fyne.Do(func() {
+			if ctx.Err() != nil || vp.path != path {
+				return
+			}
+			cancel()
+			delete(lp.cancels, vp)
+			if err != nil {
+				log.Printf("loudness of %s: %v", path, err)
+			} else {
+				lp.loudness[vp] = lufs
+			}
+			lp.apply()
+		})
+	}()
+}
+
+// gain is the correction applied to vp, 0 while normalization is off or
+// its loudness is unknown.
+func (lp *loudnessPanel) gain(vp *VideoPlayer) (float64, bool) {
+	lufs, ok := lp.loudness[vp]
+	if !lp.enabled || !ok {
+		return 0, false
+	}
+	return lp.target - lufs, true
+}
+
+// apply sets both players' volumes from the current gains.
+func (lp *loudnessPanel) apply() {
+	for _, vp := range []*VideoPlayer{lp.app.leftPlayer, lp.app.rightPlayer} {
+		gain, _ := lp.gain(vp)
+		vp.volume, _ = gainVolume(gain)
+		vp.applyVolume()
+	}
+	lp.updateLabels()
+}
+
+func (lp *loudnessPanel) updateLabels() {
+	for vp, label := range lp.labels {
+		if lp.cancels[vp] != nil {
+			continue
+		}
+		lufs, ok := lp.loudness[vp]
+		switch {
+		case vp.path == "":
+			label.SetText(vp.title + ": —")
+		case !ok:
+			label.SetText(vp.title + ": loudness unknown")
+		default:
+			text := fmt.Sprintf("%s: %.1f LUFS", vp.title, lufs)
+			if gain, ok := lp.gain(vp); ok {
+				text += fmt.Sprintf(", gain %+.1f dB", gain)
+				if _, limited := gainVolume(gain); limited {
+					text += " (limited to +6 dB)"
+				}
+			}
+			label.SetText(text)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	defaultLoupeRegion        = 32 // source pixels sampled around the cursor
+	defaultLoupeMagnification = 8
+)
+
+// loupe is a floating window showing a magnified region around the cursor
+// from both players at once, stacked vertically.
+type loupe struct {
+	app    *VideoCompareApp
+	window fyne.Window
+
+	leftImage   *canvas.Image
+	rightImage  *canvas.Image
+	leftLabel   *widget.Label
+	rightLabel  *widget.Label
+	regionSize  int
+	magnify     int
+	enabled     bool
+	lastNX      float64
+	lastNY      float64
+	hasPosition bool
+}
+
+func newLoupe(app *VideoCompareApp) *loupe {
+	return &loupe{
+		app:        app,
+		regionSize: defaultLoupeRegion,
+		magnify:    defaultLoupeMagnification,
+	}
+}
+
+func (l *loupe) newImage() *canvas.Image {
+	img := canvas.NewImageFromImage(image.NewRGBA(image.Rect(0, 0, l.regionSize, l.regionSize)))
+	img.ScaleMode = canvas.ImageScalePixels
+	img.FillMode = canvas.ImageFillStretch
+	side := float32(l.regionSize * l.magnify)
+	img.SetMinSize(fyne.NewSize(side, side))
+	return img
+}
+
+// setEnabled shows or hides the loupe window.
+func (l *loupe) setEnabled(enabled bool) {
+	l.enabled = enabled
+	if !enabled {
+		if l.window != nil {
+			l.window.Hide()
+		}
+		return
+	}
+
+	if l.window == nil {
+		l.leftImage = l.newImage()
+		l.rightImage = l.newImage()
+		l.leftLabel = widget.NewLabel(l.app.leftPlayer.title)
+		l.rightLabel = widget.NewLabel(l.app.rightPlayer.title)
+
+		l.window = fyne.CurrentApp().NewWindow("Loupe")
+		l.window.SetContent(container.NewVBox(
+			l.leftLabel, l.leftImage,
+			widget.NewSeparator(),
+			l.rightLabel, l.rightImage,
+		))
+		l.window.SetFixedSize(true)
+		l.window.SetOnClosed(func() {
+			l.window = nil
+			l.app.loupeCheck.SetChecked(false)
+		})
+	}
+	l.window.Show()
+}
+
+// track is called with the pointer position over either player's video area.
+func (l *loupe) track(vp *VideoPlayer, pos fyne.Position) {
+	if !l.enabled || vp.display == nil {
+		return
+	}
+	nx, ny, ok := vp.display.normalizedPosition(pos)
+	if !ok {
+		return
+	}
+	l.lastNX, l.lastNY, l.hasPosition = nx, ny, true
+	l.refresh()
+}
+
+// refresh re-samples both players at the last known cursor position.
+func (l *loupe) refresh() {
+	if !l.enabled || !l.hasPosition || l.window == nil {
+		return
+	}
+	l.update(l.app.leftPlayer, l.leftImage, l.leftLabel)
+	l.update(l.app.rightPlayer, l.rightImage, l.rightLabel)
+}
+
+func (l *loupe) update(vp *VideoPlayer, target *canvas.Image, label *widget.Label) {
+	// The cursor is over the display, which may show a zoomed region
+	p := vp.framePoint([2]float64{l.lastNX, l.lastNY})
+	x, y, ok := vp.sourcePixel(p[0], p[1])
+	if !ok {
+		label.SetText(fmt.Sprintf("%s: no video", vp.title))
+		return
+	}
+
+	nx, ny := l.lastNX, l.lastNY
+	vp.grabFrameThen(func(frame image.Image, err error) {
+		if l.lastNX != nx || l.lastNY != ny {
+			return // the cursor moved on while grabbing
+		}
+		if err != nil {
+			log.Printf("loupe: %v", err)
+			label.SetText(fmt.Sprintf("%s: %v", vp.title, err))
+			return
+		}
+		target.Image = cropRegion(frame, x, y, l.regionSize)
+		target.Refresh()
+		label.SetText(fmt.Sprintf("%s @ %d,%d (%dx)", vp.title, x, y, l.magnify))
+	})
+}
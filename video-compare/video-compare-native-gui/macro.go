@@ -0,0 +1,457 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// macroCommand is one step of a navigation macro. side is nil when the step
+// applies to both players.
+type macroCommand struct {
+	name  string // seek, step, snapshot, play, pause or wait
+	side  *int
+	value float64 // seconds for seek and wait, frames for step
+}
+
+func (c macroCommand) String() string {
+	words := []string{c.name}
+	if c.side != nil {
+		words = append(words, sideNames[*c.side])
+	}
+	switch c.name {
+	case "seek":
+		words = append(words, formatMacroTime(c.value))
+	case "step":
+		words = append(words, strconv.Itoa(int(c.value)))
+	case "wait":
+		words = append(words, strconv.FormatFloat(c.value, 'f', -1, 64))
+	}
+	return strings.Join(words, " ")
+}
+
+var sideNames = [2]string{"left", "right"}
+
+// formatMacroTime writes seconds as HH:MM:SS.mmm, which parseElapsed reads
+// back exactly.
+func formatMacroTime(seconds float64) string {
+	ms := int(math.Round(seconds * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// parseMacro reads a macro: commands separated by newlines or semicolons,
+// such as "seek 00:01:30; step 3; snapshot". A command may name the left
+// or right player after its name, otherwise it applies to both. Seek times
+// are elapsed time, whatever the time display shows. Text after # is a
+// comment.
+func parseMacro(text string) ([]macroCommand, error) {
+	var commands []macroCommand
+	for n, line := range strings.Split(text, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		for _, stmt := range strings.Split(line, ";") {
+			words := strings.Fields(strings.ToLower(stmt))
+			if len(words) == 0 {
+				continue
+			}
+			cmd, err := parseMacroCommand(words)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %q: %w", n+1, strings.TrimSpace(stmt), err)
+			}
+			commands = append(commands, cmd)
+		}
+	}
+	return commands, nil
+}
+
+func parseMacroCommand(words []string) (macroCommand, error) {
+	cmd := macroCommand{name: words[0]}
+	args := words[1:]
+	if len(args) > 0 {
+		for side, name := range sideNames {
+			if args[0] == name {
+				cmd.side = &side
+				args = args[1:]
+				break
+			}
+		}
+	}
+
+	switch cmd.name {
+	case "seek":
+		if len(args) != 1 {
+			return cmd, fmt.Errorf("seek takes a time")
+		}
+		seconds, ok := parseElapsed(args[0])
+		if !ok {
+			return cmd, fmt.Errorf("bad time, use HH:MM:SS, MM:SS or seconds")
+		}
+		cmd.value = seconds
+	case "step":
+		cmd.value = 1
+		if len(args) > 1 {
+			return cmd, fmt.Errorf("step takes a frame count")
+		}
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n == 0 {
+				return cmd, fmt.Errorf("bad frame count")
+			}
+			cmd.value = float64(n)
+		}
+	case "wait":
+		if cmd.side != nil || len(args) != 1 {
+			return cmd, fmt.Errorf("wait takes a number of seconds")
+		}
+		seconds, err := strconv.ParseFloat(args[0], 64)
+		if err != nil || seconds < 0 || seconds > 3600 {
+			return cmd, fmt.Errorf("bad number of seconds")
+		}
+		cmd.value = seconds
+	case "snapshot", "play", "pause":
+		if len(args) != 0 {
+			return cmd, fmt.Errorf("%s takes no arguments", cmd.name)
+		}
+	default:
+		return cmd, fmt.Errorf("unknown command, use seek, step, snapshot, play, pause or wait")
+	}
+	return cmd, nil
+}
+
+// macroPanel records navigation into a macro and replays macros, typed,
+// recorded or loaded from a file, through the same player methods the
+// controls use.
+type macroPanel struct {
+	app *VideoCompareApp
+
+	recording bool
+	running   context.CancelFunc
+
+	script    *widget.Entry
+	recordBtn *widget.Button
+	runBtn    *widget.Button
+	status    *widget.Label
+}
+
+func newMacroPanel(app *VideoCompareApp) *macroPanel {
+	return &macroPanel{app: app}
+}
+
+func (mp *macroPanel) content() fyne.CanvasObject {
+	mp.script = widget.NewMultiLineEntry()
+	mp.script.SetPlaceHolder("seek 00:01:30\nstep 3\nsnapshot")
+	mp.script.Wrapping = fyne.TextWrapOff
+	mp.status = widget.NewLabel("Record navigation, or type commands: seek [left|right] TIME, step [left|right] N, snapshot [left|right], play, pause, wait SECONDS")
+	mp.status.Wrapping = fyne.TextWrapWord
+
+	mp.recordBtn = widget.NewButtonWithIcon("Record", theme.MediaRecordIcon(), mp.toggleRecording)
+	mp.runBtn = widget.NewButtonWithIcon("Run", theme.MediaPlayIcon(), mp.run)
+	loadBtn := widget.NewButtonWithIcon("Load…", theme.FolderOpenIcon(), mp.load)
+	saveBtn := widget.NewButtonWithIcon("Save…", theme.DocumentSaveIcon(), mp.save)
+	toolbar := container.NewHBox(mp.recordBtn, mp.runBtn, loadBtn, saveBtn)
+
+	return container.NewBorder(toolbar, mp.status, nil, nil, mp.script)
+}
+
+func (mp *macroPanel) toggleRecording() {
+	mp.recording = !mp.recording
+	if mp.recording {
+		mp.recordBtn.SetText("Stop Recording")
+		mp.recordBtn.SetIcon(theme.MediaStopIcon())
+		mp.status.SetText("Recording seeks, frame steps, snapshots and play/pause…")
+	} else {
+		mp.recordBtn.SetText("Record")
+		mp.recordBtn.SetIcon(theme.MediaRecordIcon())
+		mp.status.SetText("Recording stopped")
+	}
+}
+
+// record appends cmd to the script while recording. Consecutive steps add
+// up, and a seek replaces the seeks just before it that it overrides, so
+// scrubbing records only where it ended.
+func (mp *macroPanel) record(cmd macroCommand) {
+	if !mp.recording || mp.script == nil {
+		return
+	}
+	lines := strings.Split(strings.TrimRight(mp.script.Text, "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		lines = nil
+	}
+	switch cmd.name {
+	case "step":
+		if n := len(lines); n > 0 {
+			if last, err := parseMacro(lines[n-1]); err == nil && len(last) == 1 && last[0].name == "step" && sameSide(last[0], cmd) {
+				cmd.value += last[0].value
+				lines = lines[:n-1]
+			}
+		}
+	case "seek":
+		// Seeks of the other player may be interleaved, as with the sync lock
+		trailing := len(lines)
+		for trailing > 0 {
+			last, err := parseMacro(lines[trailing-1])
+			if err != nil || len(last) != 1 || last[0].name != "seek" {
+				break
+			}
+			trailing--
+		}
+		kept := lines[:trailing]
+		for _, line := range lines[trailing:] {
+			if last, _ := parseMacro(line); !overrides(cmd, last[0]) {
+				kept = append(kept, line)
+			}
+		}
+		lines = kept
+	}
+	if cmd.name != "step" || cmd.value != 0 {
+		lines = append(lines, cmd.String())
+	}
+	mp.script.SetText(strings.Join(lines, "\n"))
+}
+
+func sameSide(a, b macroCommand) bool {
+	if a.side == nil || b.side == nil {
+		return a.side == nil && b.side == nil
+	}
+	return *a.side == *b.side
+}
+
+// overrides reports whether running a after b leaves b without effect.
+func overrides(a, b macroCommand) bool {
+	return a.side == nil || sameSide(a, b)
+}
+
+// recordSide records cmd for vp alone.
+func (mp *macroPanel) recordSide(vp *VideoPlayer, cmd macroCommand) {
+	side := mp.app.side(vp)
+	cmd.side = &side
+	mp.record(cmd)
+}
+
+// run replays the script, asking first for the folder snapshots go to when
+// it takes any.
+func (mp *macroPanel) run() {
+	if mp.running != nil {
+		mp.running()
+		return
+	}
+	commands, err := parseMacro(mp.script.Text)
+	if err != nil {
+		mp.status.SetText(err.Error())
+		return
+	}
+	if len(commands) == 0 {
+		mp.status.SetText("Nothing to run")
+		return
+	}
+	if mp.recording {
+		mp.toggleRecording()
+	}
+	for _, c := range commands {
+		if c.name == "snapshot" {
+			dialog.ShowFolderOpen(func(folder fyne.ListableURI, err error) {
+				if err == nil && folder != nil {
+					mp.start(commands, folder.Path())
+				}
+			}, mp.app.window)
+			return
+		}
+	}
+	mp.start(commands, "")
+}
+
+// start replays commands in the background, writing snapshots to dir.
+func (mp *macroPanel) start(commands []macroCommand, dir string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	mp.running = cancel
+	mp.runBtn.SetText("Cancel")
+	mp.runBtn.SetIcon(theme.CancelIcon())
+
+	go func() {
+		snapshots, err := mp.replay(ctx, commands, dir)
+		fyne.Do(func() {
+			cancel()
+			mp.running = nil
+			mp.runBtn.SetText("Run")
+			mp.runBtn.SetIcon(theme.MediaPlayIcon())
+			switch {
+			case err != nil:
+				log.Printf("macro: %v", err)
+				mp.status.SetText(err.Error())
+			case ctx.Err() != nil:
+				mp.status.SetText("Cancelled")
+			case snapshots > 0:
+				mp.status.SetText(fmt.Sprintf("Done, wrote %d snapshots to %s", snapshots, dir))
+			default:
+				mp.status.SetText("Done")
+			}
+		})
+	}()
+}
+
+// replay runs commands one after another on the UI goroutine, letting
+// each seek settle before the next, and returns how many snapshots it
+// wrote.
+func (mp *macroPanel) replay(ctx context.Context, commands []macroCommand, dir string) (int, error) {
+	snapshots := 0
+	for i, c := range commands {
+		if ctx.Err() != nil {
+			return snapshots, nil
+		}
+		fyne.Do(func() { mp.status.SetText(fmt.Sprintf("%d/%d: %s", i+1, len(commands), c)) })
+
+		var err error
+		switch c.name {
+		case "wait":
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Duration(c.value * float64(time.Second))):
+			}
+			continue
+		case "snapshot":
+			var img image.Image
+			var name string
+			var render func() (image.Image, error)
+			fyne.DoAndWait(func() { render, name, err = mp.snapshot(c) })
+			if err == nil {
+				img, err = render()
+			}
+			if err == nil {
+				name = fmt.Sprintf("macro-%02d-%s%s", snapshots+1, name, defaultImageFormat().ext())
+				err = writeImage(filepath.Join(dir, name), img)
+			}
+			if err == nil {
+				snapshots++
+			}
+		default:
+			fyne.DoAndWait(func() { err = mp.navigate(c) })
+			time.Sleep(frameSettleDelay)
+		}
+		if err != nil {
+			return snapshots, fmt.Errorf("step %d (%s): %w", i+1, c, err)
+		}
+	}
+	return snapshots, nil
+}
+
+// targets returns the loaded players c applies to.
+func (mp *macroPanel) targets(c macroCommand) []*VideoPlayer {
+	players := []*VideoPlayer{mp.app.leftPlayer, mp.app.rightPlayer}
+	if c.side != nil {
+		players = players[*c.side : *c.side+1]
+	}
+	var loaded []*VideoPlayer
+	for _, vp := range players {
+		if vp.canPlay() {
+			loaded = append(loaded, vp)
+		}
+	}
+	return loaded
+}
+
+func (mp *macroPanel) navigate(c macroCommand) error {
+	players := mp.targets(c)
+	if len(players) == 0 {
+		return fmt.Errorf("no video loaded")
+	}
+	for _, vp := range players {
+		switch c.name {
+		case "seek":
+			if c.value > vp.duration {
+				return fmt.Errorf("%s ends at %s", vp.title, formatTime(vp.duration))
+			}
+			vp.seekTo(c.value)
+		case "step":
+			if !vp.canStep() {
+				return fmt.Errorf("%s can't be stepped frame by frame", vp.title)
+			}
+			vp.stepFrame(int(c.value))
+		case "play":
+			vp.play()
+		case "pause":
+			vp.pause()
+		}
+	}
+	return nil
+}
+
+// snapshot returns a function rendering what a snapshot command captures,
+// which may run on any goroutine: both players side by side when it names
+// neither and both are loaded, otherwise one player's frame as the
+// Snapshot button saves it. It also returns a file name stem.
+func (mp *macroPanel) snapshot(c macroCommand) (func() (image.Image, error), string, error) {
+	players := mp.targets(c)
+	switch {
+	case len(players) == 0:
+		return nil, "", fmt.Errorf("no video loaded")
+	case len(players) == 2:
+		export := mp.app.sideBySideExporter()
+		return func() (image.Image, error) { return export() }, "side-by-side-" + players[0].timecodeFileStamp(), nil
+	}
+	vp := players[0]
+	export := mp.app.frameExporter(vp)
+	var caption []string
+	if captionEnabled() {
+		caption = vp.captionLines()
+	}
+	render := func() (image.Image, error) {
+		img, err := export()
+		if err != nil {
+			return nil, err
+		}
+		if caption != nil {
+			img = addCaptionBar(img, caption)
+		}
+		return img, nil
+	}
+	return render, sideNames[mp.app.side(vp)] + "-" + vp.timecodeFileStamp(), nil
+}
+
+func (mp *macroPanel) load() {
+	fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			dialog.ShowError(err, mp.app.window)
+			return
+		}
+		mp.script.SetText(string(data))
+		if _, err := parseMacro(mp.script.Text); err != nil {
+			mp.status.SetText(err.Error())
+		} else {
+			mp.status.SetText("Loaded " + reader.URI().Name())
+		}
+	}, mp.app.window)
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{".txt", ".macro"}))
+	fd.Show()
+}
+
+func (mp *macroPanel) save() {
+	fd := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+		if _, err := io.WriteString(writer, strings.TrimRight(mp.script.Text, "\n")+"\n"); err != nil {
+			dialog.ShowError(err, mp.app.window)
+		}
+	}, mp.app.window)
+	fd.SetFileName("navigation.macro")
+	fd.Show()
+}
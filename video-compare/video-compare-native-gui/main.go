@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"image"
 	"log"
-	"path/filepath"
-	"strconv"
-	"strings"
+	"math"
+	"sync"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -17,8 +19,12 @@ import (
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	libvlc "github.com/adrg/libvlc-go/v3"
+	"videocompare"
 )
 
+// VideoPlayer state is owned by the Fyne UI goroutine. Background work such
+// as the progress ticker or stream reconnection must hand updates back with
+// fyne.Do instead of touching the fields directly.
 type VideoPlayer struct {
 	player *libvlc.Player
 	media  *libvlc.Media
@@ -27,13 +33,157 @@ type VideoPlayer struct {
 
 	// UI elements
 	fileLabel   *widget.Label
+	pathLabel   *widget.Label // Full path of the file, subdued under fileLabel
+	noticeLabel *widget.Label // Explains why controls are disabled for this file
 	timeLabel   *widget.Label
 	statsLabel  *widget.Label
-	progressBar *widget.Slider
+	progressBar *seekBar
+	ticks       *canvas.Raster    // marks under the progress bar
 	videoCanvas *canvas.Rectangle // Video display area
+	videoFit    *fyne.Container   // Fits videoCanvas to the area's aspect
+	display     *videoArea        // Pointer-aware wrapper around videoCanvas
+	stillView   *canvas.Image     // Shown instead of videoCanvas for a still reference
+	zoomView    *canvas.Image     // Zoomed crop shown over the video while zoomed in
+	fieldView   *canvas.Image     // Separated fields shown over the video
+
+	// Pixel storage format probed with ffprobe
+	format *videoFormat
+
+	// Static HDR metadata probed with ffprobe
+	hdr *hdrMetadata
+
+	// Frames in the video stream and its duration in seconds probed with
+	// ffprobe, 0 until known
+	frameCount     int
+	streamDuration float64
+
+	// Presentation timestamps of every frame in seconds, nil until probed
+	frameTimes []float64
+
+	// Files joined into the loaded timeline, nil for a single file
+	segments []timelineSegment
+
+	// Releases the pin keeping the loaded file in the cache, nil when
+	// nothing is pinned
+	unpinCache func()
+
+	// Parent directories shown with the file name, to tell it apart from
+	// the other player's file of the same name
+	nameDirs int
+
+	// Original of a file re-encoded for libvlc, nil when shown as is
+	transcoded   *transcodedSource
+	transcodeBtn *widget.Button // Offers the re-encode, nil without ffmpeg
+
+	// Preview-only brightness/contrast/saturation/gamma
+	adjust videoAdjust
+	levels levelsConversion
+
+	// Field cadence detected by ffmpeg and whether IVTC is applied to it
+	cadence       string
+	cadenceCancel context.CancelFunc
+	ivtc          bool
+
+	// Offset between audio and video within the file, nil until analyzed
+	avSync       *avSync
+	avSyncCancel context.CancelFunc
+
+	// Decoder and hardware backend ffmpeg picks for the file, nil until probed
+	decoder *decoderInfo
+
+	// Embedded SMPTE start timecode, "" when the file has none, and whether
+	// times are shown and entered in it
+	startTimecode  string
+	sourceTimecode *bool
+
+	// Reference still image loaded instead of a video
+	still image.Image
+
+	// Set while the progress bar is moved from code rather than by the user,
+	// so the change isn't mistaken for a seek request
+	updatingProgress bool
+
+	// Stops the progress ticker, which only runs while playing
+	tickerStop chan struct{}
+
+	// Overlay drawn on top of the video canvas
+	overlay      *fyne.Container
+	timecodeText *canvas.Text
+	timecodeBox  *canvas.Rectangle
+	burnIn       *burnInSettings
+
+	// Drawings over the current frame, in frame coordinates; zoom maps
+	// them onto the display
+	zoom            *zoomPanel
+	annotations     []*annotation
+	annotationLayer *canvas.Raster
+	measurement     *measurement
+	roiOutline      *[2][2]float64 // Region of interest in display coordinates
+	onSeek          func()
+	onSeekEntered   func()
+	onPosition      func() // after the position is polled during playback
+	onStateChange   func()
+
+	// Decode error found at the current frame, covering the video
+	decodeCheck       context.CancelFunc
+	decodeFault       *decodeError
+	decodeErrorView   *fyne.Container
+	decodeErrorTitle  *canvas.Text
+	decodeErrorDetail *canvas.Text
+
+	// Background analysis of the loaded media's tracks
+	parsing       bool
+	parseTimedOut bool
+	parseProgress *widget.ProgressBarInfinite
+	opening       bool
+	loadSeq       int
+	detachParse   func()
+	afterParse    []func()
+	onParsed      func()
+
+	// Controls enabled or disabled depending on what the loaded file supports
+	playBtn          *widget.Button
+	pauseBtn         *widget.Button
+	playbackControls []fyne.Disableable
+	seekControls     []fyne.Disableable
+	timeInput        *widget.Entry
+	seekError        *widget.Label
+	frameControls    []fyne.Disableable
+
+	// In/out points confining playback to part of the clip; an end of 0
+	// means the end of the clip
+	rangeStart float64
+	rangeEnd   float64
+	rangeLabel *widget.Label
+
+	// Start over at the end of the clip or range instead of stopping
+	loop bool
+
+	// Last grabbed frame, shared by every pixel-reading tool. Grabs run on
+	// background goroutines, hence the lock.
+	grabMu   sync.Mutex
+	lastGrab grabbedFrame
+
+	// Network stream reconnection
+	reconnectCancel    chan struct{}
+	cancelReconnectBtn *widget.Button
+
+	// Adaptive stream renditions
+	variants      []streamVariant
+	variant       *streamVariant
+	variantSelect *widget.Select
+
+	// Audio tracks of the loaded file and the index of the one selected
+	audioTracks []audioTrack
+	audioTrack  int
+	audioSelect *widget.Select
+
+	// Playback state indicator and when libvlc last reported buffering
+	stateLabel    *widget.Label
+	lastBuffering time.Time
 
 	// State
-	isPlaying   bool
+	state       playerState
 	currentTime float64
 	duration    float64
 	fps         float64
@@ -41,21 +191,138 @@ type VideoPlayer struct {
 	height      int
 	bitrate     int
 	codec       string
+	hasVideo    bool
+	hasAudio    bool
+	volume      int // libvlc volume in percent, reapplied whenever playback starts
+	orientation libvlc.VideoOrientation
+	transform   string // transform filter type applied to match the other clip
 }
 
 type VideoCompareApp struct {
 	leftPlayer  *VideoPlayer
 	rightPlayer *VideoPlayer
 
+	// Which of ffmpeg and ffprobe were found at startup
+	tools videocompare.Capabilities
+
 	// Common controls
 	syncBtn     *widget.Button
 	playAllBtn  *widget.Button
 	pauseAllBtn *widget.Button
 	stopAllBtn  *widget.Button
 
+	// Progress bars linked at a fixed offset while locked
+	syncLockCheck  *widget.Check
+	syncPointBtn   *widget.Button
+	syncLocked     bool
+	syncLockOffset float64
+
 	// Frame controls
 	prevFrameBtn *widget.Button
 	nextFrameBtn *widget.Button
+	wheelScroll  wheelScroll
+
+	// J/K/L keyboard shuttle
+	shuttle *shuttleControl
+
+	// Position mapping between clips running at different speeds
+	contentSync *contentSync
+
+	// Inspection tools
+	loupe       *loupe
+	loupeCheck  *widget.Check
+	scopes      *scopesPanel
+	scopesCheck *widget.Check
+	zoom        *zoomPanel
+	roi         *roiPanel
+	decodeErrs  *decodeErrorsPanel
+	worstFrames *worstFramesPanel
+	blink       *blinkComparator
+	overlay     *overlayComposite
+
+	// Audio waveforms and loudness normalization
+	audio    *audioPanel
+	loudness *loudnessPanel
+
+	// Comparing audio files, with audio views in place of the video
+	audioMode *audioMode
+
+	// Per-frame coded sizes of both clips
+	bitrate *bitratePanel
+
+	// Pausing while the window is in the background
+	focus *focusPause
+
+	// Recorded and scripted navigation
+	macro *macroPanel
+
+	// Caption track comparison
+	captions *captionsPanel
+
+	// Keeps both players on the same audio track index
+	audioTrackSync *widget.Check
+
+	// Duplicate-frame detection
+	duplicates *duplicatesPanel
+
+	// Decode speed comparison
+	benchmark *benchmarkPanel
+
+	// Inverse telecine for clips with 3:2 pulldown
+	ivtc      bool
+	ivtcCheck *widget.Check
+	fields    *fieldPanel
+
+	// PTS, DTS and picture type of the current frames
+	frameInfo *frameInfoReadout
+
+	// Times shown as the files' own timecode, and lining them up by it
+	sourceTimecode   bool
+	alignTimecodeBtn *widget.Button
+
+	// Timecode burn-in
+	burnIn        burnInSettings
+	burnInCheck   *widget.Check
+	burnInCorner  *widget.Select
+	sideBySideBtn *widget.Button
+	heatmapBtn    *widget.Button
+	wipeSweepBtn  *widget.Button
+
+	// Clipboard
+	copySideBySideBtn *widget.Button
+
+	// Explains why the comparison buttons are disabled
+	comparisonHint *widget.Label
+
+	// Side labels drawn on every export
+	labels exportLabels
+
+	// Drawing tools
+	annotator *annotator
+
+	// Review notes and bookmarks
+	notes     *notesPanel
+	bookmarks *bookmarksPanel
+
+	// Pipeline provenance of the compared files
+	provenance *provenancePanel
+
+	// Log of past comparisons
+	history *historyPanel
+
+	// Display settings remembered per file
+	fileSettings *fileSettingsStore
+
+	// Metadata diff table
+	metadataTable *widget.Table
+	metadataRows  []metadataRow
+
+	// PSNR/SSIM of a video frame against a still reference
+	stillMetricsLabel *widget.Label
+	stillPending      int // bumped on every refresh so stale results are dropped
+
+	// The last quality scores measured for the loaded files, for the report
+	scores []reportScore
 
 	// Stats display
 	statsDisplay *widget.TextGrid
@@ -63,34 +330,93 @@ type VideoCompareApp struct {
 	window fyne.Window
 }
 
+// defaultProgressInterval is how often the position display is refreshed
+// during playback.
+const defaultProgressInterval = 100 * time.Millisecond
+
+var progressInterval = defaultProgressInterval
+
 func main() {
-	// Initialize libVLC
-	if err := libvlc.Init(""); err != nil {
-		log.Fatalf("failed to init libvlc: %v", err)
+	flag.DurationVar(&progressInterval, "progress-interval", defaultProgressInterval,
+		"how often the playback position display is refreshed")
+	flag.Parse()
+
+	// Files opened with video-compare from the file manager go to the
+	// instance already running, if there is one
+	paths := absolutePaths(flag.Args())
+	if len(paths) > 0 && forwardToRunning(paths) {
+		return
 	}
-	defer libvlc.Release()
 
-	myApp := app.New()
+	myApp := app.NewWithID(appID)
 	myApp.SetIcon(theme.ComputerIcon())
+	setupLanguage(myApp.Preferences())
+	applyJobLimit(myApp.Preferences())
 
-	window := myApp.NewWindow("Video Compare - Advanced Side-by-Side Comparison")
-	window.Resize(fyne.NewSize(1600, 1000))
-	window.CenterOnScreen()
+	// Initialize libVLC, explaining how to install VLC if that fails
+	if err := initLibVLC(); err != nil {
+		log.Printf("failed to init libvlc: %v", err)
+		showVLCMissing(myApp, err)
+		return
+	}
+	defer libvlc.Release()
+
+	window := myApp.NewWindow(tr("Video Compare - Advanced Side-by-Side Comparison"))
+	restoreWindowGeometry(window, myApp.Preferences())
+	rememberWindowGeometry(window, myApp.Preferences())
 
 	app := &VideoCompareApp{
 		window: window,
 	}
 
+	app.detectTools()
 	app.initializePlayers()
 	app.createUI()
 	app.setupEventHandlers()
+	stopListening := app.listenForOpenRequests()
+	defer stopListening()
+	app.openPaths(paths, nil)
 
 	window.ShowAndRun()
 }
 
 func (app *VideoCompareApp) initializePlayers() {
-	app.leftPlayer = newVideoPlayer("Left Video")
-	app.rightPlayer = newVideoPlayer("Right Video")
+	app.leftPlayer = newVideoPlayer(tr("Left Video"))
+	app.rightPlayer = newVideoPlayer(tr("Right Video"))
+	app.labels = loadExportLabels(fyne.CurrentApp().Preferences())
+	app.leftPlayer.burnIn = &app.burnIn
+	app.rightPlayer.burnIn = &app.burnIn
+	app.leftPlayer.sourceTimecode = &app.sourceTimecode
+	app.rightPlayer.sourceTimecode = &app.sourceTimecode
+	app.loupe = newLoupe(app)
+	app.scopes = newScopesPanel(app)
+	app.zoom = newZoomPanel(app)
+	app.leftPlayer.zoom = app.zoom
+	app.rightPlayer.zoom = app.zoom
+	app.roi = newROIPanel(app)
+	app.decodeErrs = newDecodeErrorsPanel(app)
+	app.worstFrames = newWorstFramesPanel(app)
+	app.blink = newBlinkComparator(app)
+	app.overlay = newOverlayComposite(app)
+	app.fields = newFieldPanel(app)
+	app.shuttle = newShuttleControl(app)
+	app.contentSync = newContentSync(app)
+	app.frameInfo = newFrameInfoReadout(app)
+	app.audio = newAudioPanel(app)
+	app.audioMode = newAudioMode(app)
+	app.bitrate = newBitratePanel(app)
+	app.focus = newFocusPause(app)
+	app.macro = newMacroPanel(app)
+	app.captions = newCaptionsPanel(app)
+	app.loudness = newLoudnessPanel(app)
+	app.duplicates = newDuplicatesPanel(app)
+	app.benchmark = newBenchmarkPanel(app)
+	app.annotator = newAnnotator(app)
+	app.notes = newNotesPanel(app)
+	app.provenance = newProvenancePanel(app)
+	app.bookmarks = newBookmarksPanel(app)
+	app.history = newHistoryPanel(app)
+	app.fileSettings = newFileSettingsStore(app)
 }
 
 func newVideoPlayer(title string) *VideoPlayer {
@@ -99,44 +425,115 @@ func newVideoPlayer(title string) *VideoPlayer {
 		log.Fatalf("failed to create vlc player: %v", err)
 	}
 
-	return &VideoPlayer{
+	vp := &VideoPlayer{
 		player:      player,
 		title:       title,
-		fileLabel:   widget.NewLabel("No file selected"),
+		fileLabel:   widget.NewLabel(tr("No file selected")),
+		noticeLabel: widget.NewLabel(""),
 		timeLabel:   widget.NewLabel("00:00 / 00:00"),
-		statsLabel:  widget.NewLabel("No video loaded"),
-		progressBar: widget.NewSlider(0, 100),
+		statsLabel:  widget.NewLabel(tr("No video loaded")),
+		progressBar: newSeekBar(),
 		videoCanvas: canvas.NewRectangle(theme.BackgroundColor()),
+		volume:      100,
+		adjust:      defaultAdjust,
+		levels:      levelsAsEncoded,
 	}
+	vp.videoFit = container.New(&videoFitLayout{player: vp}, vp.videoCanvas)
+	vp.display = newVideoArea(vp, container.NewStack(vp.videoFit, vp.newStillView(), vp.newZoomView(), vp.newFieldView(), vp.newAnnotationLayer(), vp.newOverlay(), vp.newDecodeErrorView()))
+	vp.noticeLabel.Importance = widget.WarningImportance
+	vp.noticeLabel.Hide()
+	vp.cancelReconnectBtn = widget.NewButtonWithIcon(tr("Cancel Reconnect"), theme.CancelIcon(), vp.userCancelReconnect)
+	vp.cancelReconnectBtn.Hide()
+	vp.variantSelect = widget.NewSelect(nil, nil)
+	vp.variantSelect.PlaceHolder = tr("Select variant")
+	vp.variantSelect.Hide()
+	vp.watchPlayerEvents()
+	return vp
 }
 
 func (app *VideoCompareApp) createUI() {
 	// Create file selection buttons
-	leftFileBtn := widget.NewButtonWithIcon("Choose Left Video", theme.FolderOpenIcon(), func() {
+	leftFileBtn := widget.NewButtonWithIcon(tr("Choose Left Video"), theme.FolderOpenIcon(), func() {
 		app.selectVideoFile(app.leftPlayer)
 	})
 
-	rightFileBtn := widget.NewButtonWithIcon("Choose Right Video", theme.FolderOpenIcon(), func() {
+	rightFileBtn := widget.NewButtonWithIcon(tr("Choose Right Video"), theme.FolderOpenIcon(), func() {
 		app.selectVideoFile(app.rightPlayer)
 	})
 
+	// Network stream buttons
+	leftURLBtn := widget.NewButtonWithIcon(tr("Open URL"), theme.MediaVideoIcon(), func() {
+		app.openURL(app.leftPlayer)
+	})
+
+	rightURLBtn := widget.NewButtonWithIcon(tr("Open URL"), theme.MediaVideoIcon(), func() {
+		app.openURL(app.rightPlayer)
+	})
+
+	// Unloading a single player
+	leftClearBtn := widget.NewButtonWithIcon(tr("Clear"), theme.ContentClearIcon(), func() {
+		app.clearPlayer(app.leftPlayer)
+	})
+
+	rightClearBtn := widget.NewButtonWithIcon(tr("Clear"), theme.ContentClearIcon(), func() {
+		app.clearPlayer(app.rightPlayer)
+	})
+
 	// Individual player controls
 	leftControls := app.createPlayerControls(app.leftPlayer, "Left")
 	rightControls := app.createPlayerControls(app.rightPlayer, "Right")
 
 	// Common controls
-	app.syncBtn = widget.NewButtonWithIcon("Sync Videos", theme.MediaSkipNextIcon(), app.syncVideos)
-	app.playAllBtn = widget.NewButtonWithIcon("Play All", theme.MediaPlayIcon(), app.playAll)
-	app.pauseAllBtn = widget.NewButtonWithIcon("Pause All", theme.MediaPauseIcon(), app.pauseAll)
-	app.stopAllBtn = widget.NewButtonWithIcon("Stop All", theme.MediaStopIcon(), app.stopAll)
+	app.syncBtn = widget.NewButtonWithIcon(tr("Sync Videos"), theme.MediaSkipNextIcon(), app.syncVideos)
+	app.syncPointBtn = widget.NewButtonWithIcon(tr("Set Sync Point"), theme.ContentAddIcon(), app.calibrateSync)
+	app.playAllBtn = widget.NewButtonWithIcon(tr("Play All"), theme.MediaPlayIcon(), func() {
+		app.playAll()
+		app.macro.record(macroCommand{name: "play"})
+	})
+	app.pauseAllBtn = widget.NewButtonWithIcon(tr("Pause All"), theme.MediaPauseIcon(), func() {
+		app.pauseAll()
+		app.macro.record(macroCommand{name: "pause"})
+	})
+	app.stopAllBtn = widget.NewButtonWithIcon(tr("Stop All"), theme.MediaStopIcon(), app.stopAll)
 
 	// Frame controls
-	app.prevFrameBtn = widget.NewButtonWithIcon("Previous Frame", theme.MediaSkipPreviousIcon(), app.previousFrame)
-	app.nextFrameBtn = widget.NewButtonWithIcon("Next Frame", theme.MediaSkipNextIcon(), app.nextFrame)
+	app.prevFrameBtn = widget.NewButtonWithIcon(tr("Previous Frame"), theme.MediaSkipPreviousIcon(), app.previousFrame)
+	app.nextFrameBtn = widget.NewButtonWithIcon(tr("Next Frame"), theme.MediaSkipNextIcon(), app.nextFrame)
+
+	// Inspection tools
+	app.loupeCheck = widget.NewCheck(tr("Loupe"), app.loupe.setEnabled)
+	app.audioTrackSync = widget.NewCheck(tr("Keep both players on the same audio track"), app.syncAudioTracks)
+	app.scopesCheck = widget.NewCheck(tr("Scopes"), app.scopes.setEnabled)
+	blinkCheck, blinkRate := app.blink.controls()
+	app.ivtcCheck = widget.NewCheck(tr("Inverse Telecine"), func(enabled bool) {
+		app.setInverseTelecine(enabled)
+		app.fileSettings.remember(app.leftPlayer)
+		app.fileSettings.remember(app.rightPlayer)
+	})
+
+	// Timecode burn-in
+	app.burnInCheck = widget.NewCheck(tr("Timecode"), func(enabled bool) {
+		app.burnIn.enabled = enabled
+		app.refreshOverlays()
+	})
+	app.burnInCorner = widget.NewSelect(overlayCornerNames, func(name string) {
+		app.burnIn.corner = parseOverlayCorner(name)
+		app.refreshOverlays()
+	})
+	app.burnInCorner.SetSelected(app.burnIn.corner.String())
+	sourceTimecodeCheck := app.newSourceTimecodeCheck()
+	app.alignTimecodeBtn = widget.NewButton(tr("Align by Timecode"), app.alignByTimecode)
+	app.alignTimecodeBtn.Disable()
+	app.sideBySideBtn = widget.NewButtonWithIcon(tr("Save Side-by-Side"), theme.DocumentSaveIcon(), app.saveSideBySide)
+	app.copySideBySideBtn = widget.NewButtonWithIcon(tr("Copy Side-by-Side"), theme.ContentCopyIcon(), app.copySideBySide)
+	app.heatmapBtn = widget.NewButtonWithIcon(tr("Export Diff Heatmap"), theme.DocumentSaveIcon(), app.exportDiffHeatmap)
+	app.wipeSweepBtn = widget.NewButtonWithIcon(tr("Export Wipe Sweep"), theme.MediaVideoIcon(), app.exportWipeSweep)
 
 	// Common controls container
 	commonControls := container.NewHBox(
 		app.syncBtn,
+		app.syncPointBtn,
+		app.newSyncLockCheck(),
 		widget.NewSeparator(),
 		app.playAllBtn,
 		app.pauseAllBtn,
@@ -144,73 +541,190 @@ func (app *VideoCompareApp) createUI() {
 		widget.NewSeparator(),
 		app.prevFrameBtn,
 		app.nextFrameBtn,
+		app.shuttle.indicator(),
+		widget.NewSeparator(),
+		app.loupeCheck,
+		app.scopesCheck,
+		blinkCheck,
+		blinkRate,
+		app.overlay.control(),
+		app.ivtcCheck,
+		app.fields.selector(),
+		app.burnInCheck,
+		app.burnInCorner,
+		sourceTimecodeCheck,
+		app.alignTimecodeBtn,
+		app.sideBySideBtn,
+		app.copySideBySideBtn,
+		app.heatmapBtn,
+		app.wipeSweepBtn,
 	)
 
+	app.comparisonHint = widget.NewLabel("")
+	app.comparisonHint.Importance = widget.LowImportance
+	app.comparisonHint.Hide()
+
+	app.stillMetricsLabel = widget.NewLabel("")
+	app.stillMetricsLabel.Hide()
+
 	// Stats display
 	app.statsDisplay = widget.NewTextGrid()
-	app.statsDisplay.SetText("Video Statistics\n\nLeft: No video loaded\nRight: No video loaded")
+	app.statsDisplay.SetText(trf("Video Statistics\n\nLeft:\n%s\n\nRight:\n%s", tr("No video loaded"), tr("No video loaded")))
 
-	// Left panel
-	leftPanel := container.NewVBox(
-		leftFileBtn,
+	// Left panel; the video area takes the space left by the controls
+	leftPanel := container.NewBorder(container.NewVBox(
+		container.NewGridWithColumns(3, leftFileBtn, leftURLBtn, leftClearBtn),
 		app.leftPlayer.fileLabel,
-		app.leftPlayer.videoCanvas, // Video display area
+		app.leftPlayer.newPathLabel(),
+		app.leftPlayer.newParseProgress(),
+		container.NewBorder(nil, nil, nil, app.newTranscodeButton(app.leftPlayer), app.leftPlayer.noticeLabel),
+		app.leftPlayer.variantSelect,
+		app.createAudioTrackSelect(app.leftPlayer),
+	), container.NewVBox(
 		app.leftPlayer.progressBar,
-		app.leftPlayer.timeLabel,
+		app.newTimelineTicks(app.leftPlayer),
+		container.NewBorder(nil, nil, app.leftPlayer.timeLabel, app.leftPlayer.newStateLabel()),
+		app.frameInfo.label(app.leftPlayer),
 		leftControls,
+		app.createRangeControls(app.leftPlayer),
 		app.leftPlayer.statsLabel,
-	)
+	), nil, nil, app.audioMode.wrap(app.leftPlayer))
 
-	// Right panel
-	rightPanel := container.NewVBox(
-		rightFileBtn,
+	// Right panel; the video area takes the space left by the controls
+	rightPanel := container.NewBorder(container.NewVBox(
+		container.NewGridWithColumns(3, rightFileBtn, rightURLBtn, rightClearBtn),
 		app.rightPlayer.fileLabel,
-		app.rightPlayer.videoCanvas, // Video display area
+		app.rightPlayer.newPathLabel(),
+		app.rightPlayer.newParseProgress(),
+		container.NewBorder(nil, nil, nil, app.newTranscodeButton(app.rightPlayer), app.rightPlayer.noticeLabel),
+		app.rightPlayer.variantSelect,
+		app.createAudioTrackSelect(app.rightPlayer),
+	), container.NewVBox(
 		app.rightPlayer.progressBar,
-		app.rightPlayer.timeLabel,
+		app.newTimelineTicks(app.rightPlayer),
+		container.NewBorder(nil, nil, app.rightPlayer.timeLabel, app.rightPlayer.newStateLabel()),
+		app.frameInfo.label(app.rightPlayer),
 		rightControls,
+		app.createRangeControls(app.rightPlayer),
 		app.rightPlayer.statsLabel,
-	)
+	), nil, nil, app.audioMode.wrap(app.rightPlayer))
 
 	// Main layout
 	videoContainer := container.NewHSplit(leftPanel, rightPanel)
 	videoContainer.SetOffset(0.5)
 
-	// Bottom panel with stats
+	// Bottom panel with stats, metadata, notes, bookmarks and history
+	app.metadataTable = app.newMetadataTable()
+	app.refreshMetadataTable()
+	bottomTabs := container.NewAppTabs(
+		container.NewTabItem(tr("Statistics"), app.statsTab()),
+		container.NewTabItem(tr("Metadata"), app.metadataTable),
+		container.NewTabItem(tr("Audio"), container.NewBorder(container.NewVBox(app.loudness.content(), app.audioTrackSync), nil, nil, nil, app.audio.content())),
+		container.NewTabItem(tr("Bitrate"), app.bitrate.content()),
+		container.NewTabItem(tr("Duplicates"), app.duplicates.content()),
+		container.NewTabItem(tr("Captions"), app.captions.content()),
+		container.NewTabItem(tr("Benchmark"), app.benchmark.content()),
+		container.NewTabItem(tr("Notes"), app.notes.content()),
+		container.NewTabItem(tr("Provenance"), app.provenance.content()),
+		container.NewTabItem(tr("Macro"), app.macro.content()),
+		container.NewTabItem(tr("ROI"), app.roi.content()),
+		container.NewTabItem(tr("Worst Frames"), app.worstFrames.content()),
+		container.NewTabItem(tr("Content Sync"), app.contentSync.content()),
+		container.NewTabItem(tr("Decode Errors"), app.decodeErrs.content()),
+		container.NewTabItem(tr("Bookmarks"), app.bookmarks.content()),
+		container.NewTabItem(tr("History"), app.history.content()),
+	)
 	bottomPanel := container.NewVBox(
+		app.newToolsNotice(),
+		app.newJobsIndicator(),
 		commonControls,
+		app.comparisonHint,
+		app.audioMode.toolbar(),
+		app.annotator.toolbar(),
+		app.zoom.toolbar(),
+		app.scopes.content(),
+		app.stillMetricsLabel,
 		widget.NewSeparator(),
-		app.statsDisplay,
+		bottomTabs,
 	)
 
 	// Main content
 	content := container.NewBorder(nil, bottomPanel, nil, nil, videoContainer)
 	app.window.SetContent(content)
+	app.leftPlayer.updateControls()
+	app.rightPlayer.updateControls()
+	app.updateFrameControls()
+	app.window.SetMainMenu(app.createMainMenu())
+}
+
+func (app *VideoCompareApp) createMainMenu() *fyne.MainMenu {
+	fileMenu := fyne.NewMenu(tr("File"),
+		fyne.NewMenuItem(tr("Open Session…"), app.openSessionDialog),
+		fyne.NewMenuItem(tr("Save Session…"), app.saveSessionDialog),
+		app.requireTools(fyne.NewMenuItem(tr("Open Segments in Left…"), func() { app.openSegmentsDialog(app.leftPlayer) }), needsFFmpeg, needsFFprobe),
+		app.requireTools(fyne.NewMenuItem(tr("Open Segments in Right…"), func() { app.openSegmentsDialog(app.rightPlayer) }), needsFFmpeg, needsFFprobe),
+		app.requireTools(fyne.NewMenuItem(tr("Transcode Left to Compatible MP4"), func() { app.transcodePlayer(app.leftPlayer) }), needsFFmpeg, needsFFprobe),
+		app.requireTools(fyne.NewMenuItem(tr("Transcode Right to Compatible MP4"), func() { app.transcodePlayer(app.rightPlayer) }), needsFFmpeg, needsFFprobe),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(tr("Generate Report…"), app.generateReportDialog),
+		fyne.NewMenuItem(tr("Export Labels…"), app.exportLabelsDialog),
+		app.requireTools(fyne.NewMenuItem(tr("Export Aligned Clips…"), app.exportAlignedDialog), needsFFmpeg, needsFFprobe),
+		fyne.NewMenuItem(tr("Export All Bookmark Snapshots…"), app.exportBookmarkSnapshots),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(tr("Supported Formats…"), app.supportedFormatsDialog),
+		app.audioMode.menuItem(),
+		app.autoPlayMenuItem(),
+		app.focus.pauseMenuItem(),
+		app.focus.resumeMenuItem(),
+		app.normalizeRangeMenuItem(),
+		app.measuredStepMenuItem(),
+		app.frameInfo.menuItem(),
+		app.shortcutsMenuItem(),
+		app.captionMenuItem(),
+		app.imageFormatMenuItem(),
+		fyne.NewMenuItem(tr("Forget File Settings…"), app.fileSettings.forgetDialog),
+		fyne.NewMenuItem(tr("Cache…"), app.cacheDialog),
+		fyne.NewMenuItem(tr("Background Jobs…"), app.jobLimitDialog),
+		app.languageMenuItem(),
+	)
+	return fyne.NewMainMenu(fileMenu)
 }
 
 func (app *VideoCompareApp) createPlayerControls(player *VideoPlayer, side string) *fyne.Container {
-	playBtn := widget.NewButtonWithIcon("Play", theme.MediaPlayIcon(), func() {
+	playBtn := widget.NewButtonWithIcon(tr("Play"), theme.MediaPlayIcon(), func() {
 		player.play()
+		app.macro.recordSide(player, macroCommand{name: "play"})
 	})
 
-	pauseBtn := widget.NewButtonWithIcon("Pause", theme.MediaPauseIcon(), func() {
+	pauseBtn := widget.NewButtonWithIcon(tr("Pause"), theme.MediaPauseIcon(), func() {
 		player.pause()
+		app.macro.recordSide(player, macroCommand{name: "pause"})
 	})
 
-	stopBtn := widget.NewButtonWithIcon("Stop", theme.MediaStopIcon(), func() {
+	stopBtn := widget.NewButtonWithIcon(tr("Stop"), theme.MediaStopIcon(), func() {
 		player.stop()
 	})
 
 	// Time input for seeking
-	timeInput := widget.NewEntry()
-	timeInput.SetPlaceHolder("00:00:00")
+	timeInput, seekBtn, seekError := player.newSeekEntry()
 
-	seekBtn := widget.NewButton("Seek", func() {
-		if timeStr := timeInput.Text; timeStr != "" {
-			player.seekToTime(timeStr)
-		}
+	snapshotBtn := widget.NewButtonWithIcon(tr("Snapshot"), theme.DocumentSaveIcon(), func() {
+		app.saveSnapshot(player)
+	})
+
+	copyFrameBtn := widget.NewButtonWithIcon(tr("Copy Frame"), theme.ContentCopyIcon(), func() {
+		app.copyFrame(player)
+	})
+
+	adjustBtn := widget.NewButtonWithIcon(tr("Adjust"), theme.ColorPaletteIcon(), func() {
+		app.showAdjustDialog(player)
 	})
 
+	player.playBtn, player.pauseBtn = playBtn, pauseBtn
+	player.playbackControls = []fyne.Disableable{playBtn, pauseBtn, stopBtn}
+	player.seekControls = []fyne.Disableable{timeInput, seekBtn, player.progressBar}
+	player.frameControls = []fyne.Disableable{snapshotBtn, copyFrameBtn, adjustBtn}
+
 	controls := container.NewHBox(
 		playBtn,
 		pauseBtn,
@@ -218,6 +732,12 @@ func (app *VideoCompareApp) createPlayerControls(player *VideoPlayer, side strin
 		widget.NewSeparator(),
 		timeInput,
 		seekBtn,
+		seekError,
+		widget.NewSeparator(),
+		snapshotBtn,
+		copyFrameBtn,
+		adjustBtn,
+		player.cancelReconnectBtn,
 	)
 
 	return controls
@@ -229,24 +749,181 @@ func (app *VideoCompareApp) selectVideoFile(player *VideoPlayer) {
 			return
 		}
 		path := reader.URI().Path()
-		player.load(path)
-		app.updateStats()
+		app.loadVideo(player, path)
 	}, app.window)
 
-	// Support for more video formats
-	fd.SetFilter(storage.NewExtensionFileFilter([]string{
-		".mp4", ".mkv", ".avi", ".mov", ".webm", ".flv", ".wmv", ".m4v", ".3gp", ".ogv", ".ts", ".mts", ".m2ts",
-	}))
+	// Extensions are configurable under File > Supported Formats
+	fd.SetFilter(storage.NewExtensionFileFilter(acceptedFormats()))
 	fd.Show()
 }
 
-func (vp *VideoPlayer) load(path string) {
+// loadVideo opens path in player and starts playback if auto-play is on.
+func (app *VideoCompareApp) loadVideo(player *VideoPlayer, path string) {
+	app.openVideo(player, path)
+	player.whenParsed(func() { app.autoPlayLoaded(player) })
+}
+
+// openVideo loads paths into player, joined into one timeline when there
+// are several, and kicks off any follow-up probing.
+func (app *VideoCompareApp) openVideo(player *VideoPlayer, paths ...string) {
+	player.load(paths, func() {
+		if path := player.path; isManifest(path) {
+			player.loadVariants(app)
+		}
+		app.fileSettings.restore(player)
+		app.playerChanged(player)
+	})
+	// Nothing about the previous file applies while the new one opens
+	app.updateFileLabels()
+	app.updateFrameControls()
+	app.updateComparisonControls()
+	app.history.record()
+}
+
+// playerChanged refreshes everything derived from player's file after it
+// was loaded or cleared.
+func (app *VideoCompareApp) playerChanged(player *VideoPlayer) {
+	app.shuttle.reset()
+	app.updateFileLabels()
+	app.updateFrameControls()
+	app.updateComparisonControls()
+	app.updateStats()
+	app.checkRotation()
+	app.refreshStillMetrics()
+	app.audio.load(player)
+	app.audioMode.load(player)
+	app.bitrate.load(player)
+	app.loudness.load(player)
+	app.duplicates.reset(player)
+	app.captions.reset()
+	app.benchmark.forget(player)
+	app.zoom.forget(player)
+	app.roi.forget()
+	app.worstFrames.forget()
+	app.scores = nil
+	app.contentSync.forget()
+	app.decodeErrs.forget(player)
+	app.fields.forget(player)
+	app.frameInfo.refresh(player)
+	app.analyzeCadence(player)
+	app.analyzeFormat(player)
+	app.analyzeHDR(player)
+	app.analyzeFrameCount(player)
+	app.analyzeAVSync(player)
+	app.analyzeFrameTimes(player)
+	app.analyzeDecoder(player)
+	app.analyzeTimecode(player)
+	app.analyzeFPS(player)
+}
+
+// load opens paths in vp: a single file, or several played back to back
+// as one continuous timeline. Joining the segments, creating the media or
+// decoding a still runs in the background so large or remote files don't
+// freeze the window; done runs on the UI goroutine once the file is open,
+// and is dropped when another load or an unload supersedes this one first.
+func (vp *VideoPlayer) load(paths []string, done func()) {
+	vp.cancelReconnect()
+	vp.stopParsing()
+	vp.cancelLoad()
+	path := paths[0]
+	vp.segments = nil
+	if len(paths) > 1 {
+		path = joinedPath(paths)
+		for _, p := range paths {
+			vp.segments = append(vp.segments, timelineSegment{path: p})
+		}
+	}
+	// openTranscoded sets the original before loading its transcode
+	if vp.transcoded != nil && vp.transcoded.out != path {
+		vp.transcoded = nil
+	}
 	vp.path = path
-	vp.fileLabel.SetText(filepath.Base(path))
+	vp.pinCache(path)
+	vp.variants = nil
+	vp.variant = nil
+	vp.transform = ""
+	vp.rangeStart, vp.rangeEnd = 0, 0
+	vp.updateRangeLabel()
+	vp.variantSelect.Hide()
+	vp.audioTracks, vp.audioTrack = nil, 0
+
+	// Let go of the previous file right away rather than showing it while
+	// the new one opens
+	vp.player.Stop()
+	if vp.media != nil {
+		vp.media.Release()
+		vp.media = nil
+	}
+	vp.clearStill()
+	vp.dropGrabbedFrame()
+	vp.resetMediaInfo()
+	vp.parseTimedOut = false
+	vp.frameCount, vp.streamDuration = 0, 0
+	vp.frameTimes = nil
+
+	vp.opening = true
+	seq := vp.loadSeq
+	vp.setState(stateLoading)
+	vp.refreshFileLabel()
+	vp.parseProgress.Show()
+	vp.parseProgress.Start()
+	vp.updateTimeDisplay()
+	vp.updateStats()
+	vp.updateVideoCanvas()
+
+	go func() {
+		var (
+			segments []timelineSegment
+			media    *libvlc.Media
+			still    image.Image
+			err      error
+		)
+		switch {
+		case len(paths) > 1:
+			if segments, err = joinSegments(paths, path); err == nil {
+				media, err = newMedia(path)
+			}
+		case isStillImage(path):
+			still, err = decodeStill(path)
+		default:
+			media, err = newMedia(path)
+		}
+		fyne.Do(func() {
+			if vp.loadSeq != seq {
+				if media != nil {
+					media.Release()
+				}
+				return
+			}
+			if segments != nil {
+				vp.segments = segments
+			}
+			vp.opened(path, media, still, err)
+			done()
+		})
+	}()
+}
+
+// opened takes over what load read in the background.
+func (vp *VideoPlayer) opened(path string, media *libvlc.Media, still image.Image, err error) {
+	vp.opening = false
+	vp.parseProgress.Stop()
+	vp.parseProgress.Hide()
+	vp.refreshFileLabel()
 
-	media, err := libvlc.NewMediaFromPath(path)
 	if err != nil {
-		log.Printf("failed to load media: %v", err)
+		log.Printf("failed to load %s: %v", path, err)
+		vp.setState(stateError)
+		vp.updateTimeDisplay()
+		vp.updateStats()
+		vp.updateControls()
+		vp.runAfterParse()
+		return
+	}
+	if still != nil {
+		vp.showStill(still)
+		vp.updateControls()
+		vp.runAfterParse()
 		return
 	}
 
@@ -256,36 +933,32 @@ func (vp *VideoPlayer) load(path string) {
 	// Removed SetOption (not available in libvlc-go)
 	// vp.player.SetOption("--no-xlib")
 
-	// Get media information
-	vp.extractMediaInfo()
+	// Setting new media stops playback, and with it the progress ticker
+	vp.setState(stateIdle)
 
-	// Set up progress bar callback
-	vp.setupProgressCallback()
+	// Read the tracks in the background; large or remote files can take
+	// a while
+	vp.resetMediaInfo()
+	vp.parseMedia()
 
 	// Update stats
 	vp.updateStats()
 
 	// Update video canvas to show video info
 	vp.updateVideoCanvas()
+	vp.updateControls()
 }
 
+// updateVideoCanvas refits the video canvas to the loaded video's aspect
+// ratio; videoFitLayout keeps it fitted as the window is resized.
 func (vp *VideoPlayer) updateVideoCanvas() {
-	// Create a visual representation of the video
 	if vp.width > 0 && vp.height > 0 {
-		// Set canvas size based on video dimensions (scaled down for GUI)
-		scale := 0.3 // Scale factor for GUI display
-		canvasWidth := int(float64(vp.width) * scale)
-		canvasHeight := int(float64(vp.height) * scale)
-
-		vp.videoCanvas.Resize(fyne.NewSize(float32(canvasWidth), float32(canvasHeight)))
 		vp.videoCanvas.FillColor = theme.PrimaryColor()
-		vp.videoCanvas.Refresh()
 	} else {
-		// Default size for no video
-		vp.videoCanvas.Resize(fyne.NewSize(320, 240))
 		vp.videoCanvas.FillColor = theme.DisabledColor()
-		vp.videoCanvas.Refresh()
 	}
+	vp.videoCanvas.Refresh()
+	vp.videoFit.Refresh()
 }
 
 func (vp *VideoPlayer) extractMediaInfo() {
@@ -293,217 +966,516 @@ func (vp *VideoPlayer) extractMediaInfo() {
 		return
 	}
 
-	_ = vp.media.Parse() // ignore error for now
+	// Don't carry over properties of the previously loaded file
+	vp.resetMediaInfo()
+
 	// Get duration
 	duration, err := vp.media.Duration()
-	if err == nil {
+	if err == nil && duration > 0 {
 		vp.duration = float64(duration) / 1000.0 // Convert to seconds
 	}
 	// Get tracks information
 	tracks, err := vp.media.Tracks()
 	if err == nil && len(tracks) > 0 {
 		for _, track := range tracks {
-			if track.Type == libvlc.MediaTrackVideo {
+			switch track.Type {
+			case libvlc.MediaTrackAudio:
+				vp.hasAudio = true
+				vp.audioTracks = append(vp.audioTracks, newAudioTrack(track))
+			case libvlc.MediaTrackVideo:
 				videoTrack := track.Video
-				if videoTrack != nil {
+				if videoTrack != nil && !vp.hasVideo {
+					vp.hasVideo = true
 					vp.width = int(videoTrack.Width)
 					vp.height = int(videoTrack.Height)
+					vp.orientation = videoTrack.Orientation
 					if videoTrack.FrameRateDen != 0 {
 						vp.fps = float64(videoTrack.FrameRateNum) / float64(videoTrack.FrameRateDen)
 					}
-					break
 				}
 			}
 		}
 	}
+}
+
+// resetMediaInfo clears the properties read from the loaded media.
+func (vp *VideoPlayer) resetMediaInfo() {
+	vp.duration, vp.currentTime, vp.fps = 0, 0, 0
+	vp.width, vp.height = 0, 0
 	vp.bitrate = 0
+	vp.hasVideo, vp.hasAudio = false, false
+	vp.audioTracks, vp.audioTrack = nil, 0
+	vp.orientation = libvlc.OrientationTopLeft
+}
+
+// mediaNotice explains what the loaded file can't do, or returns "" when
+// it is an ordinary seekable video.
+func (vp *VideoPlayer) mediaNotice() string {
+	switch {
+	case vp.path == "" || vp.still != nil || vp.state == stateLoading:
+		return ""
+	case vp.media == nil:
+		return tr("Could not open this file")
+	case vp.state == stateError && !isNetworkSource(vp.path):
+		return tr("libvlc reported a playback error")
+	case vp.parseTimedOut:
+		return vp.parseNotice()
+	case isNetworkSource(vp.path):
+		// Tracks of network streams are often only known once playing
+		if vp.duration <= 0 {
+			return tr("Live stream — seeking and frame stepping are unavailable")
+		}
+		return ""
+	case !vp.hasVideo && !vp.hasAudio:
+		return tr("No playable tracks — libvlc could not parse this file")
+	case !vp.hasVideo && !audioOnlyEnabled():
+		return tr("Audio only — no video track")
+	case vp.duration <= 0:
+		return tr("Could not determine duration — seeking and frame stepping are unavailable")
+	}
+	return ""
+}
+
+// canPlay reports whether the loaded file has anything to play back.
+func (vp *VideoPlayer) canPlay() bool {
+	return vp.media != nil && vp.still == nil && vp.state != stateLoading &&
+		(vp.hasVideo || vp.hasAudio || isNetworkSource(vp.path))
+}
+
+// canGrabFrame reports whether the loaded file has pictures to grab.
+func (vp *VideoPlayer) canGrabFrame() bool {
+	return vp.still != nil || (vp.media != nil && vp.state != stateLoading && (vp.hasVideo || isNetworkSource(vp.path)))
+}
+
+// canStep reports whether frame stepping applies to the loaded file.
+func (vp *VideoPlayer) canStep() bool {
+	return vp.canGrabFrame() && vp.still == nil && vp.duration > 0
+}
+
+// updateControls enables only the controls that apply to the loaded file
+// and shows why the others are disabled.
+func (vp *VideoPlayer) updateControls() {
+	setEnabled := func(controls []fyne.Disableable, enabled bool) {
+		for _, c := range controls {
+			if enabled {
+				c.Enable()
+			} else {
+				c.Disable()
+			}
+		}
+	}
+	setEnabled(vp.playbackControls, vp.canPlay())
+	if vp.canPlay() {
+		// Only offer what changes the state
+		if vp.playing() {
+			vp.playBtn.Disable()
+		} else {
+			vp.pauseBtn.Disable()
+		}
+	}
+	setEnabled(vp.seekControls, vp.canPlay() && vp.duration > 0)
+	setEnabled(vp.frameControls, vp.canGrabFrame())
+	vp.updateAudioTrackSelect()
+	vp.updateTranscodeButton()
+
+	if notice := vp.mediaNotice(); notice != "" {
+		vp.noticeLabel.SetText(notice)
+		vp.noticeLabel.Show()
+	} else {
+		vp.noticeLabel.Hide()
+	}
+}
+
+// updateFrameControls enables frame stepping while either player supports it.
+func (app *VideoCompareApp) updateFrameControls() {
+	if app.leftPlayer.canStep() || app.rightPlayer.canStep() {
+		app.prevFrameBtn.Enable()
+		app.nextFrameBtn.Enable()
+	} else {
+		app.prevFrameBtn.Disable()
+		app.nextFrameBtn.Disable()
+	}
+}
+
+// bothLoaded reports whether both players show pictures that can be
+// compared with each other.
+func (app *VideoCompareApp) bothLoaded() bool {
+	return app.leftPlayer.canGrabFrame() && app.rightPlayer.canGrabFrame()
+}
+
+// updateComparisonControls enables the features that need a frame on both
+// sides only while both are loaded, and says why otherwise.
+func (app *VideoCompareApp) updateComparisonControls() {
+	controls := []fyne.Disableable{
+		app.syncBtn,
+		app.syncPointBtn,
+		app.syncLockCheck,
+		app.blink.check,
+		app.overlay.check,
+		app.sideBySideBtn,
+		app.copySideBySideBtn,
+		app.heatmapBtn,
+		app.wipeSweepBtn,
+	}
+	app.contentSync.refresh()
+	if app.bothLoaded() {
+		for _, c := range controls {
+			c.Enable()
+		}
+		app.comparisonHint.Hide()
+		if reason := app.unavailable(needsFFmpeg); reason != "" {
+			app.wipeSweepBtn.Disable()
+			app.comparisonHint.SetText(trf("Wipe sweep export %s.", reason))
+			app.comparisonHint.Show()
+		}
+		return
+	}
+	for _, c := range controls {
+		c.Disable()
+	}
+	app.blink.check.SetChecked(false)
+	app.overlay.check.SetChecked(false)
+	hint := tr("Load a video on both sides to sync, export and compare frames.")
+	switch {
+	case app.leftPlayer.canGrabFrame():
+		hint = tr("Load a video on the right side to sync, export and compare frames.")
+	case app.rightPlayer.canGrabFrame():
+		hint = tr("Load a video on the left side to sync, export and compare frames.")
+	}
+	app.comparisonHint.SetText(hint)
+	app.comparisonHint.Show()
 }
 
-func (vp *VideoPlayer) setupProgressCallback() {
-	// Set up a timer to update progress
+// startProgressTicker starts the player's single progress ticker if it is
+// not already running.
+func (vp *VideoPlayer) startProgressTicker() {
+	if vp.tickerStop != nil {
+		return
+	}
+	stop := make(chan struct{})
+	vp.tickerStop = stop
 	go func() {
-		ticker := time.NewTicker(100 * time.Millisecond)
+		ticker := time.NewTicker(progressInterval)
 		defer ticker.Stop()
-		for range ticker.C {
-			if vp.player != nil && vp.isPlaying {
-				timeMs, err := vp.player.MediaTime()
-				if err == nil {
-					vp.currentTime = float64(timeMs) / 1000.0
-					vp.updateTimeDisplay()
-					vp.updateProgressBar()
-				}
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				fyne.Do(vp.pollPosition)
 			}
 		}
 	}()
 }
 
+func (vp *VideoPlayer) stopProgressTicker() {
+	if vp.tickerStop != nil {
+		close(vp.tickerStop)
+		vp.tickerStop = nil
+	}
+}
+
+// pollPosition reads the playback position from libvlc and updates the
+// display. It must run on the UI goroutine.
+func (vp *VideoPlayer) pollPosition() {
+	if vp.player == nil || !vp.playing() {
+		return
+	}
+	timeMs, err := vp.player.MediaTime()
+	if err == nil {
+		seconds := float64(timeMs) / 1000.0
+		vp.trackStall(seconds != vp.currentTime)
+		vp.currentTime = seconds
+		vp.updateTimeDisplay()
+		vp.updateProgressBar()
+		vp.stopAtRangeEnd()
+		if vp.onPosition != nil {
+			vp.onPosition()
+		}
+	}
+}
+
 func (vp *VideoPlayer) updateTimeDisplay() {
 	current := formatTime(vp.currentTime)
 	total := formatTime(vp.duration)
-	vp.timeLabel.SetText(fmt.Sprintf("%s / %s", current, total))
+	if vp.usesSourceTimecode() {
+		current = vp.sourceTimecodeAt(vp.currentTime)
+		total = vp.sourceTimecodeAt(vp.duration)
+	}
+	status := vp.segmentStatus()
+	if status == "" {
+		status = vp.transcodeStatus()
+	}
+	if status != "" {
+		vp.timeLabel.SetText(fmt.Sprintf("%s / %s · %s", current, total, status))
+	} else {
+		vp.timeLabel.SetText(fmt.Sprintf("%s / %s", current, total))
+	}
+	vp.updateTimecodeOverlay()
+}
+
+// bindProgressBar calls scrub with the position vp's progress bar was
+// dragged to. updateProgressBar moving the bar as playback advances
+// doesn't count as a drag.
+func (vp *VideoPlayer) bindProgressBar(scrub func(seconds float64)) {
+	vp.progressBar.OnChanged = func(value float64) {
+		if vp.updatingProgress || vp.duration <= 0 {
+			return
+		}
+		start, end := vp.playRange()
+		scrub(start + (value/seekBarSteps)*(end-start))
+	}
 }
 
 func (vp *VideoPlayer) updateProgressBar() {
-	if vp.duration > 0 {
-		progress := (vp.currentTime / vp.duration) * 100
+	if start, end := vp.playRange(); end > start {
+		progress := (vp.currentTime - start) / (end - start) * seekBarSteps
+		vp.updatingProgress = true
 		vp.progressBar.SetValue(progress)
+		vp.updatingProgress = false
 	}
 }
 
 func (vp *VideoPlayer) updateStats() {
-	stats := fmt.Sprintf("Resolution: %dx%d\nFPS: %.2f\nDuration: %s",
-		vp.width, vp.height, vp.fps, formatTime(vp.duration))
+	stats := trf("Resolution: %s\nFPS: %s\nDuration: %s",
+		resolution(vp.width, vp.height), vp.fpsSummary(), formatTime(vp.duration))
+	if vp.variant != nil {
+		stats += trf("\nVariant: %s", vp.variant)
+	}
+	if vp.transcoded != nil {
+		stats += trf("\nTranscoded from: %s (original time = shown + %s)", displayName(vp.transcoded.path), formatTime(vp.transcoded.offset))
+	}
+	if vp.decoder != nil {
+		stats += trf("\nDecoder: %s", vp.decoder)
+	}
+	if vp.startTimecode != "" {
+		stats += trf("\nStart timecode: %s", vp.startTimecode)
+	}
+	if vp.cadence != "" {
+		stats += trf("\nCadence: %s", vp.cadence)
+	}
+	if vp.avSync != nil {
+		stats += trf("\nA/V sync: %s", vp.avSync)
+	}
+	if vp.adjust != defaultAdjust {
+		stats += trf("\nPreview adjusted: %s", vp.adjust)
+	}
+	if vp.levels != levelsAsEncoded {
+		stats += trf("\nLevels: %s", vp.levels)
+	}
 	vp.statsLabel.SetText(stats)
 }
 
 func (app *VideoCompareApp) updateStats() {
-	leftStats := "No video loaded"
-	rightStats := "No video loaded"
+	leftStats := tr("No video loaded")
+	rightStats := tr("No video loaded")
 	if app.leftPlayer.path != "" {
-		leftStats = fmt.Sprintf("File: %s\nResolution: %dx%d\nFPS: %.2f",
-			filepath.Base(app.leftPlayer.path),
-			app.leftPlayer.width, app.leftPlayer.height,
+		leftStats = trf("File: %s\nResolution: %s\nFPS: %.2f",
+			app.leftPlayer.shortName(app.leftPlayer.labelPath()),
+			resolution(app.leftPlayer.width, app.leftPlayer.height),
 			app.leftPlayer.fps)
+		if app.leftPlayer.variant != nil {
+			leftStats += trf("\nVariant: %s", app.leftPlayer.variant)
+		}
 	}
 	if app.rightPlayer.path != "" {
-		rightStats = fmt.Sprintf("File: %s\nResolution: %dx%d\nFPS: %.2f",
-			filepath.Base(app.rightPlayer.path),
-			app.rightPlayer.width, app.rightPlayer.height,
+		rightStats = trf("File: %s\nResolution: %s\nFPS: %.2f",
+			app.rightPlayer.shortName(app.rightPlayer.labelPath()),
+			resolution(app.rightPlayer.width, app.rightPlayer.height),
 			app.rightPlayer.fps)
+		if app.rightPlayer.variant != nil {
+			rightStats += trf("\nVariant: %s", app.rightPlayer.variant)
+		}
 	}
-	combinedStats := fmt.Sprintf("Video Statistics\n\nLeft:\n%s\n\nRight:\n%s", leftStats, rightStats)
+	combinedStats := trf("Video Statistics\n\nLeft:\n%s\n\nRight:\n%s", leftStats, rightStats)
 	app.statsDisplay.SetText(combinedStats)
+	app.refreshMetadataTable()
 }
 
 // Playback controls
 func (vp *VideoPlayer) play() {
-	if vp.player != nil {
+	if vp.player != nil && vp.still == nil {
+		// Start over from the in point when outside the range or at its end
+		if start, end := vp.playRange(); vp.hasRange() && (vp.currentTime < start || vp.currentTime >= end) {
+			vp.seekTo(start)
+		}
 		vp.player.Play()
-		vp.isPlaying = true
+		vp.setState(statePlaying)
+		vp.applyVolume()
+		vp.applyAudioTrack()
+	}
+}
+
+// applyVolume sets libvlc's volume, which only sticks once audio output
+// has started.
+func (vp *VideoPlayer) applyVolume() {
+	if err := vp.player.SetVolume(vp.volume); err != nil {
+		log.Printf("%s: setting volume: %v", vp.title, err)
 	}
 }
 
 func (vp *VideoPlayer) pause() {
 	if vp.player != nil {
 		vp.player.SetPause(true)
-		vp.isPlaying = false
+		vp.setState(statePaused)
 	}
 }
 
 func (vp *VideoPlayer) stop() {
 	if vp.player != nil {
 		vp.player.Stop()
-		vp.isPlaying = false
+		vp.setState(stateIdle)
 		vp.currentTime = 0
 		vp.updateTimeDisplay()
 		vp.updateProgressBar()
 	}
 }
 
-func (vp *VideoPlayer) seekToTime(timeStr string) {
+// seekTo moves playback to the given position in seconds.
+func (vp *VideoPlayer) seekTo(seconds float64) {
 	if vp.player == nil || vp.duration == 0 {
 		return
 	}
-	// Parse time string (HH:MM:SS or MM:SS)
-	parts := strings.Split(timeStr, ":")
-	var seconds float64
-	if len(parts) == 3 {
-		h, _ := strconv.Atoi(parts[0])
-		m, _ := strconv.Atoi(parts[1])
-		s, _ := strconv.Atoi(parts[2])
-		seconds = float64(h*3600 + m*60 + s)
-	} else if len(parts) == 2 {
-		m, _ := strconv.Atoi(parts[0])
-		s, _ := strconv.Atoi(parts[1])
-		seconds = float64(m*60 + s)
-	}
+	seconds = vp.clampToRange(seconds)
 	if seconds >= 0 && seconds <= vp.duration {
 		_ = vp.player.SetMediaTime(int(seconds * 1000))
 		vp.currentTime = seconds
 		vp.updateTimeDisplay()
 		vp.updateProgressBar()
+		if vp.onSeek != nil {
+			vp.onSeek()
+		}
 	}
 }
 
 // Common controls
 func (app *VideoCompareApp) playAll() {
+	app.shuttle.reset()
+	app.contentSync.align()
+	app.contentSync.applyRates(1)
 	app.leftPlayer.play()
 	app.rightPlayer.play()
 }
 
 func (app *VideoCompareApp) pauseAll() {
+	app.shuttle.reset()
 	app.leftPlayer.pause()
 	app.rightPlayer.pause()
 }
 
 func (app *VideoCompareApp) stopAll() {
+	app.shuttle.reset()
 	app.leftPlayer.stop()
 	app.rightPlayer.stop()
 }
 
+// seekAll moves both players to the same position.
+func (app *VideoCompareApp) seekAll(seconds float64) {
+	app.leftPlayer.seekTo(seconds)
+	app.rightPlayer.seekTo(seconds)
+}
+
 func (app *VideoCompareApp) syncVideos() {
+	if app.contentSync.active() {
+		app.contentSync.align()
+		return
+	}
 	// Sync both videos to the same timestamp
 	if app.leftPlayer.currentTime > 0 {
-		app.rightPlayer.seekToTime(formatTime(app.leftPlayer.currentTime))
+		app.rightPlayer.seekTo(app.leftPlayer.currentTime)
 	} else if app.rightPlayer.currentTime > 0 {
-		app.leftPlayer.seekToTime(formatTime(app.rightPlayer.currentTime))
+		app.leftPlayer.seekTo(app.rightPlayer.currentTime)
 	}
 }
 
 // Frame-by-frame controls
 func (app *VideoCompareApp) nextFrame() {
-	// Calculate frame duration based on FPS
-	if app.leftPlayer.fps > 0 {
-		frameDuration := 1.0 / app.leftPlayer.fps
-		newTime := app.leftPlayer.currentTime + frameDuration
-		app.leftPlayer.seekToTime(formatTime(newTime))
-	}
-
-	if app.rightPlayer.fps > 0 {
-		frameDuration := 1.0 / app.rightPlayer.fps
-		newTime := app.rightPlayer.currentTime + frameDuration
-		app.rightPlayer.seekToTime(formatTime(newTime))
-	}
+	app.leftPlayer.stepFrame(1)
+	app.rightPlayer.stepFrame(1)
+	app.macro.record(macroCommand{name: "step", value: 1})
 }
 
 func (app *VideoCompareApp) previousFrame() {
-	// Calculate frame duration based on FPS
-	if app.leftPlayer.fps > 0 {
-		frameDuration := 1.0 / app.leftPlayer.fps
-		newTime := app.leftPlayer.currentTime - frameDuration
-		if newTime >= 0 {
-			app.leftPlayer.seekToTime(formatTime(newTime))
+	app.leftPlayer.stepFrame(-1)
+	app.rightPlayer.stepFrame(-1)
+	app.macro.record(macroCommand{name: "step", value: -1})
+}
+
+func (app *VideoCompareApp) setupEventHandlers() {
+	// Feed pointer movement over either video into the loupe and drawing tools
+	for _, vp := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		vp.display.onHover = app.loupe.track
+		vp.display.onDrag = app.dragVideo
+		vp.display.onDragEnd = app.annotator.dragEnd
+		vp.display.onTap = app.annotator.tap
+		vp.display.onScroll = app.wheel
+		vp.onSeek = func() {
+			app.playerSeeked()
+			app.decodeErrs.check(vp)
+			app.frameInfo.refresh(vp)
 		}
+		vp.onStateChange = func() {
+			app.decodeErrs.stateChanged(vp)
+			app.frameInfo.refresh(vp)
+		}
+		vp.onSeekEntered = func() { app.macro.recordSide(vp, macroCommand{name: "seek", value: vp.currentTime}) }
+		vp.onParsed = func() { app.mediaParsed(vp) }
 	}
-
-	if app.rightPlayer.fps > 0 {
-		frameDuration := 1.0 / app.rightPlayer.fps
-		newTime := app.rightPlayer.currentTime - frameDuration
-		if newTime >= 0 {
-			app.rightPlayer.seekToTime(formatTime(newTime))
+	for _, vp := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		vp.onPosition = func() {
+			if vp == app.leftPlayer {
+				app.contentSync.follow()
+			}
+			app.audio.playheadMoved(vp)
+			app.audioMode.playheadMoved(vp)
 		}
 	}
-}
 
-func (app *VideoCompareApp) setupEventHandlers() {
-	// Set up progress bar callbacks
-	app.leftPlayer.progressBar.OnChanged = func(value float64) {
-		if app.leftPlayer.duration > 0 {
-			newTime := (value / 100.0) * app.leftPlayer.duration
-			app.leftPlayer.seekToTime(formatTime(newTime))
-		}
+	// Set up progress bar callbacks; only user drags seek
+	for _, vp := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		vp.bindProgressBar(func(seconds float64) { app.scrubbed(vp, seconds) })
+		vp.progressBar.onSeek = func(amount seekAmount, direction int) { app.nudge(vp, amount, direction) }
 	}
 
-	app.rightPlayer.progressBar.OnChanged = func(value float64) {
-		if app.rightPlayer.duration > 0 {
-			newTime := (value / 100.0) * app.rightPlayer.duration
-			app.rightPlayer.seekToTime(formatTime(newTime))
-		}
+	// Files dragged from the file manager open like files opened with the app
+	app.window.SetOnDropped(app.filesDropped)
+
+	// Arrow and page keys nudge, J/K/L shuttle while no control has focus
+	app.window.Canvas().SetOnTypedKey(app.typedKey)
+
+	// Comparison features stay disabled until both sides are loaded
+	app.updateComparisonControls()
+}
+
+// playerSeeked refreshes the views computed from the current frame.
+func (app *VideoCompareApp) playerSeeked() {
+	app.scopes.refresh()
+	app.blink.refresh()
+	app.overlay.refresh()
+	app.refreshStillMetrics()
+	app.audio.refresh()
+	app.audioMode.refresh()
+	app.bitrate.refresh()
+	app.zoom.refresh()
+	app.fields.refresh()
+}
+
+// dragVideo pans the zoomed views while no drawing tool is selected and
+// draws otherwise.
+func (app *VideoCompareApp) dragVideo(vp *VideoPlayer, ev *fyne.DragEvent) {
+	if app.annotator.tool == toolNone && app.zoom.active() {
+		app.zoom.pan(vp, ev)
+		return
 	}
+	app.annotator.drag(vp, ev)
 }
 
 // Utility functions
 func formatTime(seconds float64) string {
+	// Hours aren't wrapped at a day; negative and unknown times show as 0
+	if !(seconds > 0) || math.IsInf(seconds, 1) {
+		seconds = 0
+	}
 	hours := int(seconds) / 3600
 	minutes := (int(seconds) % 3600) / 60
 	secs := int(seconds) % 60
@@ -513,3 +1485,24 @@ func formatTime(seconds float64) string {
 	}
 	return fmt.Sprintf("%02d:%02d", minutes, secs)
 }
+
+// formatTimecode formats seconds as HH:MM:SS:FF using the given frame rate.
+func formatTimecode(seconds, fps float64) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	whole := int(seconds)
+	frames := 0
+	if fps > 0 {
+		frames = int((seconds - float64(whole)) * fps)
+	}
+	return fmt.Sprintf("%02d:%02d:%02d:%02d", whole/3600, (whole%3600)/60, whole%60, frames)
+}
+
+// frameNumber returns the zero-based index of the frame shown at seconds.
+func frameNumber(seconds, fps float64) int {
+	if fps <= 0 || seconds <= 0 {
+		return 0
+	}
+	return int(seconds*fps + 1e-6)
+}
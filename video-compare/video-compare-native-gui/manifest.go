@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+)
+
+// streamVariant is one rendition of an adaptive (HLS/DASH) stream.
+type streamVariant struct {
+	bitrate int // bits per second
+	width   int
+	height  int
+}
+
+func (v streamVariant) String() string {
+	if v.height > 0 {
+		return fmt.Sprintf("%dp @ %s", v.height, formatBitrate(v.bitrate))
+	}
+	return formatBitrate(v.bitrate)
+}
+
+// isManifest reports whether path points to an HLS or DASH manifest.
+func isManifest(p string) bool {
+	if u, err := url.Parse(p); err == nil && u.Scheme != "" {
+		p = u.Path
+	}
+	switch strings.ToLower(path.Ext(p)) {
+	case ".m3u8", ".mpd":
+		return true
+	}
+	return false
+}
+
+type probeTags struct {
+	VariantBitrate string `json:"variant_bitrate"`
+}
+
+type probeVariantStream struct {
+	CodecType string    `json:"codec_type"`
+	Width     int       `json:"width"`
+	Height    int       `json:"height"`
+	Tags      probeTags `json:"tags"`
+}
+
+type probeVariantOutput struct {
+	Programs []struct {
+		Tags    probeTags            `json:"tags"`
+		Streams []probeVariantStream `json:"streams"`
+	} `json:"programs"`
+	Streams []probeVariantStream `json:"streams"`
+}
+
+// probeVariants lists the renditions advertised by a manifest, highest
+// bitrate first.
+func probeVariants(manifest string) ([]streamVariant, error) {
+	out, err := runFFprobe("-show_programs", "-show_streams", manifest)
+	if err != nil {
+		return nil, err
+	}
+	return parseVariants(out)
+}
+
+// parseVariants extracts renditions from ffprobe JSON. HLS exposes each
+// variant as a program; DASH exposes each representation as a stream.
+func parseVariants(data []byte) ([]streamVariant, error) {
+	var probe probeVariantOutput
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("parsing ffprobe output: %w", err)
+	}
+
+	seen := make(map[streamVariant]bool)
+	var variants []streamVariant
+	add := func(bitrate string, streams []probeVariantStream) {
+		bps, err := strconv.Atoi(bitrate)
+		if err != nil || bps <= 0 {
+			return
+		}
+		v := streamVariant{bitrate: bps}
+		for _, s := range streams {
+			if s.CodecType == "video" {
+				v.width, v.height = s.Width, s.Height
+				break
+			}
+		}
+		if !seen[v] {
+			seen[v] = true
+			variants = append(variants, v)
+		}
+	}
+
+	for _, prog := range probe.Programs {
+		add(prog.Tags.VariantBitrate, prog.Streams)
+	}
+	if len(variants) == 0 {
+		for _, s := range probe.Streams {
+			if s.CodecType == "video" {
+				add(s.Tags.VariantBitrate, []probeVariantStream{s})
+			}
+		}
+	}
+
+	sort.Slice(variants, func(i, j int) bool {
+		return variants[i].bitrate > variants[j].bitrate
+	})
+	return variants, nil
+}
+
+// variantOptions pins libvlc's adaptive demuxer to a single rendition.
+// The fixedrate logic picks the highest representation whose bandwidth
+// fits :adaptive-bw (in kb/s, rounded up so the variant itself fits),
+// which tells apart renditions of the same resolution; the size caps keep
+// it off larger renditions whose bandwidth happens to fit too.
+func variantOptions(v streamVariant) []string {
+	opts := []string{":adaptive-logic=fixedrate", fmt.Sprintf(":adaptive-bw=%d", (v.bitrate+999)/1000)}
+	if v.width > 0 && v.height > 0 {
+		opts = append(opts,
+			fmt.Sprintf(":adaptive-maxwidth=%d", v.width),
+			fmt.Sprintf(":adaptive-maxheight=%d", v.height))
+	}
+	return opts
+}
+
+// loadVariants probes a manifest in the background, fills the variant
+// selector and switches to the highest rendition.
+func (vp *VideoPlayer) loadVariants(app *VideoCompareApp) {
+	manifest := vp.path
+	go func() {
+		variants, err := probeVariants(manifest)
+		if err != nil {
+			log.Printf("failed to probe variants of %s: %v", manifest, err)
+		}
+		fyne.Do(func() {
+			if vp.path != manifest {
+				return
+			}
+			vp.variants = variants
+			if len(variants) == 0 {
+				vp.variantSelect.Hide()
+				return
+			}
+			names := make([]string, len(variants))
+			for i, v := range variants {
+				names[i] = v.String()
+			}
+			vp.variantSelect.OnChanged = nil
+			vp.variantSelect.Options = names
+			vp.variantSelect.SetSelectedIndex(0)
+			vp.variantSelect.OnChanged = func(string) {
+				vp.selectVariant(vp.variantSelect.SelectedIndex())
+				app.updateStats()
+			}
+			vp.variantSelect.Show()
+			vp.selectVariant(0)
+			app.updateStats()
+		})
+	}()
+}
+
+// selectVariant reloads the manifest pinned to the chosen rendition,
+// keeping the playback position.
+func (vp *VideoPlayer) selectVariant(index int) {
+	if index < 0 || index >= len(vp.variants) {
+		return
+	}
+	v := vp.variants[index]
+	vp.variant = &v
+	vp.reloadMedia()
+}
+
+// formatBitrate renders bits per second in human readable units.
+func formatBitrate(bps int) string {
+	switch {
+	case bps >= 1000000:
+		return fmt.Sprintf("%.2f Mbps", float64(bps)/1e6)
+	case bps >= 1000:
+		return fmt.Sprintf("%d kbps", bps/1000)
+	default:
+		return fmt.Sprintf("%d bps", bps)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"fyne.io/fyne/v2"
+)
+
+const toolMeasure = "Measure"
+
+var measureColor = color.NRGBA{R: 0xff, G: 0xe0, B: 0x20, A: 0xff}
+
+// measurement is a line between two normalised points on a player's whole
+// frame, whatever the zoom it was taken at.
+type measurement struct {
+	start    [2]float64
+	end      [2]float64
+	complete bool
+}
+
+// sourceDelta returns the measurement's extent in source pixels.
+func (m *measurement) sourceDelta(vp *VideoPlayer) (float64, float64) {
+	return (m.end[0] - m.start[0]) * float64(vp.width), (m.end[1] - m.start[1]) * float64(vp.height)
+}
+
+// distance is the length of the measurement in source pixels.
+func (m *measurement) distance(vp *VideoPlayer) float64 {
+	dx, dy := m.sourceDelta(vp)
+	return math.Hypot(dx, dy)
+}
+
+// angle is the measurement's direction in degrees, counter-clockwise from
+// the positive x axis as usual (screen y grows downwards).
+func (m *measurement) angle(vp *VideoPlayer) float64 {
+	dx, dy := m.sourceDelta(vp)
+	return math.Atan2(-dy, dx) * 180 / math.Pi
+}
+
+func (m *measurement) String(vp *VideoPlayer) string {
+	return fmt.Sprintf("%.1f px @ %.1f°", m.distance(vp), m.angle(vp))
+}
+
+// measureDrag updates the measurement being dragged out on vp.
+func (an *annotator) measureDrag(vp *VideoPlayer, ev *fyne.DragEvent) {
+	point := vp.framePoint(vp.display.clampedPosition(ev.Position))
+	if vp.measurement == nil || vp.measurement.complete && an.activePlayer != vp {
+		start := vp.framePoint(vp.display.clampedPosition(ev.Position.Subtract(ev.Dragged)))
+		vp.measurement = &measurement{start: start}
+		an.activePlayer = vp
+	}
+	vp.measurement.end = point
+	vp.measurement.complete = true
+	vp.annotationLayer.Refresh()
+	an.updateMeasureLabel()
+}
+
+// measureTap places measurement end points by clicking: the first click
+// starts a new measurement, the second completes it.
+func (an *annotator) measureTap(vp *VideoPlayer, ev *fyne.PointEvent) {
+	point := vp.framePoint(vp.display.clampedPosition(ev.Position))
+	if vp.measurement == nil || vp.measurement.complete {
+		vp.measurement = &measurement{start: point, end: point}
+	} else {
+		vp.measurement.end = point
+		vp.measurement.complete = true
+	}
+	vp.annotationLayer.Refresh()
+	an.updateMeasureLabel()
+}
+
+func (an *annotator) clearMeasurements() {
+	for _, vp := range []*VideoPlayer{an.app.leftPlayer, an.app.rightPlayer} {
+		vp.measurement = nil
+		vp.annotationLayer.Refresh()
+	}
+	an.updateMeasureLabel()
+}
+
+// updateMeasureLabel shows both players' measurements and, when both
+// exist, how they differ.
+func (an *annotator) updateMeasureLabel() {
+	if an.measureLabel == nil {
+		return
+	}
+	l, r := an.app.leftPlayer, an.app.rightPlayer
+	lm, rm := l.measurement, r.measurement
+	if lm != nil && !lm.complete {
+		lm = nil
+	}
+	if rm != nil && !rm.complete {
+		rm = nil
+	}
+
+	text := ""
+	if lm != nil {
+		text = "L: " + lm.String(l)
+	}
+	if rm != nil {
+		if text != "" {
+			text += "   "
+		}
+		text += "R: " + rm.String(r)
+	}
+	if lm != nil && rm != nil {
+		ld, rd := lm.distance(l), rm.distance(r)
+		text += fmt.Sprintf("   Δ %.1f px", rd-ld)
+		if ld > 0 {
+			text += fmt.Sprintf(" (%+.2f%%)", (rd-ld)/ld*100)
+		}
+		text += fmt.Sprintf(", %.1f°", rm.angle(r)-lm.angle(l))
+	}
+	an.measureLabel.SetText(text)
+}
+
+// drawMeasurement renders the measurement line, end markers and length on
+// img, which shows vp's display at img's size.
+func drawMeasurement(img *image.RGBA, vp *VideoPlayer) {
+	m := vp.measurement
+	if m == nil {
+		return
+	}
+	b := img.Bounds()
+	w, h := float64(b.Dx()), float64(b.Dy())
+	start, end := vp.displayPoint(m.start), vp.displayPoint(m.end)
+	p0 := [2]float64{float64(b.Min.X) + start[0]*w, float64(b.Min.Y) + start[1]*h}
+	p1 := [2]float64{float64(b.Min.X) + end[0]*w, float64(b.Min.Y) + end[1]*h}
+
+	drawLine(img, p0, p1, 2, measureColor)
+	fillDisc(img, p0[0], p0[1], 4, measureColor)
+	if !m.complete {
+		return
+	}
+	fillDisc(img, p1[0], p1[1], 4, measureColor)
+
+	label := renderTextBox(m.String(vp))
+	x := int((p0[0]+p1[0])/2) + 6
+	y := int((p0[1]+p1[1])/2) + 6
+	x = clampInt(x, b.Min.X, max(b.Min.X, b.Max.X-label.Bounds().Dx()))
+	y = clampInt(y, b.Min.Y, max(b.Min.Y, b.Max.Y-label.Bounds().Dy()))
+	drawImageAt(img, label, x, y, 1)
+}
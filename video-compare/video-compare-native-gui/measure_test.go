@@ -0,0 +1,45 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMeasurementZoomed(t *testing.T) {
+	zp := newZoomPanel(nil)
+	zp.factor = 4
+	vp := &VideoPlayer{width: 1920, height: 1080, zoom: zp}
+	zp.offsets[vp] = [2]float64{2, 0}
+
+	// Half the display's width at 4× covers an eighth of the frame
+	m := &measurement{
+		start:    vp.framePoint([2]float64{0.25, 0.5}),
+		end:      vp.framePoint([2]float64{0.75, 0.5}),
+		complete: true,
+	}
+	if got := m.distance(vp); math.Abs(got-240) > 1e-9 {
+		t.Errorf("distance at 4× = %v px, want 240", got)
+	}
+	if got := m.angle(vp); got != 0 {
+		t.Errorf("angle = %v°, want 0", got)
+	}
+
+	// The registration offset shifts where the line lands on the frame
+	if want := 0.4375 + 2.0/1920; math.Abs(m.start[0]-want) > 1e-12 {
+		t.Errorf("start x = %v, want %v", m.start[0], want)
+	}
+	for _, p := range [][2]float64{m.start, m.end} {
+		if got, want := vp.framePoint(vp.displayPoint(p)), p; math.Abs(got[0]-want[0]) > 1e-12 || math.Abs(got[1]-want[1]) > 1e-12 {
+			t.Errorf("framePoint(displayPoint(%v)) = %v", p, got)
+		}
+	}
+
+	// Zooming out leaves the measurement unchanged
+	zp.factor = 1
+	if got := m.distance(vp); math.Abs(got-240) > 1e-9 {
+		t.Errorf("distance after zooming out = %v px, want 240", got)
+	}
+	if got := vp.displayPoint(m.start); got != m.start {
+		t.Errorf("displayPoint at 1× = %v, want %v", got, m.start)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"fyne.io/fyne/v2"
+)
+
+// fpsTolerance is how far, as a fraction of the declared rate, the
+// measured average frame rate may be off before the declared one is
+// flagged. It absorbs rounding of the last frame's duration.
+const fpsTolerance = 0.01
+
+const prefMeasuredStep = "playback.measuredFPS"
+
+// measuredFPS is the average frame rate over the whole stream, frames per
+// second of stream duration, or 0 until the frame count is known. It
+// differs from the declared rate for variable frame rate or mislabeled
+// files.
+func (vp *VideoPlayer) measuredFPS() float64 {
+	duration := vp.streamDuration
+	if duration <= 0 {
+		duration = vp.duration
+	}
+	if vp.frameCount == 0 || duration <= 0 {
+		return 0
+	}
+	return float64(vp.frameCount) / duration
+}
+
+// fpsDiverges reports whether the measured frame rate is off the declared
+// one by more than fpsTolerance.
+func (vp *VideoPlayer) fpsDiverges() bool {
+	measured := vp.measuredFPS()
+	return measured > 0 && vp.fps > 0 && math.Abs(measured-vp.fps)/vp.fps > fpsTolerance
+}
+
+// fpsSummary gives the declared frame rate, followed by the measured one
+// when it is known to differ. Without a declared rate it says which rate
+// frame stepping falls back to.
+func (vp *VideoPlayer) fpsSummary() string {
+	if vp.fps <= 0 {
+		if vp.fpsEstimated() {
+			return fmt.Sprintf("unknown (stepping at %.0f, estimated)", estimatedFPS)
+		}
+		return fmt.Sprintf("unknown (stepping at measured %.2f)", vp.measuredFPS())
+	}
+	if vp.fpsDiverges() {
+		return fmt.Sprintf("%.2f (measured %.2f ⚠)", vp.fps, vp.measuredFPS())
+	}
+	return fmt.Sprintf("%.2f", vp.fps)
+}
+
+// measuredStepEnabled reports whether frame stepping uses the measured
+// average frame rate rather than the declared one.
+func measuredStepEnabled() bool {
+	return fyne.CurrentApp().Preferences().Bool(prefMeasuredStep)
+}
+
+// measuredStepMenuItem toggles frame stepping at the measured frame rate.
+func (app *VideoCompareApp) measuredStepMenuItem() *fyne.MenuItem {
+	item := fyne.NewMenuItem(tr("Step Frames at Measured FPS"), nil)
+	item.Checked = measuredStepEnabled()
+	item.Action = func() {
+		item.Checked = !item.Checked
+		fyne.CurrentApp().Preferences().SetBool(prefMeasuredStep, item.Checked)
+		app.window.MainMenu().Refresh()
+		app.updateFrameControls()
+	}
+	return item
+}
+
+// measuredFPSChanged refreshes what shows or steps by vp's measured frame
+// rate once its frame count is known.
+func (app *VideoCompareApp) measuredFPSChanged(vp *VideoPlayer) {
+	vp.updateStats()
+	app.updateFrameControls()
+	app.refreshMetadataTable()
+}
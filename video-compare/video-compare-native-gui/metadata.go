@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// metadataRow is one property of the two loaded files, compared side by side.
+type metadataRow struct {
+	Name  string
+	Left  string
+	Right string
+}
+
+// Differs reports whether both files are loaded and disagree on this row.
+func (r metadataRow) Differs() bool {
+	return r.Left != "" && r.Right != "" && r.Left != r.Right
+}
+
+// metadataDiff builds the rows of the metadata comparison table.
+func (app *VideoCompareApp) metadataDiff() []metadataRow {
+	l, r := app.leftPlayer, app.rightPlayer
+	row := func(name string, value func(vp *VideoPlayer) string) metadataRow {
+		return metadataRow{Name: name, Left: loadedValue(l, value), Right: loadedValue(r, value)}
+	}
+	rows := []metadataRow{
+		row("File", func(vp *VideoPlayer) string { return displayName(vp.path) }),
+		row("Resolution", func(vp *VideoPlayer) string { return fmt.Sprintf("%dx%d", vp.width, vp.height) }),
+		row("Rotation", func(vp *VideoPlayer) string {
+			if vp.transform != "" {
+				return fmt.Sprintf("%s (shown with %s)", orientationLabel(vp.orientation), vp.transform)
+			}
+			return orientationLabel(vp.orientation)
+		}),
+		row("FPS", func(vp *VideoPlayer) string { return fmt.Sprintf("%.3f", vp.fps) }),
+		row("Measured FPS", func(vp *VideoPlayer) string {
+			measured := vp.measuredFPS()
+			if measured == 0 {
+				return "unknown"
+			}
+			if vp.fpsDiverges() {
+				return fmt.Sprintf("%.3f — differs from declared", measured)
+			}
+			return fmt.Sprintf("%.3f", measured)
+		}),
+		row("Duration", func(vp *VideoPlayer) string { return formatTime(vp.duration) }),
+		app.frameCountRow(),
+		row("Cadence", func(vp *VideoPlayer) string { return vp.cadence }),
+		row("Codec", func(vp *VideoPlayer) string { return vp.codec }),
+		row("Bit Depth", formatValue(func(f *videoFormat) string { return fmt.Sprintf("%d-bit", f.BitDepth) })),
+		row("Chroma Subsampling", formatValue(func(f *videoFormat) string { return f.Chroma })),
+		app.colorRangeRow(),
+		row("Sample Aspect", formatValue(func(f *videoFormat) string { return f.SAR })),
+		row("Display Aspect", formatValue(func(f *videoFormat) string { return f.DAR })),
+		row("Bitrate", func(vp *VideoPlayer) string {
+			if vp.bitrate <= 0 {
+				return "unknown"
+			}
+			return formatBitrate(vp.bitrate)
+		}),
+	}
+	return append(rows, hdrRows(row)...)
+}
+
+// formatValue reads a property of the probed pixel format, "unknown" until
+// the probe has finished.
+func formatValue(value func(f *videoFormat) string) func(vp *VideoPlayer) string {
+	return func(vp *VideoPlayer) string {
+		if vp.format == nil {
+			return "unknown"
+		}
+		return value(vp.format)
+	}
+}
+
+func loadedValue(vp *VideoPlayer, value func(vp *VideoPlayer) string) string {
+	if vp.path == "" {
+		return ""
+	}
+	return value(vp)
+}
+
+// newMetadataTable creates the metadata diff table shown in the bottom panel.
+func (app *VideoCompareApp) newMetadataTable() *widget.Table {
+	headers := []string{"Property", "Left", "Right", ""}
+	table := widget.NewTableWithHeaders(
+		func() (int, int) { return len(app.metadataRows), len(headers) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			row := app.metadataRows[id.Row]
+			switch id.Col {
+			case 0:
+				label.SetText(row.Name)
+			case 1:
+				label.SetText(row.Left)
+			case 2:
+				label.SetText(row.Right)
+			case 3:
+				if row.Differs() {
+					label.SetText("≠")
+				} else {
+					label.SetText("")
+				}
+			}
+		},
+	)
+	table.ShowHeaderColumn = false
+	table.CreateHeader = func() fyne.CanvasObject { return widget.NewLabel("") }
+	table.UpdateHeader = func(id widget.TableCellID, obj fyne.CanvasObject) {
+		if id.Row < 0 && id.Col >= 0 {
+			obj.(*widget.Label).SetText(headers[id.Col])
+		}
+	}
+	table.SetColumnWidth(0, 140)
+	table.SetColumnWidth(1, 260)
+	table.SetColumnWidth(2, 260)
+	table.SetColumnWidth(3, 30)
+	return table
+}
+
+func (app *VideoCompareApp) refreshMetadataTable() {
+	app.metadataRows = app.metadataDiff()
+	if app.metadataTable != nil {
+		app.metadataTable.Refresh()
+	}
+	if app.history != nil {
+		app.history.updateMetrics()
+	}
+}
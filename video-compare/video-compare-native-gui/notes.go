@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// note is a timestamped review observation.
+type note struct {
+	Time float64 `json:"time"` // seconds, on the left player's timeline
+	Text string  `json:"text"`
+}
+
+// notesPanel lists the session's notes and lets the user add, edit,
+// delete and export them.
+type notesPanel struct {
+	app      *VideoCompareApp
+	notes    []note
+	selected int
+
+	list      *widget.List
+	entry     *widget.Entry
+	updateBtn *widget.Button
+	deleteBtn *widget.Button
+}
+
+func newNotesPanel(app *VideoCompareApp) *notesPanel {
+	return &notesPanel{app: app, selected: -1}
+}
+
+func (np *notesPanel) content() fyne.CanvasObject {
+	np.list = widget.NewList(
+		func() int { return len(np.notes) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			n := np.notes[id]
+			obj.(*widget.Label).SetText(fmt.Sprintf("[%s] %s", formatTimecode(n.Time, np.app.leftPlayer.fps), n.Text))
+		},
+	)
+	np.list.OnSelected = np.selectNote
+	np.list.OnUnselected = func(widget.ListItemID) { np.clearSelection() }
+
+	np.entry = widget.NewEntry()
+	np.entry.SetPlaceHolder("Observation at the current position…")
+	np.entry.OnSubmitted = func(string) { np.addNote() }
+
+	addBtn := widget.NewButtonWithIcon("Add Note", theme.ContentAddIcon(), np.addNote)
+	np.updateBtn = widget.NewButtonWithIcon("Update", theme.DocumentSaveIcon(), np.updateNote)
+	np.deleteBtn = widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), np.deleteNote)
+	exportBtn := widget.NewButtonWithIcon("Export…", theme.DownloadIcon(), np.export)
+	np.updateBtn.Disable()
+	np.deleteBtn.Disable()
+
+	buttons := container.NewHBox(addBtn, np.updateBtn, np.deleteBtn, widget.NewSeparator(), exportBtn)
+	return container.NewBorder(container.NewBorder(nil, nil, nil, buttons, np.entry), nil, nil, nil, np.list)
+}
+
+// addNote stamps the entry text with the current synced position.
+func (np *notesPanel) addNote() {
+	text := strings.TrimSpace(np.entry.Text)
+	if text == "" {
+		return
+	}
+	np.notes = append(np.notes, note{Time: np.app.leftPlayer.currentTime, Text: text})
+	np.sortNotes()
+	np.entry.SetText("")
+	np.list.UnselectAll()
+	np.list.Refresh()
+}
+
+func (np *notesPanel) selectNote(id widget.ListItemID) {
+	if id < 0 || id >= len(np.notes) {
+		return
+	}
+	np.selected = id
+	n := np.notes[id]
+	np.entry.SetText(n.Text)
+	np.updateBtn.Enable()
+	np.deleteBtn.Enable()
+	np.app.seekAll(n.Time)
+}
+
+func (np *notesPanel) clearSelection() {
+	np.selected = -1
+	np.updateBtn.Disable()
+	np.deleteBtn.Disable()
+}
+
+func (np *notesPanel) updateNote() {
+	text := strings.TrimSpace(np.entry.Text)
+	if np.selected < 0 || text == "" {
+		return
+	}
+	np.notes[np.selected].Text = text
+	np.list.Refresh()
+}
+
+func (np *notesPanel) deleteNote() {
+	if np.selected < 0 {
+		return
+	}
+	np.notes = append(np.notes[:np.selected], np.notes[np.selected+1:]...)
+	np.entry.SetText("")
+	np.list.UnselectAll()
+	np.clearSelection()
+	np.list.Refresh()
+}
+
+// setNotes replaces all notes, e.g. when a session is loaded.
+func (np *notesPanel) setNotes(notes []note) {
+	np.notes = append([]note(nil), notes...)
+	np.sortNotes()
+	np.list.UnselectAll()
+	np.clearSelection()
+	np.list.Refresh()
+}
+
+func (np *notesPanel) sortNotes() {
+	sort.SliceStable(np.notes, func(i, j int) bool { return np.notes[i].Time < np.notes[j].Time })
+}
+
+// export writes the notes as Markdown or CSV depending on the chosen
+// file extension.
+func (np *notesPanel) export() {
+	fd := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		if strings.EqualFold(filepath.Ext(writer.URI().Path()), ".csv") {
+			err = writeNotesCSV(writer, np.notes, np.app.leftPlayer.fps)
+		} else {
+			err = writeNotesMarkdown(writer, np.notes, np.app)
+		}
+		if err != nil {
+			dialog.ShowError(err, np.app.window)
+		}
+	}, np.app.window)
+	fd.SetFileName("review-notes.md")
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{".md", ".csv"}))
+	fd.Show()
+}
+
+func writeNotesCSV(w io.Writer, notes []note, fps float64) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"seconds", "timecode", "note"}); err != nil {
+		return err
+	}
+	for _, n := range notes {
+		record := []string{strconv.FormatFloat(n.Time, 'f', 3, 64), formatTimecode(n.Time, fps), n.Text}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeNotesMarkdown(w io.Writer, notes []note, app *VideoCompareApp) error {
+	var b strings.Builder
+	b.WriteString("# Review Notes\n\n")
+	fmt.Fprintf(&b, "- Left: `%s`\n", app.leftPlayer.path)
+	fmt.Fprintf(&b, "- Right: `%s`\n", app.rightPlayer.path)
+	if len(app.provenance.fields) > 0 {
+		fmt.Fprintf(&b, "- Provenance: %s\n", app.provenance.summary())
+	}
+	b.WriteString("\n")
+	b.WriteString("| Timecode | Note |\n|---|---|\n")
+	for _, n := range notes {
+		text := strings.ReplaceAll(n.Text, "|", `\|`)
+		text = strings.ReplaceAll(text, "\n", " ")
+		fmt.Fprintf(&b, "| %s | %s |\n", formatTimecode(n.Time, app.leftPlayer.fps), text)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
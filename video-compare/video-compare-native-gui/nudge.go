@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	prefNudgeAmount = "shortcuts.nudgeAmount"
+	prefPageAmount  = "shortcuts.pageAmount"
+)
+
+// seekAmount is how far a relative seek moves: a number of frames or of
+// seconds.
+type seekAmount struct {
+	name    string
+	frames  int
+	seconds float64
+}
+
+// nudgeAmounts are the choices for the arrow keys; pageAmounts for clicks
+// on a progress bar's track and Page Up/Down.
+var (
+	nudgeAmounts = []seekAmount{
+		{name: "1 frame", frames: 1},
+		{name: "1 s", seconds: 1},
+		{name: "5 s", seconds: 5},
+		{name: "10 s", seconds: 10},
+	}
+	pageAmounts = []seekAmount{
+		{name: "10 s", seconds: 10},
+		{name: "30 s", seconds: 30},
+		{name: "1 min", seconds: 60},
+		{name: "5 min", seconds: 300},
+	}
+)
+
+// configuredAmount returns the choice saved under key, or the first one.
+func configuredAmount(key string, choices []seekAmount) seekAmount {
+	name := fyne.CurrentApp().Preferences().StringWithFallback(key, choices[0].name)
+	for _, a := range choices {
+		if a.name == name {
+			return a
+		}
+	}
+	return choices[0]
+}
+
+func nudgeAmount() seekAmount { return configuredAmount(prefNudgeAmount, nudgeAmounts) }
+func pageAmount() seekAmount  { return configuredAmount(prefPageAmount, pageAmounts) }
+
+// seekRelative moves vp by amount in direction, -1 for back and 1 for
+// forward, staying within its in/out range. All keyboard nudges and track
+// clicks go through here.
+func (vp *VideoPlayer) seekRelative(amount seekAmount, direction int) {
+	if !vp.canPlay() || vp.duration <= 0 {
+		return
+	}
+	if amount.frames > 0 {
+		vp.stepFrame(direction * amount.frames)
+		return
+	}
+	start, end := vp.playRange()
+	vp.seekTo(math.Max(start, math.Min(end, vp.currentTime+float64(direction)*amount.seconds)))
+}
+
+// seekBarSteps is the resolution of the progress bars, fine enough that a
+// scrub lands within a second on a day-long file.
+const seekBarSteps = 100000
+
+// seekBar is a progress bar whose arrow keys nudge and whose track, away
+// from the handle, jumps by a page instead of to the clicked position.
+// Dragging the handle scrubs as usual.
+type seekBar struct {
+	widget.Slider
+	onSeek func(amount seekAmount, direction int)
+}
+
+func newSeekBar() *seekBar {
+	s := &seekBar{}
+	s.Max = seekBarSteps
+	s.Step = 1
+	s.Orientation = widget.Horizontal
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+// Tapped jumps a page towards the tap, or does nothing on the handle.
+func (s *seekBar) Tapped(ev *fyne.PointEvent) {
+	if s.Disabled() || s.onSeek == nil {
+		return
+	}
+	// Mirrors the slider's own geometry: the handle's centre travels
+	// between two end offsets
+	inline := s.Theme().Size(theme.SizeNameInlineIcon)
+	diameter := inline - 4
+	pad := diameter/2 + s.Theme().Size(theme.SizeNameInnerPadding) - 1.5
+	ratio := float32((s.Value - s.Min) / (s.Max - s.Min))
+	handle := pad + ratio*(s.Size().Width-2*pad)
+	switch {
+	case ev.Position.X < handle-diameter/2:
+		s.onSeek(pageAmount(), -1)
+	case ev.Position.X > handle+diameter/2:
+		s.onSeek(pageAmount(), 1)
+	}
+}
+
+// TypedKey nudges with the left and right arrows.
+func (s *seekBar) TypedKey(ev *fyne.KeyEvent) {
+	if s.Disabled() || s.onSeek == nil {
+		s.Slider.TypedKey(ev)
+		return
+	}
+	switch ev.Name {
+	case fyne.KeyLeft:
+		s.onSeek(nudgeAmount(), -1)
+	case fyne.KeyRight:
+		s.onSeek(nudgeAmount(), 1)
+	case fyne.KeyPageUp:
+		s.onSeek(pageAmount(), -1)
+	case fyne.KeyPageDown:
+		s.onSeek(pageAmount(), 1)
+	default:
+		s.Slider.TypedKey(ev)
+	}
+}
+
+// nudge moves vp relatively, taking the other player along while the sync
+// lock is on, like a scrub.
+func (app *VideoCompareApp) nudge(vp *VideoPlayer, amount seekAmount, direction int) {
+	vp.seekRelative(amount, direction)
+	app.macro.recordSide(vp, macroCommand{name: "seek", value: vp.currentTime})
+	app.followScrub(vp)
+}
+
+// nudgeAll moves both players relatively, for the arrow and page keys
+// pressed while no control has focus.
+func (app *VideoCompareApp) nudgeAll(amount seekAmount, direction int) {
+	for _, vp := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		vp.seekRelative(amount, direction)
+		if amount.frames == 0 && vp.canPlay() {
+			app.macro.recordSide(vp, macroCommand{name: "seek", value: vp.currentTime})
+		}
+	}
+	if amount.frames > 0 {
+		app.macro.record(macroCommand{name: "step", value: float64(direction * amount.frames)})
+	}
+}
+
+// typedKey handles keys typed while no control has focus: the arrows and
+// Page Up/Down seek both players, everything else goes to the shuttle.
+func (app *VideoCompareApp) typedKey(ev *fyne.KeyEvent) {
+	switch ev.Name {
+	case fyne.KeyLeft:
+		app.nudgeAll(nudgeAmount(), -1)
+	case fyne.KeyRight:
+		app.nudgeAll(nudgeAmount(), 1)
+	case fyne.KeyPageUp:
+		app.nudgeAll(pageAmount(), -1)
+	case fyne.KeyPageDown:
+		app.nudgeAll(pageAmount(), 1)
+	default:
+		app.shuttle.typedKey(ev)
+	}
+}
+
+// shortcutsMenuItem groups the keyboard and mouse preferences, naming the
+// current nudge and page amounts.
+func (app *VideoCompareApp) shortcutsMenuItem() *fyne.MenuItem {
+	item := fyne.NewMenuItem(tr("Shortcuts"), nil)
+	nudge := app.seekAmountMenuItem(tr("Arrow Key Nudge"), prefNudgeAmount, nudgeAmounts)
+	page := app.seekAmountMenuItem(tr("Track Click and Page Up/Down"), prefPageAmount, pageAmounts)
+	item.ChildMenu = fyne.NewMenu("", app.scrubAloneMenuItem(), nudge, page)
+	return item
+}
+
+// seekAmountMenuItem chooses the amount saved under key. Its label shows
+// the current choice.
+func (app *VideoCompareApp) seekAmountMenuItem(title, key string, choices []seekAmount) *fyne.MenuItem {
+	item := fyne.NewMenuItem("", nil)
+	menu := fyne.NewMenu("")
+	update := func() {
+		current := configuredAmount(key, choices)
+		item.Label = fmt.Sprintf("%s (%s)", title, current.name)
+		for i, choice := range menu.Items {
+			choice.Checked = choices[i].name == current.name
+		}
+	}
+	for _, a := range choices {
+		menu.Items = append(menu.Items, fyne.NewMenuItem(a.name, func() {
+			fyne.CurrentApp().Preferences().SetString(key, a.name)
+			update()
+			app.window.MainMenu().Refresh()
+		}))
+	}
+	update()
+	item.ChildMenu = menu
+	return item
+}
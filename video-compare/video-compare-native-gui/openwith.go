@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// openRequestTimeout bounds how long a second launch waits on the running
+// instance before opening a window of its own.
+const openRequestTimeout = 2 * time.Second
+
+// openSocketPath is where the running instance listens for files opened
+// from the file manager while it is already up. It sits beside the cache,
+// not in it, so eviction never removes it.
+func openSocketPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "video-compare-open.sock")
+}
+
+// absolutePaths resolves paths against the working directory, which the
+// running instance receiving them may not share.
+func absolutePaths(paths []string) []string {
+	abs := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if a, err := filepath.Abs(p); err == nil {
+			p = a
+		}
+		abs = append(abs, p)
+	}
+	return abs
+}
+
+// forwardToRunning hands paths to an already running instance, reporting
+// whether it took them; this launch should then exit.
+func forwardToRunning(paths []string) bool {
+	conn, err := net.DialTimeout("unix", openSocketPath(), openRequestTimeout)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(openRequestTimeout))
+	if err := json.NewEncoder(conn).Encode(paths); err != nil {
+		log.Printf("forwarding files to the running instance: %v", err)
+		return false
+	}
+	// Wait for the acknowledgement so a dying instance doesn't swallow them
+	var ack [1]byte
+	_, err = conn.Read(ack[:])
+	return err == nil
+}
+
+// listenForOpenRequests accepts files forwarded by later launches and
+// opens them as if passed on the command line, until the returned function
+// is called. Failing to listen only means later launches open their own
+// window.
+func (app *VideoCompareApp) listenForOpenRequests() (stop func()) {
+	path := openSocketPath()
+	if conn, err := net.DialTimeout("unix", path, openRequestTimeout); err == nil {
+		// Another instance launched without files already listens
+		conn.Close()
+		return func() {}
+	}
+	// Nothing answered, so a leftover socket is stale
+	_ = os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		log.Printf("listening for files opened with video-compare: %v", err)
+		return func() {}
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			var paths []string
+			_ = conn.SetDeadline(time.Now().Add(openRequestTimeout))
+			if err := json.NewDecoder(conn).Decode(&paths); err == nil {
+				_, _ = conn.Write([]byte{1})
+				fyne.Do(func() {
+					app.window.RequestFocus()
+					app.openPaths(paths, nil)
+				})
+			}
+			conn.Close()
+		}
+	}()
+	return func() { listener.Close() }
+}
+
+// validateOpenPath checks path is a file of a supported format.
+func validateOpenPath(path string) error {
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("%s: no such file", path)
+	case err != nil:
+		return err
+	case info.IsDir():
+		return fmt.Errorf("%s is a folder", path)
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if !slices.Contains(acceptedFormats(), ext) {
+		return fmt.Errorf("%s: %q is not a supported format (see File > Supported Formats)", displayName(path), ext)
+	}
+	return nil
+}
+
+// openPaths loads files opened from the file manager, passed on the
+// command line or dropped on the window. Valid files go to the empty
+// sides first, left before right; with no side free one file replaces
+// the right video and two replace both. A single file dropped on a
+// player goes to that player instead. The rest are reported.
+func (app *VideoCompareApp) openPaths(paths []string, target *VideoPlayer) {
+	var valid, problems []string
+	for _, p := range paths {
+		if err := validateOpenPath(p); err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		valid = append(valid, p)
+	}
+	if len(valid) > 2 {
+		problems = append(problems, fmt.Sprintf("Only two files can be compared; ignored %s", strings.Join(valid[2:], ", ")))
+		valid = valid[:2]
+	}
+
+	var sides []*VideoPlayer
+	switch {
+	case target != nil && len(valid) == 1:
+		sides = []*VideoPlayer{target}
+	default:
+		for _, vp := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+			if vp.path == "" {
+				sides = append(sides, vp)
+			}
+		}
+		if len(sides) < len(valid) {
+			sides = []*VideoPlayer{app.leftPlayer, app.rightPlayer}[2-len(valid):]
+		}
+	}
+	for i, p := range valid {
+		app.loadVideo(sides[i], p)
+	}
+	if len(problems) > 0 {
+		dialog.ShowError(errors.New(strings.Join(problems, "\n")), app.window)
+	}
+}
+
+// droppedOn returns the player under pos in the window, or nil.
+func (app *VideoCompareApp) droppedOn(pos fyne.Position) *VideoPlayer {
+	for _, vp := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		d := vp.display
+		origin := fyne.CurrentApp().Driver().AbsolutePositionForObject(d)
+		size := d.Size()
+		if pos.X >= origin.X && pos.X < origin.X+size.Width && pos.Y >= origin.Y && pos.Y < origin.Y+size.Height {
+			return vp
+		}
+	}
+	return nil
+}
+
+// filesDropped opens files dragged onto the window from the file manager.
+func (app *VideoCompareApp) filesDropped(pos fyne.Position, uris []fyne.URI) {
+	var paths []string
+	for _, u := range uris {
+		if u.Scheme() == "file" {
+			paths = append(paths, u.Path())
+		}
+	}
+	if len(paths) > 0 {
+		app.openPaths(paths, app.droppedOn(pos))
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+)
+
+type overlayCorner int
+
+const (
+	cornerTopLeft overlayCorner = iota
+	cornerTopRight
+	cornerBottomLeft
+	cornerBottomRight
+)
+
+var overlayCornerNames = []string{"Top Left", "Top Right", "Bottom Left", "Bottom Right"}
+
+func (c overlayCorner) String() string {
+	return overlayCornerNames[c]
+}
+
+func parseOverlayCorner(name string) overlayCorner {
+	for i, n := range overlayCornerNames {
+		if n == name {
+			return overlayCorner(i)
+		}
+	}
+	return cornerTopLeft
+}
+
+// burnInSettings controls the timecode overlay shared by both players.
+type burnInSettings struct {
+	enabled bool
+	corner  overlayCorner
+}
+
+// newOverlay creates the layer drawn on top of the player's video canvas.
+func (vp *VideoPlayer) newOverlay() *fyne.Container {
+	vp.timecodeText = canvas.NewText("", color.White)
+	vp.timecodeText.TextStyle = fyne.TextStyle{Monospace: true, Bold: true}
+	vp.timecodeBox = canvas.NewRectangle(color.NRGBA{A: 170})
+	vp.overlay = container.NewStack()
+	return vp.overlay
+}
+
+// refreshOverlays re-applies the burn-in settings to both players.
+func (app *VideoCompareApp) refreshOverlays() {
+	app.leftPlayer.layoutTimecodeOverlay()
+	app.rightPlayer.layoutTimecodeOverlay()
+}
+
+// layoutTimecodeOverlay places the burned-in timecode in the configured
+// corner, or removes it when the overlay is disabled.
+func (vp *VideoPlayer) layoutTimecodeOverlay() {
+	if vp.overlay == nil || vp.burnIn == nil {
+		return
+	}
+	if !vp.burnIn.enabled {
+		vp.overlay.Objects = nil
+		vp.overlay.Refresh()
+		return
+	}
+
+	label := container.NewStack(vp.timecodeBox, container.NewPadded(vp.timecodeText))
+
+	var row *fyne.Container
+	if vp.burnIn.corner == cornerTopRight || vp.burnIn.corner == cornerBottomRight {
+		row = container.NewHBox(layout.NewSpacer(), label)
+	} else {
+		row = container.NewHBox(label, layout.NewSpacer())
+	}
+
+	var column *fyne.Container
+	if vp.burnIn.corner == cornerBottomLeft || vp.burnIn.corner == cornerBottomRight {
+		column = container.NewVBox(layout.NewSpacer(), row)
+	} else {
+		column = container.NewVBox(row, layout.NewSpacer())
+	}
+
+	vp.overlay.Objects = []fyne.CanvasObject{column}
+	vp.overlay.Refresh()
+	vp.updateTimecodeOverlay()
+}
+
+// updateTimecodeOverlay refreshes the burned-in timecode text.
+func (vp *VideoPlayer) updateTimecodeOverlay() {
+	if vp.timecodeText == nil || vp.burnIn == nil || !vp.burnIn.enabled {
+		return
+	}
+	vp.timecodeText.Text = vp.timecode()
+	vp.timecodeText.Refresh()
+}
+
+// timecode returns the current position as HH:MM:SS:FF plus frame number.
+func (vp *VideoPlayer) timecode() string {
+	if vp.usesSourceTimecode() {
+		return fmt.Sprintf("%s  #%d", vp.sourceTimecodeAt(vp.currentTime), frameNumber(vp.currentTime, vp.fps))
+	}
+	return fmt.Sprintf("%s  #%d", formatTimecode(vp.currentTime, vp.fps), frameNumber(vp.currentTime, vp.fps))
+}
+
+// timecodeFileStamp is the current timecode in a form safe for file names.
+func (vp *VideoPlayer) timecodeFileStamp() string {
+	return strings.ReplaceAll(formatTimecode(vp.currentTime, vp.fps), ":", "-")
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	libvlc "github.com/adrg/libvlc-go/v3"
+)
+
+// mediaParseTimeout bounds how long libvlc may spend reading a file's
+// tracks. Large remote files can take a while; past this the load is
+// reported as stalled and whatever was read so far is used.
+const mediaParseTimeout = 30 * time.Second
+
+// newParseProgress creates the hidden activity bar shown while vp's media
+// is being analyzed.
+func (vp *VideoPlayer) newParseProgress() *widget.ProgressBarInfinite {
+	vp.parseProgress = widget.NewProgressBarInfinite()
+	vp.parseProgress.Hide()
+	return vp.parseProgress
+}
+
+// parseMedia reads the tracks of vp's media in the background. Playback
+// controls stay disabled until libvlc reports the media parsed, after
+// which the queued whenParsed callbacks run.
+func (vp *VideoPlayer) parseMedia() {
+	media := vp.media
+	vp.parsing, vp.parseTimedOut = true, false
+	vp.setState(stateLoading)
+	vp.refreshFileLabel()
+	vp.parseProgress.Show()
+	vp.parseProgress.Start()
+
+	manager, err := media.EventManager()
+	if err != nil {
+		log.Printf("%s: media event manager: %v", vp.title, err)
+		vp.parsed(media)
+		return
+	}
+	// The callback runs on a libvlc thread, so the result is read on the UI
+	// goroutine
+	id, err := manager.Attach(libvlc.MediaParsedChanged, func(libvlc.Event, interface{}) {
+		fyne.Do(func() { vp.parsed(media) })
+	}, nil)
+	if err != nil {
+		log.Printf("%s: watching media parse: %v", vp.title, err)
+		vp.parsed(media)
+		return
+	}
+	vp.detachParse = func() { manager.Detach(id) }
+
+	timeout := int(mediaParseTimeout / time.Millisecond)
+	if err := media.ParseWithOptions(timeout, libvlc.MediaParseLocal, libvlc.MediaParseNetwork); err != nil {
+		log.Printf("%s: parsing %s: %v", vp.title, vp.path, err)
+		vp.parsed(media)
+	}
+}
+
+// parsed takes over the tracks of media once libvlc finished or gave up
+// parsing it. Results for media that was replaced in the meantime are
+// dropped.
+func (vp *VideoPlayer) parsed(media *libvlc.Media) {
+	if vp.media != media || !vp.parsing {
+		return
+	}
+	if vp.detachParse != nil {
+		vp.detachParse()
+		vp.detachParse = nil
+	}
+	vp.parsing = false
+	vp.parseProgress.Stop()
+	vp.parseProgress.Hide()
+	vp.refreshFileLabel()
+	vp.setState(stateIdle)
+
+	switch status, _ := media.ParseStatus(); status {
+	case libvlc.MediaParseTimeout:
+		vp.parseTimedOut = true
+		log.Printf("%s: analyzing %s timed out after %s", vp.title, vp.path, mediaParseTimeout)
+	case libvlc.MediaParseFailed, libvlc.MediaParseUnstarted:
+		log.Printf("%s: libvlc could not parse %s", vp.title, vp.path)
+	}
+	vp.extractMediaInfo()
+	// Show the duration and position right away rather than once playback
+	// starts, so the file can be seeked before it is ever played
+	vp.updateTimeDisplay()
+	vp.updateProgressBar()
+	vp.updateStats()
+	vp.updateVideoCanvas()
+	vp.updateControls()
+	vp.runAfterParse()
+}
+
+// runAfterParse runs the callbacks queued by whenParsed and onParsed, once
+// vp's tracks are known or the file turned out to have none to parse.
+func (vp *VideoPlayer) runAfterParse() {
+	callbacks := vp.afterParse
+	vp.afterParse = nil
+	for _, fn := range callbacks {
+		fn()
+	}
+	if vp.onParsed != nil {
+		vp.onParsed()
+	}
+}
+
+// mediaParsed refreshes what depends on vp's tracks once they are known.
+func (app *VideoCompareApp) mediaParsed(vp *VideoPlayer) {
+	app.updateFrameControls()
+	app.updateComparisonControls()
+	app.updateStats()
+	app.checkRotation()
+	app.loudness.load(vp)
+	app.playerSeeked()
+	app.frameInfo.refresh(vp)
+	app.updateTimecodeAlign()
+	vp.updateSeekHint()
+}
+
+// whenParsed runs fn once vp's tracks are known: right away unless the
+// file is still being opened or analyzed. Loading another file drops
+// pending calls.
+func (vp *VideoPlayer) whenParsed(fn func()) {
+	if vp.opening || vp.parsing {
+		vp.afterParse = append(vp.afterParse, fn)
+		return
+	}
+	fn()
+}
+
+// stopParsing abandons analyzing the current media, for when it is
+// replaced or unloaded.
+func (vp *VideoPlayer) stopParsing() {
+	if vp.detachParse != nil {
+		vp.detachParse()
+		vp.detachParse = nil
+	}
+	if vp.parsing {
+		_ = vp.media.StopParse()
+		vp.parsing = false
+		vp.parseProgress.Stop()
+		vp.parseProgress.Hide()
+	}
+	vp.afterParse = nil
+}
+
+// cancelLoad abandons opening a file in the background; whatever the load
+// produces once it finishes is discarded.
+func (vp *VideoPlayer) cancelLoad() {
+	vp.loadSeq++
+	if vp.opening {
+		vp.opening = false
+		vp.parseProgress.Stop()
+		vp.parseProgress.Hide()
+		vp.afterParse = nil
+	}
+}
+
+// parseNotice explains a parse that did not complete.
+func (vp *VideoPlayer) parseNotice() string {
+	if vp.parseTimedOut {
+		return trf("Analyzing timed out after %s — track information may be incomplete", mediaParseTimeout.String())
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"math"
+
+	"fyne.io/fyne/v2"
+)
+
+// Measures of the difference between two pixels offered by the diff
+// heatmap. Raw differences of the gamma-encoded values make small shifts
+// in dark areas look as large as in bright ones; the other two follow
+// what the eye notices more closely.
+const (
+	diffRaw    = "Raw RGB"
+	diffGamma  = "Gamma-weighted"
+	diffDeltaE = "Lab ΔE (CIEDE2000)"
+)
+
+const diffModePref = "export.heatmapDifference"
+
+var diffModes = []string{diffRaw, diffGamma, diffDeltaE}
+
+// deltaEFullScale is the ΔE shown at the top of the colormap before
+// amplification.
+const deltaEFullScale = 100
+
+// srgbLinear maps an 8-bit sRGB value to linear light in [0, 1].
+var srgbLinear = func() (t [256]float64) {
+	for i := range t {
+		v := float64(i) / 255
+		if v <= 0.04045 {
+			t[i] = v / 12.92
+		} else {
+			t[i] = math.Pow((v+0.055)/1.055, 2.4)
+		}
+	}
+	return t
+}()
+
+// heatmapDiffMode returns the difference measure last chosen for heatmaps.
+func heatmapDiffMode() string {
+	return fyne.CurrentApp().Preferences().StringWithFallback(diffModePref, diffRaw)
+}
+
+// pixelDifference returns a function measuring the difference between two
+// RGB pixels under mode, scaled so 255 is the top of the colormap.
+func pixelDifference(mode string) func(a, b []uint8) float64 {
+	switch mode {
+	case diffGamma:
+		return func(a, b []uint8) float64 {
+			d := math.Abs(srgbLinear[a[0]]-srgbLinear[b[0]]) +
+				math.Abs(srgbLinear[a[1]]-srgbLinear[b[1]]) +
+				math.Abs(srgbLinear[a[2]]-srgbLinear[b[2]])
+			return d / 3 * 255
+		}
+	case diffDeltaE:
+		return func(a, b []uint8) float64 {
+			return deltaE2000(srgbToLab(a[0], a[1], a[2]), srgbToLab(b[0], b[1], b[2])) * 255 / deltaEFullScale
+		}
+	}
+	return func(a, b []uint8) float64 {
+		return float64(absDiff(a[0], b[0])+absDiff(a[1], b[1])+absDiff(a[2], b[2])) / 3
+	}
+}
+
+// lab is a color in CIE L*a*b* relative to the D65 white point.
+type lab struct{ L, A, B float64 }
+
+// srgbToLab converts an 8-bit sRGB color to L*a*b*.
+func srgbToLab(r, g, b uint8) lab {
+	rl, gl, bl := srgbLinear[r], srgbLinear[g], srgbLinear[b]
+	x := (0.4124564*rl + 0.3575761*gl + 0.1804375*bl) / 0.95047
+	y := 0.2126729*rl + 0.7151522*gl + 0.0721750*bl
+	z := (0.0193339*rl + 0.1191920*gl + 0.9503041*bl) / 1.08883
+
+	f := func(t float64) float64 {
+		if t > 216.0/24389 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27*t + 16) / 116
+	}
+	fx, fy, fz := f(x), f(y), f(z)
+	return lab{L: 116*fy - 16, A: 500 * (fx - fy), B: 200 * (fy - fz)}
+}
+
+// deltaE2000 is the CIEDE2000 color difference between two L*a*b* colors,
+// with the reference weighting factors kL = kC = kH = 1.
+func deltaE2000(c1, c2 lab) float64 {
+	pow25to7 := math.Pow(25, 7)
+	sq := func(v float64) float64 { return v * v }
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	cBar := (math.Hypot(c1.A, c1.B) + math.Hypot(c2.A, c2.B)) / 2
+	g := 0.5 * (1 - math.Sqrt(math.Pow(cBar, 7)/(math.Pow(cBar, 7)+pow25to7)))
+	a1, a2 := (1+g)*c1.A, (1+g)*c2.A
+	cp1, cp2 := math.Hypot(a1, c1.B), math.Hypot(a2, c2.B)
+	hue := func(b, a float64) float64 {
+		if a == 0 && b == 0 {
+			return 0
+		}
+		h := math.Atan2(b, a) * 180 / math.Pi
+		if h < 0 {
+			h += 360
+		}
+		return h
+	}
+	hp1, hp2 := hue(c1.B, a1), hue(c2.B, a2)
+
+	dL := c2.L - c1.L
+	dC := cp2 - cp1
+	var dh float64
+	if cp1*cp2 != 0 {
+		dh = hp2 - hp1
+		if dh > 180 {
+			dh -= 360
+		} else if dh < -180 {
+			dh += 360
+		}
+	}
+	dH := 2 * math.Sqrt(cp1*cp2) * math.Sin(rad(dh/2))
+
+	lBar := (c1.L + c2.L) / 2
+	cpBar := (cp1 + cp2) / 2
+	hBar := hp1 + hp2
+	if cp1*cp2 != 0 {
+		if math.Abs(hp1-hp2) > 180 {
+			if hBar < 360 {
+				hBar += 360
+			} else {
+				hBar -= 360
+			}
+		}
+		hBar /= 2
+	}
+
+	t := 1 - 0.17*math.Cos(rad(hBar-30)) + 0.24*math.Cos(rad(2*hBar)) +
+		0.32*math.Cos(rad(3*hBar+6)) - 0.20*math.Cos(rad(4*hBar-63))
+	dTheta := 30 * math.Exp(-sq((hBar-275)/25))
+	rc := 2 * math.Sqrt(math.Pow(cpBar, 7)/(math.Pow(cpBar, 7)+pow25to7))
+	sl := 1 + 0.015*sq(lBar-50)/math.Sqrt(20+sq(lBar-50))
+	sc := 1 + 0.045*cpBar
+	sh := 1 + 0.015*cpBar*t
+	rt := -math.Sin(rad(2*dTheta)) * rc
+
+	return math.Sqrt(sq(dL/sl) + sq(dC/sc) + sq(dH/sh) + rt*(dC/sc)*(dH/sh))
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+
+	"videocompare"
+)
+
+// probeTimeout bounds in-process work waiting on a single ffmpeg run, as
+// videocompare bounds each ffprobe run.
+const probeTimeout = videocompare.ProbeTimeout
+
+// The ffmpeg and ffprobe runners are videocompare's, which share the
+// background job limit with the Wails app's.
+var (
+	runFFmpeg      = videocompare.RunFFmpeg
+	runFFmpegLog   = videocompare.RunFFmpegLog
+	runFFmpegInput = videocompare.RunFFmpegInput
+	streamFFmpeg   = videocompare.StreamFFmpeg
+	runFFmpegTo    = videocompare.RunFFmpegTo
+)
+
+// runFFprobe runs ffprobe with JSON output and returns its stdout. Probes
+// aren't cancelled; each is bounded by videocompare.ProbeTimeout once it
+// has a job slot.
+func runFFprobe(args ...string) ([]byte, error) {
+	return videocompare.RunFFprobe(context.Background(), args...)
+}
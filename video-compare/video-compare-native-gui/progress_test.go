@@ -0,0 +1,35 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+// TestProgressTicksDontSeek plays ten seconds of 100 ms ticks, each
+// moving the progress bar from code, and checks none of them seeks while
+// a drag of the bar still does.
+func TestProgressTicksDontSeek(t *testing.T) {
+	test.NewTempApp(t)
+	vp := &VideoPlayer{progressBar: newSeekBar(), duration: 60}
+	var seeks []float64
+	vp.bindProgressBar(func(seconds float64) { seeks = append(seeks, seconds) })
+
+	for range 100 {
+		vp.currentTime += 0.1
+		vp.updateProgressBar()
+	}
+	if len(seeks) != 0 {
+		t.Fatalf("playback ticks seeked %d time(s), want none", len(seeks))
+	}
+	if vp.progressBar.Value == 0 {
+		t.Error("progress bar didn't move with playback")
+	}
+
+	// A drag to the middle of the bar seeks once
+	vp.progressBar.SetValue(seekBarSteps / 2)
+	if len(seeks) != 1 || math.Abs(seeks[0]-30) > 1e-9 {
+		t.Errorf("drag to the middle seeked to %v, want [30]", seeks)
+	}
+}
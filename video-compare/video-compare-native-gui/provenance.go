@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// provenanceField is a key/value pair tying the comparison to the pipeline
+// run that produced its files, such as a build ID, encoder version or
+// commit.
+type provenanceField struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// provenancePanel edits the session's provenance fields, which are saved
+// in sessions, listed in reports and optionally captioned under
+// side-by-side images.
+type provenancePanel struct {
+	app      *VideoCompareApp
+	fields   []provenanceField
+	burnIn   bool
+	selected int
+
+	list        *widget.List
+	keyEntry    *widget.Entry
+	valueEntry  *widget.Entry
+	updateBtn   *widget.Button
+	deleteBtn   *widget.Button
+	burnInCheck *widget.Check
+}
+
+func newProvenancePanel(app *VideoCompareApp) *provenancePanel {
+	return &provenancePanel{app: app, selected: -1}
+}
+
+func (pp *provenancePanel) content() fyne.CanvasObject {
+	pp.list = widget.NewList(
+		func() int { return len(pp.fields) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(pp.fields[id].String())
+		},
+	)
+	pp.list.OnSelected = pp.selectField
+	pp.list.OnUnselected = func(widget.ListItemID) { pp.clearSelection() }
+
+	pp.keyEntry = widget.NewEntry()
+	pp.keyEntry.SetPlaceHolder("Key, e.g. build")
+	pp.valueEntry = widget.NewEntry()
+	pp.valueEntry.SetPlaceHolder("Value, e.g. 2024.06.1-42")
+	pp.valueEntry.OnSubmitted = func(string) { pp.addField() }
+
+	addBtn := widget.NewButtonWithIcon("Add", theme.ContentAddIcon(), pp.addField)
+	pp.updateBtn = widget.NewButtonWithIcon("Update", theme.DocumentSaveIcon(), pp.updateField)
+	pp.deleteBtn = widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), pp.deleteField)
+	pp.updateBtn.Disable()
+	pp.deleteBtn.Disable()
+	pp.burnInCheck = widget.NewCheck("Caption side-by-side images", func(on bool) { pp.burnIn = on })
+	pp.burnInCheck.SetChecked(pp.burnIn)
+
+	entries := container.NewGridWithColumns(2, pp.keyEntry, pp.valueEntry)
+	buttons := container.NewHBox(addBtn, pp.updateBtn, pp.deleteBtn, widget.NewSeparator(), pp.burnInCheck)
+	return container.NewBorder(container.NewBorder(nil, nil, nil, buttons, entries), nil, nil, nil, pp.list)
+}
+
+func (f provenanceField) String() string {
+	return f.Key + ": " + f.Value
+}
+
+// entered returns the field in the entries, or false without a key.
+func (pp *provenancePanel) entered() (provenanceField, bool) {
+	f := provenanceField{Key: strings.TrimSpace(pp.keyEntry.Text), Value: strings.TrimSpace(pp.valueEntry.Text)}
+	return f, f.Key != ""
+}
+
+// addField adds the entered field, replacing the value of one with the
+// same key.
+func (pp *provenancePanel) addField() {
+	f, ok := pp.entered()
+	if !ok {
+		return
+	}
+	if i := pp.index(f.Key); i >= 0 {
+		pp.fields[i] = f
+	} else {
+		pp.fields = append(pp.fields, f)
+	}
+	pp.keyEntry.SetText("")
+	pp.valueEntry.SetText("")
+	pp.list.UnselectAll()
+	pp.list.Refresh()
+}
+
+func (pp *provenancePanel) index(key string) int {
+	for i, f := range pp.fields {
+		if f.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (pp *provenancePanel) selectField(id widget.ListItemID) {
+	if id < 0 || id >= len(pp.fields) {
+		return
+	}
+	pp.selected = id
+	pp.keyEntry.SetText(pp.fields[id].Key)
+	pp.valueEntry.SetText(pp.fields[id].Value)
+	pp.updateBtn.Enable()
+	pp.deleteBtn.Enable()
+}
+
+func (pp *provenancePanel) clearSelection() {
+	pp.selected = -1
+	pp.updateBtn.Disable()
+	pp.deleteBtn.Disable()
+}
+
+func (pp *provenancePanel) updateField() {
+	f, ok := pp.entered()
+	if pp.selected < 0 || !ok {
+		return
+	}
+	if i := pp.index(f.Key); i >= 0 && i != pp.selected {
+		// Renamed onto another field's key, which it replaces
+		pp.fields[i] = f
+		pp.deleteField()
+		return
+	}
+	pp.fields[pp.selected] = f
+	pp.list.Refresh()
+}
+
+func (pp *provenancePanel) deleteField() {
+	if pp.selected < 0 {
+		return
+	}
+	pp.fields = append(pp.fields[:pp.selected], pp.fields[pp.selected+1:]...)
+	pp.keyEntry.SetText("")
+	pp.valueEntry.SetText("")
+	pp.list.UnselectAll()
+	pp.clearSelection()
+	pp.list.Refresh()
+}
+
+// setFields replaces all fields and the caption setting, e.g. when a
+// session is loaded.
+func (pp *provenancePanel) setFields(fields []provenanceField, burnIn bool) {
+	pp.fields = append([]provenanceField(nil), fields...)
+	pp.burnIn = burnIn
+	pp.burnInCheck.SetChecked(burnIn)
+	pp.list.UnselectAll()
+	pp.clearSelection()
+	pp.list.Refresh()
+}
+
+// captionLines returns the fields added under a side-by-side image, one
+// per line, or nil when captioning is off.
+func (pp *provenancePanel) captionLines() []string {
+	if !pp.burnIn || len(pp.fields) == 0 {
+		return nil
+	}
+	lines := make([]string, len(pp.fields))
+	for i, f := range pp.fields {
+		lines[i] = f.String()
+	}
+	return lines
+}
+
+// summary lists the fields on one line, for plain-text exports.
+func (pp *provenancePanel) summary() string {
+	parts := make([]string, len(pp.fields))
+	for i, f := range pp.fields {
+		parts[i] = fmt.Sprintf("%s=%s", f.Key, f.Value)
+	}
+	return strings.Join(parts, ", ")
+}
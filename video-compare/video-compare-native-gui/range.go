@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// playRange is the part of the clip the player treats as the whole clip:
+// seeking, stepping and the progress bar are confined to it. Without an
+// in/out range set it spans the full duration.
+func (vp *VideoPlayer) playRange() (start, end float64) {
+	end = vp.duration
+	if vp.rangeEnd > 0 && vp.rangeEnd < end {
+		end = vp.rangeEnd
+	}
+	return math.Min(vp.rangeStart, end), end
+}
+
+func (vp *VideoPlayer) hasRange() bool {
+	start, end := vp.playRange()
+	return start > 0 || end < vp.duration
+}
+
+func (vp *VideoPlayer) clampToRange(seconds float64) float64 {
+	start, end := vp.playRange()
+	return math.Min(end, math.Max(start, seconds))
+}
+
+// setRange sets the in/out points, moving playback inside them if needed.
+// An end of 0 means the end of the clip.
+func (vp *VideoPlayer) setRange(start, end float64) {
+	if end > 0 && end <= start {
+		return
+	}
+	vp.rangeStart, vp.rangeEnd = math.Max(0, start), end
+	if vp.rangeEnd >= vp.duration {
+		vp.rangeEnd = 0
+	}
+	if t := vp.clampToRange(vp.currentTime); t != vp.currentTime {
+		vp.seekTo(t)
+	}
+	vp.updateTimeDisplay()
+	vp.updateProgressBar()
+	vp.updateRangeLabel()
+}
+
+// stopAtRangeEnd pauses playback once it runs past the out point.
+func (vp *VideoPlayer) stopAtRangeEnd() {
+	if vp.loopAtRangeEnd() {
+		return
+	}
+	if _, end := vp.playRange(); vp.playing() && vp.rangeEnd > 0 && vp.currentTime >= end {
+		vp.pause()
+		vp.seekTo(end)
+	}
+}
+
+func (vp *VideoPlayer) updateRangeLabel() {
+	if vp.rangeLabel == nil {
+		return
+	}
+	if !vp.hasRange() {
+		vp.rangeLabel.SetText("Range: whole clip")
+		return
+	}
+	start, end := vp.playRange()
+	vp.rangeLabel.SetText(fmt.Sprintf("Range: %s – %s", formatTimecode(start, vp.fps), formatTimecode(end, vp.fps)))
+}
+
+// createRangeControls builds the in/out point controls for a player.
+func (app *VideoCompareApp) createRangeControls(vp *VideoPlayer) fyne.CanvasObject {
+	setIn := widget.NewButtonWithIcon("Set In", theme.MediaSkipPreviousIcon(), func() {
+		vp.setRange(vp.currentTime, vp.rangeEnd)
+	})
+	setOut := widget.NewButtonWithIcon("Set Out", theme.MediaSkipNextIcon(), func() {
+		vp.setRange(vp.rangeStart, vp.currentTime)
+	})
+	clearRange := widget.NewButtonWithIcon("Clear Range", theme.ContentClearIcon(), func() {
+		vp.setRange(0, 0)
+	})
+	loop := vp.newLoopCheck()
+	vp.seekControls = append(vp.seekControls, setIn, setOut, clearRange)
+	vp.playbackControls = append(vp.playbackControls, loop)
+	vp.rangeLabel = widget.NewLabel("")
+	vp.updateRangeLabel()
+	return container.NewHBox(setIn, setOut, clearRange, loop, vp.rangeLabel)
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"image"
+	"image/png"
+	"os"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// frameSettleDelay gives libvlc time to render the target frame after a
+// seek before it is grabbed.
+const frameSettleDelay = 300 * time.Millisecond
+
+type reportFigure struct {
+	Label    string
+	Timecode string
+	Image    template.URL // base64 data URI, so the report is self-contained
+}
+
+type reportNote struct {
+	Timecode string
+	Text     string
+}
+
+// reportScore is a quality measurement taken during the session.
+type reportScore struct {
+	Measure string
+	Scope   string
+	PSNR    string
+	SSIM    string
+}
+
+type reportDecodeError struct {
+	Side    string
+	File    string
+	Time    string
+	Message string
+}
+
+type reportData struct {
+	Generated    string
+	Left         string
+	Right        string
+	Provenance   []provenanceField
+	Metadata     []metadataRow
+	Scores       []reportScore
+	ScoreWarning string
+	Figures      []reportFigure
+	Notes        []reportNote
+	DecodeErrors []reportDecodeError
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8">
+<title>Video Comparison Report</title>
+<style>
+body { font-family: Arial, sans-serif; margin: 2em; background: #1a1a1a; color: #eee; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #555; padding: 6px 12px; text-align: left; }
+th { background: #333; }
+tr.differs td { background: #4a2a1a; }
+figure { margin: 0 0 2em 0; }
+figure img { max-width: 100%; border: 1px solid #555; }
+figcaption { margin-top: 0.5em; color: #ccc; }
+</style>
+</head>
+<body>
+<h1>Video Comparison Report</h1>
+<p>Generated {{.Generated}}</p>
+<p>Left: <code>{{.Left}}</code><br>Right: <code>{{.Right}}</code></p>
+
+{{if .Provenance}}<h2>Provenance</h2>
+<table>
+<tr><th>Key</th><th>Value</th></tr>
+{{range .Provenance}}<tr><td>{{.Key}}</td><td>{{.Value}}</td></tr>
+{{end}}</table>{{end}}
+
+<h2>Metadata</h2>
+<table>
+<tr><th>Property</th><th>Left</th><th>Right</th></tr>
+{{range .Metadata}}<tr{{if .Differs}} class="differs"{{end}}><td>{{.Name}}</td><td>{{.Left}}</td><td>{{.Right}}</td></tr>
+{{end}}</table>
+
+{{if .Scores}}<h2>Metrics</h2>
+{{if .ScoreWarning}}<p><strong>{{.ScoreWarning}}</strong></p>{{end}}
+<table>
+<tr><th>Measurement</th><th>Frames</th><th>PSNR</th><th>SSIM</th></tr>
+{{range .Scores}}<tr><td>{{.Measure}}</td><td>{{.Scope}}</td><td>{{.PSNR}}</td><td>{{.SSIM}}</td></tr>
+{{end}}</table>{{end}}
+
+{{if .Figures}}<h2>Figures</h2>
+{{range .Figures}}<figure>
+<img src="{{.Image}}" alt="{{.Label}}">
+<figcaption>{{.Timecode}} — {{.Label}}</figcaption>
+</figure>
+{{end}}{{end}}
+{{if .Notes}}<h2>Notes</h2>
+<table>
+<tr><th>Timecode</th><th>Note</th></tr>
+{{range .Notes}}<tr><td>{{.Timecode}}</td><td>{{.Text}}</td></tr>
+{{end}}</table>{{end}}
+{{if .DecodeErrors}}<h2>Decode Errors</h2>
+<table>
+<tr><th>Side</th><th>File</th><th>Time</th><th>Error</th></tr>
+{{range .DecodeErrors}}<tr><td>{{.Side}}</td><td>{{.File}}</td><td>{{.Time}}</td><td>{{.Message}}</td></tr>
+{{end}}</table>{{end}}
+</body>
+</html>
+`))
+
+// recordScores keeps the latest scores of measure for the report, replacing
+// the ones it gave before.
+func (app *VideoCompareApp) recordScores(measure string, scores ...reportScore) {
+	app.scores = slices.DeleteFunc(app.scores, func(s reportScore) bool { return s.Measure == measure })
+	for _, s := range scores {
+		s.Measure = measure
+		app.scores = append(app.scores, s)
+	}
+}
+
+// generateReport writes a self-contained HTML report with the provenance
+// fields, the metadata diff, the last PSNR and SSIM scores measured for the
+// loaded files, side-by-side figures at the given bookmarks, all review
+// notes and the logged decode errors. It runs in the background and reads
+// application state on the UI goroutine.
+func (app *VideoCompareApp) generateReport(outPath string, figures []bookmark) error {
+	var (
+		fps                       float64
+		data                      reportData
+		restoreLeft, restoreRight float64
+		leftDrawings              []*annotation
+		rightDrawings             []*annotation
+	)
+	fyne.DoAndWait(func() {
+		fps = app.leftPlayer.fps
+		data = reportData{
+			Generated:  time.Now().Format("2006-01-02 15:04:05"),
+			Left:       app.leftPlayer.path,
+			Right:      app.rightPlayer.path,
+			Provenance: append([]provenanceField(nil), app.provenance.fields...),
+			Metadata:   app.metadataDiff(),
+			Scores:     slices.Clone(app.scores),
+		}
+		if len(data.Scores) > 0 {
+			data.ScoreWarning = app.transcodeWarning()
+		}
+		for _, n := range app.notes.notes {
+			data.Notes = append(data.Notes, reportNote{Timecode: formatTimecode(n.Time, fps), Text: n.Text})
+		}
+		for _, e := range app.decodeErrs.errors {
+			data.DecodeErrors = append(data.DecodeErrors, reportDecodeError{
+				Side:    e.side,
+				File:    displayName(e.path),
+				Time:    formatMacroTime(e.seconds),
+				Message: e.message,
+			})
+		}
+		restoreLeft, restoreRight = app.leftPlayer.currentTime, app.rightPlayer.currentTime
+		leftDrawings = cloneAnnotations(app.leftPlayer.annotations)
+		rightDrawings = cloneAnnotations(app.rightPlayer.annotations)
+	})
+
+	if len(figures) > 0 {
+		defer fyne.DoAndWait(func() {
+			app.leftPlayer.seekTo(restoreLeft)
+			app.rightPlayer.seekTo(restoreRight)
+			app.annotator.setAnnotations(leftDrawings, rightDrawings)
+		})
+
+		for _, b := range figures {
+			fyne.DoAndWait(func() { app.annotator.setAnnotations(b.Left, b.Right) })
+			uri, err := app.captureDataURI(b.Time)
+			if err != nil {
+				return fmt.Errorf("capturing %q: %w", b.Label, err)
+			}
+			data.Figures = append(data.Figures, reportFigure{
+				Label:    b.Label,
+				Timecode: formatTimecode(b.Time, fps),
+				Image:    uri,
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, data); err != nil {
+		return err
+	}
+	return os.WriteFile(outPath, buf.Bytes(), 0o644)
+}
+
+// captureDataURI seeks both players to t and returns the side-by-side frame
+// as a PNG data URI.
+func (app *VideoCompareApp) captureDataURI(t float64) (template.URL, error) {
+	fyne.DoAndWait(func() { app.seekAll(t) })
+	time.Sleep(frameSettleDelay)
+
+	var export func() (*image.RGBA, error)
+	fyne.DoAndWait(func() { export = app.sideBySideExporter() })
+	img, err := export()
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())), nil
+}
+
+// generateReportDialog lets the user pick which bookmarks become figures and
+// where the report is written.
+func (app *VideoCompareApp) generateReportDialog() {
+	bookmarks := app.bookmarks.bookmarks
+	labels := make([]string, len(bookmarks))
+	for i, b := range bookmarks {
+		labels[i] = fmt.Sprintf("[%s] %s", formatTimecode(b.Time, app.leftPlayer.fps), b.Label)
+	}
+	choices := widget.NewCheckGroup(labels, nil)
+	choices.SetSelected(labels)
+
+	pickFile := func() {
+		var selected []bookmark
+		for i, label := range labels {
+			for _, s := range choices.Selected {
+				if s == label {
+					selected = append(selected, bookmarks[i])
+					break
+				}
+			}
+		}
+		app.saveReport(selected)
+	}
+
+	if len(bookmarks) == 0 {
+		pickFile()
+		return
+	}
+	dialog.ShowCustomConfirm("Include Bookmarks as Figures", "Continue", "Cancel", choices,
+		func(ok bool) {
+			if ok {
+				pickFile()
+			}
+		}, app.window)
+}
+
+func (app *VideoCompareApp) saveReport(figures []bookmark) {
+	fd := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		outPath := writer.URI().Path()
+		writer.Close()
+
+		progress := dialog.NewCustomWithoutButtons("Generating Report", widget.NewProgressBarInfinite(), app.window)
+		progress.Show()
+		go func() {
+			err := app.generateReport(outPath, figures)
+			fyne.Do(func() {
+				progress.Hide()
+				if err != nil {
+					dialog.ShowError(err, app.window)
+				}
+			})
+		}()
+	}, app.window)
+	fd.SetFileName("comparison-report.html")
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{".html"}))
+	fd.Show()
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"log"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"videocompare"
+)
+
+const toolROI = "ROI"
+
+var roiColor = color.NRGBA{R: 0xff, G: 0x40, B: 0xff, A: 0xff}
+
+// minROISize is the smallest ROI side, as a fraction of the frame, that a
+// drag creates; shorter drags are taken for clicks.
+const minROISize = 0.005
+
+// regionOfInterest is a rectangle on the frame as x0, y0, x1, y1 in
+// normalised frame coordinates, so one region applies to both players
+// whatever their resolution.
+type regionOfInterest [4]float64
+
+// roiSpan builds the region between two corners given in any order.
+func roiSpan(a, b [2]float64) regionOfInterest {
+	return regionOfInterest{math.Min(a[0], b[0]), math.Min(a[1], b[1]), math.Max(a[0], b[0]), math.Max(a[1], b[1])}
+}
+
+func (r regionOfInterest) valid() bool {
+	for _, v := range r {
+		if math.IsNaN(v) || v < 0 || v > 1 {
+			return false
+		}
+	}
+	return r[2]-r[0] >= minROISize && r[3]-r[1] >= minROISize
+}
+
+// pixels maps the region onto an image with bounds b, at least a pixel in
+// each direction.
+func (r regionOfInterest) pixels(b image.Rectangle) image.Rectangle {
+	w, h := float64(b.Dx()), float64(b.Dy())
+	x0, y0 := b.Min.X+int(r[0]*w), b.Min.Y+int(r[1]*h)
+	x1 := max(x0+1, b.Min.X+int(math.Ceil(r[2]*w)))
+	y1 := max(y0+1, b.Min.Y+int(math.Ceil(r[3]*h)))
+	return image.Rect(x0, y0, x1, y1).Intersect(b)
+}
+
+// cropFilter is the ffmpeg crop selecting the region, relative to the
+// input size.
+func (r regionOfInterest) cropFilter() string {
+	return fmt.Sprintf("crop=iw*%.6f:ih*%.6f:iw*%.6f:ih*%.6f", r[2]-r[0], r[3]-r[1], r[0], r[1])
+}
+
+// cropImage copies the part of img inside rect.
+func cropImage(img image.Image, rect image.Rectangle) image.Image {
+	out := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(out, out.Bounds(), img, rect.Min, draw.Src)
+	return out
+}
+
+// roiScores are the PSNR and SSIM over the whole frame and over the ROI.
+type roiScores struct {
+	fullPSNR, fullSSIM float64
+	roiPSNR, roiSSIM   float64
+}
+
+func (s roiScores) String() string {
+	text := fmt.Sprintf("Full frame: PSNR %s, SSIM %.4f\nROI: PSNR %s, SSIM %.4f",
+		formatPSNR(s.fullPSNR), s.fullSSIM, formatPSNR(s.roiPSNR), s.roiSSIM)
+	if d := s.fullPSNR - s.roiPSNR; !math.IsInf(s.fullPSNR, 0) && !math.IsInf(s.roiPSNR, 0) && math.Abs(d) >= 0.01 {
+		if d > 0 {
+			text += fmt.Sprintf(" (%.2f dB below the full frame)", d)
+		} else {
+			text += fmt.Sprintf(" (%.2f dB above the full frame)", -d)
+		}
+	}
+	return text
+}
+
+// reportScores gives s as the report's rows for frames.
+func (s roiScores) reportScores(frames string) []reportScore {
+	return []reportScore{
+		{Scope: frames + ", full frame", PSNR: formatPSNR(s.fullPSNR), SSIM: fmt.Sprintf("%.4f", s.fullSSIM)},
+		{Scope: frames + ", ROI", PSNR: formatPSNR(s.roiPSNR), SSIM: fmt.Sprintf("%.4f", s.roiSSIM)},
+	}
+}
+
+// compareROIFrames scores right against left, both as a whole and within
+// roi.
+func compareROIFrames(left, right image.Image, roi regionOfInterest) roiScores {
+	var s roiScores
+	s.fullPSNR, s.fullSSIM = videocompare.CompareImages(left, right)
+	s.roiPSNR, s.roiSSIM = videocompare.CompareImages(
+		cropImage(left, roi.pixels(left.Bounds())), cropImage(right, roi.pixels(right.Bounds())))
+	return s
+}
+
+// measureRangeMetrics runs ffmpeg over duration seconds of both files from
+// their start times, scaling right to left's size, and returns the average
+// PSNR and SSIM. crop, if not empty, is applied to both after scaling.
+func measureRangeMetrics(ctx context.Context, left string, leftStart float64, right string, rightStart, duration float64, crop string) (float64, float64, error) {
+	graph := "[1:v][0:v]scale2ref[dist][ref]"
+	if crop != "" {
+		graph += fmt.Sprintf(";[dist]%s[distc];[ref]%s[refc]", crop, crop)
+		graph += ";[distc]split[d0][d1];[refc]split[r0][r1]"
+	} else {
+		graph += ";[dist]split[d0][d1];[ref]split[r0][r1]"
+	}
+	graph += ";[d0][r0]psnr;[d1][r1]ssim"
+	out, err := runFFmpegLog(ctx,
+		"-ss", fmt.Sprintf("%.3f", leftStart), "-t", fmt.Sprintf("%.3f", duration), "-i", left,
+		"-ss", fmt.Sprintf("%.3f", rightStart), "-t", fmt.Sprintf("%.3f", duration), "-i", right,
+		"-lavfi", graph, "-f", "null", "-")
+	if err != nil {
+		return 0, 0, err
+	}
+	p, err := videocompare.ParseScore(videocompare.PSNR, out)
+	if err != nil {
+		return 0, 0, err
+	}
+	s, err := videocompare.ParseScore(videocompare.SSIM, out)
+	if err != nil {
+		return 0, 0, err
+	}
+	return p, s, nil
+}
+
+// roiPanel holds the region of interest drawn with the ROI tool and
+// measures PSNR and SSIM inside it next to the whole-frame scores, for the
+// current frames or over the players' in/out ranges.
+type roiPanel struct {
+	app *VideoCompareApp
+	roi *regionOfInterest
+
+	dragStart [2]float64
+	dragging  bool
+	cancel    context.CancelFunc
+	pending   int // bumped on every measurement so stale results are dropped
+
+	regionLabel *widget.Label
+	resultLabel *widget.Label
+	frameBtn    *widget.Button
+	rangeBtn    *widget.Button
+	cancelBtn   *widget.Button
+	clearBtn    *widget.Button
+}
+
+func newROIPanel(app *VideoCompareApp) *roiPanel {
+	return &roiPanel{app: app}
+}
+
+func (rp *roiPanel) content() fyne.CanvasObject {
+	rp.regionLabel = widget.NewLabel("")
+	rp.resultLabel = widget.NewLabel("")
+	rp.resultLabel.Wrapping = fyne.TextWrapWord
+	if reason := rp.app.unavailable(needsFFmpeg); reason != "" {
+		rp.resultLabel.SetText("Measuring a range " + reason)
+	}
+	rp.frameBtn = widget.NewButtonWithIcon("Measure Frame", theme.SearchIcon(), rp.measureFrame)
+	rp.rangeBtn = widget.NewButtonWithIcon("Measure Range", theme.MediaVideoIcon(), rp.measureRange)
+	rp.cancelBtn = widget.NewButtonWithIcon("Cancel", theme.CancelIcon(), rp.stop)
+	rp.clearBtn = widget.NewButtonWithIcon("Clear ROI", theme.ContentClearIcon(), func() {
+		rp.set(nil)
+		rp.app.bookmarks.storeROI(nil)
+	})
+	rp.refresh()
+
+	toolbar := container.NewHBox(rp.frameBtn, rp.rangeBtn, rp.cancelBtn, rp.clearBtn)
+	return container.NewVBox(toolbar, rp.regionLabel, rp.resultLabel)
+}
+
+// current returns a copy of the region, nil when none is drawn.
+func (rp *roiPanel) current() *regionOfInterest {
+	if rp.roi == nil {
+		return nil
+	}
+	roi := *rp.roi
+	return &roi
+}
+
+// set replaces the region, e.g. with the one saved on a bookmark. Regions
+// outside the frame are dropped.
+func (rp *roiPanel) set(roi *regionOfInterest) {
+	if roi != nil && !roi.valid() {
+		roi = nil
+	}
+	if roi != nil {
+		r := *roi
+		roi = &r
+	}
+	rp.roi = roi
+	rp.setResult("")
+	rp.updateOutlines()
+}
+
+// drag spans the region between where a drag over either video started
+// and the pointer, through any zoom.
+func (rp *roiPanel) drag(vp *VideoPlayer, ev *fyne.DragEvent) {
+	point := rp.app.zoom.framePoint(vp, vp.display.clampedPosition(ev.Position))
+	if !rp.dragging {
+		rp.dragStart = rp.app.zoom.framePoint(vp, vp.display.clampedPosition(ev.Position.Subtract(ev.Dragged)))
+		rp.dragging = true
+	}
+	roi := roiSpan(rp.dragStart, point)
+	rp.roi = &roi
+	rp.updateOutlines()
+}
+
+// dragEnd keeps the region if it is large enough and stores it on the
+// selected bookmark.
+func (rp *roiPanel) dragEnd() {
+	if !rp.dragging {
+		return
+	}
+	rp.dragging = false
+	rp.set(rp.roi)
+	rp.app.bookmarks.storeROI(rp.roi)
+}
+
+// updateOutlines maps the region onto each player's display and redraws
+// it there, after the region, the zoom or a resolution changed.
+func (rp *roiPanel) updateOutlines() {
+	for _, vp := range []*VideoPlayer{rp.app.leftPlayer, rp.app.rightPlayer} {
+		if rp.roi == nil {
+			vp.roiOutline = nil
+		} else {
+			outline := [2][2]float64{
+				rp.app.zoom.displayPoint(vp, [2]float64{rp.roi[0], rp.roi[1]}),
+				rp.app.zoom.displayPoint(vp, [2]float64{rp.roi[2], rp.roi[3]}),
+			}
+			vp.roiOutline = &outline
+		}
+		vp.annotationLayer.Refresh()
+	}
+	rp.refresh()
+}
+
+// drawROI renders the region outline on img, which shows vp's display at
+// img's size.
+func drawROI(img *image.RGBA, vp *VideoPlayer) {
+	o := vp.roiOutline
+	if o == nil {
+		return
+	}
+	b := img.Bounds()
+	w, h := float64(b.Dx()), float64(b.Dy())
+	p0 := [2]float64{float64(b.Min.X) + o[0][0]*w, float64(b.Min.Y) + o[0][1]*h}
+	p1 := [2]float64{float64(b.Min.X) + o[1][0]*w, float64(b.Min.Y) + o[1][1]*h}
+	corners := [][2]float64{p0, {p1[0], p0[1]}, p1, {p0[0], p1[1]}}
+	for i := range corners {
+		drawLine(img, corners[i], corners[(i+1)%4], 2, roiColor)
+	}
+	label := renderTextBox(toolROI)
+	x := clampInt(int(p0[0])+4, b.Min.X, max(b.Min.X, b.Max.X-label.Bounds().Dx()))
+	y := clampInt(int(p0[1])+4, b.Min.Y, max(b.Min.Y, b.Max.Y-label.Bounds().Dy()))
+	drawImageAt(img, label, x, y, 1)
+}
+
+// measureFrame compares the frames both players show, in full and within
+// the region.
+func (rp *roiPanel) measureFrame() {
+	l, r := rp.app.leftPlayer, rp.app.rightPlayer
+	if rp.roi == nil || !l.canGrabFrame() || !r.canGrabFrame() {
+		return
+	}
+	rp.stop()
+	rp.pending++
+	generation := rp.pending
+	roi := *rp.roi
+	grabLeft, grabRight := l.frameGrabber(), r.frameGrabber()
+	levelsLeft, levelsRight := l.metricLevels(), r.metricLevels()
+	label := fmt.Sprintf("%s @ %s vs %s @ %s", l.title, l.timecode(), r.title, r.timecode())
+	rp.setResult("Measuring…")
+
+	go func() {
+		var text string
+		var scores roiScores
+		left, err := grabLeft()
+		if err == nil {
+			var right image.Image
+			right, err = grabRight()
+			if err == nil {
+				scores = compareROIFrames(levelsLeft.apply(left), levelsRight.apply(right), roi)
+				text = label + "\n" + scores.String()
+			}
+		}
+		if err != nil {
+			log.Printf("ROI metrics: %v", err)
+			text = fmt.Sprintf("Measuring failed: %v", err)
+		}
+		fyne.Do(func() {
+			if generation != rp.pending {
+				return
+			}
+			if err != nil {
+				rp.setResult(text)
+				return
+			}
+			rp.setResult(rp.app.warnTranscoded(text))
+			rp.app.recordScores("ROI, current frames", scores.reportScores(label)...)
+		})
+	}()
+}
+
+// measureRange scores both files over their in/out ranges with ffmpeg,
+// from each range's start for the length of the shorter one.
+func (rp *roiPanel) measureRange() {
+	l, r := rp.app.leftPlayer, rp.app.rightPlayer
+	if rp.roi == nil {
+		return
+	}
+	for _, vp := range []*VideoPlayer{l, r} {
+		if vp.path == "" || vp.still != nil || isNetworkSource(vp.path) || vp.duration <= 0 {
+			rp.setResult("Measuring a range needs a local video file in both players")
+			return
+		}
+	}
+	rp.stop()
+	rp.pending++
+	generation := rp.pending
+	ctx, cancel := context.WithCancel(context.Background())
+	rp.cancel = cancel
+
+	roi := *rp.roi
+	leftStart, leftEnd := l.playRange()
+	rightStart, rightEnd := r.playRange()
+	duration := math.Min(leftEnd-leftStart, rightEnd-rightStart)
+	label := fmt.Sprintf("%s %s – %s vs %s from %s", l.title, formatTime(leftStart), formatTime(leftStart+duration),
+		r.title, formatTime(rightStart))
+	paths := [2]string{l.path, r.path}
+	rp.setResult("Measuring the full frame over the range…")
+
+	go func() {
+		var s roiScores
+		var err error
+		s.fullPSNR, s.fullSSIM, err = measureRangeMetrics(ctx, paths[0], leftStart, paths[1], rightStart, duration, "")
+		if err == nil {
+			fyne.Do(func() {
+				if generation == rp.pending {
+					rp.setResult("Measuring the ROI over the range…")
+				}
+			})
+			s.roiPSNR, s.roiSSIM, err = measureRangeMetrics(ctx, paths[0], leftStart, paths[1], rightStart, duration, roi.cropFilter())
+		}
+		fyne.Do(func() {
+			if generation != rp.pending || ctx.Err() != nil {
+				return
+			}
+			cancel()
+			rp.cancel = nil
+			if err != nil {
+				log.Printf("ROI range metrics: %v", err)
+				rp.setResult(fmt.Sprintf("Measuring failed: %v", err))
+				return
+			}
+			rp.setResult(rp.app.warnTranscoded(label + "\n" + s.String()))
+			rp.app.recordScores("ROI, in/out range", s.reportScores(label)...)
+		})
+	}()
+}
+
+// stop cancels a running range measurement.
+func (rp *roiPanel) stop() {
+	if rp.cancel == nil {
+		return
+	}
+	rp.cancel()
+	rp.cancel = nil
+	rp.pending++
+	rp.setResult("Measurement cancelled")
+}
+
+// forget drops results that no longer match the loaded files.
+func (rp *roiPanel) forget() {
+	if rp.cancel != nil {
+		rp.cancel()
+		rp.cancel = nil
+	}
+	rp.pending++
+	rp.setResult("")
+	rp.updateOutlines()
+}
+
+func (rp *roiPanel) setResult(text string) {
+	if rp.resultLabel != nil {
+		rp.resultLabel.SetText(text)
+	}
+	rp.refresh()
+}
+
+// refresh describes the region in the left player's pixels and enables
+// what applies.
+func (rp *roiPanel) refresh() {
+	if rp.regionLabel == nil {
+		return
+	}
+	switch vp := rp.app.leftPlayer; {
+	case rp.roi == nil:
+		rp.regionLabel.SetText("No ROI: choose the ROI drawing tool and drag a rectangle over either video")
+	case vp.width > 0 && vp.height > 0:
+		px := rp.roi.pixels(image.Rect(0, 0, vp.width, vp.height))
+		rp.regionLabel.SetText(fmt.Sprintf("ROI: %d×%d at %d,%d of the left frame", px.Dx(), px.Dy(), px.Min.X, px.Min.Y))
+	default:
+		rp.regionLabel.SetText(fmt.Sprintf("ROI: %.1f%% × %.1f%% of the frame", (rp.roi[2]-rp.roi[0])*100, (rp.roi[3]-rp.roi[1])*100))
+	}
+	if rp.roi == nil {
+		rp.frameBtn.Disable()
+		rp.rangeBtn.Disable()
+		rp.clearBtn.Disable()
+	} else {
+		rp.frameBtn.Enable()
+		rp.rangeBtn.Enable()
+		rp.clearBtn.Enable()
+	}
+	if rp.app.unavailable(needsFFmpeg) != "" {
+		rp.rangeBtn.Disable()
+	}
+	if rp.cancel != nil {
+		rp.cancelBtn.Enable()
+	} else {
+		rp.cancelBtn.Disable()
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2/dialog"
+	libvlc "github.com/adrg/libvlc-go/v3"
+)
+
+// orientationRotations maps the pure rotations to clockwise degrees.
+var orientationRotations = map[libvlc.VideoOrientation]int{
+	libvlc.OrientationTopLeft:     0,
+	libvlc.OrientationRightTop:    90,
+	libvlc.OrientationBottomRight: 180,
+	libvlc.OrientationLeftBottom:  270,
+}
+
+// orientationTransforms maps the mirrored orientations to the libvlc
+// transform filter type producing them.
+var orientationTransforms = map[libvlc.VideoOrientation]string{
+	libvlc.OrientationTopRight:    "hflip",
+	libvlc.OrientationBottomLeft:  "vflip",
+	libvlc.OrientationLeftTop:     "transpose",
+	libvlc.OrientationRightBottom: "antitranspose",
+}
+
+// orientationLabel describes an orientation for the metadata table.
+func orientationLabel(o libvlc.VideoOrientation) string {
+	if deg, ok := orientationRotations[o]; ok {
+		return fmt.Sprintf("%d°", deg)
+	}
+	if t, ok := orientationTransforms[o]; ok {
+		return t
+	}
+	return "unknown"
+}
+
+// matchingTransform returns the transform filter type that makes a clip
+// flagged with orientation from display like one flagged with to. It fails
+// when the difference involves a mirror on both sides.
+func matchingTransform(from, to libvlc.VideoOrientation) (string, bool) {
+	fromDeg, fromRotation := orientationRotations[from]
+	toDeg, toRotation := orientationRotations[to]
+	if fromRotation && toRotation {
+		delta := ((toDeg-fromDeg)%360 + 360) % 360
+		if delta == 0 {
+			return "", false
+		}
+		return fmt.Sprint(delta), true
+	}
+	if from == libvlc.OrientationTopLeft {
+		t, ok := orientationTransforms[to]
+		return t, ok
+	}
+	return "", false
+}
+
+func transformOptions(transformType string) []string {
+	return []string{":video-filter=transform", ":transform-type=" + transformType}
+}
+
+// checkRotation warns when the loaded clips only differ in rotation
+// metadata and offers to transform one of them to match the other.
+func (app *VideoCompareApp) checkRotation() {
+	l, r := app.leftPlayer, app.rightPlayer
+	if l.path == "" || r.path == "" || l.orientation == r.orientation {
+		return
+	}
+	if l.still != nil || r.still != nil || l.transform != "" || r.transform != "" {
+		return
+	}
+
+	// Prefer transforming the clip without rotation metadata
+	target, reference := r, l
+	if l.orientation == libvlc.OrientationTopLeft {
+		target, reference = l, r
+	}
+	transformType, ok := matchingTransform(target.orientation, reference.orientation)
+	if !ok {
+		dialog.ShowInformation("Rotation Metadata Differs",
+			fmt.Sprintf("%s is flagged %s but %s is flagged %s.\nThe videos may look different only because of this metadata.",
+				l.title, orientationLabel(l.orientation), r.title, orientationLabel(r.orientation)),
+			app.window)
+		return
+	}
+
+	msg := fmt.Sprintf("%s is flagged %s but %s is flagged %s.\n"+
+		"The videos may look different only because of this metadata.\n\n"+
+		"Apply a %s transform to %s so both are displayed the same way?",
+		l.title, orientationLabel(l.orientation), r.title, orientationLabel(r.orientation),
+		transformType, target.title)
+	dialog.ShowConfirm("Rotation Metadata Differs", msg, func(ok bool) {
+		if !ok {
+			return
+		}
+		target.transform = transformType
+		target.reloadMedia()
+		app.fileSettings.remember(target)
+		app.updateStats()
+	}, app.window)
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"math"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	waveformWidth   = 256
+	waveformHeight  = 128
+	vectorscopeSize = 128
+
+	// scopeSamples caps how many source pixels feed each scope, so large
+	// frames don't stall the UI.
+	scopeSamples = 256 * 1024
+)
+
+var scopeTrace = color.RGBA{R: 0x60, G: 0xff, B: 0x60, A: 0xff}
+
+// scopesPanel shows a luma waveform and a chroma vectorscope for the current
+// frame of each player.
+type scopesPanel struct {
+	app     *VideoCompareApp
+	enabled bool
+	pending int // bumped on every refresh so stale grabs are dropped
+
+	container *fyne.Container
+	images    map[*VideoPlayer][2]*canvas.Image
+	labels    map[*VideoPlayer]*widget.Label
+}
+
+func newScopesPanel(app *VideoCompareApp) *scopesPanel {
+	return &scopesPanel{
+		app:    app,
+		images: make(map[*VideoPlayer][2]*canvas.Image),
+		labels: make(map[*VideoPlayer]*widget.Label),
+	}
+}
+
+func (sp *scopesPanel) content() fyne.CanvasObject {
+	column := func(vp *VideoPlayer) fyne.CanvasObject {
+		waveform := newScopeImage(waveformWidth, waveformHeight)
+		vectorscope := newScopeImage(vectorscopeSize, vectorscopeSize)
+		sp.images[vp] = [2]*canvas.Image{waveform, vectorscope}
+		sp.labels[vp] = widget.NewLabel(vp.title)
+		return container.NewVBox(sp.labels[vp], container.NewHBox(waveform, vectorscope))
+	}
+	sp.container = container.NewGridWithColumns(2, column(sp.app.leftPlayer), column(sp.app.rightPlayer))
+	sp.container.Hide()
+	return sp.container
+}
+
+func newScopeImage(w, h int) *canvas.Image {
+	img := canvas.NewImageFromImage(image.NewRGBA(image.Rect(0, 0, w, h)))
+	img.FillMode = canvas.ImageFillStretch
+	img.SetMinSize(fyne.NewSize(float32(w), float32(h)))
+	return img
+}
+
+// setEnabled shows or hides the scopes panel.
+func (sp *scopesPanel) setEnabled(enabled bool) {
+	sp.enabled = enabled
+	if !enabled {
+		sp.container.Hide()
+		return
+	}
+	sp.container.Show()
+	sp.refresh()
+}
+
+// refresh recomputes the scopes from freshly grabbed frames. The grab runs
+// in the background after giving libvlc time to show the new frame.
+func (sp *scopesPanel) refresh() {
+	if !sp.enabled {
+		return
+	}
+	sp.pending++
+	generation := sp.pending
+	var players []*VideoPlayer
+	var grabs []func() (image.Image, error)
+	for _, vp := range []*VideoPlayer{sp.app.leftPlayer, sp.app.rightPlayer} {
+		if vp.path != "" {
+			players = append(players, vp)
+			grabs = append(grabs, vp.frameGrabber())
+		}
+	}
+
+	go func() {
+		time.Sleep(frameSettleDelay)
+		for i, vp := range players {
+			frame, err := grabs[i]()
+			if err != nil {
+				log.Printf("scopes: %v", err)
+				fyne.Do(func() { sp.labels[vp].SetText(fmt.Sprintf("%s: %v", vp.title, err)) })
+				continue
+			}
+			waveform := computeWaveform(frame, waveformWidth, waveformHeight)
+			vectorscope := computeVectorscope(frame, vectorscopeSize)
+			fyne.Do(func() {
+				if generation != sp.pending {
+					return
+				}
+				imgs := sp.images[vp]
+				imgs[0].Image = waveform
+				imgs[1].Image = vectorscope
+				imgs[0].Refresh()
+				imgs[1].Refresh()
+				sp.labels[vp].SetText(fmt.Sprintf("%s @ %s", vp.title, vp.timecode()))
+			})
+		}
+	}()
+}
+
+// computeWaveform builds a luma waveform monitor: each output column shows
+// the distribution of Rec. 709 luma in the matching slice of frame columns,
+// with black at the bottom and white at the top.
+func computeWaveform(frame image.Image, width, height int) *image.RGBA {
+	src := toRGBA(frame)
+	b := src.Bounds()
+	counts := make([]int, width*height)
+	step := sampleStep(b.Dx(), b.Dy())
+
+	for y := 0; y < b.Dy(); y += step {
+		for x := 0; x < b.Dx(); x += step {
+			i := src.PixOffset(b.Min.X+x, b.Min.Y+y)
+			luma, _, _ := ycbcr709(src.Pix[i], src.Pix[i+1], src.Pix[i+2])
+			col := x * width / b.Dx()
+			row := height - 1 - int(luma*float64(height-1)+0.5)
+			counts[row*width+col]++
+		}
+	}
+
+	out := renderScope(counts, width, height)
+	// Graticule at 0%, 50% and 100%
+	for _, level := range []float64{0, 0.5, 1} {
+		row := height - 1 - int(level*float64(height-1)+0.5)
+		for x := 0; x < width; x += 4 {
+			out.Set(x, row, color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff})
+		}
+	}
+	return out
+}
+
+// computeVectorscope plots each pixel's Rec. 709 chroma on a size×size
+// Cb/Cr plane, neutral pixels landing in the centre.
+func computeVectorscope(frame image.Image, size int) *image.RGBA {
+	src := toRGBA(frame)
+	b := src.Bounds()
+	counts := make([]int, size*size)
+	step := sampleStep(b.Dx(), b.Dy())
+	half := float64(size) / 2
+
+	for y := 0; y < b.Dy(); y += step {
+		for x := 0; x < b.Dx(); x += step {
+			i := src.PixOffset(b.Min.X+x, b.Min.Y+y)
+			_, cb, cr := ycbcr709(src.Pix[i], src.Pix[i+1], src.Pix[i+2])
+			px := clampInt(int(half+cb*float64(size)), 0, size-1)
+			py := clampInt(int(half-cr*float64(size)), 0, size-1)
+			counts[py*size+px]++
+		}
+	}
+
+	out := renderScope(counts, size, size)
+	grey := color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+	for i := 0; i < size; i += 4 {
+		out.Set(i, size/2, grey)
+		out.Set(size/2, i, grey)
+	}
+	// Outer circle marks full saturation
+	for a := 0.0; a < 2*math.Pi; a += 0.02 {
+		out.Set(int(half+(half-1)*math.Cos(a)), int(half+(half-1)*math.Sin(a)), grey)
+	}
+	return out
+}
+
+// renderScope turns hit counts into a trace whose brightness grows
+// logarithmically with the count.
+func renderScope(counts []int, width, height int) *image.RGBA {
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	peak := 0
+	for _, c := range counts {
+		peak = max(peak, c)
+	}
+	for i, c := range counts {
+		if c == 0 {
+			out.Pix[i*4+3] = 0xff
+			continue
+		}
+		v := math.Log1p(float64(c)) / math.Log1p(float64(peak))
+		v = 0.25 + 0.75*v
+		out.Pix[i*4] = uint8(float64(scopeTrace.R) * v)
+		out.Pix[i*4+1] = uint8(float64(scopeTrace.G) * v)
+		out.Pix[i*4+2] = uint8(float64(scopeTrace.B) * v)
+		out.Pix[i*4+3] = 0xff
+	}
+	return out
+}
+
+// sampleStep returns the stride that keeps sampling under scopeSamples.
+func sampleStep(w, h int) int {
+	step := 1
+	for (w/step)*(h/step) > scopeSamples {
+		step++
+	}
+	return step
+}
+
+// ycbcr709 converts 8-bit RGB into luma in [0, 1] and chroma in
+// [-0.5, 0.5] using Rec. 709 coefficients.
+func ycbcr709(r, g, b uint8) (float64, float64, float64) {
+	rf, gf, bf := float64(r)/255, float64(g)/255, float64(b)/255
+	y := 0.2126*rf + 0.7152*gf + 0.0722*bf
+	cb := (bf - y) / 1.8556
+	cr := (rf - y) / 1.5748
+	return y, cb, cr
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+// newSeekEntry creates vp's seek entry. An unparsable or out of range
+// target marks the entry invalid and explains why next to it, until the
+// text is corrected.
+func (vp *VideoPlayer) newSeekEntry() (*widget.Entry, *widget.Button, *widget.Label) {
+	vp.seekError = widget.NewLabel("")
+	vp.seekError.Importance = widget.DangerImportance
+	vp.seekError.Hide()
+
+	vp.timeInput = widget.NewEntry()
+	vp.timeInput.SetPlaceHolder("00:00:00")
+	vp.timeInput.Validator = func(text string) error {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		_, err := vp.parseSeekTarget(text)
+		return err
+	}
+	vp.timeInput.OnChanged = func(string) {
+		if vp.seekError.Visible() && vp.timeInput.Validate() == nil {
+			vp.seekError.Hide()
+		}
+	}
+	vp.timeInput.OnSubmitted = func(string) { vp.seekFromEntry() }
+
+	seekBtn := widget.NewButton(tr("Seek"), vp.seekFromEntry)
+	return vp.timeInput, seekBtn, vp.seekError
+}
+
+// seekFromEntry seeks to the time typed into the seek entry, or shows why
+// it can't.
+func (vp *VideoPlayer) seekFromEntry() {
+	if strings.TrimSpace(vp.timeInput.Text) == "" {
+		return
+	}
+	seconds, err := vp.parseSeekTarget(vp.timeInput.Text)
+	vp.timeInput.SetValidationError(err)
+	if err != nil {
+		vp.seekError.SetText(err.Error())
+		vp.seekError.Show()
+		return
+	}
+	vp.seekError.Hide()
+	vp.seekTo(seconds)
+	if vp.onSeekEntered != nil {
+		vp.onSeekEntered()
+	}
+}
+
+// parseSeekTarget converts the seek entry's text into seconds of playback.
+// Elapsed time is entered as [[HH:]MM:]SS[.fff], or the file's timecode as
+// HH:MM:SS:FF while source timecode is shown.
+func (vp *VideoPlayer) parseSeekTarget(text string) (float64, error) {
+	text = strings.TrimSpace(text)
+	if vp.usesSourceTimecode() {
+		seconds, err := vp.parseSourceTimecode(text)
+		if err != nil {
+			return 0, errors.New("bad format: enter a timecode as HH:MM:SS:FF")
+		}
+		if seconds < 0 || seconds > vp.duration {
+			return 0, fmt.Errorf("out of range: enter %s to %s",
+				vp.sourceTimecodeAt(0), vp.sourceTimecodeAt(vp.duration))
+		}
+		return seconds, nil
+	}
+
+	seconds, ok := parseElapsed(text)
+	if !ok {
+		return 0, errors.New("bad format: enter HH:MM:SS, MM:SS or seconds")
+	}
+	if seconds > vp.duration {
+		return 0, fmt.Errorf("out of range: the clip ends at %s", formatTime(vp.duration))
+	}
+	return seconds, nil
+}
+
+// parseElapsed parses [[HH:]MM:]SS[.fff]. Minutes and seconds below a
+// larger unit must be under 60.
+func parseElapsed(text string) (float64, bool) {
+	parts := strings.Split(text, ":")
+	if len(parts) > 3 {
+		return 0, false
+	}
+	var seconds float64
+	for i, p := range parts {
+		last := i == len(parts)-1
+		var v float64
+		if last {
+			f, err := strconv.ParseFloat(p, 64)
+			if err != nil || f < 0 || math.IsInf(f, 0) || math.IsNaN(f) || strings.ContainsAny(p, "eE+-") {
+				return 0, false
+			}
+			v = f
+		} else {
+			n, err := strconv.Atoi(p)
+			if err != nil || n < 0 || strings.ContainsAny(p, "+-") {
+				return 0, false
+			}
+			v = float64(n)
+		}
+		if i > 0 && v >= 60 {
+			return 0, false
+		}
+		seconds = seconds*60 + v
+	}
+	return seconds, true
+}
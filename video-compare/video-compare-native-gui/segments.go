@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"videocompare"
+)
+
+// timelineSegment is one of the files a player shows joined into a single
+// continuous timeline, with where it starts on that timeline.
+type timelineSegment struct {
+	path     string
+	start    float64 // seconds
+	duration float64
+}
+
+// joinedPath names the file paths are joined into. It depends on the
+// paths, their order and their versions, so a changed segment gives a new
+// file instead of a stale one.
+func joinedPath(paths []string) string {
+	h := sha256.New()
+	for _, p := range paths {
+		if abs, err := filepath.Abs(p); err == nil {
+			p = abs
+		}
+		fmt.Fprintf(h, "%s\x00", p)
+		if info, err := os.Stat(p); err == nil {
+			fmt.Fprintf(h, "%d\x00%d\x00", info.ModTime().UnixNano(), info.Size())
+		}
+	}
+	return filepath.Join(cacheDir(), "joined-"+hex.EncodeToString(h.Sum(nil)[:12])+".mkv")
+}
+
+// joinSegments concatenates paths without re-encoding into out, using
+// ffmpeg's concat demuxer so timestamps run on across the joins, and
+// returns where each segment lands on the joined timeline. The segments
+// must share codecs and stream layout. An existing out is reused.
+func joinSegments(paths []string, out string) ([]timelineSegment, error) {
+	segments := make([]timelineSegment, len(paths))
+	var start float64
+	for i, p := range paths {
+		if isNetworkSource(p) || isStillImage(p) {
+			return nil, fmt.Errorf("%s can't be joined: only local video files can", displayName(p))
+		}
+		d, err := videocompare.ProbeDuration(context.Background(), p)
+		if err != nil {
+			return nil, err
+		}
+		segments[i] = timelineSegment{path: p, start: start, duration: d}
+		start += d
+	}
+	if _, err := os.Stat(out); err == nil {
+		return segments, nil
+	}
+
+	// Make room first; trimming after writing could evict the new file
+	cacheTrim()
+	list, err := cacheTempFile("tmp-*.txt")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(list.Name())
+	for _, p := range paths {
+		if abs, err := filepath.Abs(p); err == nil {
+			p = abs
+		}
+		fmt.Fprintf(list, "file '%s'\n", strings.ReplaceAll(p, "'", `'\''`))
+	}
+	if err := list.Close(); err != nil {
+		return nil, err
+	}
+
+	tmp := filepath.Join(filepath.Dir(out), "tmp-"+filepath.Base(out))
+	if _, err := runFFmpeg(context.Background(), "-f", "concat", "-safe", "0", "-i", list.Name(),
+		"-map", "0", "-c", "copy", "-y", tmp); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("joining segments: %w", err)
+	}
+	if err := os.Rename(tmp, out); err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+	return segments, nil
+}
+
+// segmentAt returns the index of the segment playing at t seconds, -1 when
+// vp isn't showing joined segments.
+func (vp *VideoPlayer) segmentAt(t float64) int {
+	if len(vp.segments) < 2 {
+		return -1
+	}
+	for i := len(vp.segments) - 1; i > 0; i-- {
+		if t >= vp.segments[i].start {
+			return i
+		}
+	}
+	return 0
+}
+
+// segmentStatus says which segment the current position falls in, or ""
+// for a single file.
+func (vp *VideoPlayer) segmentStatus() string {
+	i := vp.segmentAt(vp.currentTime)
+	if i < 0 {
+		return ""
+	}
+	s := vp.segments[i]
+	return fmt.Sprintf("segment %d/%d %s +%s", i+1, len(vp.segments), displayName(s.path), formatTime(vp.currentTime-s.start))
+}
+
+// sourceName is how vp's file is referred to in its file label.
+func (vp *VideoPlayer) sourceName() string {
+	if len(vp.segments) > 1 {
+		return fmt.Sprintf("%s + %d more", vp.shortName(vp.segments[0].path), len(vp.segments)-1)
+	}
+	if vp.transcoded != nil {
+		return vp.shortName(vp.transcoded.path) + " (transcoded)"
+	}
+	return vp.shortName(vp.path)
+}
+
+// openSegmentsDialog lets the user pick the files making up one side's
+// timeline, in order, and opens them joined in player.
+func (app *VideoCompareApp) openSegmentsDialog(player *VideoPlayer) {
+	var paths []string
+	selected := -1
+	list := widget.NewList(
+		func() int { return len(paths) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(fmt.Sprintf("%d. %s", id+1, displayName(paths[id])))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(widget.ListItemID) { selected = -1 }
+
+	move := func(delta int) {
+		to := selected + delta
+		if selected < 0 || to < 0 || to >= len(paths) {
+			return
+		}
+		paths[selected], paths[to] = paths[to], paths[selected]
+		list.Refresh()
+		list.Select(to)
+	}
+	addBtn := widget.NewButtonWithIcon("Add…", theme.ContentAddIcon(), func() {
+		fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			reader.Close()
+			paths = append(paths, reader.URI().Path())
+			list.Refresh()
+		}, app.window)
+		fd.SetFilter(storage.NewExtensionFileFilter(acceptedFormats()))
+		fd.Show()
+	})
+	upBtn := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() { move(-1) })
+	downBtn := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() { move(1) })
+	removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		if selected < 0 {
+			return
+		}
+		paths = append(paths[:selected], paths[selected+1:]...)
+		list.UnselectAll()
+		list.Refresh()
+	})
+
+	content := container.NewBorder(
+		widget.NewLabel("Files played back to back as one timeline, in this order.\nThey must share codecs and resolution."),
+		container.NewHBox(addBtn, upBtn, downBtn, removeBtn), nil, nil, list)
+	d := dialog.NewCustomConfirm("Open Segments in "+player.title, "Open", "Cancel", content, func(ok bool) {
+		if ok && len(paths) > 0 {
+			app.openVideo(player, paths...)
+			player.whenParsed(func() { app.autoPlayLoaded(player) })
+		}
+	}, app.window)
+	d.Resize(fyne.NewSize(520, 400))
+	d.Show()
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+)
+
+const (
+	sessionExtension = ".vcompare"
+	sessionVersion   = 1
+)
+
+// session is the on-disk form of a comparison session.
+type session struct {
+	Version   int           `json:"version"`
+	Left      sessionPlayer `json:"left"`
+	Right     sessionPlayer `json:"right"`
+	Notes     []note        `json:"notes,omitempty"`
+	Bookmarks []bookmark    `json:"bookmarks,omitempty"`
+	Zoom      *sessionZoom  `json:"zoom,omitempty"`
+	Sync      *sessionSync  `json:"sync,omitempty"`
+
+	Provenance        []provenanceField `json:"provenance,omitempty"`
+	ProvenanceCaption bool              `json:"provenance_caption,omitempty"`
+}
+
+type sessionPlayer struct {
+	Path     string  `json:"path,omitempty"`
+	Position float64 `json:"position"`
+
+	// In/out points, 0 for the start and end of the clip
+	RangeStart float64 `json:"range_start,omitempty"`
+	RangeEnd   float64 `json:"range_end,omitempty"`
+
+	// Registration offset in source pixels, with the frame size it was
+	// set against so it can be rescaled if the file's resolution changed
+	Registration [2]float64 `json:"registration"`
+	Width        int        `json:"width,omitempty"`
+	Height       int        `json:"height,omitempty"`
+
+	// The file as it was when saved, to warn when it has changed since
+	Size     int64      `json:"size,omitempty"`
+	Modified *time.Time `json:"modified,omitempty"`
+	Duration float64    `json:"duration,omitempty"`
+
+	// Whether Path was shown re-encoded into a compatible MP4, and the
+	// original's start time the re-encode's positions are offset by
+	Transcoded      bool    `json:"transcoded,omitempty"`
+	TranscodeOffset float64 `json:"transcode_offset,omitempty"`
+}
+
+// sessionZoom is the shared zoom state of both players.
+type sessionZoom struct {
+	Factor float64    `json:"factor"`
+	Center [2]float64 `json:"center"`
+}
+
+// sessionSync is the calibrated sync between the players: the sync lock
+// offset and the content sync points.
+type sessionSync struct {
+	Locked  bool       `json:"locked,omitempty"`
+	Offset  float64    `json:"offset"`
+	Start   *syncPoint `json:"start,omitempty"`
+	End     *syncPoint `json:"end,omitempty"`
+	Content bool       `json:"content,omitempty"`
+}
+
+func (app *VideoCompareApp) sessionState(vp *VideoPlayer) sessionPlayer {
+	state := sessionPlayer{
+		Path:         vp.originalPath(),
+		Position:     vp.currentTime,
+		RangeStart:   vp.rangeStart,
+		RangeEnd:     vp.rangeEnd,
+		Registration: app.zoom.offsets[vp],
+		Width:        vp.width,
+		Height:       vp.height,
+		Duration:     vp.duration,
+	}
+	if vp.transcoded != nil {
+		state.Transcoded = true
+		state.TranscodeOffset = vp.transcoded.offset
+	}
+	stampFile(&state)
+	return state
+}
+
+func (app *VideoCompareApp) currentSession() session {
+	return session{
+		Version:   sessionVersion,
+		Left:      app.sessionState(app.leftPlayer),
+		Right:     app.sessionState(app.rightPlayer),
+		Notes:     app.notes.notes,
+		Bookmarks: app.bookmarks.bookmarks,
+		Zoom:      &sessionZoom{Factor: app.zoom.factor, Center: app.zoom.center},
+		Sync: &sessionSync{
+			Locked:  app.syncLocked,
+			Offset:  app.syncLockOffset,
+			Start:   app.contentSync.start,
+			End:     app.contentSync.end,
+			Content: app.contentSync.enabled,
+		},
+		Provenance:        app.provenance.fields,
+		ProvenanceCaption: app.provenance.burnIn,
+	}
+}
+
+func writeSession(w io.Writer, s session) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+func readSession(r io.Reader) (session, error) {
+	var s session
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return s, fmt.Errorf("invalid session file: %w", err)
+	}
+	if s.Version > sessionVersion {
+		return s, fmt.Errorf("session version %d is newer than supported version %d", s.Version, sessionVersion)
+	}
+	return s, nil
+}
+
+// applySession loads the session's files and restores positions, ranges,
+// zoom, registration, sync calibration, notes, bookmarks and provenance.
+// Positions and bookmarks are checked against the files' current
+// durations, and a warning lists files changed since the session was
+// saved and whatever had to be clamped or dropped. Auto-play starts from
+// the restored positions.
+func (app *VideoCompareApp) applySession(s session) {
+	check := &sessionCheck{}
+	for _, pair := range []struct {
+		player *VideoPlayer
+		state  sessionPlayer
+	}{{app.leftPlayer, s.Left}, {app.rightPlayer, s.Right}} {
+		if pair.state.Path == "" {
+			continue
+		}
+		player, state := pair.player, pair.state
+		app.openSessionFile(player, state, check)
+		// Ranges and positions are clamped to the duration, known once parsed
+		player.whenParsed(func() {
+			check.compareFile(player, state)
+			start, end, position := check.clamp(player, state)
+			player.setRange(start, end)
+			player.seekTo(position)
+			app.zoom.setRegistration(player, state.Registration, state.Width, state.Height)
+		})
+	}
+	if s.Zoom != nil {
+		app.zoom.restore(s.Zoom.Factor, s.Zoom.Center)
+	}
+	if sync := s.Sync; sync != nil {
+		// Loading the files clears the sync points, so they wait for both
+		app.leftPlayer.whenParsed(func() {
+			app.rightPlayer.whenParsed(func() { app.restoreSync(*sync) })
+		})
+	}
+	app.notes.setNotes(s.Notes)
+	app.leftPlayer.whenParsed(func() {
+		app.bookmarks.setBookmarks(check.keepBookmarks(app.leftPlayer, s.Bookmarks))
+		app.rightPlayer.whenParsed(func() { check.report(app) })
+	})
+	app.provenance.setFields(s.Provenance, s.ProvenanceCaption)
+	app.autoPlayRestored()
+}
+
+// openSessionFile opens state's file in player, as the cached transcode
+// it was shown as when that is still there, otherwise the original.
+func (app *VideoCompareApp) openSessionFile(player *VideoPlayer, state sessionPlayer, check *sessionCheck) {
+	if state.Transcoded {
+		out := transcodedPath(state.Path)
+		if _, err := os.Stat(out); err == nil {
+			app.openTranscoded(player, transcodedSource{path: state.Path, out: out, offset: state.TranscodeOffset})
+			return
+		}
+		check.warn("%s (%s) was shown transcoded, but its transcode is no longer cached, so the original is shown; transcode it again from the File menu.",
+			displayName(state.Path), player.title)
+	}
+	app.openVideo(player, state.Path)
+}
+
+func (app *VideoCompareApp) saveSessionDialog() {
+	fd := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+		if err := writeSession(writer, app.currentSession()); err != nil {
+			dialog.ShowError(err, app.window)
+			return
+		}
+		app.history.sessionSaved(writer.URI().Path())
+	}, app.window)
+	fd.SetFileName("comparison" + sessionExtension)
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{sessionExtension}))
+	fd.Show()
+}
+
+func (app *VideoCompareApp) openSessionDialog() {
+	fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		defer reader.Close()
+		s, err := readSession(reader)
+		if err != nil {
+			dialog.ShowError(err, app.window)
+			return
+		}
+		app.applySession(s)
+	}, app.window)
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{sessionExtension}))
+	fd.Show()
+}
+
+// restoreSync reapplies a session's sync lock offset and content sync.
+func (app *VideoCompareApp) restoreSync(sync sessionSync) {
+	if sync.Locked {
+		app.lockSyncAt(sync.Offset)
+	}
+	cs := app.contentSync
+	cs.start, cs.end = sync.Start, sync.End
+	if sync.Content {
+		cs.enable()
+	}
+	cs.refresh()
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2/dialog"
+)
+
+// sessionDurationTolerance is how far, in seconds, a file's duration may
+// differ from the one saved with a session before it counts as changed.
+const sessionDurationTolerance = 0.05
+
+// stampFile records the size and modification time of state's file, so a
+// file changed after the session was saved can be spotted on load.
+func stampFile(state *sessionPlayer) {
+	if state.Path == "" || isNetworkSource(state.Path) {
+		return
+	}
+	info, err := os.Stat(state.Path)
+	if err != nil {
+		return
+	}
+	modified := info.ModTime()
+	state.Size, state.Modified = info.Size(), &modified
+}
+
+// sessionCheck collects what had to be corrected while restoring a
+// session whose files changed since it was saved, for one warning once
+// both players are ready.
+type sessionCheck struct {
+	warnings []string
+}
+
+func (sc *sessionCheck) warn(format string, args ...interface{}) {
+	sc.warnings = append(sc.warnings, fmt.Sprintf(format, args...))
+}
+
+// compareFile notes how vp's parsed file differs from the one state was
+// saved with. Sessions saved before files were stamped only have the
+// duration, or nothing, to compare.
+func (sc *sessionCheck) compareFile(vp *VideoPlayer, state sessionPlayer) {
+	var changes []string
+	if info, err := os.Stat(state.Path); err == nil {
+		if state.Size > 0 && info.Size() != state.Size {
+			changes = append(changes, fmt.Sprintf("size %s → %s", formatSize(state.Size), formatSize(info.Size())))
+		}
+		if state.Modified != nil && !info.ModTime().Equal(*state.Modified) {
+			changes = append(changes, "modified "+info.ModTime().Format(time.DateTime))
+		}
+	}
+	if state.Duration > 0 && vp.duration > 0 && math.Abs(vp.duration-state.Duration) > sessionDurationTolerance {
+		changes = append(changes, fmt.Sprintf("duration %s → %s", formatMacroTime(state.Duration), formatMacroTime(vp.duration)))
+	}
+	if len(changes) > 0 {
+		sc.warn("%s (%s) changed since the session was saved: %s.", displayName(state.Path), vp.title, strings.Join(changes, ", "))
+	}
+}
+
+// clamp returns state's range and position limited to vp's duration,
+// noting any that had to move.
+func (sc *sessionCheck) clamp(vp *VideoPlayer, state sessionPlayer) (start, end, position float64) {
+	start, end, position = state.RangeStart, state.RangeEnd, state.Position
+	if vp.duration <= 0 {
+		return start, end, position
+	}
+	limit := func(name string, t float64) float64 {
+		clamped := math.Max(0, math.Min(vp.duration, t))
+		if clamped != t {
+			sc.warn("%s %s %s was outside the clip and moved to %s.", vp.title, name, formatMacroTime(t), formatMacroTime(clamped))
+		}
+		return clamped
+	}
+	position = limit("position", position)
+	if start > 0 {
+		start = limit("in point", start)
+	}
+	if end > 0 {
+		end = limit("out point", end)
+	}
+	if start >= vp.duration || (end > 0 && end <= start) {
+		// Nothing left of the range, so play the whole clip instead
+		start, end = 0, 0
+	}
+	return start, end, position
+}
+
+// keepBookmarks returns the bookmarks within the left player's clip,
+// noting those dropped. All are kept while it has no duration.
+func (sc *sessionCheck) keepBookmarks(left *VideoPlayer, bookmarks []bookmark) []bookmark {
+	if left.duration <= 0 {
+		return bookmarks
+	}
+	var kept []bookmark
+	var dropped []string
+	for _, b := range bookmarks {
+		if b.Time < 0 || b.Time > left.duration {
+			dropped = append(dropped, fmt.Sprintf("  [%s] %s", formatMacroTime(b.Time), b.Label))
+			continue
+		}
+		kept = append(kept, b)
+	}
+	if len(dropped) > 0 {
+		sc.warn("Dropped %d bookmark(s) past the end of %s:\n%s", len(dropped), displayName(left.path), strings.Join(dropped, "\n"))
+	}
+	return kept
+}
+
+// report shows everything corrected, if anything was.
+func (sc *sessionCheck) report(app *VideoCompareApp) {
+	if len(sc.warnings) == 0 {
+		return
+	}
+	dialog.ShowInformation("Session Files Changed", strings.Join(sc.warnings, "\n\n"), app.window)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxShuttleSpeed caps how far repeated J or L presses speed up.
+const maxShuttleSpeed = 8
+
+// reverseShuttleInterval is how often reverse shuttling seeks back. libvlc
+// can't decode backwards, so reverse play is a series of backward seeks.
+const reverseShuttleInterval = 100 * time.Millisecond
+
+// shuttleControl drives both players NLE-style from the keyboard: L plays
+// forward, J plays in reverse, repeated presses double the speed, and K
+// pauses.
+type shuttleControl struct {
+	app         *VideoCompareApp
+	speed       int // negative in reverse, 0 while paused
+	stopReverse chan struct{}
+	label       *widget.Label
+}
+
+func newShuttleControl(app *VideoCompareApp) *shuttleControl {
+	return &shuttleControl{app: app}
+}
+
+// indicator shows the current shuttle speed.
+func (sc *shuttleControl) indicator() fyne.CanvasObject {
+	sc.label = widget.NewLabel("")
+	sc.updateLabel()
+	return sc.label
+}
+
+func (sc *shuttleControl) updateLabel() {
+	switch {
+	case sc.speed > 0:
+		sc.label.SetText(fmt.Sprintf("Shuttle ▶ %d×", sc.speed))
+	case sc.speed < 0:
+		sc.label.SetText(fmt.Sprintf("Shuttle ◀ %d×", -sc.speed))
+	default:
+		sc.label.SetText("Shuttle ⏸ (J/K/L)")
+	}
+}
+
+// typedKey handles the J, K and L keys typed outside text entries.
+func (sc *shuttleControl) typedKey(ev *fyne.KeyEvent) {
+	switch ev.Name {
+	case fyne.KeyJ:
+		sc.reverse()
+	case fyne.KeyK:
+		sc.pause()
+	case fyne.KeyL:
+		sc.forward()
+	}
+}
+
+// players returns the players shuttling applies to.
+func (sc *shuttleControl) players() []*VideoPlayer {
+	var players []*VideoPlayer
+	for _, vp := range []*VideoPlayer{sc.app.leftPlayer, sc.app.rightPlayer} {
+		if vp.canPlay() {
+			players = append(players, vp)
+		}
+	}
+	return players
+}
+
+// forward plays both players forward, doubling the speed while already
+// going forward.
+func (sc *shuttleControl) forward() {
+	sc.endReverse()
+	if sc.speed <= 0 {
+		sc.speed = 1
+	} else {
+		sc.speed = min(sc.speed*2, maxShuttleSpeed)
+	}
+	for _, vp := range sc.players() {
+		vp.setRate(float32(float64(sc.speed) * sc.app.contentSync.rate(vp)))
+		if !vp.playing() {
+			vp.play()
+		}
+	}
+	sc.updateLabel()
+}
+
+// reverse starts stepping both players backwards, doubling the speed while
+// already in reverse.
+func (sc *shuttleControl) reverse() {
+	if sc.speed >= 0 {
+		sc.speed = -1
+	} else {
+		sc.speed = max(sc.speed*2, -maxShuttleSpeed)
+	}
+	for _, vp := range sc.players() {
+		vp.pause()
+		vp.setRate(float32(sc.app.contentSync.rate(vp)))
+	}
+	sc.updateLabel()
+	if sc.stopReverse != nil {
+		return // the running ticker picks up the new speed
+	}
+
+	stop := make(chan struct{})
+	sc.stopReverse = stop
+	go func() {
+		ticker := time.NewTicker(reverseShuttleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				fyne.Do(func() {
+					if sc.stopReverse == stop {
+						sc.stepBack()
+					}
+				})
+			}
+		}
+	}()
+}
+
+// stepBack moves the players back by one tick's worth of reverse play,
+// pausing at the start of their ranges.
+func (sc *shuttleControl) stepBack() {
+	back := reverseShuttleInterval.Seconds() * float64(-sc.speed)
+	atStart := true
+	for _, vp := range sc.players() {
+		start, _ := vp.playRange()
+		if vp.currentTime > start {
+			atStart = false
+			vp.seekTo(max(start, vp.currentTime-back*sc.app.contentSync.rate(vp)))
+		}
+	}
+	if atStart {
+		sc.pause()
+	}
+}
+
+// pause stops shuttling and returns to normal speed.
+func (sc *shuttleControl) pause() {
+	sc.reset()
+	for _, vp := range sc.players() {
+		vp.pause()
+	}
+}
+
+// reset ends shuttling and returns to normal speed without touching the
+// play state, for when the players are controlled by other means.
+func (sc *shuttleControl) reset() {
+	sc.endReverse()
+	if sc.speed > 1 {
+		sc.app.contentSync.applyRates(1)
+	}
+	sc.speed = 0
+	sc.updateLabel()
+}
+
+func (sc *shuttleControl) endReverse() {
+	if sc.stopReverse != nil {
+		close(sc.stopReverse)
+		sc.stopReverse = nil
+	}
+}
+
+// setRate sets vp's playback speed.
+func (vp *VideoPlayer) setRate(rate float32) {
+	if err := vp.player.SetPlaybackRate(rate); err != nil {
+		log.Printf("%s: setting playback rate: %v", vp.title, err)
+	}
+}
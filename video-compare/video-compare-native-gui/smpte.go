@@ -0,0 +1,259 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// smpteTimecode is a file's embedded start timecode, such as the
+// 10:00:00:00 professional masters usually begin at.
+type smpteTimecode struct {
+	frame int  // frames counted from 00:00:00:00
+	rate  int  // nominal frames per second: 30 for 29.97
+	drop  bool // drop-frame counting, written with ; before the frames
+}
+
+// parseSMPTE parses HH:MM:SS:FF, or HH:MM:SS;FF for drop-frame, counting
+// frames at the nominal rate of fps.
+func parseSMPTE(s string, fps float64) (smpteTimecode, error) {
+	return parseSMPTEAs(s, fps, strings.ContainsAny(s, ";."))
+}
+
+// parseSMPTEAs parses a timecode counted with or without dropped frames,
+// regardless of the separator it is written with. Only 30 and 60 fps timecodes
+// drop frames.
+func parseSMPTEAs(s string, fps float64, drop bool) (smpteTimecode, error) {
+	s = strings.TrimSpace(s)
+	tc := smpteTimecode{rate: int(math.Round(fps))}
+	tc.drop = drop && tc.rate > 0 && tc.rate%30 == 0
+	if tc.rate <= 0 {
+		return tc, fmt.Errorf("timecode %q needs a known frame rate", s)
+	}
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ':' || r == ';' || r == '.' })
+	if len(fields) != 4 {
+		return tc, fmt.Errorf("timecode %q is not HH:MM:SS:FF", s)
+	}
+	var n [4]int
+	for i, f := range fields {
+		v, err := strconv.Atoi(f)
+		if err != nil || v < 0 {
+			return tc, fmt.Errorf("timecode %q is not HH:MM:SS:FF", s)
+		}
+		n[i] = v
+	}
+	h, m, sec, f := n[0], n[1], n[2], n[3]
+	if m > 59 || sec > 59 || f >= tc.rate {
+		return tc, fmt.Errorf("timecode %q is out of range at %d fps", s, tc.rate)
+	}
+	tc.frame = (h*3600+m*60+sec)*tc.rate + f
+	if tc.drop {
+		// Two frame numbers (four at 60) are skipped every minute except
+		// every tenth
+		minutes := h*60 + m
+		tc.frame -= tc.dropped() * (minutes - minutes/10)
+	}
+	return tc, nil
+}
+
+// dropped is how many frame numbers drop-frame counting skips a minute.
+func (tc smpteTimecode) dropped() int {
+	return tc.rate / 15
+}
+
+// at returns the timecode index frames after tc.
+func (tc smpteTimecode) at(index int) smpteTimecode {
+	tc.frame += index
+	return tc
+}
+
+func (tc smpteTimecode) String() string {
+	frame, sep := tc.frame, ":"
+	if tc.drop {
+		sep = ";"
+		perTen := tc.rate*600 - tc.dropped()*9
+		perMinute := tc.rate*60 - tc.dropped()
+		tens, rest := frame/perTen, frame%perTen
+		frame += tc.dropped() * 9 * tens
+		if rest > tc.dropped() {
+			frame += tc.dropped() * ((rest - tc.dropped()) / perMinute)
+		}
+	}
+	secs := frame / tc.rate
+	return fmt.Sprintf("%02d:%02d:%02d%s%02d", secs/3600%24, secs/60%60, secs%60, sep, frame%tc.rate)
+}
+
+// probeStartTimecode reads the start timecode ffprobe reports for path,
+// from the container, a video stream or a tmcd track. It returns "" for
+// files without one.
+func probeStartTimecode(path string) (string, error) {
+	out, err := runFFprobe("-show_entries", "format_tags=timecode:stream_tags=timecode", path)
+	if err != nil {
+		return "", err
+	}
+	var probe struct {
+		Format struct {
+			Tags struct {
+				Timecode string `json:"timecode"`
+			} `json:"tags"`
+		} `json:"format"`
+		Streams []struct {
+			Tags struct {
+				Timecode string `json:"timecode"`
+			} `json:"tags"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return "", fmt.Errorf("parsing ffprobe output: %w", err)
+	}
+	if tc := probe.Format.Tags.Timecode; tc != "" {
+		return tc, nil
+	}
+	for _, s := range probe.Streams {
+		if s.Tags.Timecode != "" {
+			return s.Tags.Timecode, nil
+		}
+	}
+	return "", nil
+}
+
+// analyzeTimecode reads vp's start timecode in the background.
+func (app *VideoCompareApp) analyzeTimecode(vp *VideoPlayer) {
+	vp.startTimecode = ""
+	app.updateTimecodeAlign()
+	if vp.path == "" || vp.still != nil || isNetworkSource(vp.path) {
+		return
+	}
+	path := vp.path
+	go func() {
+		tc, err := probeStartTimecode(path)
+		fyne.Do(func() {
+			if vp.path != path {
+				return
+			}
+			if err != nil {
+				log.Printf("probing timecode of %s: %v", path, err)
+				return
+			}
+			vp.startTimecode = tc
+			vp.updateTimeDisplay()
+			vp.updateStats()
+			vp.updateSeekHint()
+			app.updateTimecodeAlign()
+		})
+	}()
+}
+
+// sourceStart parses vp's start timecode at its frame rate. ok is false
+// when the file has none, or the frame rate isn't known yet.
+func (vp *VideoPlayer) sourceStart() (smpteTimecode, bool) {
+	if vp.startTimecode == "" || vp.fps <= 0 {
+		return smpteTimecode{}, false
+	}
+	tc, err := parseSMPTE(vp.startTimecode, vp.fps)
+	return tc, err == nil
+}
+
+// usesSourceTimecode reports whether vp's times are shown and entered as
+// the file's own timecode rather than elapsed time.
+func (vp *VideoPlayer) usesSourceTimecode() bool {
+	if vp.sourceTimecode == nil || !*vp.sourceTimecode {
+		return false
+	}
+	_, ok := vp.sourceStart()
+	return ok
+}
+
+// sourceTimecodeAt returns the file's timecode for the frame shown at
+// seconds.
+func (vp *VideoPlayer) sourceTimecodeAt(seconds float64) string {
+	start, _ := vp.sourceStart()
+	return start.at(frameNumber(seconds, vp.fps)).String()
+}
+
+// parseSourceTimecode converts a timecode of vp's file into seconds of
+// playback, negative when it is before the file starts. A timecode without
+// frames counts as frame 0 of that second.
+func (vp *VideoPlayer) parseSourceTimecode(s string) (float64, error) {
+	start, _ := vp.sourceStart()
+	s = strings.TrimSpace(s)
+	if strings.Count(s, ":") == 2 && !strings.ContainsAny(s, ";.") {
+		s += ":00"
+	}
+	// Typed timecodes are counted like the file's, whichever separator
+	// was used
+	tc, err := parseSMPTEAs(s, vp.fps, start.drop)
+	if err != nil {
+		return 0, err
+	}
+	return float64(tc.frame-start.frame) / vp.fps, nil
+}
+
+// updateSeekHint shows the format the seek entry expects.
+func (vp *VideoPlayer) updateSeekHint() {
+	if vp.timeInput == nil {
+		return
+	}
+	if vp.usesSourceTimecode() {
+		start, _ := vp.sourceStart()
+		vp.timeInput.SetPlaceHolder(start.String())
+	} else {
+		vp.timeInput.SetPlaceHolder("00:00:00")
+	}
+}
+
+// newSourceTimecodeCheck creates the toggle between elapsed time and the
+// files' own timecode in the time displays and seek entries.
+func (app *VideoCompareApp) newSourceTimecodeCheck() *widget.Check {
+	return widget.NewCheck(tr("Source Timecode"), func(enabled bool) {
+		app.sourceTimecode = enabled
+		for _, vp := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+			vp.updateTimeDisplay()
+			vp.updateSeekHint()
+		}
+	})
+}
+
+// updateTimecodeAlign enables aligning by timecode once both files turned
+// out to carry one.
+func (app *VideoCompareApp) updateTimecodeAlign() {
+	if app.alignTimecodeBtn == nil {
+		return
+	}
+	_, left := app.leftPlayer.sourceStart()
+	_, right := app.rightPlayer.sourceStart()
+	if left && right {
+		app.alignTimecodeBtn.Enable()
+	} else {
+		app.alignTimecodeBtn.Disable()
+	}
+}
+
+// alignByTimecode moves the right player to the timecode the left one
+// shows, so files starting at different timecodes line up by content
+// time. The sync lock keeps the resulting offset.
+func (app *VideoCompareApp) alignByTimecode() {
+	l, r := app.leftPlayer, app.rightPlayer
+	leftStart, okLeft := l.sourceStart()
+	rightStart, okRight := r.sourceStart()
+	if !okLeft || !okRight {
+		return
+	}
+	// Timecodes count frames; converting through seconds allows the files
+	// to differ in frame rate
+	offset := float64(leftStart.frame)/l.fps - float64(rightStart.frame)/r.fps
+	target := l.currentTime + offset
+	if target < 0 || target > r.duration {
+		dialog.ShowError(fmt.Errorf("%s doesn't cover timecode %s", r.title, l.sourceTimecodeAt(l.currentTime)), app.window)
+		return
+	}
+	r.seekTo(target)
+	app.syncLockOffset = r.currentTime - l.currentTime
+}
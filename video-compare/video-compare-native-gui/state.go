@@ -0,0 +1,126 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	libvlc "github.com/adrg/libvlc-go/v3"
+)
+
+// playerState is what a player is doing, derived from user actions and
+// libvlc's events.
+type playerState int
+
+const (
+	stateIdle      playerState = iota // nothing loaded, or loaded and not started
+	stateLoading                      // media being analyzed
+	statePlaying                      // playing and the position advances
+	statePaused                       // paused mid-clip
+	stateBuffering                    // playing, but stalled waiting for data
+	stateEnded                        // played to the end
+	stateError                        // libvlc reported an error
+)
+
+func (s playerState) String() string {
+	switch s {
+	case stateLoading:
+		return "Loading"
+	case statePlaying:
+		return "Playing"
+	case statePaused:
+		return "Paused"
+	case stateBuffering:
+		return "Buffering"
+	case stateEnded:
+		return "Ended"
+	case stateError:
+		return "Error"
+	}
+	return "Idle"
+}
+
+// bufferingGrace is how recently libvlc must have reported buffering for a
+// position that stopped advancing to count as a stall.
+const bufferingGrace = time.Second
+
+// newStateLabel creates the indicator showing vp's state.
+func (vp *VideoPlayer) newStateLabel() *widget.Label {
+	vp.stateLabel = widget.NewLabel(stateIdle.String())
+	vp.stateLabel.Importance = widget.LowImportance
+	return vp.stateLabel
+}
+
+// playing reports whether playback is under way, including while stalled
+// on buffering.
+func (vp *VideoPlayer) playing() bool {
+	return vp.state == statePlaying || vp.state == stateBuffering
+}
+
+// setState records what vp is doing, runs the progress ticker only while
+// playback is under way so idle players don't keep waking the CPU, and
+// updates the controls to match.
+func (vp *VideoPlayer) setState(s playerState) {
+	if s == vp.state {
+		return
+	}
+	vp.state = s
+	if vp.playing() {
+		vp.startProgressTicker()
+	} else {
+		vp.stopProgressTicker()
+	}
+	if vp.stateLabel != nil {
+		vp.stateLabel.SetText(s.String())
+		switch s {
+		case stateError:
+			vp.stateLabel.Importance = widget.DangerImportance
+		case stateBuffering:
+			vp.stateLabel.Importance = widget.WarningImportance
+		default:
+			vp.stateLabel.Importance = widget.LowImportance
+		}
+		vp.stateLabel.Refresh()
+	}
+	if vp.playbackControls != nil {
+		vp.updateControls()
+	}
+	if vp.onStateChange != nil {
+		vp.onStateChange()
+	}
+}
+
+// handleStateEvent runs on a libvlc thread and moves vp to the state the
+// event signals on the UI goroutine.
+func (vp *VideoPlayer) handleStateEvent(event libvlc.Event, _ interface{}) {
+	fyne.Do(func() {
+		if vp.media == nil {
+			return
+		}
+		switch event {
+		case libvlc.MediaPlayerPlaying:
+			vp.setState(statePlaying)
+		case libvlc.MediaPlayerPaused:
+			vp.setState(statePaused)
+		case libvlc.MediaPlayerStopped:
+			if vp.state != stateEnded && vp.state != stateError {
+				vp.setState(stateIdle)
+			}
+		case libvlc.MediaPlayerBuffering:
+			// libvlc reports buffering progress without saying when a stall
+			// is over, so pollPosition decides from whether time advances
+			vp.lastBuffering = time.Now()
+		}
+	})
+}
+
+// trackStall switches between playing and buffering depending on whether
+// the position advanced since the last poll.
+func (vp *VideoPlayer) trackStall(advanced bool) {
+	switch {
+	case advanced && vp.state == stateBuffering:
+		vp.setState(statePlaying)
+	case !advanced && vp.state == statePlaying && time.Since(vp.lastBuffering) < bufferingGrace:
+		vp.setState(stateBuffering)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// statsTab is the Statistics tab: the combined stats with actions copying
+// or saving them, with each player's details and the metadata diff, for
+// pasting a comparison summary into a ticket.
+func (app *VideoCompareApp) statsTab() fyne.CanvasObject {
+	copyBtn := widget.NewButtonWithIcon("Copy Stats", theme.ContentCopyIcon(), app.copyStats)
+	saveBtn := widget.NewButtonWithIcon("Save Stats…", theme.DocumentSaveIcon(), app.saveStatsDialog)
+	return container.NewBorder(container.NewHBox(copyBtn, saveBtn), nil, nil, nil, app.statsDisplay)
+}
+
+func (app *VideoCompareApp) copyStats() {
+	fyne.CurrentApp().Clipboard().SetContent(app.statsText())
+}
+
+// saveStatsDialog writes the stats as plain text, or as Markdown when the
+// chosen name ends in .md.
+func (app *VideoCompareApp) saveStatsDialog() {
+	fd := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		text := app.statsText()
+		if strings.EqualFold(filepath.Ext(writer.URI().Path()), ".md") {
+			text = app.statsMarkdown()
+		}
+		if _, err := io.WriteString(writer, text); err != nil {
+			dialog.ShowError(err, app.window)
+		}
+	}, app.window)
+	fd.SetFileName("comparison-stats.txt")
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{".txt", ".md"}))
+	fd.Show()
+}
+
+// playerStats returns the details shown under each loaded player.
+func (app *VideoCompareApp) playerStats() []string {
+	var sections []string
+	for _, vp := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		if vp.path != "" {
+			sections = append(sections, vp.title+"\n"+vp.statsLabel.Text)
+		}
+	}
+	return sections
+}
+
+// statsText renders the combined stats, each player's details and the
+// metadata diff as plain text, with the table's columns padded to line up
+// in a monospace font.
+func (app *VideoCompareApp) statsText() string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(app.statsDisplay.Text()))
+	for _, s := range app.playerStats() {
+		b.WriteString("\n\n" + s)
+	}
+
+	rows := [][]string{{"Property", "Left", "Right", ""}}
+	for _, r := range app.metadataRows {
+		mark := ""
+		if r.Differs() {
+			mark = "≠"
+		}
+		rows = append(rows, []string{r.Name, r.Left, r.Right, mark})
+	}
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	b.WriteString("\n\nMetadata\n")
+	for _, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			line.WriteString(cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+2))
+		}
+		b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+	return b.String()
+}
+
+// statsMarkdown renders the same as statsText, with the metadata diff as
+// a Markdown table.
+func (app *VideoCompareApp) statsMarkdown() string {
+	var b strings.Builder
+	b.WriteString("# Comparison Statistics\n\n```\n" + strings.TrimSpace(app.statsDisplay.Text()) + "\n```\n")
+	for _, s := range app.playerStats() {
+		title, details, _ := strings.Cut(s, "\n")
+		fmt.Fprintf(&b, "\n## %s\n\n```\n%s\n```\n", title, details)
+	}
+
+	cell := func(s string) string { return strings.ReplaceAll(s, "|", `\|`) }
+	b.WriteString("\n## Metadata\n\n| Property | Left | Right | Differs |\n|---|---|---|---|\n")
+	for _, r := range app.metadataRows {
+		mark := ""
+		if r.Differs() {
+			mark = "≠"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", cell(r.Name), cell(r.Left), cell(r.Right), mark)
+	}
+	return b.String()
+}
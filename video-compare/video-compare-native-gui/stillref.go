@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	libvlc "github.com/adrg/libvlc-go/v3"
+	"videocompare"
+)
+
+// stillExtensions are the image formats accepted as a reference frame.
+var stillExtensions = []string{".png", ".jpg", ".jpeg"}
+
+func isStillImage(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range stillExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// newStillView creates the hidden image shown instead of the video canvas
+// while a still reference is loaded.
+func (vp *VideoPlayer) newStillView() *canvas.Image {
+	vp.stillView = canvas.NewImageFromImage(nil)
+	vp.stillView.FillMode = canvas.ImageFillContain
+	vp.stillView.Hide()
+	return vp.stillView
+}
+
+// decodeStill reads the image at path. It touches no player state, so it
+// can run off the UI goroutine.
+func decodeStill(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", filepath.Base(path), err)
+	}
+	return img, nil
+}
+
+// showStill shows a decoded still image as this player's reference frame.
+// libvlc is left idle; frame grabs return the image itself.
+func (vp *VideoPlayer) showStill(img image.Image) {
+	vp.player.Stop()
+	vp.setState(stateIdle)
+	if vp.media != nil {
+		vp.media.Release()
+		vp.media = nil
+	}
+
+	vp.still = img
+	vp.width, vp.height = img.Bounds().Dx(), img.Bounds().Dy()
+	vp.duration, vp.currentTime, vp.fps = 0, 0, 0
+	vp.bitrate = 0
+	vp.hasVideo, vp.hasAudio = false, false
+	vp.codec = "still image"
+	vp.orientation = libvlc.OrientationTopLeft
+
+	vp.stillView.Image = img
+	vp.stillView.Show()
+	vp.stillView.Refresh()
+	vp.updateTimeDisplay()
+	vp.updateStats()
+	vp.updateVideoCanvas()
+}
+
+// clearStill drops a previously loaded still so a video can be shown.
+func (vp *VideoPlayer) clearStill() {
+	vp.still = nil
+	vp.stillView.Image = nil
+	vp.stillView.Hide()
+	vp.codec = ""
+}
+
+// stillReference pairs the player showing a still with the one showing
+// video, if exactly that is loaded.
+func (app *VideoCompareApp) stillReference() (still, video *VideoPlayer, ok bool) {
+	l, r := app.leftPlayer, app.rightPlayer
+	switch {
+	case l.still != nil && r.still == nil && r.path != "":
+		return l, r, true
+	case r.still != nil && l.still == nil && l.path != "":
+		return r, l, true
+	}
+	return nil, nil, false
+}
+
+// refreshStillMetrics compares the video's current frame with the still
+// reference in the background and shows PSNR and SSIM.
+func (app *VideoCompareApp) refreshStillMetrics() {
+	still, video, ok := app.stillReference()
+	if !ok {
+		app.stillMetricsLabel.Hide()
+		return
+	}
+	app.stillMetricsLabel.Show()
+	app.stillMetricsLabel.SetText("Still reference: measuring…")
+
+	app.stillPending++
+	generation := app.stillPending
+	reference := still.metricLevels().apply(still.still)
+	grab, levels := video.frameGrabber(), video.metricLevels()
+	label := fmt.Sprintf("%s @ %s vs still %s", video.title, video.timecode(), displayName(still.path))
+
+	go func() {
+		time.Sleep(frameSettleDelay)
+		frame, err := grab()
+		var text string
+		var score reportScore
+		if err != nil {
+			log.Printf("still metrics: %v", err)
+			text = fmt.Sprintf("Still reference: %v", err)
+		} else {
+			p, s := videocompare.CompareImages(reference, levels.apply(frame))
+			text = fmt.Sprintf("%s — PSNR %s  SSIM %.4f", label, formatPSNR(p), s)
+			score = reportScore{Scope: label, PSNR: formatPSNR(p), SSIM: fmt.Sprintf("%.4f", s)}
+		}
+		fyne.Do(func() {
+			if generation != app.stillPending {
+				return
+			}
+			if err != nil {
+				app.stillMetricsLabel.SetText(text)
+				return
+			}
+			app.stillMetricsLabel.SetText(app.warnTranscoded(text))
+			app.recordScores("Still reference", score)
+		})
+	}()
+}
+
+func formatPSNR(db float64) string {
+	if math.IsInf(db, 1) {
+		return "∞ dB (identical)"
+	}
+	return fmt.Sprintf("%.2f dB", db)
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	libvlc "github.com/adrg/libvlc-go/v3"
+)
+
+const (
+	maxReconnectAttempts  = 5
+	initialReconnectDelay = time.Second
+	maxReconnectDelay     = 30 * time.Second
+	reconnectProbeTimeout = 10 * time.Second
+)
+
+// errReconnectCancelled is returned by reopen when the reconnect was
+// cancelled, by the user or by loading another file.
+var errReconnectCancelled = errors.New("reconnect cancelled")
+
+var networkSchemes = []string{"http", "https", "rtsp", "rtmp", "rtp", "udp", "mms", "srt"}
+
+// isNetworkSource reports whether path is a URL libvlc should stream rather
+// than a local file.
+func isNetworkSource(path string) bool {
+	u, err := url.Parse(path)
+	if err != nil || u.Scheme == "" {
+		return false
+	}
+	for _, scheme := range networkSchemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// displayName is the short name shown in a player's file label.
+func displayName(path string) string {
+	if isNetworkSource(path) {
+		return path
+	}
+	return filepath.Base(path)
+}
+
+// newMedia creates libvlc media for either a local path or a stream URL.
+func newMedia(path string) (*libvlc.Media, error) {
+	if isNetworkSource(path) {
+		return libvlc.NewMediaFromURL(path)
+	}
+	return libvlc.NewMediaFromPath(path)
+}
+
+// mediaOptions returns the options applied whenever vp's media is recreated:
+// the pinned rendition and any rotation transform.
+func (vp *VideoPlayer) mediaOptions() []string {
+	var opts []string
+	if vp.variant != nil {
+		opts = append(opts, variantOptions(*vp.variant)...)
+	}
+	if vp.transform != "" {
+		opts = append(opts, transformOptions(vp.transform)...)
+	}
+	return opts
+}
+
+// reloadMedia recreates vp's media with the current options, keeping the
+// playback position.
+func (vp *VideoPlayer) reloadMedia() {
+	media, err := newMedia(vp.path)
+	if err != nil {
+		log.Printf("failed to reload %s: %v", vp.path, err)
+		return
+	}
+	if opts := vp.mediaOptions(); len(opts) > 0 {
+		if err := media.AddOptions(opts...); err != nil {
+			log.Printf("failed to apply media options: %v", err)
+		}
+	}
+	if err := vp.player.SetMedia(media); err != nil {
+		log.Printf("failed to reload %s: %v", vp.path, err)
+		media.Release()
+		return
+	}
+	// Analyze the replacement if the original was still being analyzed,
+	// keeping what waits for the result
+	reparse, pending := vp.parsing, vp.afterParse
+	vp.stopParsing()
+	if vp.media != nil {
+		vp.media.Release()
+	}
+	vp.media = media
+	if reparse {
+		vp.afterParse = pending
+		vp.parseMedia()
+	}
+
+	if vp.playing() {
+		resumeAt := vp.currentTime
+		_ = vp.player.Play()
+		_ = vp.player.SetMediaTime(int(resumeAt * 1000))
+	}
+}
+
+func (app *VideoCompareApp) openURL(player *VideoPlayer) {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder(tr("rtsp://host/stream or https://host/video.mp4"))
+	entry.Validator = func(s string) error {
+		if !isNetworkSource(s) {
+			return errors.New(trf("unsupported URL (expected one of: %s)", strings.Join(networkSchemes, ", ")))
+		}
+		return nil
+	}
+
+	dialog.ShowForm(tr("Open URL"), tr("Open"), tr("Cancel"),
+		[]*widget.FormItem{widget.NewFormItem(tr("URL"), entry)},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			app.loadVideo(player, strings.TrimSpace(entry.Text))
+		}, app.window)
+}
+
+// watchPlayerEvents subscribes to the libvlc events that signal the end of
+// playback or a dropped network stream.
+func (vp *VideoPlayer) watchPlayerEvents() {
+	manager, err := vp.player.EventManager()
+	if err != nil {
+		log.Printf("failed to get event manager: %v", err)
+		return
+	}
+	for _, event := range []libvlc.Event{libvlc.MediaPlayerEncounteredError, libvlc.MediaPlayerEndReached} {
+		if _, err := manager.Attach(event, vp.handleStreamEvent, nil); err != nil {
+			log.Printf("failed to attach to player event %v: %v", event, err)
+		}
+	}
+	for _, event := range []libvlc.Event{libvlc.MediaPlayerPlaying, libvlc.MediaPlayerPaused,
+		libvlc.MediaPlayerStopped, libvlc.MediaPlayerBuffering} {
+		if _, err := manager.Attach(event, vp.handleStateEvent, nil); err != nil {
+			log.Printf("failed to attach to player event %v: %v", event, err)
+		}
+	}
+}
+
+// handleStreamEvent runs on a libvlc thread, which must not call back into
+// the player, so the event is handled on the UI goroutine and reconnection
+// happens on its own goroutine.
+func (vp *VideoPlayer) handleStreamEvent(event libvlc.Event, _ interface{}) {
+	fyne.Do(func() {
+		if !isNetworkSource(vp.path) {
+			switch {
+			case event == libvlc.MediaPlayerEncounteredError:
+				vp.setState(stateError)
+			case vp.loop:
+				vp.restartLoop()
+			default:
+				vp.setState(stateEnded)
+			}
+			return
+		}
+		// A finite stream that simply played to the end is not a dropout
+		if event == libvlc.MediaPlayerEndReached && vp.duration > 0 && vp.currentTime >= vp.duration-1 {
+			vp.setState(stateEnded)
+			return
+		}
+		vp.startReconnect()
+	})
+}
+
+// startReconnect records where the stream dropped and starts reconnecting
+// in the background, unless that is already happening.
+func (vp *VideoPlayer) startReconnect() {
+	if vp.reconnectCancel != nil {
+		return
+	}
+	cancel := make(chan struct{})
+	vp.reconnectCancel = cancel
+	vp.setState(stateError)
+	vp.cancelReconnectBtn.Show()
+	go vp.reconnect(vp.path, vp.currentTime, cancel)
+}
+
+// reconnect retries loading path with exponential backoff, resuming near
+// resumeAt when the stream is seekable.
+func (vp *VideoPlayer) reconnect(path string, resumeAt float64, cancel chan struct{}) {
+	defer fyne.Do(func() {
+		if vp.reconnectCancel == cancel {
+			vp.reconnectCancel = nil
+		}
+		vp.cancelReconnectBtn.Hide()
+	})
+
+	delay := initialReconnectDelay
+	for attempt := 1; attempt <= maxReconnectAttempts; attempt++ {
+		vp.setStreamStatus(path, trf("reconnecting… (attempt %d/%d)", attempt, maxReconnectAttempts))
+
+		select {
+		case <-cancel:
+			return
+		case <-time.After(delay):
+		}
+
+		// Another file was loaded while we were waiting
+		var current string
+		fyne.DoAndWait(func() { current = vp.path })
+		if current != path {
+			return
+		}
+
+		err := vp.reopen(path, resumeAt, cancel)
+		if errors.Is(err, errReconnectCancelled) {
+			// Whatever cancelled it owns the label now
+			return
+		}
+		if err != nil {
+			log.Printf("reconnect to %s failed: %v", path, err)
+			delay = min(delay*2, maxReconnectDelay)
+			continue
+		}
+
+		fyne.Do(func() {
+			if vp.path != path {
+				return
+			}
+			vp.setState(statePlaying)
+			vp.fileLabel.SetText(displayName(path))
+		})
+		return
+	}
+	vp.setStreamStatus(path, tr("connection lost"))
+}
+
+// reopen recreates the media for path and waits until libvlc reports it is
+// playing again.
+func (vp *VideoPlayer) reopen(path string, resumeAt float64, cancel <-chan struct{}) error {
+	media, err := newMedia(path)
+	if err != nil {
+		return err
+	}
+	fyne.DoAndWait(func() {
+		// The user may have loaded another file or cancelled while the
+		// media was being created
+		stale := vp.path != path
+		select {
+		case <-cancel:
+			stale = true
+		default:
+		}
+		if stale {
+			media.Release()
+			err = errReconnectCancelled
+			return
+		}
+		if opts := vp.mediaOptions(); len(opts) > 0 {
+			_ = media.AddOptions(opts...)
+		}
+		if err = vp.player.SetMedia(media); err != nil {
+			media.Release()
+			return
+		}
+		if vp.media != nil {
+			vp.media.Release()
+		}
+		vp.media = media
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := vp.player.Play(); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(reconnectProbeTimeout)
+	for time.Now().Before(deadline) {
+		select {
+		case <-cancel:
+			return errReconnectCancelled
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		state, err := vp.player.MediaState()
+		if err != nil {
+			return err
+		}
+		switch state {
+		case libvlc.MediaPlaying:
+			if resumeAt > 0 && vp.player.IsSeekable() {
+				_ = vp.player.SetMediaTime(int(resumeAt * 1000))
+			}
+			return nil
+		case libvlc.MediaError, libvlc.MediaEnded:
+			return fmt.Errorf("stream state %v", state)
+		}
+	}
+	return fmt.Errorf("timed out after %s", reconnectProbeTimeout)
+}
+
+// userCancelReconnect stops reconnecting at the user's request. Like
+// loading another file, it takes over the label from the reconnect.
+func (vp *VideoPlayer) userCancelReconnect() {
+	if vp.reconnectCancel == nil {
+		return
+	}
+	vp.cancelReconnect()
+	vp.setStreamStatus(vp.path, tr("reconnect cancelled"))
+}
+
+func (vp *VideoPlayer) cancelReconnect() {
+	if vp.reconnectCancel != nil {
+		close(vp.reconnectCancel)
+		vp.reconnectCancel = nil
+	}
+}
+
+// setStreamStatus shows status after the name of the stream at path,
+// unless another file has been loaded since.
+func (vp *VideoPlayer) setStreamStatus(path, status string) {
+	fyne.Do(func() {
+		if vp.path == path {
+			vp.fileLabel.SetText(fmt.Sprintf("%s — %s", displayName(path), status))
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+const prefScrubAloneModifier = "shortcuts.scrubAloneModifier"
+
+// scrubAloneModifiers are the keys that can be held while dragging a
+// progress bar to move only that player despite the sync lock.
+var scrubAloneModifiers = []struct {
+	name     string
+	modifier fyne.KeyModifier
+}{
+	{"Ctrl", fyne.KeyModifierControl},
+	{"Alt", fyne.KeyModifierAlt},
+	{"Shift", fyne.KeyModifierShift},
+}
+
+// scrubAloneModifier returns the configured modifier and its name.
+func scrubAloneModifier() (string, fyne.KeyModifier) {
+	name := fyne.CurrentApp().Preferences().StringWithFallback(prefScrubAloneModifier, "Ctrl")
+	for _, m := range scrubAloneModifiers {
+		if m.name == name {
+			return m.name, m.modifier
+		}
+	}
+	return scrubAloneModifiers[0].name, scrubAloneModifiers[0].modifier
+}
+
+// scrubbingAlone reports whether the scrub-alone modifier is held.
+func scrubbingAlone() bool {
+	d, ok := fyne.CurrentApp().Driver().(desktop.Driver)
+	if !ok {
+		return false
+	}
+	_, modifier := scrubAloneModifier()
+	return d.CurrentKeyModifiers()&modifier != 0
+}
+
+// newSyncLockCheck creates the toggle that links the progress bars.
+func (app *VideoCompareApp) newSyncLockCheck() *widget.Check {
+	app.syncLockCheck = widget.NewCheck("", app.setSyncLock)
+	app.updateSyncLockLabel()
+	return app.syncLockCheck
+}
+
+// updateSyncLockLabel names the scrub-alone modifier next to the toggle,
+// the one place it can be discovered from.
+func (app *VideoCompareApp) updateSyncLockLabel() {
+	name, _ := scrubAloneModifier()
+	app.syncLockCheck.Text = fmt.Sprintf("Sync Lock (hold %s to scrub one side)", name)
+	app.syncLockCheck.Refresh()
+}
+
+// setSyncLock links or unlinks the progress bars. The offset between the
+// players when locking is kept for all linked scrubs, so after scrubbing
+// one side alone the next linked scrub snaps back to it.
+func (app *VideoCompareApp) setSyncLock(locked bool) {
+	app.syncLocked = locked
+	app.syncLockOffset = app.rightPlayer.currentTime - app.leftPlayer.currentTime
+}
+
+// lockSyncAt turns the sync lock on with the given offset of the right
+// player from the left one.
+func (app *VideoCompareApp) lockSyncAt(offset float64) {
+	app.syncLockCheck.SetChecked(true)
+	app.syncLocked = true
+	app.syncLockOffset = offset
+}
+
+// scrubbed seeks vp to where its progress bar was dragged and, while the
+// sync lock is on and the modifier isn't held, moves the other player to
+// the matching position: at the locked offset, or by content while
+// content sync is on.
+func (app *VideoCompareApp) scrubbed(vp *VideoPlayer, seconds float64) {
+	vp.seekTo(seconds)
+	app.macro.recordSide(vp, macroCommand{name: "seek", value: vp.currentTime})
+	app.followScrub(vp)
+}
+
+// followScrub moves the other player to match vp after vp was moved on
+// its own, while the sync lock is on and the modifier isn't held.
+func (app *VideoCompareApp) followScrub(vp *VideoPlayer) {
+	if !app.syncLocked || scrubbingAlone() {
+		return
+	}
+	other, offset := app.rightPlayer, app.syncLockOffset
+	if vp == app.rightPlayer {
+		other, offset = app.leftPlayer, -offset
+	}
+	target := vp.currentTime + offset
+	if app.contentSync.active() {
+		target = app.contentSync.mapFrom(vp, vp.currentTime)
+	}
+	if other.canPlay() {
+		other.seekTo(target)
+		app.macro.recordSide(other, macroCommand{name: "seek", value: other.currentTime})
+	}
+}
+
+// scrubAloneMenuItem chooses the modifier for scrubbing one side alone.
+func (app *VideoCompareApp) scrubAloneMenuItem() *fyne.MenuItem {
+	item := fyne.NewMenuItem(tr("Scrub One Side With"), nil)
+	menu := fyne.NewMenu("")
+	current, _ := scrubAloneModifier()
+	for _, m := range scrubAloneModifiers {
+		choice := fyne.NewMenuItem(m.name, nil)
+		choice.Checked = m.name == current
+		choice.Action = func() {
+			fyne.CurrentApp().Preferences().SetString(prefScrubAloneModifier, m.name)
+			for _, other := range menu.Items {
+				other.Checked = other == choice
+			}
+			app.window.MainMenu().Refresh()
+			app.updateSyncLockLabel()
+		}
+		menu.Items = append(menu.Items, choice)
+	}
+	item.ChildMenu = menu
+	return item
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	libvlc "github.com/adrg/libvlc-go/v3"
+)
+
+// cadenceFrames is how many frames the field analysis looks at.
+const cadenceFrames = 1000
+
+const (
+	cadenceProgressive = "progressive"
+	cadenceInterlaced  = "interlaced"
+	cadencePulldown    = "3:2 pulldown"
+)
+
+var (
+	idetRepeatedPattern = regexp.MustCompile(`Repeated Fields: Neither:\s*(\d+)\s*Top:\s*(\d+)\s*Bottom:\s*(\d+)`)
+	idetMultiPattern    = regexp.MustCompile(`Multi frame detection: TFF:\s*(\d+)\s*BFF:\s*(\d+)\s*Progressive:\s*(\d+)\s*Undetermined:\s*(\d+)`)
+)
+
+// fieldStats are the counters reported by ffmpeg's idet filter.
+type fieldStats struct {
+	neither, top, bottom             int
+	tff, bff, progressive, undecided int
+}
+
+// classifyCadence derives the cadence from field statistics. 3:2 pulldown
+// repeats a field in two out of every five frames, so about 40% of the
+// frames carry a repeated field.
+func classifyCadence(s fieldStats) string {
+	if total := s.neither + s.top + s.bottom; total > 0 {
+		repeated := float64(s.top+s.bottom) / float64(total)
+		if math.Abs(repeated-0.4) < 0.1 && s.top > 0 && s.bottom > 0 {
+			return cadencePulldown
+		}
+	}
+	if s.tff+s.bff > s.progressive {
+		return cadenceInterlaced
+	}
+	return cadenceProgressive
+}
+
+// detectCadence runs ffmpeg's interlace detection over the start of path.
+func detectCadence(ctx context.Context, path string) (string, error) {
+	out, err := runFFmpegLog(ctx, "-i", path, "-an", "-frames:v", strconv.Itoa(cadenceFrames),
+		"-vf", "idet", "-f", "null", "-")
+	if err != nil {
+		return "", err
+	}
+
+	var s fieldStats
+	m := idetRepeatedPattern.FindAllStringSubmatch(out, -1)
+	mm := idetMultiPattern.FindAllStringSubmatch(out, -1)
+	if len(m) == 0 || len(mm) == 0 {
+		return "", fmt.Errorf("no field statistics in ffmpeg output")
+	}
+	r, f := m[len(m)-1], mm[len(mm)-1]
+	s.neither, _ = strconv.Atoi(r[1])
+	s.top, _ = strconv.Atoi(r[2])
+	s.bottom, _ = strconv.Atoi(r[3])
+	s.tff, _ = strconv.Atoi(f[1])
+	s.bff, _ = strconv.Atoi(f[2])
+	s.progressive, _ = strconv.Atoi(f[3])
+	s.undecided, _ = strconv.Atoi(f[4])
+	return classifyCadence(s), nil
+}
+
+// analyzeCadence detects vp's cadence in the background and warns when
+// frame stepping would be misleading.
+func (app *VideoCompareApp) analyzeCadence(vp *VideoPlayer) {
+	if cancel := vp.cadenceCancel; cancel != nil {
+		cancel()
+		vp.cadenceCancel = nil
+	}
+	vp.cadence = ""
+	if vp.ivtc {
+		vp.ivtc = false
+		_ = vp.player.SetDeinterlaceMode(libvlc.DeinterlaceModeDisable)
+	}
+	if vp.path == "" || vp.still != nil || isNetworkSource(vp.path) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	vp.cadenceCancel = cancel
+	path := vp.path
+
+	go func() {
+		cadence, err := detectCadence(ctx, path)
+		fyne.Do(func() {
+			if ctx.Err() != nil || vp.path != path {
+				return
+			}
+			cancel()
+			vp.cadenceCancel = nil
+			if err != nil {
+				log.Printf("cadence detection for %s: %v", path, err)
+				return
+			}
+			vp.cadence = cadence
+			app.setInverseTelecine(app.ivtc)
+			vp.updateStats()
+			app.updateStats()
+			if cadence == cadencePulldown && !app.ivtc {
+				dialog.ShowInformation("3:2 Pulldown Detected",
+					fmt.Sprintf("%s is telecined film (3:2 pulldown).\n"+
+						"Stepping frame by frame will show repeated and combed frames rather than the original 24 fps pictures.\n"+
+						"Enable Inverse Telecine to compare the true film frames.", vp.title),
+					app.window)
+			}
+		})
+	}()
+}
+
+// setInverseTelecine switches libvlc's IVTC filter on or off for players
+// with detected pulldown.
+func (app *VideoCompareApp) setInverseTelecine(enabled bool) {
+	app.ivtc = enabled
+	if enabled {
+		app.fields.deactivate()
+	}
+	for _, vp := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		mode := libvlc.DeinterlaceModeDisable
+		if enabled && vp.cadence == cadencePulldown {
+			mode = libvlc.DeinterlaceModeIVTC
+		}
+		vp.ivtc = mode == libvlc.DeinterlaceModeIVTC
+		vp.dropGrabbedFrame()
+		if err := vp.player.SetDeinterlaceMode(mode); err != nil {
+			log.Printf("%s: setting deinterlace mode: %v", vp.title, err)
+		}
+	}
+}
+
+// stepFPS is the frame rate frame stepping advances by: the declared or,
+// when preferred or nothing is declared, measured rate, reduced to the
+// recovered film rate while inverse telecine is active. Without either
+// rate it is estimatedFPS.
+func (vp *VideoPlayer) stepFPS() float64 {
+	fps := vp.fps
+	if measured := vp.measuredFPS(); measured > 0 && (measuredStepEnabled() || fps <= 0) {
+		fps = measured
+	}
+	if fps <= 0 {
+		fps = estimatedFPS
+	}
+	if vp.ivtc {
+		return fps * 4 / 5
+	}
+	return fps
+}
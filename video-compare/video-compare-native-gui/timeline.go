@@ -0,0 +1,51 @@
+package main
+
+import (
+	"image"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+)
+
+// timelineTick marks a stretch of a player's timeline, in seconds, under
+// its progress bar.
+type timelineTick struct {
+	start, end float64
+	color      color.RGBA
+}
+
+// newTimelineTicks returns the strip drawn under vp's progress bar marking
+// the duplicate runs found and the worst frames ranked. Ticks are at
+// least a pixel wide; later ones are drawn over earlier ones.
+func (app *VideoCompareApp) newTimelineTicks(vp *VideoPlayer) fyne.CanvasObject {
+	vp.ticks = canvas.NewRaster(func(w, h int) image.Image {
+		img := image.NewRGBA(image.Rect(0, 0, w, h))
+		if vp.duration <= 0 || w == 0 {
+			return img
+		}
+		ticks := append(app.duplicates.ticks(vp), app.worstFrames.ticks(vp)...)
+		for _, t := range ticks {
+			x0 := int(t.start / vp.duration * float64(w))
+			x1 := max(x0, int(t.end/vp.duration*float64(w)))
+			for x := max(0, x0); x <= min(w-1, x1); x++ {
+				for y := 0; y < h; y++ {
+					img.SetRGBA(x, y, t.color)
+				}
+			}
+		}
+		return img
+	})
+	vp.ticks.SetMinSize(fyne.NewSize(0, 4))
+	return vp.ticks
+}
+
+// refreshTimelineTicks redraws both players' strips after the marks
+// changed.
+func (app *VideoCompareApp) refreshTimelineTicks() {
+	for _, vp := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		if vp.ticks != nil {
+			vp.ticks.Refresh()
+		}
+	}
+}
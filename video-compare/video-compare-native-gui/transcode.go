@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"videocompare"
+)
+
+// transcodedSource is the original of a file a player shows re-encoded
+// into a format libvlc plays reliably.
+type transcodedSource struct {
+	path string
+	out  string // the re-encode in the cache
+	// offset is the original's start time in seconds; ffmpeg starts the
+	// re-encode at 0, so a position in the original is the one shown plus
+	// offset
+	offset float64
+}
+
+// transcodedPath names the file path is re-encoded into. Like joinedPath
+// it depends on the file's version, so a changed source is transcoded
+// again instead of showing a stale copy.
+func transcodedPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00", path)
+	if info, err := os.Stat(path); err == nil {
+		fmt.Fprintf(h, "%d\x00%d\x00", info.ModTime().UnixNano(), info.Size())
+	}
+	return filepath.Join(cacheDir(), "transcoded-"+hex.EncodeToString(h.Sum(nil)[:12])+".mp4")
+}
+
+// transcodeCompatible re-encodes path's first video and audio streams,
+// whichever it has, into out as H.264 in yuv420p with AAC audio, which
+// libvlc opens, seeks and steps through reliably. Every frame keeps its
+// timing, so positions map back to the original by its start time.
+// progress receives the position reached in seconds. An existing out is
+// reused.
+func transcodeCompatible(ctx context.Context, path, out string, progress func(float64)) error {
+	if _, err := os.Stat(out); err == nil {
+		return nil
+	}
+
+	// Make room first; trimming after writing could evict the new file
+	cacheTrim()
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		return err
+	}
+	tmp := filepath.Join(filepath.Dir(out), "tmp-"+filepath.Base(out))
+	err := streamFFmpeg(ctx, func(line string) {
+		// -progress reports out_time_us, and out_time_ms holding the same
+		// microseconds in older releases
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || (key != "out_time_us" && key != "out_time_ms") {
+			return
+		}
+		if us, err := strconv.ParseInt(value, 10, 64); err == nil && us >= 0 {
+			progress(float64(us) / 1e6)
+		}
+	}, "-i", path, "-map", "0:v:0?", "-map", "0:a:0?",
+		"-c:v", "libx264", "-preset", "veryfast", "-crf", "18", "-pix_fmt", "yuv420p",
+		"-fps_mode", "passthrough", "-c:a", "aac", "-b:a", "192k",
+		"-movflags", "+faststart", "-f", "mp4", "-progress", "pipe:1", "-nostats", "-y", tmp)
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("transcoding %s: %w", displayName(path), err)
+	}
+	if err := os.Rename(tmp, out); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// openTranscoded opens t's re-encode in vp in place of its original.
+func (app *VideoCompareApp) openTranscoded(vp *VideoPlayer, t transcodedSource) {
+	vp.transcoded = &t
+	app.openVideo(vp, t.out)
+}
+
+// originalPath is the file vp was asked to show: the original of a
+// transcode, otherwise the loaded file. Sessions, history and file
+// settings record it rather than the cache file.
+func (vp *VideoPlayer) originalPath() string {
+	if vp.transcoded != nil {
+		return vp.transcoded.path
+	}
+	return vp.path
+}
+
+// transcodeWarning notes the players showing a transcode, whose scores
+// measure the re-encode rather than the original, or returns "".
+func (app *VideoCompareApp) transcodeWarning() string {
+	var sides []string
+	for _, vp := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		if vp.transcoded != nil {
+			sides = append(sides, fmt.Sprintf("%s shows a transcode of %s", vp.title, displayName(vp.transcoded.path)))
+		}
+	}
+	if len(sides) == 0 {
+		return ""
+	}
+	return "Warning: " + strings.Join(sides, "; ") + ", so scores include its re-encoding losses."
+}
+
+// warnTranscoded adds transcodeWarning to the scores in text, if any.
+func (app *VideoCompareApp) warnTranscoded(text string) string {
+	if warning := app.transcodeWarning(); warning != "" {
+		return text + "\n" + warning
+	}
+	return text
+}
+
+// needsTranscode reports whether vp's file is a local one libvlc failed to
+// open, parse or find a duration in, which a re-encode may rescue.
+func (vp *VideoPlayer) needsTranscode() bool {
+	switch {
+	case vp.path == "" || vp.still != nil || vp.state == stateLoading || vp.transcoded != nil:
+		return false
+	case isNetworkSource(vp.path) || len(vp.segments) > 1:
+		return false
+	case vp.media == nil || vp.state == stateError || vp.parseTimedOut:
+		return true
+	case !vp.hasVideo && !vp.hasAudio:
+		return true
+	}
+	return vp.hasVideo && vp.duration <= 0
+}
+
+// transcodeStatus gives the position in the original of a transcoded
+// file, or "" when vp shows its file as is.
+func (vp *VideoPlayer) transcodeStatus() string {
+	if vp.transcoded == nil {
+		return ""
+	}
+	return "original " + formatTime(vp.currentTime+vp.transcoded.offset)
+}
+
+// newTranscodeButton creates the button offering a re-encode next to vp's
+// notice, shown while the file looks like one libvlc can't handle. It is
+// never shown without ffmpeg and ffprobe.
+func (app *VideoCompareApp) newTranscodeButton(vp *VideoPlayer) *widget.Button {
+	btn := widget.NewButtonWithIcon(tr("Transcode to Compatible MP4"), theme.MediaReplayIcon(), func() {
+		app.transcodePlayer(vp)
+	})
+	btn.Hide()
+	if app.unavailable(needsFFmpeg, needsFFprobe) == "" {
+		vp.transcodeBtn = btn
+	}
+	return btn
+}
+
+// updateTranscodeButton shows the transcode button when it applies.
+func (vp *VideoPlayer) updateTranscodeButton() {
+	if vp.transcodeBtn == nil {
+		return
+	}
+	if vp.needsTranscode() {
+		vp.transcodeBtn.Show()
+	} else {
+		vp.transcodeBtn.Hide()
+	}
+}
+
+// transcodePlayer re-encodes vp's file into the cache with a progress
+// dialog and opens the result in its place.
+func (app *VideoCompareApp) transcodePlayer(vp *VideoPlayer) {
+	source := vp.path
+	if vp.transcoded != nil {
+		source = vp.transcoded.path
+	}
+	switch {
+	case source == "":
+		return
+	case isNetworkSource(source) || vp.still != nil || len(vp.segments) > 1:
+		dialog.ShowInformation(tr("Transcode to Compatible MP4"), tr("Only single local video files can be transcoded."), app.window)
+		return
+	}
+	app.pauseAll()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bar := widget.NewProgressBar()
+	status := widget.NewLabel(trf("Re-encoding %s to H.264…", displayName(source)))
+	progress := dialog.NewCustom(tr("Transcoding"), tr("Cancel"), container.NewVBox(status, bar), app.window)
+	progress.SetOnClosed(cancel)
+	progress.Show()
+
+	out := transcodedPath(source)
+	// Nothing may evict the re-encode before the player shows it
+	unpin := pinCacheFile(out)
+	go func() {
+		// Neither is needed for the re-encode, only for progress and the
+		// mapping back to the original
+		duration, _ := videocompare.ProbeDuration(context.Background(), source)
+		offset, err := videocompare.ProbeStartTime(context.Background(), source)
+		if err != nil {
+			log.Printf("reading the start time of %s: %v", source, err)
+		}
+		err = transcodeCompatible(ctx, source, out, func(t float64) {
+			if duration > 0 {
+				fyne.Do(func() { bar.SetValue(min(1, t/duration)) })
+			}
+		})
+		fyne.Do(func() {
+			defer unpin()
+			cancelled := ctx.Err() != nil
+			progress.Hide()
+			switch {
+			case cancelled:
+			case err != nil:
+				log.Printf("transcode: %v", err)
+				dialog.ShowError(err, app.window)
+			default:
+				app.openTranscoded(vp, transcodedSource{path: source, out: out, offset: offset})
+			}
+		})
+	}()
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	libvlc "github.com/adrg/libvlc-go/v3"
+)
+
+const vlcDownloadURL = "https://www.videolan.org/vlc/"
+
+// vlcPluginDirs lists where VLC's plugins are found when libvlc is bundled
+// next to the executable or installed somewhere libvlc doesn't look by
+// itself. The shared library itself is resolved by the system loader before
+// main runs, so only a missing or misplaced plugin directory can be
+// recovered from here.
+func vlcPluginDirs() []string {
+	var dirs []string
+	if exe, err := os.Executable(); err == nil {
+		dir := filepath.Dir(exe)
+		dirs = append(dirs, filepath.Join(dir, "plugins"), filepath.Join(dir, "vlc", "plugins"))
+		if runtime.GOOS == "darwin" {
+			dirs = append(dirs, filepath.Join(dir, "..", "Frameworks", "plugins"))
+		}
+	}
+	switch runtime.GOOS {
+	case "windows":
+		for _, env := range []string{"ProgramFiles", "ProgramFiles(x86)"} {
+			if root := os.Getenv(env); root != "" {
+				dirs = append(dirs, filepath.Join(root, "VideoLAN", "VLC", "plugins"))
+			}
+		}
+	case "darwin":
+		dirs = append(dirs, "/Applications/VLC.app/Contents/MacOS/plugins")
+	default:
+		dirs = append(dirs,
+			"/usr/lib/x86_64-linux-gnu/vlc/plugins",
+			"/usr/lib/aarch64-linux-gnu/vlc/plugins",
+			"/usr/lib64/vlc/plugins",
+			"/usr/lib/vlc/plugins",
+			"/usr/local/lib/vlc/plugins",
+		)
+	}
+	return dirs
+}
+
+// initLibVLC initializes libvlc, retrying with each known plugin directory
+// when the default lookup fails.
+func initLibVLC() error {
+	err := libvlc.Init("")
+	if err == nil || os.Getenv("VLC_PLUGIN_PATH") != "" {
+		return err
+	}
+	for _, dir := range vlcPluginDirs() {
+		if info, statErr := os.Stat(dir); statErr != nil || !info.IsDir() {
+			continue
+		}
+		os.Setenv("VLC_PLUGIN_PATH", dir)
+		if libvlc.Init("") == nil {
+			log.Printf("libvlc: using plugins in %s", dir)
+			return nil
+		}
+	}
+	os.Unsetenv("VLC_PLUGIN_PATH")
+	return err
+}
+
+// vlcInstallInstructions explains how to install VLC on the running
+// platform, as Markdown.
+func vlcInstallInstructions() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "Download and run the VLC installer for Windows from the VideoLAN website. " +
+			"Use the 64-bit installer to match this application, and keep the default install location."
+	case "darwin":
+		return "Install VLC with Homebrew:\n\n```\nbrew install --cask vlc\n```\n\n" +
+			"or download it from the VideoLAN website and move it to the Applications folder."
+	default:
+		return "Install VLC and its development library with your package manager, for example:\n\n" +
+			"```\nsudo apt install vlc libvlc-dev     # Debian, Ubuntu\n" +
+			"sudo dnf install vlc vlc-devel      # Fedora\n" +
+			"sudo pacman -S vlc                  # Arch\n```"
+	}
+}
+
+// showVLCMissing replaces the main window with an explanation of how to
+// install VLC when libvlc could not be initialized.
+func showVLCMissing(a fyne.App, cause error) {
+	window := a.NewWindow("Video Compare - VLC Required")
+
+	intro := widget.NewRichTextFromMarkdown("## VLC is required\n\n" +
+		"Video Compare plays videos through VLC's libvlc, which could not be started. " +
+		"Install VLC, then start Video Compare again.\n\n" + vlcInstallInstructions())
+	intro.Wrapping = fyne.TextWrapWord
+
+	details := widget.NewLabel(fmt.Sprintf("Details: %v", cause))
+	details.Wrapping = fyne.TextWrapWord
+	details.Importance = widget.LowImportance
+
+	link, _ := url.Parse(vlcDownloadURL)
+	quit := widget.NewButton("Quit", a.Quit)
+
+	window.SetContent(container.NewBorder(nil,
+		container.NewHBox(widget.NewHyperlink("Download VLC", link), layout.NewSpacer(), quit),
+		nil, nil,
+		container.NewVBox(intro, details)))
+	window.Resize(fyne.NewSize(560, 380))
+	window.CenterOnScreen()
+	window.ShowAndRun()
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+// wheelNotch is the scroll delta counted as one wheel notch. Drivers
+// report a notch as 10 or more, while touchpads send a stream of smaller
+// deltas that add up to a step.
+const wheelNotch = 10
+
+// wheelFastFrames is how many frames one notch steps with Shift held.
+const wheelFastFrames = 10
+
+// wheelZoomModifier returns the key turning the wheel from frame stepping
+// to zooming: Ctrl, or Alt while Ctrl is the scrub-alone modifier so that
+// it still steps one side alone.
+func wheelZoomModifier() fyne.KeyModifier {
+	if _, alone := scrubAloneModifier(); alone == fyne.KeyModifierControl {
+		return fyne.KeyModifierAlt
+	}
+	return fyne.KeyModifierControl
+}
+
+// wheelScroll accumulates touchpad deltas between steps.
+type wheelScroll struct {
+	pending float32
+}
+
+// notches returns the whole notches scrolled, up being positive, keeping
+// what is left of a partial one for the next event.
+func (ws *wheelScroll) notches(dy float32) int {
+	if dy >= wheelNotch || dy <= -wheelNotch {
+		ws.pending = 0
+		if dy > 0 {
+			return 1
+		}
+		return -1
+	}
+	ws.pending += dy
+	n := int(ws.pending / wheelNotch)
+	ws.pending -= float32(n) * wheelNotch
+	return n
+}
+
+func keyModifiers() fyne.KeyModifier {
+	if d, ok := fyne.CurrentApp().Driver().(desktop.Driver); ok {
+		return d.CurrentKeyModifiers()
+	}
+	return 0
+}
+
+// wheel steps the player under the pointer a frame per notch, up for the
+// next frame and down for the previous one, or 10 frames with Shift held.
+// The other player follows while the sync lock is on, unless the
+// scrub-alone modifier is held, as for a nudge. With the zoom modifier
+// held the wheel zooms instead.
+func (app *VideoCompareApp) wheel(vp *VideoPlayer, ev *fyne.ScrollEvent) {
+	dy := ev.Scrolled.DY
+	if dy == 0 {
+		// macOS turns Shift+wheel into horizontal scrolling
+		dy = ev.Scrolled.DX
+	}
+	n := app.wheelScroll.notches(dy)
+	if n == 0 {
+		return
+	}
+	modifiers := keyModifiers()
+	if modifiers&wheelZoomModifier() != 0 {
+		factor := app.zoom.factor
+		for ; n > 0; n-- {
+			factor *= 2
+		}
+		for ; n < 0; n++ {
+			factor /= 2
+		}
+		app.zoom.setZoom(factor)
+		return
+	}
+	frames := 1
+	if modifiers&fyne.KeyModifierShift != 0 {
+		frames = wheelFastFrames
+	}
+	direction := 1
+	if n < 0 {
+		direction, n = -1, -n
+	}
+	app.nudge(vp, seekAmount{frames: frames * n}, direction)
+}
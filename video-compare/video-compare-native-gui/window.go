@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+)
+
+// appID identifies the application to Fyne, which keys the preferences
+// store on it.
+const appID = "io.github.hammond95.video-compare"
+
+const (
+	prefWindowWidth  = "window.width"
+	prefWindowHeight = "window.height"
+)
+
+var (
+	defaultWindowSize = fyne.NewSize(1600, 1000)
+	minWindowSize     = fyne.NewSize(640, 480)
+)
+
+// restoreWindowGeometry sizes window as it was when last closed and centres
+// it. Fyne doesn't expose window positions or monitors, so the window always
+// opens on the display the window manager picks, normally the primary one.
+func restoreWindowGeometry(window fyne.Window, prefs fyne.Preferences) {
+	size := fyne.NewSize(
+		float32(prefs.FloatWithFallback(prefWindowWidth, float64(defaultWindowSize.Width))),
+		float32(prefs.FloatWithFallback(prefWindowHeight, float64(defaultWindowSize.Height))),
+	)
+	if size.Width < minWindowSize.Width || size.Height < minWindowSize.Height {
+		size = defaultWindowSize
+	}
+	window.Resize(size)
+	window.CenterOnScreen()
+}
+
+// rememberWindowGeometry stores window's size in prefs when it is closed.
+func rememberWindowGeometry(window fyne.Window, prefs fyne.Preferences) {
+	window.SetOnClosed(func() {
+		size := window.Canvas().Size()
+		if size.Width >= minWindowSize.Width && size.Height >= minWindowSize.Height {
+			prefs.SetFloat(prefWindowWidth, float64(size.Width))
+			prefs.SetFloat(prefWindowHeight, float64(size.Height))
+		}
+	})
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	"golang.org/x/image/draw"
+)
+
+const (
+	wipeSweepFPS  = 25
+	wipeGIFWidth  = 640 // GIFs are downscaled to keep them shareable
+	wipeLineWidth = 2
+	wipeFormatGIF = "GIF"
+	wipeFormatMP4 = "MP4"
+)
+
+var wipeSweepFrames = []string{"25", "50", "100", "200"}
+
+// composeWipe shows left up to pos (0–1) of the frame width and right
+// beyond it, separated by a white line. right is resampled to left's size.
+func composeWipe(left, right image.Image, pos float64) *image.RGBA {
+	lb := left.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, lb.Dx(), lb.Dy()))
+	draw.BiLinear.Scale(out, out.Bounds(), right, right.Bounds(), draw.Src, nil)
+
+	split := int(pos * float64(lb.Dx()))
+	draw.Draw(out, image.Rect(0, 0, split, lb.Dy()), left, lb.Min, draw.Src)
+	line := image.Rect(split-wipeLineWidth/2, 0, split-wipeLineWidth/2+wipeLineWidth, lb.Dy())
+	draw.Draw(out, line.Intersect(out.Bounds()), image.NewUniform(color.White), image.Point{}, draw.Src)
+	return out
+}
+
+// wipeSweep renders n frames of the wipe line moving from the left edge to
+// the right. With advance set, both players step one frame between sweep
+// frames; otherwise the current frames are reused. Frames are handed to
+// emit in order from the calling goroutine, which must not be the UI one.
+func (app *VideoCompareApp) wipeSweep(ctx context.Context, n int, advance bool, emit func(*image.RGBA, int) error) error {
+	var left, right *image.RGBA
+	grab := func() error {
+		var exportLeft, exportRight func() (*image.RGBA, error)
+		fyne.DoAndWait(func() {
+			exportLeft, exportRight = app.frameExporter(app.leftPlayer), app.frameExporter(app.rightPlayer)
+		})
+		var err error
+		if left, err = exportLeft(); err != nil {
+			return err
+		}
+		right, err = exportRight()
+		return err
+	}
+	if err := grab(); err != nil {
+		return err
+	}
+
+	for i := 0; i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if advance && i > 0 {
+			fyne.DoAndWait(func() {
+				for _, vp := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+					if fps := vp.stepFPS(); fps > 0 {
+						vp.seekTo(vp.currentTime + 1/fps)
+					}
+				}
+			})
+			time.Sleep(frameSettleDelay)
+			if err := grab(); err != nil {
+				return err
+			}
+		}
+		if err := emit(composeWipe(left, right, float64(i)/float64(max(1, n-1))), i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeWipeGIF collects the sweep into an animated GIF.
+func (app *VideoCompareApp) encodeWipeGIF(ctx context.Context, n int, advance bool, progress func(int)) ([]byte, error) {
+	anim := &gif.GIF{}
+	err := app.wipeSweep(ctx, n, advance, func(frame *image.RGBA, i int) error {
+		b := frame.Bounds()
+		w := min(wipeGIFWidth, b.Dx())
+		h := max(1, b.Dy()*w/b.Dx())
+		scaled := image.NewRGBA(image.Rect(0, 0, w, h))
+		draw.BiLinear.Scale(scaled, scaled.Bounds(), frame, b, draw.Src, nil)
+
+		paletted := image.NewPaletted(scaled.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), scaled, image.Point{})
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, 100/wipeSweepFPS)
+		progress(i + 1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeWipeMP4 renders the sweep into numbered PNGs in a temporary
+// directory and then encodes them into an H.264 MP4, which it returns.
+// The frames are all rendered before ffmpeg starts: grabbing an adjusted
+// player's frame runs ffmpeg too, and must not wait for a job slot while
+// the encoder holds one.
+func (app *VideoCompareApp) encodeWipeMP4(ctx context.Context, n int, advance bool, progress func(int)) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "video-compare-wipe-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	// Speed matters more than size for files read back right away
+	encoder := png.Encoder{CompressionLevel: png.BestSpeed}
+	err = app.wipeSweep(ctx, n, advance, func(frame *image.RGBA, i int) error {
+		f, err := os.Create(filepath.Join(dir, fmt.Sprintf("frame-%05d.png", i)))
+		if err != nil {
+			return err
+		}
+		err = encoder.Encode(f, frame)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			progress(i + 1)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Encoders want even dimensions; the odd last row/column is dropped
+	return runFFmpeg(ctx, "-framerate", strconv.Itoa(wipeSweepFPS), "-i", filepath.Join(dir, "frame-%05d.png"),
+		"-vf", "crop=trunc(iw/2)*2:trunc(ih/2)*2:0:0",
+		"-c:v", "libx264", "-pix_fmt", "yuv420p", "-movflags", "frag_keyframe+empty_moov", "-f", "mp4", "-")
+}
+
+// exportWipeSweep asks for the sweep settings and a destination, then
+// renders and encodes the animation in the background.
+func (app *VideoCompareApp) exportWipeSweep() {
+	framesSelect := widget.NewSelect(wipeSweepFrames, nil)
+	framesSelect.SetSelected(wipeSweepFrames[1])
+	formatSelect := widget.NewSelect([]string{wipeFormatGIF, wipeFormatMP4}, nil)
+	formatSelect.SetSelected(wipeFormatGIF)
+	advanceCheck := widget.NewCheck("Play video during the sweep", nil)
+
+	dialog.ShowForm("Export Wipe Sweep", "Next", "Cancel",
+		[]*widget.FormItem{
+			widget.NewFormItem("Frames", framesSelect),
+			widget.NewFormItem("Format", formatSelect),
+			widget.NewFormItem("", advanceCheck),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			n, _ := strconv.Atoi(framesSelect.Selected)
+			app.saveWipeSweep(max(2, n), formatSelect.Selected, advanceCheck.Checked)
+		}, app.window)
+}
+
+func (app *VideoCompareApp) saveWipeSweep(n int, format string, advance bool) {
+	ext := ".gif"
+	encode := app.encodeWipeGIF
+	if format == wipeFormatMP4 {
+		ext = ".mp4"
+		encode = app.encodeWipeMP4
+	}
+
+	fd := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		app.pauseAll()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		bar := widget.NewProgressBar()
+		bar.Max = float64(n)
+		progress := dialog.NewCustom("Exporting Wipe Sweep", "Cancel", bar, app.window)
+		progress.SetOnClosed(cancel)
+		progress.Show()
+
+		go func() {
+			data, err := encode(ctx, n, advance, func(done int) {
+				fyne.Do(func() { bar.SetValue(float64(done)) })
+			})
+			if err == nil {
+				_, err = writer.Write(data)
+			}
+			writer.Close()
+			fyne.Do(func() {
+				cancelled := ctx.Err() != nil
+				progress.Hide()
+				if err != nil && !cancelled {
+					log.Printf("wipe sweep export: %v", err)
+					dialog.ShowError(err, app.window)
+				}
+			})
+		}()
+	}, app.window)
+	fd.SetFileName(fmt.Sprintf("wipe-sweep-%s%s", app.leftPlayer.timecodeFileStamp(), ext))
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{ext}))
+	fd.Show()
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"videocompare"
+)
+
+var (
+	worstFrameCounts = []string{"5", "10", "20", "50"}
+	worstFrameTick   = color.RGBA{R: 0xff, G: 0x30, B: 0x30, A: 0xff}
+)
+
+const defaultWorstFrameCount = "10"
+
+// worstFrameSpacing is how far apart, in seconds, ranked moments must be,
+// so one bad shot doesn't fill the list with its neighbouring frames.
+const worstFrameSpacing = 1.0
+
+// worstFrame is a ranked moment: the positions of the compared frames in
+// both players and their PSNR.
+type worstFrame struct {
+	left, right float64
+	psnr        float64
+}
+
+// scanFramePSNR runs ffmpeg's psnr filter over duration seconds of both
+// files from their start times, scaling right to left's size, and returns
+// every frame's score. progress receives the frame reached.
+func scanFramePSNR(ctx context.Context, left string, leftStart float64, right string, rightStart, duration float64, progress func(frame int)) ([]videocompare.FrameScore, error) {
+	var scores []videocompare.FrameScore
+	err := streamFFmpeg(ctx, func(line string) {
+		if s, ok := videocompare.ParseFramePSNR(line); ok {
+			scores = append(scores, s)
+			progress(s.Frame)
+		}
+	},
+		"-ss", fmt.Sprintf("%.3f", leftStart), "-t", fmt.Sprintf("%.3f", duration), "-i", left,
+		"-ss", fmt.Sprintf("%.3f", rightStart), "-t", fmt.Sprintf("%.3f", duration), "-i", right,
+		"-lavfi", "[1:v][0:v]scale2ref[dist][ref];[dist][ref]psnr=stats_file=-", "-f", "null", "-")
+	if err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
+
+// rankWorstFrames returns up to n of the lowest scores, worst first, at
+// least spacing frames apart.
+func rankWorstFrames(scores []videocompare.FrameScore, n, spacing int) []videocompare.FrameScore {
+	sorted := append([]videocompare.FrameScore(nil), scores...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Score < sorted[j].Score })
+	var ranked []videocompare.FrameScore
+	for _, s := range sorted {
+		if len(ranked) == n {
+			break
+		}
+		near := false
+		for _, r := range ranked {
+			if d := s.Frame - r.Frame; d > -spacing && d < spacing {
+				near = true
+				break
+			}
+		}
+		if !near {
+			ranked = append(ranked, s)
+		}
+	}
+	return ranked
+}
+
+// worstFramesPanel scores every frame of the players' in/out ranges,
+// ranks the worst moments, marks them under both progress bars and seeks
+// there on click, jumping to the worst one as soon as the scan is done.
+type worstFramesPanel struct {
+	app    *VideoCompareApp
+	frames []worstFrame
+	fps    float64 // of the left file when scanned, for the tick width
+	cancel context.CancelFunc
+
+	countSelect *widget.Select
+	scanBtn     *widget.Button
+	cancelBtn   *widget.Button
+	worstBtn    *widget.Button
+	progressBar *widget.ProgressBar
+	statusLabel *widget.Label
+	list        *widget.List
+}
+
+func newWorstFramesPanel(app *VideoCompareApp) *worstFramesPanel {
+	return &worstFramesPanel{app: app}
+}
+
+func (wp *worstFramesPanel) content() fyne.CanvasObject {
+	wp.countSelect = widget.NewSelect(worstFrameCounts, nil)
+	wp.countSelect.SetSelected(defaultWorstFrameCount)
+	wp.scanBtn = widget.NewButtonWithIcon("Scan Range", theme.SearchIcon(), wp.scan)
+	wp.cancelBtn = widget.NewButtonWithIcon("Cancel", theme.CancelIcon(), wp.stop)
+	wp.worstBtn = widget.NewButtonWithIcon("Go to Worst Frame", theme.MediaSkipNextIcon(), func() { wp.seek(0) })
+	wp.progressBar = widget.NewProgressBar()
+	wp.statusLabel = widget.NewLabel("")
+
+	wp.list = widget.NewList(
+		func() int { return len(wp.frames) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			f := wp.frames[id]
+			obj.(*widget.Label).SetText(fmt.Sprintf("%d. %s ↔ %s: PSNR %s",
+				id+1, formatTimecode(f.left, wp.app.leftPlayer.fps), formatTimecode(f.right, wp.app.rightPlayer.fps), formatPSNR(f.psnr)))
+		},
+	)
+	wp.list.OnSelected = wp.seek
+
+	toolbar := container.NewHBox(widget.NewLabel("Worst"), wp.countSelect, wp.scanBtn, wp.cancelBtn, widget.NewSeparator(), wp.worstBtn)
+	top := container.NewVBox(toolbar, wp.progressBar, wp.statusLabel)
+	wp.refresh()
+	return container.NewBorder(top, nil, nil, nil, wp.list)
+}
+
+// ticks marks the ranked moments on vp's timeline, a frame wide each.
+func (wp *worstFramesPanel) ticks(vp *VideoPlayer) []timelineTick {
+	ticks := make([]timelineTick, len(wp.frames))
+	for i, f := range wp.frames {
+		t := f.left
+		if vp == wp.app.rightPlayer {
+			t = f.right
+		}
+		ticks[i] = timelineTick{start: t, end: t + 1/wp.fps, color: worstFrameTick}
+	}
+	return ticks
+}
+
+// scan scores both files over their in/out ranges, from each range's
+// start for the length of the shorter one, and ranks the worst frames.
+func (wp *worstFramesPanel) scan() {
+	l, r := wp.app.leftPlayer, wp.app.rightPlayer
+	for _, vp := range []*VideoPlayer{l, r} {
+		if vp.path == "" || vp.still != nil || isNetworkSource(vp.path) || vp.duration <= 0 {
+			wp.setStatus("Scanning needs a local video file in both players")
+			return
+		}
+	}
+	wp.stop()
+	wp.frames = nil
+	wp.list.UnselectAll()
+	ctx, cancel := context.WithCancel(context.Background())
+	wp.cancel = cancel
+
+	count, _ := strconv.Atoi(wp.countSelect.Selected)
+	fps := l.fps
+	if fps <= 0 {
+		fps = estimatedFPS
+	}
+	wp.fps = fps
+	leftStart, leftEnd := l.playRange()
+	rightStart, rightEnd := r.playRange()
+	duration := math.Min(leftEnd-leftStart, rightEnd-rightStart)
+	paths := [2]string{l.path, r.path}
+	stale := func() bool { return ctx.Err() != nil || l.path != paths[0] || r.path != paths[1] }
+	wp.progressBar.SetValue(0)
+	wp.setStatus(fmt.Sprintf("Scoring every frame of %s – %s…", formatTime(leftStart), formatTime(leftStart+duration)))
+
+	go func() {
+		var lastReport time.Time
+		scores, err := scanFramePSNR(ctx, paths[0], leftStart, paths[1], rightStart, duration, func(frame int) {
+			if time.Since(lastReport) < duplicateProgressInterval {
+				return
+			}
+			lastReport = time.Now()
+			fyne.Do(func() {
+				if !stale() {
+					wp.progressBar.SetValue(min(1, float64(frame)/fps/duration))
+				}
+			})
+		})
+		fyne.Do(func() {
+			if stale() {
+				return
+			}
+			cancel()
+			wp.cancel = nil
+			if err != nil {
+				log.Printf("worst frame scan: %v", err)
+				wp.setStatus(fmt.Sprintf("Scan failed: %v", err))
+				return
+			}
+			if len(scores) == 0 {
+				wp.setStatus("No frames were compared")
+				return
+			}
+			for _, s := range rankWorstFrames(scores, count, int(math.Ceil(worstFrameSpacing*fps))) {
+				offset := float64(s.Frame) / fps
+				wp.frames = append(wp.frames, worstFrame{left: leftStart + offset, right: rightStart + offset, psnr: s.Score})
+			}
+			if len(wp.frames) > 0 {
+				worst := wp.frames[0]
+				wp.app.recordScores("Worst frames", reportScore{
+					Scope: fmt.Sprintf("%d frame(s) from %s and %s", len(scores), formatTime(leftStart), formatTime(rightStart)),
+					PSNR:  fmt.Sprintf("lowest %s at %s", formatPSNR(worst.psnr), formatTimecode(worst.left, fps)),
+					SSIM:  "—",
+				})
+			}
+			wp.progressBar.SetValue(1)
+			wp.setStatus(wp.app.warnTranscoded(fmt.Sprintf("%d frame(s) scored; the worst %d moment(s) at least %.0f s apart are marked in red under the progress bars",
+				len(scores), len(wp.frames), worstFrameSpacing)))
+			wp.list.Select(0)
+		})
+	}()
+}
+
+// seek moves both players to the ranked moment id, paused so they stay on
+// it.
+func (wp *worstFramesPanel) seek(id widget.ListItemID) {
+	if id < 0 || id >= len(wp.frames) {
+		return
+	}
+	f := wp.frames[id]
+	wp.app.pauseAll()
+	wp.app.leftPlayer.seekTo(f.left)
+	wp.app.rightPlayer.seekTo(f.right)
+}
+
+// stop cancels a running scan.
+func (wp *worstFramesPanel) stop() {
+	if wp.cancel == nil {
+		return
+	}
+	wp.cancel()
+	wp.cancel = nil
+	wp.setStatus("Scan cancelled")
+}
+
+// forget drops results that no longer match the loaded files.
+func (wp *worstFramesPanel) forget() {
+	if wp.cancel != nil {
+		wp.cancel()
+		wp.cancel = nil
+	}
+	wp.frames = nil
+	if wp.list != nil {
+		wp.list.UnselectAll()
+		wp.progressBar.SetValue(0)
+	}
+	wp.setStatus("")
+}
+
+func (wp *worstFramesPanel) setStatus(text string) {
+	if wp.statusLabel != nil {
+		wp.statusLabel.SetText(text)
+	}
+	wp.refresh()
+}
+
+// refresh enables what applies and redraws the list and timeline ticks.
+func (wp *worstFramesPanel) refresh() {
+	wp.app.refreshTimelineTicks()
+	if wp.list == nil {
+		return
+	}
+	if wp.cancel != nil {
+		wp.scanBtn.Disable()
+		wp.cancelBtn.Enable()
+	} else {
+		wp.scanBtn.Enable()
+		wp.cancelBtn.Disable()
+	}
+	if len(wp.frames) > 0 {
+		wp.worstBtn.Enable()
+	} else {
+		wp.worstBtn.Disable()
+	}
+	if !wp.app.bothLoaded() {
+		wp.scanBtn.Disable()
+	}
+	if reason := wp.app.unavailable(needsFFmpeg); reason != "" {
+		wp.scanBtn.Disable()
+		wp.statusLabel.SetText("Scanning for the worst frames " + reason)
+	}
+	wp.list.Refresh()
+}
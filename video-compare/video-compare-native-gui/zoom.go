@@ -0,0 +1,366 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+)
+
+const maxZoom = 64
+
+// defaultZoomWidth is the rendered width of a zoomed view before the
+// player's display has been laid out.
+const defaultZoomWidth = 640
+
+var nudgeSteps = []string{"0.1 px", "0.25 px", "0.5 px", "1 px"}
+
+// newZoomView creates the hidden image showing the zoomed crop over the
+// video canvas.
+func (vp *VideoPlayer) newZoomView() *canvas.Image {
+	vp.zoomView = canvas.NewImageFromImage(nil)
+	vp.zoomView.FillMode = canvas.ImageFillContain
+	vp.zoomView.Hide()
+	return vp.zoomView
+}
+
+// zoomPanel zooms both players into the same region of their frames. Each
+// player can additionally be shifted by a sub-pixel registration offset
+// that only moves its displayed crop, so small misalignments between the
+// two sources can be cancelled out while inspecting detail.
+type zoomPanel struct {
+	app     *VideoCompareApp
+	factor  float64    // 1 shows the whole frame
+	center  [2]float64 // normalized centre of the zoomed region
+	offsets map[*VideoPlayer][2]float64
+	step    float64
+	pending int // bumped on every render so stale results are dropped
+
+	frames     map[*VideoPlayer]image.Image
+	frameTimes map[*VideoPlayer]float64
+
+	zoomLabel   *widget.Label
+	offsetLabel *widget.Label
+}
+
+func newZoomPanel(app *VideoCompareApp) *zoomPanel {
+	return &zoomPanel{
+		app:        app,
+		factor:     1,
+		center:     [2]float64{0.5, 0.5},
+		offsets:    make(map[*VideoPlayer][2]float64),
+		step:       0.25,
+		frames:     make(map[*VideoPlayer]image.Image),
+		frameTimes: make(map[*VideoPlayer]float64),
+	}
+}
+
+func (zp *zoomPanel) toolbar() fyne.CanvasObject {
+	zp.zoomLabel = widget.NewLabel("")
+	zp.offsetLabel = widget.NewLabel("")
+
+	zoomIn := widget.NewButtonWithIcon("", theme.ZoomInIcon(), func() { zp.setZoom(zp.factor * 2) })
+	zoomOut := widget.NewButtonWithIcon("", theme.ZoomOutIcon(), func() { zp.setZoom(zp.factor / 2) })
+	fit := widget.NewButtonWithIcon(tr("Fit"), theme.ZoomFitIcon(), func() { zp.setZoom(1) })
+
+	stepSelect := widget.NewSelect(nudgeSteps, func(s string) {
+		zp.step, _ = strconv.ParseFloat(strings.TrimSuffix(s, " px"), 64)
+	})
+	stepSelect.SetSelected(nudgeSteps[1])
+
+	nudges := func(vp *VideoPlayer) fyne.CanvasObject {
+		nudge := func(icon fyne.Resource, dx, dy float64) *widget.Button {
+			return widget.NewButtonWithIcon("", icon, func() { zp.nudge(vp, dx, dy) })
+		}
+		return container.NewHBox(widget.NewLabel(vp.title+":"),
+			nudge(theme.NavigateBackIcon(), -1, 0), nudge(theme.NavigateNextIcon(), 1, 0),
+			nudge(theme.MoveUpIcon(), 0, -1), nudge(theme.MoveDownIcon(), 0, 1))
+	}
+	resetBtn := widget.NewButtonWithIcon(tr("Reset Registration"), theme.ViewRefreshIcon(), zp.resetRegistration)
+
+	zp.updateLabels()
+	return container.NewHBox(
+		widget.NewLabel(tr("Zoom:")), zoomOut, zoomIn, fit, zp.zoomLabel,
+		widget.NewSeparator(),
+		nudges(zp.app.leftPlayer), nudges(zp.app.rightPlayer),
+		widget.NewLabel(tr("Step:")), stepSelect,
+		resetBtn, zp.offsetLabel,
+	)
+}
+
+func (zp *zoomPanel) active() bool {
+	return zp.factor > 1
+}
+
+// setZoom changes the shared zoom factor, keeping the zoomed region centred.
+func (zp *zoomPanel) setZoom(factor float64) {
+	zp.factor = math.Min(maxZoom, math.Max(1, factor))
+	zp.clampCenter()
+	zp.updateLabels()
+	zp.app.roi.updateOutlines()
+	zp.refresh()
+}
+
+// framePoint maps a normalized point on vp's display to normalized frame
+// coordinates, through the zoomed region and vp's registration offset.
+func (zp *zoomPanel) framePoint(vp *VideoPlayer, p [2]float64) [2]float64 {
+	if !zp.active() {
+		return p
+	}
+	offset := zp.offsets[vp]
+	size := [2]float64{float64(vp.width), float64(vp.height)}
+	for i := range p {
+		p[i] = zp.center[i] + (p[i]-0.5)/zp.factor
+		if size[i] > 0 {
+			p[i] += offset[i] / size[i]
+		}
+		p[i] = math.Min(1, math.Max(0, p[i]))
+	}
+	return p
+}
+
+// displayPoint is the inverse of framePoint, for drawing frame positions
+// over the display. Points outside the zoomed region fall outside [0, 1].
+func (zp *zoomPanel) displayPoint(vp *VideoPlayer, p [2]float64) [2]float64 {
+	if !zp.active() {
+		return p
+	}
+	offset := zp.offsets[vp]
+	size := [2]float64{float64(vp.width), float64(vp.height)}
+	for i := range p {
+		if size[i] > 0 {
+			p[i] -= offset[i] / size[i]
+		}
+		p[i] = (p[i]-zp.center[i])*zp.factor + 0.5
+	}
+	return p
+}
+
+// framePoint maps a normalized point on vp's display onto its frame
+// through the shared zoom.
+func (vp *VideoPlayer) framePoint(p [2]float64) [2]float64 {
+	if vp.zoom == nil {
+		return p
+	}
+	return vp.zoom.framePoint(vp, p)
+}
+
+// displayPoint maps a normalized frame point onto vp's display through
+// the shared zoom.
+func (vp *VideoPlayer) displayPoint(p [2]float64) [2]float64 {
+	if vp.zoom == nil {
+		return p
+	}
+	return vp.zoom.displayPoint(vp, p)
+}
+
+// displayScale is how many times larger than at fit size the display
+// shows vp's frame.
+func (vp *VideoPlayer) displayScale() float64 {
+	if vp.zoom == nil || !vp.zoom.active() {
+		return 1
+	}
+	return vp.zoom.factor
+}
+
+// clampCenter keeps the zoomed region inside the frame.
+func (zp *zoomPanel) clampCenter() {
+	half := 0.5 / zp.factor
+	for i := range zp.center {
+		zp.center[i] = math.Min(1-half, math.Max(half, zp.center[i]))
+	}
+}
+
+// pan moves the zoomed region of both players by a drag over either video.
+func (zp *zoomPanel) pan(vp *VideoPlayer, ev *fyne.DragEvent) {
+	size := vp.display.Size()
+	if !zp.active() || size.Width <= 0 || size.Height <= 0 {
+		return
+	}
+	zp.center[0] -= float64(ev.Dragged.DX/size.Width) / zp.factor
+	zp.center[1] -= float64(ev.Dragged.DY/size.Height) / zp.factor
+	zp.clampCenter()
+	zp.render()
+}
+
+// nudge shifts vp's registration offset by one step in the given direction.
+func (zp *zoomPanel) nudge(vp *VideoPlayer, dx, dy float64) {
+	o := zp.offsets[vp]
+	zp.offsets[vp] = [2]float64{o[0] + dx*zp.step, o[1] + dy*zp.step}
+	zp.updateLabels()
+	zp.render()
+}
+
+func (zp *zoomPanel) resetRegistration() {
+	clear(zp.offsets)
+	zp.updateLabels()
+	zp.render()
+}
+
+func (zp *zoomPanel) updateLabels() {
+	if zp.zoomLabel == nil {
+		return
+	}
+	zp.zoomLabel.SetText(fmt.Sprintf("%.0f×", zp.factor))
+	l, r := zp.offsets[zp.app.leftPlayer], zp.offsets[zp.app.rightPlayer]
+	zp.offsetLabel.SetText(trf("Offset L %+.2f,%+.2f px  R %+.2f,%+.2f px", l[0], l[1], r[0], r[1]))
+}
+
+// refresh grabs fresh frames for the zoomed views when the playback
+// position has moved, then renders them.
+func (zp *zoomPanel) refresh() {
+	if !zp.active() {
+		for _, vp := range []*VideoPlayer{zp.app.leftPlayer, zp.app.rightPlayer} {
+			vp.zoomView.Hide()
+			vp.zoomView.Image = nil
+		}
+		clear(zp.frames)
+		return
+	}
+
+	var stale []*VideoPlayer
+	var grabs []func() (image.Image, error)
+	for _, vp := range []*VideoPlayer{zp.app.leftPlayer, zp.app.rightPlayer} {
+		if _, ok := zp.frames[vp]; vp.path != "" && (!ok || zp.frameTimes[vp] != vp.currentTime) {
+			stale = append(stale, vp)
+			grabs = append(grabs, vp.frameGrabber())
+		}
+	}
+	if len(stale) == 0 {
+		zp.render()
+		return
+	}
+
+	zp.pending++
+	generation := zp.pending
+	go func() {
+		time.Sleep(frameSettleDelay)
+		frames := make([]image.Image, len(stale))
+		for i, grab := range grabs {
+			frame, err := grab()
+			if err != nil {
+				log.Printf("zoom: %v", err)
+				continue
+			}
+			frames[i] = frame
+		}
+		fyne.Do(func() {
+			if generation != zp.pending {
+				return
+			}
+			for i, vp := range stale {
+				if frames[i] != nil {
+					zp.frames[vp] = frames[i]
+					zp.frameTimes[vp] = vp.currentTime
+				}
+			}
+			zp.render()
+		})
+	}()
+}
+
+// render crops the cached frames in the background and shows the result
+// over each player's video.
+func (zp *zoomPanel) render() {
+	if !zp.active() {
+		return
+	}
+	zp.app.roi.updateOutlines()
+	zp.pending++
+	generation := zp.pending
+	factor, center := zp.factor, zp.center
+
+	for vp, frame := range zp.frames {
+		offset := zp.offsets[vp]
+		width := int(vp.display.Size().Width)
+		if width <= 0 {
+			width = defaultZoomWidth
+		}
+		go func() {
+			img := zoomCrop(frame, factor, center, offset, width)
+			fyne.Do(func() {
+				if generation != zp.pending || zp.frames[vp] != frame {
+					return
+				}
+				vp.zoomView.Image = img
+				vp.zoomView.Show()
+				vp.zoomView.Refresh()
+			})
+		}()
+	}
+}
+
+// zoomCrop renders the 1/factor region of frame around the normalized
+// centre, shifted by offset source pixels, at the given output width.
+// Offsets need not be whole pixels; the crop is resampled bilinearly.
+func zoomCrop(frame image.Image, factor float64, center, offset [2]float64, width int) *image.RGBA {
+	b := frame.Bounds()
+	srcW, srcH := float64(b.Dx()), float64(b.Dy())
+	height := max(1, int(float64(width)*srcH/srcW))
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	scale := float64(width) / (srcW / factor)
+	x0 := float64(b.Min.X) + center[0]*srcW - srcW/factor/2 + offset[0]
+	y0 := float64(b.Min.Y) + center[1]*srcH - srcH/factor/2 + offset[1]
+	s2d := f64.Aff3{scale, 0, -x0 * scale, 0, scale, -y0 * scale}
+	draw.BiLinear.Transform(out, s2d, frame, b, draw.Src, nil)
+	return out
+}
+
+// forget drops vp's cached frame when a new file is loaded into it.
+func (zp *zoomPanel) forget(vp *VideoPlayer) {
+	delete(zp.frames, vp)
+	vp.zoomView.Hide()
+	vp.zoomView.Image = nil
+	zp.refresh()
+}
+
+// restore applies a saved zoom factor and centre, falling back to the
+// defaults for values that don't make sense.
+func (zp *zoomPanel) restore(factor float64, center [2]float64) {
+	if math.IsNaN(factor) || factor < 1 {
+		factor = 1
+	}
+	for i, c := range center {
+		if math.IsNaN(c) || math.IsInf(c, 0) {
+			center[i] = 0.5
+		}
+	}
+	zp.center = center
+	zp.setZoom(factor)
+}
+
+// setRegistration applies a saved registration offset to vp. The offset
+// was set against a width×height frame; if vp's resolution differs it is
+// rescaled, and it is clamped to the frame size either way.
+func (zp *zoomPanel) setRegistration(vp *VideoPlayer, offset [2]float64, width, height int) {
+	if vp.width > 0 && vp.height > 0 {
+		if width > 0 && height > 0 && (width != vp.width || height != vp.height) {
+			offset[0] *= float64(vp.width) / float64(width)
+			offset[1] *= float64(vp.height) / float64(height)
+		}
+		offset[0] = math.Max(-float64(vp.width), math.Min(float64(vp.width), offset[0]))
+		offset[1] = math.Max(-float64(vp.height), math.Min(float64(vp.height), offset[1]))
+	}
+	if math.IsNaN(offset[0]) || math.IsNaN(offset[1]) {
+		offset = [2]float64{}
+	}
+	if offset == ([2]float64{}) {
+		delete(zp.offsets, vp)
+	} else {
+		zp.offsets[vp] = offset
+	}
+	zp.updateLabels()
+	zp.render()
+}
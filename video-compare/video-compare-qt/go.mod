@@ -2,10 +2,16 @@ module video-compare-qt
 
 go 1.23.0
 
-require github.com/visualfc/atk v1.2.3
+require (
+	github.com/visualfc/atk v1.2.3
+	videocompare v0.0.0
+)
 
 require (
 	github.com/gopherjs/gopherjs v0.0.0-20190411002643-bd77b112433e // indirect
 	github.com/therecipe/qt v0.0.0-20200904063919-c0c124a5770d // indirect
+	golang.org/x/image v0.24.0 // indirect
 	golang.org/x/sync v0.16.0 // indirect
 )
+
+replace videocompare => ../videocompare
@@ -1,15 +1,32 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/visualfc/atk/tk"
+	"videocompare"
 )
 
+// estimatedFPS is the frame rate frame stepping assumes while a file's is
+// unknown.
+const estimatedFPS = 25.0
+
+// seekSteps is the resolution of the progress bars, fine enough that a
+// scrub lands within a second on a day-long file.
+const seekSteps = 100000
+
+// supportedFormats matches the default extension list of the other front
+// ends.
+var supportedFormats = []string{
+	".mp4", ".mkv", ".avi", ".mov", ".webm", ".flv", ".wmv", ".m4v", ".3gp", ".ogv", ".ts", ".mts", ".m2ts",
+}
+
 type VideoPlayer struct {
 	mediaPlayer *tk.MediaPlayer
 	videoWidget *tk.VideoWidget
@@ -21,8 +38,12 @@ type VideoPlayer struct {
 	timeLabel   *tk.Label
 	statsLabel  *tk.Label
 	progressBar *tk.Scale
+	seekBtn     *tk.Button
 
 	// State
+	ready       bool // duration and frame rate are known, so seeking and stepping work
+	loadSeq     int  // bumped on every load so superseded probes are dropped
+	onReady     func()
 	isPlaying   bool
 	currentTime float64
 	duration    float64
@@ -30,6 +51,9 @@ type VideoPlayer struct {
 	width       int
 	height      int
 	bitrate     int
+
+	// Set while the progress bar is moved from code, so it doesn't seek
+	updatingProgress bool
 }
 
 type VideoCompareApp struct {
@@ -75,6 +99,12 @@ func main() {
 func (app *VideoCompareApp) initializePlayers() {
 	app.leftPlayer = newVideoPlayer("Left Video")
 	app.rightPlayer = newVideoPlayer("Right Video")
+	for _, player := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		player.onReady = func() {
+			app.updateStats()
+			app.updateFrameControls()
+		}
+	}
 }
 
 func newVideoPlayer(title string) *VideoPlayer {
@@ -88,10 +118,17 @@ func newVideoPlayer(title string) *VideoPlayer {
 		fileLabel:   tk.NewLabel("No file selected"),
 		timeLabel:   tk.NewLabel("00:00 / 00:00"),
 		statsLabel:  tk.NewLabel("No video loaded"),
-		progressBar: tk.NewScale(),
+		progressBar: newProgressBar(),
 	}
 }
 
+// newProgressBar creates a seek bar running from 0 to seekSteps.
+func newProgressBar() *tk.Scale {
+	bar := tk.NewScale()
+	bar.SetRange(0, seekSteps)
+	return bar
+}
+
 func (app *VideoCompareApp) createUI() {
 	// Create file selection buttons
 	leftFileBtn := tk.NewButton("Choose Left Video")
@@ -185,6 +222,9 @@ func (app *VideoCompareApp) createUI() {
 	mainLayout.AddWidget(bottomPanel)
 
 	app.window.SetLayout(mainLayout)
+	app.leftPlayer.updateSeekControls()
+	app.rightPlayer.updateSeekControls()
+	app.updateFrameControls()
 }
 
 func (app *VideoCompareApp) createPlayerControls(player *VideoPlayer, side string) *tk.Frame {
@@ -211,6 +251,7 @@ func (app *VideoCompareApp) createPlayerControls(player *VideoPlayer, side strin
 	timeInput.SetPlaceHolder("00:00:00")
 
 	seekBtn := tk.NewButton("Seek")
+	player.seekBtn = seekBtn
 	seekBtn.OnCommand(func() {
 		if timeStr := timeInput.Text(); timeStr != "" {
 			player.seekToTime(timeStr)
@@ -230,16 +271,28 @@ func (app *VideoCompareApp) createPlayerControls(player *VideoPlayer, side strin
 func (app *VideoCompareApp) selectVideoFile(player *VideoPlayer) {
 	// For now, we'll use a simple file dialog
 	// In a real implementation, you'd use Qt's file dialog
-	filePath := tk.ChooseFile("Select Video File", "Video Files (*.mp4 *.mkv *.avi *.mov *.webm)")
+	patterns := make([]string, len(supportedFormats))
+	for i, ext := range supportedFormats {
+		patterns[i] = "*" + ext
+	}
+	filePath := tk.ChooseFile("Select Video File", fmt.Sprintf("Video Files (%s)", strings.Join(patterns, " ")))
 	if filePath != "" {
 		player.load(filePath)
 		app.updateStats()
+		app.updateFrameControls()
 	}
 }
 
 func (player *VideoPlayer) load(path string) {
+	player.loadSeq++
+	seq := player.loadSeq
 	player.path = path
-	player.fileLabel.SetText(filepath.Base(path))
+	player.currentTime, player.duration, player.fps = 0, 0, 0
+	player.ready = false
+	player.updateSeekControls()
+	player.updateTimeDisplay()
+	player.progressBar.SetValue(0)
+	player.fileLabel.SetText(filepath.Base(path) + " — reading metadata…")
 
 	// Set the media source
 	player.mediaPlayer.SetSource(path)
@@ -247,8 +300,33 @@ func (player *VideoPlayer) load(path string) {
 	// Connect the media player to the video widget
 	player.videoWidget.SetMediaPlayer(player.mediaPlayer)
 
-	// Get media information
-	player.extractMediaInfo()
+	// Get media information in the background; seeking and frame stepping
+	// stay disabled until it is known rather than acting on a zero
+	// duration and frame rate
+	go func() {
+		// Either is left at 0 when ffprobe can't tell
+		ctx := context.Background()
+		duration, _ := videocompare.ProbeDuration(ctx, path)
+		fps, _ := videocompare.ProbeFrameRate(ctx, path)
+		tk.Async(func() {
+			if player.loadSeq != seq {
+				return
+			}
+			player.extractMediaInfo(duration, fps)
+			player.fileLabel.SetText(filepath.Base(path))
+			player.ready = player.duration > 0
+			player.updateSeekControls()
+
+			// Show the duration now; the ticker only updates while
+			// playing, and the file can be seeked before it ever is
+			player.updateTimeDisplay()
+			player.updateProgressBar()
+			player.updateStats()
+			if player.onReady != nil {
+				player.onReady()
+			}
+		})
+	}()
 
 	// Set up progress bar callback
 	player.setupProgressCallback()
@@ -257,19 +335,43 @@ func (player *VideoPlayer) load(path string) {
 	player.updateStats()
 }
 
-func (player *VideoPlayer) extractMediaInfo() {
+// extractMediaInfo takes over the media information, preferring the
+// media player's duration to the one probed from the file.
+func (player *VideoPlayer) extractMediaInfo(probedDuration, probedFPS float64) {
 	// Get duration
 	player.duration = player.mediaPlayer.Duration() / 1000.0 // Convert to seconds
+	if player.duration <= 0 {
+		// The media player may not know it until the file is loaded
+		player.duration = probedDuration
+	}
 
 	// Get video information
 	// Note: This is a simplified version. In a real implementation,
 	// you'd get more detailed information from the media player
 	player.width = 1920 // Default values
 	player.height = 1080
-	player.fps = 30.0
+	player.fps = probedFPS
 	player.bitrate = 0
 }
 
+// stepFPS is the frame rate frame stepping advances by: the file's, or
+// estimatedFPS while that is unknown.
+func (player *VideoPlayer) stepFPS() float64 {
+	if player.fps > 0 {
+		return player.fps
+	}
+	return estimatedFPS
+}
+
+// fpsSummary gives the frame rate, flagging the estimate used when the
+// file's is unknown.
+func (player *VideoPlayer) fpsSummary() string {
+	if player.fps > 0 {
+		return fmt.Sprintf("%.2f", player.fps)
+	}
+	return fmt.Sprintf("%.2f (estimated)", estimatedFPS)
+}
+
 func (player *VideoPlayer) setupProgressCallback() {
 	// Set up a timer to update progress
 	go func() {
@@ -281,7 +383,7 @@ func (player *VideoPlayer) setupProgressCallback() {
 				player.currentTime = player.mediaPlayer.Position() / 1000.0
 
 				// Update UI on main thread
-				tk.QueueMain(func() {
+				tk.Async(func() {
 					player.updateTimeDisplay()
 					player.updateProgressBar()
 				})
@@ -298,14 +400,16 @@ func (player *VideoPlayer) updateTimeDisplay() {
 
 func (player *VideoPlayer) updateProgressBar() {
 	if player.duration > 0 {
-		progress := (player.currentTime / player.duration) * 100
+		progress := (player.currentTime / player.duration) * seekSteps
+		player.updatingProgress = true
 		player.progressBar.SetValue(int(progress))
+		player.updatingProgress = false
 	}
 }
 
 func (player *VideoPlayer) updateStats() {
-	stats := fmt.Sprintf("Resolution: %dx%d\nFPS: %.2f\nDuration: %s",
-		player.width, player.height, player.fps, formatTime(player.duration))
+	stats := fmt.Sprintf("Resolution: %dx%d\nFPS: %s\nDuration: %s",
+		player.width, player.height, player.fpsSummary(), formatTime(player.duration))
 	player.statsLabel.SetText(stats)
 }
 
@@ -314,17 +418,17 @@ func (app *VideoCompareApp) updateStats() {
 	rightStats := "No video loaded"
 
 	if app.leftPlayer.path != "" {
-		leftStats = fmt.Sprintf("File: %s\nResolution: %dx%d\nFPS: %.2f",
+		leftStats = fmt.Sprintf("File: %s\nResolution: %dx%d\nFPS: %s",
 			filepath.Base(app.leftPlayer.path),
 			app.leftPlayer.width, app.leftPlayer.height,
-			app.leftPlayer.fps)
+			app.leftPlayer.fpsSummary())
 	}
 
 	if app.rightPlayer.path != "" {
-		rightStats = fmt.Sprintf("File: %s\nResolution: %dx%d\nFPS: %.2f",
+		rightStats = fmt.Sprintf("File: %s\nResolution: %dx%d\nFPS: %s",
 			filepath.Base(app.rightPlayer.path),
 			app.rightPlayer.width, app.rightPlayer.height,
-			app.rightPlayer.fps)
+			app.rightPlayer.fpsSummary())
 	}
 
 	combinedStats := fmt.Sprintf("Video Statistics\n\nLeft:\n%s\n\nRight:\n%s", leftStats, rightStats)
@@ -356,11 +460,9 @@ func (player *VideoPlayer) stop() {
 	}
 }
 
+// seekToTime seeks to a time typed as HH:MM:SS or MM:SS, whose seconds may
+// have a fraction.
 func (player *VideoPlayer) seekToTime(timeStr string) {
-	if player.mediaPlayer == nil || player.duration == 0 {
-		return
-	}
-
 	// Parse time string (HH:MM:SS or MM:SS)
 	parts := strings.Split(timeStr, ":")
 	var seconds float64
@@ -369,23 +471,58 @@ func (player *VideoPlayer) seekToTime(timeStr string) {
 		// HH:MM:SS
 		h, _ := strconv.Atoi(parts[0])
 		m, _ := strconv.Atoi(parts[1])
-		s, _ := strconv.Atoi(parts[2])
-		seconds = float64(h*3600 + m*60 + s)
+		s, _ := strconv.ParseFloat(parts[2], 64)
+		seconds = float64(h*3600+m*60) + s
 	} else if len(parts) == 2 {
 		// MM:SS
 		m, _ := strconv.Atoi(parts[0])
-		s, _ := strconv.Atoi(parts[1])
-		seconds = float64(m*60 + s)
+		s, _ := strconv.ParseFloat(parts[1], 64)
+		seconds = float64(m*60) + s
 	}
+	player.seekTo(seconds)
+}
 
+// seekTo moves the player to seconds, to the millisecond the media player
+// positions by. Frame steps and drags seek through it rather than a
+// formatted time, which only holds whole seconds.
+func (player *VideoPlayer) seekTo(seconds float64) {
+	if player.mediaPlayer == nil || !player.ready {
+		return
+	}
 	if seconds >= 0 && seconds <= player.duration {
-		player.mediaPlayer.SetPosition(int64(seconds * 1000))
+		player.mediaPlayer.SetPosition(int64(math.Round(seconds * 1000)))
 		player.currentTime = seconds
 		player.updateTimeDisplay()
 		player.updateProgressBar()
 	}
 }
 
+// setEnabled makes btn clickable or greys it out.
+func setEnabled(btn *tk.Button, enabled bool) {
+	if enabled {
+		btn.SetState(tk.StateNormal)
+	} else {
+		btn.SetState(tk.StateDisable)
+	}
+}
+
+// updateSeekControls enables seeking once the player's duration is known.
+func (player *VideoPlayer) updateSeekControls() {
+	if player.seekBtn != nil {
+		setEnabled(player.seekBtn, player.ready)
+	}
+}
+
+// updateFrameControls enables frame stepping once either player is ready.
+func (app *VideoCompareApp) updateFrameControls() {
+	if app.prevFrameBtn == nil {
+		return
+	}
+	ready := app.leftPlayer.ready || app.rightPlayer.ready
+	setEnabled(app.prevFrameBtn, ready)
+	setEnabled(app.nextFrameBtn, ready)
+}
+
 // Common controls
 func (app *VideoCompareApp) playAll() {
 	app.leftPlayer.play()
@@ -405,66 +542,46 @@ func (app *VideoCompareApp) stopAll() {
 func (app *VideoCompareApp) syncVideos() {
 	// Sync both videos to the same timestamp
 	if app.leftPlayer.currentTime > 0 {
-		app.rightPlayer.seekToTime(formatTime(app.leftPlayer.currentTime))
+		app.rightPlayer.seekTo(app.leftPlayer.currentTime)
 	} else if app.rightPlayer.currentTime > 0 {
-		app.leftPlayer.seekToTime(formatTime(app.rightPlayer.currentTime))
+		app.leftPlayer.seekTo(app.rightPlayer.currentTime)
 	}
 }
 
 // Frame-by-frame controls
 func (app *VideoCompareApp) nextFrame() {
-	// Calculate frame duration based on FPS
-	if app.leftPlayer.fps > 0 {
-		frameDuration := 1.0 / app.leftPlayer.fps
-		newTime := app.leftPlayer.currentTime + frameDuration
-		app.leftPlayer.seekToTime(formatTime(newTime))
-	}
-
-	if app.rightPlayer.fps > 0 {
-		frameDuration := 1.0 / app.rightPlayer.fps
-		newTime := app.rightPlayer.currentTime + frameDuration
-		app.rightPlayer.seekToTime(formatTime(newTime))
+	for _, player := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		if player.ready {
+			player.seekTo(player.currentTime + 1.0/player.stepFPS())
+		}
 	}
 }
 
 func (app *VideoCompareApp) previousFrame() {
-	// Calculate frame duration based on FPS
-	if app.leftPlayer.fps > 0 {
-		frameDuration := 1.0 / app.leftPlayer.fps
-		newTime := app.leftPlayer.currentTime - frameDuration
-		if newTime >= 0 {
-			app.leftPlayer.seekToTime(formatTime(newTime))
-		}
-	}
-
-	if app.rightPlayer.fps > 0 {
-		frameDuration := 1.0 / app.rightPlayer.fps
-		newTime := app.rightPlayer.currentTime - frameDuration
-		if newTime >= 0 {
-			app.rightPlayer.seekToTime(formatTime(newTime))
+	for _, player := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		if player.ready {
+			player.seekTo(math.Max(0, player.currentTime-1.0/player.stepFPS()))
 		}
 	}
 }
 
 func (app *VideoCompareApp) setupEventHandlers() {
-	// Set up progress bar callbacks
-	app.leftPlayer.progressBar.OnValueChanged(func(value int) {
-		if app.leftPlayer.duration > 0 {
-			newTime := (float64(value) / 100.0) * app.leftPlayer.duration
-			app.leftPlayer.seekToTime(formatTime(newTime))
-		}
-	})
-
-	app.rightPlayer.progressBar.OnValueChanged(func(value int) {
-		if app.rightPlayer.duration > 0 {
-			newTime := (float64(value) / 100.0) * app.rightPlayer.duration
-			app.rightPlayer.seekToTime(formatTime(newTime))
-		}
-	})
+	// Set up progress bar callbacks; only user drags seek
+	for _, player := range []*VideoPlayer{app.leftPlayer, app.rightPlayer} {
+		player.progressBar.OnValueChanged(func(value int) {
+			if player.ready && !player.updatingProgress {
+				player.seekTo(float64(value) / seekSteps * player.duration)
+			}
+		})
+	}
 }
 
 // Utility functions
 func formatTime(seconds float64) string {
+	// Hours aren't wrapped at a day; negative and unknown times show as 0
+	if !(seconds > 0) || math.IsInf(seconds, 1) {
+		seconds = 0
+	}
 	hours := int(seconds) / 3600
 	minutes := (int(seconds) % 3600) / 60
 	secs := int(seconds) % 60
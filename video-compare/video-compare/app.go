@@ -3,13 +3,20 @@ package main
 import (
 	"context"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 )
 
 // App struct
 type App struct {
 	ctx context.Context
+
+	// Stops the running watch-folder session, if any
+	watchMu   sync.Mutex
+	stopWatch func()
 }
 
 // NewApp creates a new App application struct
@@ -21,6 +28,13 @@ func NewApp() *App {
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	applyJobLimit()
+	// Check the toolchain up front so GetCapabilities answers at once
+	go func() {
+		for feature, reason := range currentCapabilities().Unavailable {
+			log.Printf("%s unavailable: %s", feature, reason)
+		}
+	}()
 }
 
 // GetVideoInfo returns information about a video file
@@ -45,21 +59,32 @@ func (a *App) GetVideoInfo(filePath string) map[string]interface{} {
 	info["size"] = fileInfo.Size()
 	info["modified"] = fileInfo.ModTime()
 
+	// Pixel format details need ffprobe; the basic info is still useful
+	// without it
+	if md, err := probeVideo(filePath); err == nil {
+		info["pixel_format"] = md.PixelFormat
+		info["bit_depth"] = md.BitDepth
+		info["chroma_subsampling"] = md.Chroma
+		info["color_range"] = md.ColorRange
+	} else {
+		info["probe_error"] = err.Error()
+	}
+
 	return info
 }
 
-// ValidateVideoFile checks if a file is a valid video file
+// ValidateVideoFile checks if a file is a valid video file, or a still
+// image used as a single reference frame, or an audio file in audio-only
+// mode
 func (a *App) ValidateVideoFile(filePath string) bool {
 	// Check if file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		return false
 	}
 
-	// Check file extension
-	ext := filepath.Ext(filePath)
-	validExtensions := []string{".mp4", ".avi", ".mkv", ".mov", ".wmv", ".flv", ".webm", ".m4v"}
-
-	for _, validExt := range validExtensions {
+	// Check file extension against the configured formats
+	ext := strings.ToLower(filepath.Ext(filePath))
+	for _, validExt := range a.acceptedFormats() {
 		if ext == validExt {
 			return true
 		}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"videocompare"
+)
+
+// Exit codes of the headless mode and of batches run with SetExitCode.
+const (
+	exitPassed      = 0
+	exitThreshold   = 1
+	exitUsageOrFail = 2
+)
+
+const (
+	webhookAttempts = 3
+	webhookTimeout  = 10 * time.Second
+	webhookBackoff  = 2 * time.Second // multiplied by the attempt number
+)
+
+// pairOutcome is what every comparison of a pair reports, whether one
+// batch operation or a full PairSummary: the pair, its metadata
+// differences and, when judged by thresholds, the verdict.
+type pairOutcome struct {
+	Left        string               `json:"left"`
+	Right       string               `json:"right"`
+	Differences []MetadataDifference `json:"differences,omitempty"`
+	Verdict     *Verdict             `json:"verdict,omitempty"`
+	Passed      bool                 `json:"passed"`
+}
+
+// comparisonResult is the outcome of comparing one pair of files.
+type comparisonResult struct {
+	Operation string `json:"operation"`
+	pairOutcome
+	Score     *float64 `json:"score,omitempty"`
+	Threshold *float64 `json:"threshold,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// compareFiles runs one operation on a pair of files. A negative threshold
+// disables the pass/fail check.
+func compareFiles(op, left, right string, threshold float64) (comparisonResult, error) {
+	result := comparisonResult{Operation: op, pairOutcome: pairOutcome{Left: left, Right: right, Passed: true}}
+	if threshold >= 0 {
+		result.Threshold = &threshold
+	}
+
+	if op == "metadata-diff" {
+		lm, err := probeVideo(left)
+		if err != nil {
+			return result, err
+		}
+		rm, err := probeVideo(right)
+		if err != nil {
+			return result, err
+		}
+		result.Differences = diffMetadata(lm, rm)
+		if threshold >= 0 && float64(len(result.Differences)) > threshold {
+			result.Passed = false
+		}
+		return result, nil
+	}
+
+	score, err := computeMetric(op, left, right)
+	if err != nil {
+		return result, err
+	}
+	result.Score = &score
+	if threshold >= 0 && score < threshold {
+		result.Passed = false
+	}
+	return result, nil
+}
+
+// judgeFiles evaluates a pair of files against thresholds instead of a
+// single operation.
+func judgeFiles(left, right string, thresholds Thresholds) (comparisonResult, error) {
+	result := comparisonResult{Operation: "verdict", pairOutcome: pairOutcome{Left: left, Right: right}}
+	verdict, err := evaluateFiles(left, right, thresholds)
+	if err != nil {
+		return result, err
+	}
+	result.Verdict = &verdict
+	result.Passed = verdict.Passed
+	return result, nil
+}
+
+// BatchPair is a reference and a distorted file to compare.
+type BatchPair struct {
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+// BatchOptions configures BatchCompare.
+type BatchOptions struct {
+	Operation string  `json:"operation"`
+	Threshold float64 `json:"threshold"` // negative disables the check
+	// Thresholds, when set, judge each pair by a PASS/FAIL verdict instead
+	// of Operation and Threshold
+	Thresholds *Thresholds `json:"thresholds,omitempty"`
+	// WebhookURL receives the summary as a JSON POST when set
+	WebhookURL string `json:"webhook_url,omitempty"`
+	// SetExitCode exits the process once the batch is done, with status 1
+	// if any pair failed, for unattended runs
+	SetExitCode bool `json:"set_exit_code,omitempty"`
+}
+
+// BatchSummary is the result of a batch, also used as the webhook payload.
+type BatchSummary struct {
+	Operation string             `json:"operation"`
+	Started   time.Time          `json:"started"`
+	Finished  time.Time          `json:"finished"`
+	Results   []comparisonResult `json:"results"`
+	Total     int                `json:"total"`
+	Passed    int                `json:"passed"`
+	Failed    int                `json:"failed"` // including pairs that errored
+	Errors    int                `json:"errors"`
+	// Files of a directory batch that had no partner and were skipped
+	UnmatchedLeft  []string `json:"unmatched_left,omitempty"`
+	UnmatchedRight []string `json:"unmatched_right,omitempty"`
+}
+
+// runBatch compares every pair in turn. A pair that can't be compared is
+// recorded as failed and the batch carries on.
+func runBatch(pairs []BatchPair, options BatchOptions) BatchSummary {
+	summary := BatchSummary{Operation: options.Operation, Started: time.Now(), Total: len(pairs)}
+	if options.Thresholds != nil {
+		summary.Operation = "verdict"
+	}
+	for _, pair := range pairs {
+		var result comparisonResult
+		var err error
+		if options.Thresholds != nil {
+			result, err = judgeFiles(pair.Left, pair.Right, *options.Thresholds)
+		} else {
+			result, err = compareFiles(options.Operation, pair.Left, pair.Right, options.Threshold)
+		}
+		if err != nil {
+			result.Passed = false
+			result.Error = err.Error()
+			summary.Errors++
+		}
+		if result.Passed {
+			summary.Passed++
+		} else {
+			summary.Failed++
+		}
+		summary.Results = append(summary.Results, result)
+	}
+	summary.Finished = time.Now()
+	return summary
+}
+
+// exitCode is the process status reporting summary.
+func (s BatchSummary) exitCode() int {
+	if s.Failed > 0 {
+		return exitThreshold
+	}
+	return exitPassed
+}
+
+// postWebhook POSTs summary as JSON to url, retrying a bounded number of
+// times on network errors and non-2xx responses.
+func postWebhook(url string, summary BatchSummary) error {
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: webhookTimeout}
+	for attempt := 1; ; attempt++ {
+		err = sendWebhook(client, url, body)
+		if err == nil {
+			return nil
+		}
+		log.Printf("webhook attempt %d/%d failed: %v", attempt, webhookAttempts, err)
+		if attempt == webhookAttempts {
+			return fmt.Errorf("webhook failed after %d attempts: %w", webhookAttempts, err)
+		}
+		time.Sleep(time.Duration(attempt) * webhookBackoff)
+	}
+}
+
+func sendWebhook(client *http.Client, url string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// readBatchPairs reads reference,distorted pairs, one per CSV line.
+// Blank lines and lines starting with # are skipped.
+func readBatchPairs(r io.Reader) ([]BatchPair, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("reading batch pairs: %w", err)
+	}
+	pairs := make([]BatchPair, 0, len(records))
+	for _, rec := range records {
+		pairs = append(pairs, BatchPair{Left: strings.TrimSpace(rec[0]), Right: strings.TrimSpace(rec[1])})
+	}
+	return pairs, nil
+}
+
+// BatchCompare compares every pair with the same operation and threshold,
+// then notifies the webhook and sets the exit code as configured. A webhook
+// that keeps failing is logged but doesn't fail the batch.
+func (a *App) BatchCompare(pairs []BatchPair, options BatchOptions) (BatchSummary, error) {
+	if err := options.validate(); err != nil {
+		return BatchSummary{}, err
+	}
+	summary := runBatch(pairs, options)
+	finishBatch(summary, options)
+	return summary, nil
+}
+
+// BatchCompareDirectories pairs the video files of two directories using
+// match, then compares the pairs like BatchCompare. Files left without a
+// partner on either side are listed in the summary.
+func (a *App) BatchCompareDirectories(leftDir, rightDir string, match MatchOptions, options BatchOptions) (BatchSummary, error) {
+	if err := options.validate(); err != nil {
+		return BatchSummary{}, err
+	}
+	matched, err := matchDirectories(leftDir, rightDir, a.GetSupportedFormats(), match)
+	if err != nil {
+		return BatchSummary{}, err
+	}
+	summary := runBatch(matched.Pairs, options)
+	summary.UnmatchedLeft, summary.UnmatchedRight = matched.UnmatchedLeft, matched.UnmatchedRight
+	finishBatch(summary, options)
+	return summary, nil
+}
+
+func (o BatchOptions) validate() error {
+	if o.Thresholds != nil {
+		return nil
+	}
+	if !videocompare.IsMetric(o.Operation) && o.Operation != "metadata-diff" {
+		return fmt.Errorf("unknown operation %q", o.Operation)
+	}
+	return checkFeature(o.Operation)
+}
+
+// finishBatch notifies the webhook and exits as options ask.
+func finishBatch(summary BatchSummary, options BatchOptions) {
+	if options.WebhookURL != "" {
+		if err := postWebhook(options.WebhookURL, summary); err != nil {
+			log.Print(err)
+		}
+	}
+	if options.SetExitCode {
+		os.Exit(summary.exitCode())
+	}
+}
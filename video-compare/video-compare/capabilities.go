@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"videocompare"
+)
+
+// capabilityTimeout bounds the startup check for ffmpeg and ffprobe.
+const capabilityTimeout = 10 * time.Second
+
+const (
+	featureFramePSNR     = "frame-psnr"
+	featureSavings       = "savings"
+	featureReportFigures = "report-figures"
+)
+
+// featureTools lists the external tools each feature needs. The metrics
+// and metadata-diff are also the operations batch, watch and verdict runs
+// are made of.
+var featureTools = map[string][]string{
+	metricPSNR:           {videocompare.ToolFFmpeg},
+	metricSSIM:           {videocompare.ToolFFmpeg},
+	metricVMAF:           {videocompare.ToolFFmpeg, videocompare.ToolVMAF},
+	"metadata-diff":      {videocompare.ToolFFprobe},
+	featureFramePSNR:     {videocompare.ToolFFmpeg},
+	featureSavings:       {videocompare.ToolFFprobe},
+	featureReportFigures: {videocompare.ToolFFmpeg},
+}
+
+// toolchain detects the installed tools once, on first use.
+var toolchain = sync.OnceValue(func() videocompare.Capabilities {
+	ctx, cancel := context.WithTimeout(context.Background(), capabilityTimeout)
+	defer cancel()
+	return videocompare.DetectCapabilities(ctx)
+})
+
+// Capabilities reports which external tools were found and, from that,
+// which features work. Unavailable features are listed with the reason.
+type Capabilities struct {
+	Tools       videocompare.Capabilities `json:"tools"`
+	Available   map[string]bool           `json:"available"`
+	Unavailable map[string]string         `json:"unavailable,omitempty"`
+}
+
+func currentCapabilities() Capabilities {
+	c := Capabilities{Tools: toolchain(), Available: make(map[string]bool)}
+	for feature, tools := range featureTools {
+		reason := c.Tools.Explain(tools...)
+		c.Available[feature] = reason == ""
+		if reason != "" {
+			if c.Unavailable == nil {
+				c.Unavailable = make(map[string]string)
+			}
+			c.Unavailable[feature] = reason
+		}
+	}
+	return c
+}
+
+// GetCapabilities tells the frontend which features the installed ffmpeg
+// and ffprobe support, so it can disable the rest.
+func (a *App) GetCapabilities() Capabilities {
+	return currentCapabilities()
+}
+
+// checkFeature returns an error saying why feature can't run with the
+// installed tools, or nil when it can.
+func checkFeature(feature string) error {
+	if reason := toolchain().Explain(featureTools[feature]...); reason != "" {
+		return fmt.Errorf("%s %s", feature, reason)
+	}
+	return nil
+}
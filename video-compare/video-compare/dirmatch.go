@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Strategies for pairing the files of two directories.
+const (
+	matchExact       = "exact"
+	matchStripSuffix = "strip-suffix"
+	matchMapping     = "mapping"
+)
+
+// defaultStripPatterns are the encode suffixes removed by the strip-suffix
+// strategy when no patterns are given, so clip_x265_crf23.mp4 pairs with
+// clip.mov.
+var defaultStripPatterns = []string{
+	`[_.-](x26[45]|h\.?26[45]|hevc|avc|av1|vp[89])`,
+	`[_.-](crf|qp|cq)\d+`,
+	`[_.-]\d+(p|k|kbps)`,
+	`[_.-](enc|encoded|out|output|test|ref|reference|src|source|master)`,
+}
+
+// MatchOptions configures how BatchCompareDirectories pairs files.
+type MatchOptions struct {
+	// Strategy is exact (same name without extension, the default),
+	// strip-suffix or mapping
+	Strategy string `json:"strategy"`
+	// StripPatterns are regular expressions removed from the end of names
+	// for strip-suffix, repeatedly, before comparing them; defaults to
+	// common codec, quality and resolution suffixes
+	StripPatterns []string `json:"strip_patterns,omitempty"`
+	// MappingFile lists reference,distorted file names, relative to the
+	// two directories, for the mapping strategy
+	MappingFile string `json:"mapping_file,omitempty"`
+}
+
+// DirectoryPairs is the result of matching two directories.
+type DirectoryPairs struct {
+	Pairs          []BatchPair `json:"pairs"`
+	UnmatchedLeft  []string    `json:"unmatched_left,omitempty"`
+	UnmatchedRight []string    `json:"unmatched_right,omitempty"`
+}
+
+// listVideoFiles returns the names of the files in dir with one of the
+// given extensions, sorted.
+func listVideoFiles(dir string, formats []string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && slices.Contains(formats, strings.ToLower(filepath.Ext(e.Name()))) {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// suffixStripper returns a function reducing a file name to the key the
+// strip-suffix strategy compares.
+func suffixStripper(patterns []string) (func(string) string, error) {
+	if len(patterns) == 0 {
+		patterns = defaultStripPatterns
+	}
+	var res []*regexp.Regexp
+	for _, p := range patterns {
+		re, err := regexp.Compile("(?i)(" + p + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid strip pattern %q: %w", p, err)
+		}
+		res = append(res, re)
+	}
+	return func(name string) string {
+		key := strings.TrimSuffix(name, filepath.Ext(name))
+		for stripped := true; stripped; {
+			stripped = false
+			for _, re := range res {
+				if loc := re.FindStringIndex(key); loc != nil && loc[0] > 0 {
+					key, stripped = key[:loc[0]], true
+				}
+			}
+		}
+		return strings.ToLower(key)
+	}, nil
+}
+
+// matchDirectories pairs the video files of leftDir with those of
+// rightDir and lists the files left without a partner on either side.
+func matchDirectories(leftDir, rightDir string, formats []string, match MatchOptions) (DirectoryPairs, error) {
+	var result DirectoryPairs
+	left, err := listVideoFiles(leftDir, formats)
+	if err != nil {
+		return result, err
+	}
+	right, err := listVideoFiles(rightDir, formats)
+	if err != nil {
+		return result, err
+	}
+
+	if match.Strategy == matchMapping {
+		return matchByMapping(leftDir, rightDir, left, right, match.MappingFile)
+	}
+
+	key := func(name string) string { return strings.TrimSuffix(name, filepath.Ext(name)) }
+	switch match.Strategy {
+	case "", matchExact:
+	case matchStripSuffix:
+		if key, err = suffixStripper(match.StripPatterns); err != nil {
+			return result, err
+		}
+	default:
+		return result, fmt.Errorf("unknown matching strategy %q", match.Strategy)
+	}
+
+	// Files sharing a key are paired in name order; extras stay unmatched
+	byKey := make(map[string][]string)
+	for _, name := range right {
+		byKey[key(name)] = append(byKey[key(name)], name)
+	}
+	for _, name := range left {
+		k := key(name)
+		if candidates := byKey[k]; len(candidates) > 0 {
+			result.Pairs = append(result.Pairs, BatchPair{
+				Left:  filepath.Join(leftDir, name),
+				Right: filepath.Join(rightDir, candidates[0]),
+			})
+			byKey[k] = candidates[1:]
+			continue
+		}
+		result.UnmatchedLeft = append(result.UnmatchedLeft, filepath.Join(leftDir, name))
+	}
+	for _, name := range right {
+		if slices.Contains(byKey[key(name)], name) {
+			result.UnmatchedRight = append(result.UnmatchedRight, filepath.Join(rightDir, name))
+		}
+	}
+	return result, nil
+}
+
+// matchByMapping pairs files as listed in a reference,distorted CSV file.
+// Entries naming a file that isn't there are skipped; files no entry pairs
+// are reported as unmatched.
+func matchByMapping(leftDir, rightDir string, left, right []string, mappingFile string) (DirectoryPairs, error) {
+	var result DirectoryPairs
+	if mappingFile == "" {
+		return result, fmt.Errorf("the mapping strategy needs a mapping file")
+	}
+	f, err := os.Open(mappingFile)
+	if err != nil {
+		return result, err
+	}
+	entries, err := readBatchPairs(f)
+	f.Close()
+	if err != nil {
+		return result, err
+	}
+
+	usedLeft, usedRight := make(map[string]bool), make(map[string]bool)
+	for _, e := range entries {
+		hasLeft, hasRight := slices.Contains(left, e.Left), slices.Contains(right, e.Right)
+		if hasLeft && hasRight {
+			result.Pairs = append(result.Pairs, BatchPair{
+				Left:  filepath.Join(leftDir, e.Left),
+				Right: filepath.Join(rightDir, e.Right),
+			})
+			usedLeft[e.Left], usedRight[e.Right] = true, true
+		}
+	}
+	for _, name := range left {
+		if !usedLeft[name] {
+			result.UnmatchedLeft = append(result.UnmatchedLeft, filepath.Join(leftDir, name))
+		}
+	}
+	for _, name := range right {
+		if !usedRight[name] {
+			result.UnmatchedRight = append(result.UnmatchedRight, filepath.Join(rightDir, name))
+		}
+	}
+	return result, nil
+}
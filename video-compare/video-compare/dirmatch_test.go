@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSuffixStripper(t *testing.T) {
+	key, err := suffixStripper(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct{ name, want string }{
+		{"clip.mov", "clip"},
+		// Suffixes are stripped repeatedly, in any order
+		{"clip_x265_crf23_1080p.mp4", "clip"},
+		{"clip_1080p-x265.mkv", "clip"},
+		{"Clip_HEVC_CRF23.MP4", "clip"},
+		// A name that is all suffix is kept rather than stripped to nothing
+		{"_x264.mp4", "_x264"},
+		{"clip_x264_source.mp4", "clip"},
+		{"clipx264.mp4", "clipx264"},
+	}
+	for _, tt := range tests {
+		if got := key(tt.name); got != tt.want {
+			t.Errorf("key(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	custom, err := suffixStripper([]string{`-v\d+`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := custom("shot-v2-V3_x264.mp4"); got != "shot-v2-v3_x264" {
+		t.Errorf("custom key = %q, want the default patterns left alone", got)
+	}
+	if got := custom("shot-v2-V3.mp4"); got != "shot" {
+		t.Errorf("custom key = %q, want %q", got, "shot")
+	}
+
+	if _, err := suffixStripper([]string{"("}); err == nil {
+		t.Error("suffixStripper accepted an invalid pattern")
+	}
+}
+
+func writeFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMatchDirectories(t *testing.T) {
+	left, right := t.TempDir(), t.TempDir()
+	writeFiles(t, left, "a.mov", "b.mov", "c.mov", "notes.txt")
+	writeFiles(t, right, "a_x264.mp4", "a_x265.mp4", "b_crf23.mp4", "d_x264.mp4")
+
+	got, err := matchDirectories(left, right, defaultFormats, MatchOptions{Strategy: matchStripSuffix})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Both encodes of a share its key; the first in name order is paired
+	want := DirectoryPairs{
+		Pairs: []BatchPair{
+			{filepath.Join(left, "a.mov"), filepath.Join(right, "a_x264.mp4")},
+			{filepath.Join(left, "b.mov"), filepath.Join(right, "b_crf23.mp4")},
+		},
+		UnmatchedLeft:  []string{filepath.Join(left, "c.mov")},
+		UnmatchedRight: []string{filepath.Join(right, "a_x265.mp4"), filepath.Join(right, "d_x264.mp4")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("strip-suffix = %#v, want %#v", got, want)
+	}
+
+	got, err = matchDirectories(left, right, defaultFormats, MatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Pairs) != 0 || len(got.UnmatchedLeft) != 3 || len(got.UnmatchedRight) != 4 {
+		t.Errorf("exact = %#v, want nothing paired", got)
+	}
+
+	if _, err := matchDirectories(left, right, defaultFormats, MatchOptions{Strategy: "fuzzy"}); err == nil {
+		t.Error("matchDirectories accepted an unknown strategy")
+	}
+}
+
+func TestMatchByMapping(t *testing.T) {
+	left, right := t.TempDir(), t.TempDir()
+	writeFiles(t, left, "ref1.mov", "ref2.mov", "ref3.mov")
+	writeFiles(t, right, "enc_a.mp4", "enc_b.mp4")
+	mapping := filepath.Join(t.TempDir(), "pairs.csv")
+	csv := "# reference,distorted\nref1.mov, enc_b.mp4\nref2.mov,missing.mp4\nref3.mov,enc_a.mp4\n"
+	if err := os.WriteFile(mapping, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := matchDirectories(left, right, defaultFormats, MatchOptions{Strategy: matchMapping, MappingFile: mapping})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The entry naming a missing file is skipped and ref2 stays unmatched
+	want := DirectoryPairs{
+		Pairs: []BatchPair{
+			{filepath.Join(left, "ref1.mov"), filepath.Join(right, "enc_b.mp4")},
+			{filepath.Join(left, "ref3.mov"), filepath.Join(right, "enc_a.mp4")},
+		},
+		UnmatchedLeft: []string{filepath.Join(left, "ref2.mov")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mapping = %#v, want %#v", got, want)
+	}
+
+	if _, err := matchDirectories(left, right, defaultFormats, MatchOptions{Strategy: matchMapping}); err == nil {
+		t.Error("the mapping strategy accepted no mapping file")
+	}
+}
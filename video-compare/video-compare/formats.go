@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// defaultFormats are the file extensions accepted out of the box: the
+// common video containers and the still image formats usable as a
+// reference frame.
+var defaultFormats = []string{
+	".mp4", ".mkv", ".avi", ".mov", ".webm", ".flv", ".wmv", ".m4v", ".3gp", ".ogv", ".ts", ".mts", ".m2ts",
+	".png", ".jpg", ".jpeg",
+}
+
+// audioFormats are accepted on top of the configured extensions while the
+// audio-only comparison mode is on.
+var audioFormats = []string{
+	".wav", ".flac", ".mp3", ".aac", ".m4a", ".ogg", ".opus", ".ac3", ".eac3", ".wma", ".aif", ".aiff", ".mka",
+}
+
+var extensionPattern = regexp.MustCompile(`^\.[a-z0-9][a-z0-9_+-]*$`)
+
+// settings are the user preferences kept in the config directory.
+type settings struct {
+	// Extensions replaces defaultFormats when set
+	Extensions []string `json:"extensions,omitempty"`
+	// ThresholdProfiles are named pass/fail thresholds saved by the user
+	ThresholdProfiles map[string]Thresholds `json:"threshold_profiles,omitempty"`
+	// AudioOnly compares audio files, accepting audioFormats too
+	AudioOnly bool `json:"audio_only,omitempty"`
+	// JobLimit caps concurrent ffmpeg and ffprobe runs; 0 is one per CPU
+	JobLimit int `json:"job_limit,omitempty"`
+}
+
+func settingsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "video-compare", "settings.json"), nil
+}
+
+// loadSettings reads the saved settings; a missing file gives the defaults.
+func loadSettings() (settings, error) {
+	var s settings
+	path, err := settingsPath()
+	if err != nil {
+		return s, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func saveSettings(s settings) error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// validateExtensions checks every entry is a lowercase, dot-prefixed
+// extension and returns the list without duplicates.
+func validateExtensions(exts []string) ([]string, error) {
+	var out []string
+	for _, ext := range exts {
+		ext = strings.TrimSpace(ext)
+		if !extensionPattern.MatchString(ext) {
+			return nil, fmt.Errorf("invalid extension %q: use a lowercase extension starting with a dot, like .mp4", ext)
+		}
+		if !slices.Contains(out, ext) {
+			out = append(out, ext)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("at least one extension is required")
+	}
+	return out, nil
+}
+
+// GetSupportedFormats returns the accepted file extensions: the customized
+// list when there is one, otherwise the defaults.
+func (a *App) GetSupportedFormats() []string {
+	s, err := loadSettings()
+	if err != nil || len(s.Extensions) == 0 {
+		return slices.Clone(defaultFormats)
+	}
+	return s.Extensions
+}
+
+// acceptedFormats is what ValidateVideoFile lets through: the supported
+// formats plus, in audio-only mode, the audio extensions.
+func (a *App) acceptedFormats() []string {
+	exts := a.GetSupportedFormats()
+	if a.GetAudioOnlyMode() {
+		for _, ext := range audioFormats {
+			if !slices.Contains(exts, ext) {
+				exts = append(exts, ext)
+			}
+		}
+	}
+	return exts
+}
+
+// GetAudioOnlyMode reports whether audio-only comparison mode is on.
+func (a *App) GetAudioOnlyMode() bool {
+	s, err := loadSettings()
+	return err == nil && s.AudioOnly
+}
+
+// SetAudioOnlyMode turns audio-only comparison mode on or off, for A/B
+// tests of audio files such as two codecs' encodes.
+func (a *App) SetAudioOnlyMode(enabled bool) error {
+	s, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	s.AudioOnly = enabled
+	return saveSettings(s)
+}
+
+// SetSupportedFormats validates and saves a customized extension list, for
+// containers libvlc or ffmpeg can open but aren't accepted by default.
+func (a *App) SetSupportedFormats(exts []string) ([]string, error) {
+	exts, err := validateExtensions(exts)
+	if err != nil {
+		return nil, err
+	}
+	s, err := loadSettings()
+	if err != nil {
+		return nil, err
+	}
+	s.Extensions = exts
+	if err := saveSettings(s); err != nil {
+		return nil, err
+	}
+	return exts, nil
+}
+
+// ResetSupportedFormats drops the customized list and returns the defaults.
+func (a *App) ResetSupportedFormats() ([]string, error) {
+	s, err := loadSettings()
+	if err != nil {
+		return nil, err
+	}
+	s.Extensions = nil
+	if err := saveSettings(s); err != nil {
+		return nil, err
+	}
+	return slices.Clone(defaultFormats), nil
+}
@@ -2,10 +2,90 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function BatchCompare(arg1, arg2) {
+  return window['go']['main']['App']['BatchCompare'](arg1, arg2);
+}
+
+export function BatchCompareDirectories(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['BatchCompareDirectories'](arg1, arg2, arg3, arg4);
+}
+
+export function CompareToJSON(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CompareToJSON'](arg1, arg2, arg3);
+}
+
+export function DeleteThresholdProfile(arg1) {
+  return window['go']['main']['App']['DeleteThresholdProfile'](arg1);
+}
+
+export function EvaluateComparison(arg1, arg2, arg3) {
+  return window['go']['main']['App']['EvaluateComparison'](arg1, arg2, arg3);
+}
+
+export function GenerateReport(arg1, arg2) {
+  return window['go']['main']['App']['GenerateReport'](arg1, arg2);
+}
+
+export function GetActiveJobs() {
+  return window['go']['main']['App']['GetActiveJobs']();
+}
+
+export function GetAudioOnlyMode() {
+  return window['go']['main']['App']['GetAudioOnlyMode']();
+}
+
+export function GetCapabilities() {
+  return window['go']['main']['App']['GetCapabilities']();
+}
+
+export function GetJobLimit() {
+  return window['go']['main']['App']['GetJobLimit']();
+}
+
+export function GetSavingsReport(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetSavingsReport'](arg1, arg2, arg3);
+}
+
+export function GetSupportedFormats() {
+  return window['go']['main']['App']['GetSupportedFormats']();
+}
+
+export function GetThresholdProfiles() {
+  return window['go']['main']['App']['GetThresholdProfiles']();
+}
+
 export function GetVideoInfo(arg1) {
   return window['go']['main']['App']['GetVideoInfo'](arg1);
 }
 
+export function ResetSupportedFormats() {
+  return window['go']['main']['App']['ResetSupportedFormats']();
+}
+
+export function SaveThresholdProfile(arg1, arg2) {
+  return window['go']['main']['App']['SaveThresholdProfile'](arg1, arg2);
+}
+
+export function SetAudioOnlyMode(arg1) {
+  return window['go']['main']['App']['SetAudioOnlyMode'](arg1);
+}
+
+export function SetJobLimit(arg1) {
+  return window['go']['main']['App']['SetJobLimit'](arg1);
+}
+
+export function SetSupportedFormats(arg1) {
+  return window['go']['main']['App']['SetSupportedFormats'](arg1);
+}
+
+export function StartWatch(arg1) {
+  return window['go']['main']['App']['StartWatch'](arg1);
+}
+
+export function StopWatch() {
+  return window['go']['main']['App']['StopWatch']();
+}
+
 export function ValidateVideoFile(arg1) {
   return window['go']['main']['App']['ValidateVideoFile'](arg1);
 }
@@ -2,7 +2,11 @@ module video-compare
 
 go 1.23
 
-require github.com/wailsapp/wails/v2 v2.10.2
+require (
+	github.com/fsnotify/fsnotify v1.9.0
+	github.com/wailsapp/wails/v2 v2.10.2
+	videocompare v0.0.0
+)
 
 require (
 	github.com/bep/debounce v1.2.1 // indirect
@@ -29,9 +33,12 @@ require (
 	github.com/wailsapp/go-webview2 v1.0.19 // indirect
 	github.com/wailsapp/mimetype v1.4.1 // indirect
 	golang.org/x/crypto v0.33.0 // indirect
+	golang.org/x/image v0.24.0 // indirect
 	golang.org/x/net v0.35.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
 )
 
 // replace github.com/wailsapp/wails/v2 v2.10.2 => /Users/martin.deluca/.asdf/installs/golang/1.23.0/packages/pkg/mod
+
+replace videocompare => ../videocompare
@@ -0,0 +1,125 @@
+//go:build headless
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// main runs a single comparison, or a batch of them, without any GUI and
+// prints the result as JSON, for use as a regression gate in CI.
+func main() {
+	op := flag.String("op", metricPSNR, "operation: psnr, ssim, vmaf or metadata-diff")
+	threshold := flag.Float64("threshold", -1,
+		"fail when the score is below this value, or for metadata-diff when more fields than this differ (negative disables)")
+	batch := flag.String("batch", "", "CSV file of reference,distorted pairs to compare instead of the two arguments")
+	webhook := flag.String("webhook", "", "URL to POST the batch summary to as JSON")
+	profile := flag.String("profile", "",
+		"judge by a named threshold profile (e.g. broadcast, web) instead of -op and -threshold")
+	dirs := flag.Bool("dirs", false, "compare the files of the two directory arguments as a batch")
+	match := flag.String("match", matchExact, "how -dirs pairs files: exact, strip-suffix or mapping")
+	strip := flag.String("strip", "", "comma-separated regular expressions removed from the end of names for -match strip-suffix")
+	mapping := flag.String("mapping", "", "CSV file of reference,distorted names for -match mapping")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <reference> <distorted>\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s [flags] -batch pairs.csv\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s [flags] -dirs <reference dir> <distorted dir>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	applyJobLimit()
+
+	var thresholds *Thresholds
+	if *profile != "" {
+		t, err := thresholdProfile(*profile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(exitUsageOrFail)
+		}
+		thresholds = &t
+	}
+
+	if *batch != "" {
+		if flag.NArg() != 0 {
+			flag.Usage()
+			os.Exit(exitUsageOrFail)
+		}
+		os.Exit(runHeadlessBatch(*batch, BatchOptions{
+			Operation: *op, Threshold: *threshold, Thresholds: thresholds, WebhookURL: *webhook,
+		}))
+	}
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(exitUsageOrFail)
+	}
+	if *dirs {
+		matchOptions := MatchOptions{Strategy: *match, MappingFile: *mapping}
+		if *strip != "" {
+			matchOptions.StripPatterns = strings.Split(*strip, ",")
+		}
+		summary, err := NewApp().BatchCompareDirectories(flag.Arg(0), flag.Arg(1), matchOptions, BatchOptions{
+			Operation: *op, Threshold: *threshold, Thresholds: thresholds, WebhookURL: *webhook,
+		})
+		os.Exit(reportHeadlessBatch(summary, err))
+	}
+
+	var result comparisonResult
+	var err error
+	if thresholds != nil {
+		result, err = judgeFiles(flag.Arg(0), flag.Arg(1), *thresholds)
+	} else {
+		result, err = compareFiles(*op, flag.Arg(0), flag.Arg(1), *threshold)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(exitUsageOrFail)
+	}
+	if err := printJSON(result); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(exitUsageOrFail)
+	}
+	if !result.Passed {
+		os.Exit(exitThreshold)
+	}
+}
+
+// runHeadlessBatch compares the pairs listed in path and returns the exit
+// status: 1 when any pair failed or couldn't be compared.
+func runHeadlessBatch(path string, options BatchOptions) int {
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return exitUsageOrFail
+	}
+	pairs, err := readBatchPairs(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return exitUsageOrFail
+	}
+
+	return reportHeadlessBatch(NewApp().BatchCompare(pairs, options))
+}
+
+// reportHeadlessBatch prints a batch summary and returns the exit status.
+func reportHeadlessBatch(summary BatchSummary, err error) int {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return exitUsageOrFail
+	}
+	if err := printJSON(summary); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return exitUsageOrFail
+	}
+	return summary.exitCode()
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,19 @@
+//go:build !headless
+
+package main
+
+import (
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"videocompare"
+)
+
+// eventJobsChanged carries a videocompare.JobStats whenever a background
+// job starts, finishes or queues, for the active jobs indicator.
+const eventJobsChanged = "jobs:changed"
+
+// watchJobs forwards job changes to the frontend.
+func (a *App) watchJobs() {
+	videocompare.OnJobsChanged(func(stats videocompare.JobStats) {
+		runtime.EventsEmit(a.ctx, eventJobsChanged, stats)
+	})
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"videocompare"
+)
+
+// applyJobLimit applies the saved job limit, at startup of both the app
+// and the headless binary.
+func applyJobLimit() {
+	s, err := loadSettings()
+	if err != nil {
+		log.Printf("reading the job limit: %v", err)
+		return
+	}
+	videocompare.SetJobLimit(s.JobLimit)
+}
+
+// GetJobLimit returns how many ffmpeg and ffprobe processes may run at
+// once.
+func (a *App) GetJobLimit() int {
+	return videocompare.JobLimit()
+}
+
+// SetJobLimit saves and applies how many ffmpeg and ffprobe processes may
+// run at once; 0 restores the default of one per CPU. It returns the
+// limit now in effect.
+func (a *App) SetJobLimit(n int) (int, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("the job limit can't be negative")
+	}
+	s, err := loadSettings()
+	if err != nil {
+		return 0, err
+	}
+	s.JobLimit = n
+	if err := saveSettings(s); err != nil {
+		return 0, err
+	}
+	videocompare.SetJobLimit(n)
+	return videocompare.JobLimit(), nil
+}
+
+// GetActiveJobs reports how many background jobs are running and queued.
+func (a *App) GetActiveJobs() videocompare.JobStats {
+	return videocompare.CurrentJobs()
+}
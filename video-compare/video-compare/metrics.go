@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"videocompare"
+)
+
+// These helpers wrap the videocompare package for the GUI bindings and the
+// headless command line mode, so they must not depend on Wails.
+
+const toolTimeout = 30 * time.Minute
+
+// Supported quality metrics. Scores are computed with the left file as the
+// reference and the right file as the distorted clip.
+const (
+	metricPSNR = videocompare.PSNR
+	metricSSIM = videocompare.SSIM
+	metricVMAF = videocompare.VMAF
+)
+
+// VideoMetadata holds the properties compared by the metadata diff.
+type VideoMetadata = videocompare.Info
+
+// MetadataDifference is one property that differs between two files.
+type MetadataDifference = videocompare.Difference
+
+// probeVideo reads the first video stream's properties with ffprobe.
+func probeVideo(path string) (VideoMetadata, error) {
+	if reason := toolchain().Explain(videocompare.ToolFFprobe); reason != "" {
+		return VideoMetadata{}, fmt.Errorf("probing %s: %s", path, reason)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), toolTimeout)
+	defer cancel()
+	return videocompare.Probe(ctx, path)
+}
+
+// diffMetadata lists the properties that differ between left and right.
+func diffMetadata(left, right VideoMetadata) []MetadataDifference {
+	return videocompare.Diff(left, right)
+}
+
+// computeMetric scores right against the reference left with the given
+// metric.
+func computeMetric(metric, left, right string) (float64, error) {
+	if err := checkFeature(metric); err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), toolTimeout)
+	defer cancel()
+	return videocompare.CompareFiles(ctx, metric, left, right)
+}
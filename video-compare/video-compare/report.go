@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"os"
+
+	"videocompare"
+)
+
+// reportFigureHeight is the height both frames of a report figure are
+// scaled to before they are put side by side.
+const reportFigureHeight = 360
+
+// ReportOptions chooses what GenerateReport covers. The app keeps no
+// session, so the frontend passes the pair and the bookmarks and notes it
+// holds.
+type ReportOptions struct {
+	Left  string `json:"left"`
+	Right string `json:"right"`
+	// Profile is the threshold profile the verdict uses, "broadcast" when
+	// empty
+	Profile string `json:"profile"`
+	// Bookmarks are the positions shown as side-by-side figures
+	Bookmarks []ReportBookmark `json:"bookmarks"`
+	Notes     string           `json:"notes"`
+}
+
+// ReportBookmark is one position to include in a report as a figure.
+type ReportBookmark struct {
+	Time  float64 `json:"time"` // seconds
+	Label string  `json:"label"`
+}
+
+type reportFigure struct {
+	Label     string
+	Timestamp string
+	Image     template.URL // base64 data URI, so the report is self-contained
+	Error     string
+}
+
+type reportData struct {
+	Summary PairSummary
+	Scores  []reportScore
+	Figures []reportFigure
+	Notes   string
+}
+
+type reportScore struct {
+	Metric string
+	Value  string
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8">
+<title>Video Comparison Report</title>
+<style>
+body { font-family: Arial, sans-serif; margin: 2em; background: #1a1a1a; color: #eee; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #555; padding: 6px 12px; text-align: left; }
+th { background: #333; }
+figure { margin: 0 0 2em 0; }
+figure img { max-width: 100%; border: 1px solid #555; }
+figcaption { margin-top: 0.5em; color: #ccc; }
+.pass { color: #6c6; } .fail { color: #e66; }
+pre { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>Video Comparison Report</h1>
+{{with .Summary}}<p>Generated {{.Generated.Format "2006-01-02 15:04:05"}}</p>
+<p>Left: <code>{{.Left}}</code><br>Right: <code>{{.Right}}</code></p>
+{{if .Verdict}}<p>Verdict ({{.Profile}} profile): <strong class="{{if .Passed}}pass{{else}}fail{{end}}">{{.Verdict.Badge}}</strong>
+{{range .Verdict.Reasons}}<br>{{.}}{{end}}</p>{{end}}
+
+<h2>Metadata</h2>
+{{if .MetadataMatch}}<p>The compared properties match.</p>{{else}}<table>
+<tr><th>Property</th><th>Left</th><th>Right</th></tr>
+{{range .Differences}}<tr><td>{{.Field}}</td><td>{{.Left}}</td><td>{{.Right}}</td></tr>
+{{end}}</table>{{end}}{{end}}
+
+<h2>Metrics</h2>
+<table>
+<tr><th>Metric</th><th>Score</th></tr>
+{{range .Scores}}<tr><td>{{.Metric}}</td><td>{{.Value}}</td></tr>
+{{end}}</table>
+{{with .Summary.Savings}}<p>Size: {{.Verdict}}</p>{{end}}
+{{if .Summary.Unavailable}}<p>Not measured:</p>
+<ul>{{range $feature, $reason := .Summary.Unavailable}}<li>{{$reason}}</li>{{end}}</ul>{{end}}
+
+{{if .Figures}}<h2>Figures</h2>
+{{range .Figures}}<figure>
+{{if .Image}}<img src="{{.Image}}" alt="{{.Label}}">{{else}}<p>{{.Error}}</p>{{end}}
+<figcaption>{{.Timestamp}}{{if .Label}} — {{.Label}}{{end}}</figcaption>
+</figure>
+{{end}}{{end}}
+{{if .Notes}}<h2>Notes</h2>
+<pre>{{.Notes}}</pre>{{end}}
+</body>
+</html>
+`))
+
+// reportScores lists the summary's scores in a fixed order for the report.
+func reportScores(s PairSummary) []reportScore {
+	var scores []reportScore
+	for _, metric := range []string{metricPSNR, metricSSIM, metricVMAF} {
+		if v, ok := s.Scores[metric]; ok {
+			scores = append(scores, reportScore{metricLabels[metric], fmt.Sprintf("%.4f", v)})
+		}
+	}
+	if s.WorstFramePSNR != nil {
+		scores = append(scores, reportScore{"Worst frame dB PSNR", fmt.Sprintf("%.4f", *s.WorstFramePSNR)})
+	}
+	return scores
+}
+
+// sideBySideFrame renders the frames of left and right at seconds next to
+// each other as a PNG.
+func sideBySideFrame(left, right string, seconds float64) ([]byte, error) {
+	if err := checkFeature(featureReportFigures); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), toolTimeout)
+	defer cancel()
+	at := fmt.Sprintf("%.3f", seconds)
+	return videocompare.RunFFmpeg(ctx, "-ss", at, "-i", left, "-ss", at, "-i", right,
+		"-filter_complex", fmt.Sprintf("[0:v:0]scale=-2:%[1]d,setsar=1[l];[1:v:0]scale=-2:%[1]d,setsar=1[r];[l][r]hstack", reportFigureHeight),
+		"-frames:v", "1", "-f", "image2pipe", "-c:v", "png", "-")
+}
+
+// formatTimestamp renders seconds as HH:MM:SS.mmm.
+func formatTimestamp(seconds float64) string {
+	ms := int64(seconds*1000 + 0.5)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// GenerateReport writes a self-contained HTML report on the pair in
+// options to outPath: the verdict, the metadata differences, the PSNR,
+// SSIM, VMAF and worst-frame scores of CompareToJSON's summary, the size
+// savings, side-by-side figures at the chosen bookmarks with the images
+// inlined, and the notes. A figure that can't be rendered is described
+// instead of failing the report.
+func (a *App) GenerateReport(outPath string, options ReportOptions) error {
+	summary, err := summarizePair(options.Left, options.Right, options.Profile)
+	if err != nil {
+		return err
+	}
+	data := reportData{Summary: summary, Scores: reportScores(summary), Notes: options.Notes}
+	for _, b := range options.Bookmarks {
+		figure := reportFigure{Label: b.Label, Timestamp: formatTimestamp(b.Time)}
+		if img, err := sideBySideFrame(options.Left, options.Right, b.Time); err != nil {
+			figure.Error = fmt.Sprintf("No figure: %v", err)
+		} else {
+			figure.Image = template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(img))
+		}
+		data.Figures = append(data.Figures, figure)
+	}
+
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, data); err != nil {
+		return fmt.Errorf("rendering report: %w", err)
+	}
+	return os.WriteFile(outPath, buf.Bytes(), 0o644)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+)
+
+// SavingsReport sets the size of the distorted (right) file against the
+// reference (left) next to a quality score, answering "how much smaller
+// for how much quality loss".
+type SavingsReport struct {
+	LeftSize     int64   `json:"left_size"`
+	RightSize    int64   `json:"right_size"`
+	LeftBitrate  int     `json:"left_bitrate"` // overall bits per second
+	RightBitrate int     `json:"right_bitrate"`
+	SizeChange   float64 `json:"size_change"` // percent, negative when right is smaller
+	Metric       string  `json:"metric,omitempty"`
+	Score        float64 `json:"score,omitempty"`
+	Verdict      string  `json:"verdict"`
+}
+
+// overallBitrate is the file size over the duration, covering every stream
+// and the container overhead.
+func overallBitrate(size int64, duration float64) int {
+	if duration <= 0 {
+		return 0
+	}
+	return int(math.Round(float64(size) * 8 / duration))
+}
+
+// sizeVerdict describes the size change, e.g. "38.2% smaller".
+func sizeVerdict(change float64) string {
+	switch {
+	case math.Abs(change) < 0.05:
+		return "same size"
+	case change < 0:
+		return fmt.Sprintf("%.1f%% smaller", -change)
+	default:
+		return fmt.Sprintf("%.1f%% larger", change)
+	}
+}
+
+// GetSavingsReport compares the file sizes and overall bitrates of left and
+// right and, unless metric is empty, scores right against left with it.
+func (a *App) GetSavingsReport(left, right, metric string) (SavingsReport, error) {
+	return savingsReport(left, right, metric)
+}
+
+func savingsReport(left, right, metric string) (SavingsReport, error) {
+	var r SavingsReport
+	if err := checkFeature(featureSavings); err != nil {
+		return r, err
+	}
+	if metric != "" {
+		if err := checkFeature(metric); err != nil {
+			return r, err
+		}
+	}
+	for _, side := range []struct {
+		path    string
+		size    *int64
+		bitrate *int
+	}{{left, &r.LeftSize, &r.LeftBitrate}, {right, &r.RightSize, &r.RightBitrate}} {
+		info, err := os.Stat(side.path)
+		if err != nil {
+			return r, err
+		}
+		md, err := probeVideo(side.path)
+		if err != nil {
+			return r, err
+		}
+		*side.size = info.Size()
+		*side.bitrate = overallBitrate(info.Size(), md.Duration)
+	}
+	if r.LeftSize == 0 {
+		return r, fmt.Errorf("%s is empty", left)
+	}
+	r.SizeChange = 100 * float64(r.RightSize-r.LeftSize) / float64(r.LeftSize)
+	r.Verdict = sizeVerdict(r.SizeChange)
+
+	if metric != "" {
+		score, err := computeMetric(metric, left, right)
+		if err != nil {
+			return r, err
+		}
+		r.Metric, r.Score = metric, score
+		r.Verdict += fmt.Sprintf(", %.2f %s", score, metricLabels[metric])
+	}
+	return r, nil
+}
+
+var metricLabels = map[string]string{
+	metricPSNR: "dB PSNR",
+	metricSSIM: "SSIM",
+	metricVMAF: "VMAF",
+}
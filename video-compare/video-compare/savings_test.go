@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestOverallBitrate(t *testing.T) {
+	tests := []struct {
+		size     int64
+		duration float64
+		want     int
+	}{
+		{1_000_000, 8, 1_000_000},
+		{1_000_000, 3, 2_666_667},
+		// An unknown duration gives no bitrate rather than dividing by zero
+		{1_000_000, 0, 0},
+		{1_000_000, -1, 0},
+	}
+	for _, tt := range tests {
+		if got := overallBitrate(tt.size, tt.duration); got != tt.want {
+			t.Errorf("overallBitrate(%d, %v) = %d, want %d", tt.size, tt.duration, got, tt.want)
+		}
+	}
+}
+
+func TestSizeVerdict(t *testing.T) {
+	tests := []struct {
+		change float64
+		want   string
+	}{
+		{-38.24, "38.2% smaller"},
+		{12.5, "12.5% larger"},
+		{0, "same size"},
+		// Changes that would round to 0.0% read as the same size
+		{0.04, "same size"},
+		{-0.04, "same size"},
+		{0.06, "0.1% larger"},
+	}
+	for _, tt := range tests {
+		if got := sizeVerdict(tt.change); got != tt.want {
+			t.Errorf("sizeVerdict(%v) = %q, want %q", tt.change, got, tt.want)
+		}
+	}
+}
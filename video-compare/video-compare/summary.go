@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// summarySchemaVersion is bumped whenever PairSummary changes in a way
+// consumers have to handle.
+const summarySchemaVersion = 1
+
+// defaultSummaryProfile is the threshold profile CompareToJSON's verdict
+// uses when none is named.
+const defaultSummaryProfile = "broadcast"
+
+// PairSummary is the machine-readable summary of one pair: both files'
+// metadata, which of it differs and the overall quality, size and
+// verdict. It shares the pair, differences, verdict and pass/fail fields
+// with the batch results. Metrics the installed tools can't compute are
+// listed in Unavailable instead of failing the whole summary.
+type PairSummary struct {
+	SchemaVersion int       `json:"schema_version"`
+	Generated     time.Time `json:"generated"`
+	pairOutcome
+	Metadata      PairMetadata       `json:"metadata"`
+	MetadataMatch bool               `json:"metadata_match"`
+	Scores        map[string]float64 `json:"scores"`
+	// WorstFramePSNR is the lowest PSNR of any single frame, in dB
+	WorstFramePSNR *float64          `json:"worst_frame_psnr,omitempty"`
+	Savings        *SavingsReport    `json:"savings,omitempty"`
+	Profile        string            `json:"profile"`
+	Unavailable    map[string]string `json:"unavailable,omitempty"`
+}
+
+// PairMetadata holds both files' metadata in a PairSummary.
+type PairMetadata struct {
+	Left  VideoMetadata `json:"left"`
+	Right VideoMetadata `json:"right"`
+}
+
+// summarizePair measures everything PairSummary reports, scoring right
+// against the reference left, and judges it by the named threshold
+// profile, defaultSummaryProfile when profile is "".
+func summarizePair(left, right, profile string) (PairSummary, error) {
+	if profile == "" {
+		profile = defaultSummaryProfile
+	}
+	s := PairSummary{
+		SchemaVersion: summarySchemaVersion,
+		Generated:     time.Now(),
+		pairOutcome:   pairOutcome{Left: left, Right: right},
+		Scores:        map[string]float64{},
+		Profile:       profile,
+	}
+	// Check the name before the slow measurements
+	thresholds, err := thresholdProfile(profile)
+	if err != nil {
+		return s, err
+	}
+	if s.Metadata.Left, err = probeVideo(left); err != nil {
+		return s, err
+	}
+	if s.Metadata.Right, err = probeVideo(right); err != nil {
+		return s, err
+	}
+	s.Differences = diffMetadata(s.Metadata.Left, s.Metadata.Right)
+	s.MetadataMatch = len(s.Differences) == 0
+
+	// unavailable records why feature is missing from the summary, if err
+	// says it is
+	unavailable := func(feature string, err error) bool {
+		if err == nil {
+			return false
+		}
+		if s.Unavailable == nil {
+			s.Unavailable = map[string]string{}
+		}
+		s.Unavailable[feature] = err.Error()
+		return true
+	}
+	for _, metric := range []string{metricPSNR, metricSSIM, metricVMAF} {
+		if unavailable(metric, checkFeature(metric)) {
+			continue
+		}
+		score, err := computeMetric(metric, left, right)
+		if err != nil {
+			return s, err
+		}
+		s.Scores[metric] = score
+	}
+	if !unavailable(featureFramePSNR, checkFeature(featureFramePSNR)) {
+		worst, err := worstFramePSNR(left, right)
+		if err != nil {
+			return s, err
+		}
+		s.WorstFramePSNR = &worst
+	}
+	// The scores are already in, so the savings are measured without one
+	if savings, err := savingsReport(left, right, ""); !unavailable(featureSavings, err) {
+		s.Savings = &savings
+	}
+
+	verdict := thresholds.Evaluate(comparisonMetrics{
+		Scores:         s.Scores,
+		WorstFramePSNR: s.WorstFramePSNR,
+		Differences:    s.Differences,
+	})
+	s.Verdict = &verdict
+	s.Passed = verdict.Passed
+	return s, nil
+}
+
+// CompareToJSON compares one pair and returns its PairSummary as indented
+// JSON, the machine-readable counterpart of the CSV and HTML exports for
+// CI and other tools. The verdict uses the named threshold profile, or
+// "broadcast" when profile is "".
+func (a *App) CompareToJSON(left, right, profile string) (string, error) {
+	s, err := summarizePair(left, right, profile)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding summary: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strings"
+
+	"videocompare"
+)
+
+// Thresholds are the limits a comparison must meet to pass. Limits left
+// unset are not checked, so only the metrics they need are computed.
+type Thresholds struct {
+	MinVMAF      *float64 `json:"min_vmaf,omitempty"`
+	MinPSNR      *float64 `json:"min_psnr,omitempty"`       // average, dB
+	MinFramePSNR *float64 `json:"min_frame_psnr,omitempty"` // worst single frame, dB
+	MinSSIM      *float64 `json:"min_ssim,omitempty"`
+	// MaxDifferences limits how many metadata fields may differ
+	MaxDifferences *int `json:"max_differences,omitempty"`
+}
+
+// defaultThresholdProfiles are offered until the user saves a profile of
+// the same name.
+var defaultThresholdProfiles = map[string]Thresholds{
+	"broadcast": {MinVMAF: ptr(93.0), MinFramePSNR: ptr(30.0)},
+	"web":       {MinVMAF: ptr(85.0), MinFramePSNR: ptr(25.0)},
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// comparisonMetrics are the measurements thresholds are evaluated against.
+// Metrics that weren't computed are nil.
+type comparisonMetrics struct {
+	Scores         map[string]float64
+	WorstFramePSNR *float64
+	Differences    []MetadataDifference
+}
+
+// Verdict is the pass/fail outcome of a comparison with the reasons for a
+// failure.
+type Verdict struct {
+	Passed  bool               `json:"passed"`
+	Badge   string             `json:"badge"` // PASS or FAIL
+	Reasons []string           `json:"reasons,omitempty"`
+	Scores  map[string]float64 `json:"scores,omitempty"`
+	// WorstFramePSNR is the lowest PSNR of any single frame, in dB
+	WorstFramePSNR *float64 `json:"worst_frame_psnr,omitempty"`
+}
+
+// Evaluate checks m against every set limit.
+func (t Thresholds) Evaluate(m comparisonMetrics) Verdict {
+	v := Verdict{Passed: true, Scores: m.Scores, WorstFramePSNR: m.WorstFramePSNR}
+	fail := func(format string, args ...any) {
+		v.Passed = false
+		v.Reasons = append(v.Reasons, fmt.Sprintf(format, args...))
+	}
+	for _, limit := range []struct {
+		metric string
+		min    *float64
+	}{{metricVMAF, t.MinVMAF}, {metricPSNR, t.MinPSNR}, {metricSSIM, t.MinSSIM}} {
+		if limit.min == nil {
+			continue
+		}
+		if score, ok := m.Scores[limit.metric]; !ok {
+			fail("%s not computed", metricLabels[limit.metric])
+		} else if score < *limit.min {
+			fail("%s %.2f below %.2f", metricLabels[limit.metric], score, *limit.min)
+		}
+	}
+	if t.MinFramePSNR != nil {
+		if m.WorstFramePSNR == nil {
+			fail("frame PSNR not computed")
+		} else if *m.WorstFramePSNR < *t.MinFramePSNR {
+			fail("a frame's PSNR is %.2f dB, below %.2f dB", *m.WorstFramePSNR, *t.MinFramePSNR)
+		}
+	}
+	if t.MaxDifferences != nil && len(m.Differences) > *t.MaxDifferences {
+		fields := make([]string, len(m.Differences))
+		for i, d := range m.Differences {
+			fields[i] = d.Field
+		}
+		fail("%d metadata fields differ (%s), at most %d allowed",
+			len(m.Differences), strings.Join(fields, ", "), *t.MaxDifferences)
+	}
+	v.Badge = "PASS"
+	if !v.Passed {
+		v.Badge = "FAIL"
+	}
+	return v
+}
+
+// measure computes the metrics t checks, scoring right against left.
+func (t Thresholds) measure(left, right string) (comparisonMetrics, error) {
+	m := comparisonMetrics{Scores: map[string]float64{}}
+	for _, limit := range []struct {
+		metric string
+		min    *float64
+	}{{metricVMAF, t.MinVMAF}, {metricPSNR, t.MinPSNR}, {metricSSIM, t.MinSSIM}} {
+		if limit.min == nil {
+			continue
+		}
+		score, err := computeMetric(limit.metric, left, right)
+		if err != nil {
+			return m, err
+		}
+		m.Scores[limit.metric] = score
+	}
+	if t.MinFramePSNR != nil {
+		worst, err := worstFramePSNR(left, right)
+		if err != nil {
+			return m, err
+		}
+		m.WorstFramePSNR = &worst
+	}
+	if t.MaxDifferences != nil {
+		lm, err := probeVideo(left)
+		if err != nil {
+			return m, err
+		}
+		rm, err := probeVideo(right)
+		if err != nil {
+			return m, err
+		}
+		m.Differences = diffMetadata(lm, rm)
+	}
+	return m, nil
+}
+
+// worstFramePSNR returns the lowest PSNR of any frame of right against
+// left, from the per-frame statistics of ffmpeg's psnr filter. Identical
+// frames count as 100 dB, like an identical file.
+func worstFramePSNR(left, right string) (float64, error) {
+	if err := checkFeature(featureFramePSNR); err != nil {
+		return 0, err
+	}
+	stats, err := os.CreateTemp("", "video-compare-psnr-*.log")
+	if err != nil {
+		return 0, err
+	}
+	stats.Close()
+	defer os.Remove(stats.Name())
+
+	ctx, cancel := context.WithTimeout(context.Background(), toolTimeout)
+	defer cancel()
+	// The stats path is an option value inside the filter graph, so quote
+	// it and escape the characters the graph parser treats specially
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `'\''`, `:`, `\:`).Replace(stats.Name())
+	graph := fmt.Sprintf("[1:v][0:v]scale2ref[dist][ref];[dist][ref]psnr=stats_file='%s'", escaped)
+	if _, err := videocompare.RunFFmpegLog(ctx, "-i", left, "-i", right, "-lavfi", graph, "-f", "null", "-"); err != nil {
+		return 0, fmt.Errorf("frame psnr: %w", err)
+	}
+
+	data, err := os.ReadFile(stats.Name())
+	if err != nil {
+		return 0, err
+	}
+	worst := math.Inf(1)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if frame, ok := videocompare.ParseFramePSNR(scanner.Text()); ok {
+			worst = math.Min(worst, frame.Score)
+		}
+	}
+	if math.IsInf(worst, 1) {
+		// No frames compared; report the same finite value as the average
+		return 100, nil
+	}
+	return worst, nil
+}
+
+// evaluateFiles measures a pair of files and judges them against t.
+func evaluateFiles(left, right string, t Thresholds) (Verdict, error) {
+	m, err := t.measure(left, right)
+	if err != nil {
+		return Verdict{}, err
+	}
+	return t.Evaluate(m), nil
+}
+
+// thresholdProfiles returns the saved profiles on top of the defaults.
+func thresholdProfiles() map[string]Thresholds {
+	profiles := maps.Clone(defaultThresholdProfiles)
+	if s, err := loadSettings(); err == nil {
+		maps.Copy(profiles, s.ThresholdProfiles)
+	}
+	return profiles
+}
+
+// thresholdProfile looks up a profile by name.
+func thresholdProfile(name string) (Thresholds, error) {
+	t, ok := thresholdProfiles()[name]
+	if !ok {
+		return Thresholds{}, fmt.Errorf("unknown threshold profile %q (have %s)",
+			name, strings.Join(slices.Sorted(maps.Keys(thresholdProfiles())), ", "))
+	}
+	return t, nil
+}
+
+// EvaluateComparison scores right against the reference left with the
+// metrics thresholds needs and returns the PASS/FAIL verdict.
+func (a *App) EvaluateComparison(left, right string, thresholds Thresholds) (Verdict, error) {
+	return evaluateFiles(left, right, thresholds)
+}
+
+// GetThresholdProfiles returns the named threshold profiles, the built-in
+// "broadcast" and "web" ones included.
+func (a *App) GetThresholdProfiles() map[string]Thresholds {
+	return thresholdProfiles()
+}
+
+// SaveThresholdProfile saves thresholds under name, replacing a profile of
+// the same name.
+func (a *App) SaveThresholdProfile(name string, thresholds Thresholds) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("a profile name is required")
+	}
+	s, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	if s.ThresholdProfiles == nil {
+		s.ThresholdProfiles = map[string]Thresholds{}
+	}
+	s.ThresholdProfiles[name] = thresholds
+	return saveSettings(s)
+}
+
+// DeleteThresholdProfile removes a saved profile. Deleting a customized
+// built-in profile restores its defaults.
+func (a *App) DeleteThresholdProfile(name string) error {
+	s, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	delete(s.ThresholdProfiles, name)
+	return saveSettings(s)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestThresholdsEvaluate(t *testing.T) {
+	thresholds := Thresholds{MinVMAF: ptr(90.0), MinFramePSNR: ptr(30.0), MaxDifferences: ptr(1)}
+	tests := []struct {
+		name    string
+		metrics comparisonMetrics
+		reasons []string
+	}{
+		{"pass", comparisonMetrics{
+			Scores:         map[string]float64{metricVMAF: 95},
+			WorstFramePSNR: ptr(35.0),
+			Differences:    []MetadataDifference{{Field: "bitrate"}},
+		}, nil},
+		// A limit is inclusive
+		{"at the limits", comparisonMetrics{
+			Scores:         map[string]float64{metricVMAF: 90},
+			WorstFramePSNR: ptr(30.0),
+		}, nil},
+		{"low scores", comparisonMetrics{
+			Scores:         map[string]float64{metricVMAF: 80.5},
+			WorstFramePSNR: ptr(22.25),
+		}, []string{"VMAF 80.50 below 90.00", "a frame's PSNR is 22.25 dB, below 30.00 dB"}},
+		{"not computed", comparisonMetrics{}, []string{"VMAF not computed", "frame PSNR not computed"}},
+		{"too many differences", comparisonMetrics{
+			Scores:         map[string]float64{metricVMAF: 95},
+			WorstFramePSNR: ptr(35.0),
+			Differences:    []MetadataDifference{{Field: "codec"}, {Field: "width"}},
+		}, []string{"2 metadata fields differ (codec, width), at most 1 allowed"}},
+	}
+	for _, tt := range tests {
+		v := thresholds.Evaluate(tt.metrics)
+		if !reflect.DeepEqual(v.Reasons, tt.reasons) {
+			t.Errorf("%s: reasons = %q, want %q", tt.name, v.Reasons, tt.reasons)
+		}
+		passed, badge := tt.reasons == nil, "PASS"
+		if !passed {
+			badge = "FAIL"
+		}
+		if v.Passed != passed || v.Badge != badge {
+			t.Errorf("%s: verdict = %v %s, want %v %s", tt.name, v.Passed, v.Badge, passed, badge)
+		}
+	}
+
+	// Unset limits aren't checked, so an empty Thresholds passes anything
+	if v := (Thresholds{}).Evaluate(comparisonMetrics{}); !v.Passed {
+		t.Errorf("empty thresholds failed: %q", v.Reasons)
+	}
+}
+
+func TestThresholdProfile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	a := &App{}
+
+	broadcast, err := thresholdProfile("broadcast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(broadcast, defaultThresholdProfiles["broadcast"]) {
+		t.Errorf("broadcast = %+v, want the default", broadcast)
+	}
+
+	// A saved profile overrides the built-in one of the same name
+	if err := a.SaveThresholdProfile(" broadcast ", Thresholds{MinVMAF: ptr(97.0)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.SaveThresholdProfile("archive", Thresholds{MinPSNR: ptr(45.0)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := thresholdProfile("broadcast"); err != nil || *got.MinVMAF != 97 || got.MinFramePSNR != nil {
+		t.Errorf("customized broadcast = %+v, %v", got, err)
+	}
+	if got, err := thresholdProfile("archive"); err != nil || *got.MinPSNR != 45 {
+		t.Errorf("archive = %+v, %v", got, err)
+	}
+
+	// Deleting the customized profile restores the default
+	if err := a.DeleteThresholdProfile("broadcast"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := thresholdProfile("broadcast"); !reflect.DeepEqual(got, defaultThresholdProfiles["broadcast"]) {
+		t.Errorf("restored broadcast = %+v, want the default", got)
+	}
+
+	_, err = thresholdProfile("cinema")
+	if err == nil || !strings.Contains(err.Error(), "(have archive, broadcast, web)") {
+		t.Errorf("unknown profile error = %v, want the profiles listed", err)
+	}
+}
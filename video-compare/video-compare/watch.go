@@ -0,0 +1,247 @@
+//go:build !headless
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"videocompare"
+)
+
+// Events emitted to the frontend while a folder is watched.
+const (
+	eventWatchResult = "watch:result"
+	eventWatchError  = "watch:error"
+)
+
+// watchSettleDelay is how long a new file must go unmodified before it is
+// compared, so files still being written by an encoder are not picked up.
+const watchSettleDelay = 2 * time.Second
+
+// WatchConfig configures watch-folder mode.
+type WatchConfig struct {
+	Directory string `json:"directory"`
+	// Reference is either one file every new output is compared with, or
+	// a directory holding references; an output is then compared with the
+	// reference whose name (without extension) it starts with.
+	Reference string   `json:"reference"`
+	Metrics   []string `json:"metrics"` // psnr, ssim, vmaf and/or metadata-diff
+	// Thresholds holds each metric's pass/fail limit on its own scale:
+	// the lowest PSNR in dB, SSIM from 0 to 1 and VMAF from 0 to 100, and
+	// the most metadata fields that may differ. Metrics without one are
+	// reported without a pass/fail check.
+	Thresholds map[string]float64 `json:"thresholds,omitempty"`
+}
+
+// WatchResult is emitted for every new file that was compared.
+type WatchResult struct {
+	File      string             `json:"file"`
+	Reference string             `json:"reference"`
+	Time      time.Time          `json:"time"`
+	Results   []comparisonResult `json:"results"`
+	Passed    bool               `json:"passed"`
+}
+
+// folderWatch is a running watch-folder session.
+type folderWatch struct {
+	config  WatchConfig
+	watcher *fsnotify.Watcher
+	queue   chan string
+	done    chan struct{}
+
+	mu     sync.Mutex
+	timers map[string]*time.Timer
+}
+
+// StartWatch monitors config.Directory and compares every new video file
+// against its reference with each configured metric, emitting a
+// watch:result event per file. A previous watch is stopped first.
+func (a *App) StartWatch(config WatchConfig) error {
+	if len(config.Metrics) == 0 {
+		return fmt.Errorf("choose at least one metric")
+	}
+	for _, m := range config.Metrics {
+		if !videocompare.IsMetric(m) && m != "metadata-diff" {
+			return fmt.Errorf("unknown metric %q", m)
+		}
+		if err := checkFeature(m); err != nil {
+			return err
+		}
+	}
+	for m, threshold := range config.Thresholds {
+		if !slices.Contains(config.Metrics, m) {
+			return fmt.Errorf("threshold for %q, which isn't one of the metrics", m)
+		}
+		if threshold < 0 {
+			return fmt.Errorf("the %s threshold can't be negative", m)
+		}
+	}
+	if _, err := os.Stat(config.Reference); err != nil {
+		return fmt.Errorf("reference: %w", err)
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(config.Directory); err != nil {
+		watcher.Close()
+		return fmt.Errorf("watching %s: %w", config.Directory, err)
+	}
+
+	a.StopWatch()
+	w := &folderWatch{
+		config:  config,
+		watcher: watcher,
+		queue:   make(chan string, 64),
+		done:    make(chan struct{}),
+		timers:  make(map[string]*time.Timer),
+	}
+	a.watchMu.Lock()
+	a.stopWatch = w.stop
+	a.watchMu.Unlock()
+
+	go w.collect(a)
+	go w.compare(a)
+	return nil
+}
+
+// StopWatch ends the current watch-folder session, if any.
+func (a *App) StopWatch() {
+	a.watchMu.Lock()
+	stop := a.stopWatch
+	a.stopWatch = nil
+	a.watchMu.Unlock()
+	if stop != nil {
+		stop()
+	}
+}
+
+func (w *folderWatch) stop() {
+	close(w.done)
+	w.watcher.Close()
+	w.mu.Lock()
+	for _, t := range w.timers {
+		t.Stop()
+	}
+	w.mu.Unlock()
+}
+
+// collect debounces filesystem events and queues files once they have
+// settled.
+func (w *folderWatch) collect(a *App) {
+	formats := a.GetSupportedFormats()
+	for {
+		select {
+		case <-w.done:
+			return
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("watch %s: %v", w.config.Directory, err)
+			runtime.EventsEmit(a.ctx, eventWatchError, err.Error())
+		case ev, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if !ev.Has(fsnotify.Create) && !ev.Has(fsnotify.Write) {
+				continue
+			}
+			if !slices.Contains(formats, strings.ToLower(filepath.Ext(ev.Name))) {
+				continue
+			}
+			w.settle(ev.Name)
+		}
+	}
+}
+
+// settle queues path once it stops changing for watchSettleDelay.
+func (w *folderWatch) settle(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if t, ok := w.timers[path]; ok {
+		t.Reset(watchSettleDelay)
+		return
+	}
+	w.timers[path] = time.AfterFunc(watchSettleDelay, func() {
+		w.mu.Lock()
+		delete(w.timers, path)
+		w.mu.Unlock()
+		select {
+		case w.queue <- path:
+		case <-w.done:
+		}
+	})
+}
+
+// compare runs the comparisons one file at a time, as metrics are
+// CPU-heavy.
+func (w *folderWatch) compare(a *App) {
+	for {
+		select {
+		case <-w.done:
+			return
+		case path := <-w.queue:
+			reference, err := w.reference(path)
+			if err != nil {
+				log.Printf("watch: %v", err)
+				runtime.EventsEmit(a.ctx, eventWatchError, err.Error())
+				continue
+			}
+			result := WatchResult{File: path, Reference: reference, Passed: true}
+			for _, metric := range w.config.Metrics {
+				threshold, ok := w.config.Thresholds[metric]
+				if !ok {
+					threshold = -1
+				}
+				r, err := compareFiles(metric, reference, path, threshold)
+				if err != nil {
+					r.Passed = false
+					r.Error = err.Error()
+				}
+				result.Passed = result.Passed && r.Passed
+				result.Results = append(result.Results, r)
+			}
+			result.Time = time.Now()
+			runtime.EventsEmit(a.ctx, eventWatchResult, result)
+		}
+	}
+}
+
+// reference finds the reference file output is compared with.
+func (w *folderWatch) reference(output string) (string, error) {
+	info, err := os.Stat(w.config.Reference)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return w.config.Reference, nil
+	}
+	entries, err := os.ReadDir(w.config.Reference)
+	if err != nil {
+		return "", err
+	}
+	stem := strings.TrimSuffix(filepath.Base(output), filepath.Ext(output))
+	var best, bestStem string
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+		path := filepath.Join(w.config.Reference, e.Name())
+		// Prefer the longest match, so clip10 wins over clip1
+		if !e.IsDir() && path != output && strings.HasPrefix(stem, name) && len(name) > len(bestStem) {
+			best, bestStem = path, name
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no reference in %s matches %s", w.config.Reference, filepath.Base(output))
+	}
+	return best, nil
+}
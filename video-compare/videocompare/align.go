@@ -0,0 +1,50 @@
+package videocompare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CommonRange returns where two clips overlap when the right clip's
+// content at t+offset matches the left's at t, as start times in each
+// clip and the overlap's duration. The duration is zero or negative when
+// they don't overlap.
+func CommonRange(leftDuration, rightDuration, offset float64) (leftStart, rightStart, duration float64) {
+	leftStart = math.Max(0, -offset)
+	end := math.Min(leftDuration, rightDuration-offset)
+	return leftStart, leftStart + offset, end - leftStart
+}
+
+// KeyframeAt reports whether path's video has a keyframe at seconds,
+// within half a frame at fps, so a cut there can be stream copied.
+func KeyframeAt(ctx context.Context, path string, seconds, fps float64) (bool, error) {
+	if seconds <= 0 {
+		return true, nil
+	}
+	tolerance := 0.5 / math.Max(fps, 1)
+	out, err := RunFFprobe(ctx, "-select_streams", "v:0", "-show_entries", "packet=pts_time,flags",
+		"-read_intervals", fmt.Sprintf("%.3f%%+%.3f", math.Max(0, seconds-1), 2.0), path)
+	if err != nil {
+		return false, err
+	}
+	var probe struct {
+		Packets []struct {
+			PTS   string `json:"pts_time"`
+			Flags string `json:"flags"`
+		} `json:"packets"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return false, fmt.Errorf("parsing ffprobe output: %w", err)
+	}
+	for _, p := range probe.Packets {
+		t, err := strconv.ParseFloat(p.PTS, 64)
+		if err == nil && strings.Contains(p.Flags, "K") && math.Abs(t-seconds) <= tolerance {
+			return true, nil
+		}
+	}
+	return false, nil
+}
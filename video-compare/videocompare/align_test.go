@@ -0,0 +1,39 @@
+package videocompare
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCommonRange(t *testing.T) {
+	tests := []struct {
+		name                            string
+		left, right, offset             float64
+		leftStart, rightStart, duration float64
+	}{
+		{"aligned", 10, 10, 0, 0, 0, 10},
+		{"right shorter", 10, 6, 0, 0, 0, 6},
+		// The right clip's content runs 2 s ahead
+		{"right ahead", 10, 10, 2, 0, 2, 8},
+		// The right clip's content runs 3 s behind
+		{"right behind", 10, 10, -3, 3, 0, 7},
+		{"disjoint", 5, 5, 6, 0, 6, -1},
+	}
+	for _, tt := range tests {
+		l, r, d := CommonRange(tt.left, tt.right, tt.offset)
+		if l != tt.leftStart || r != tt.rightStart || d != tt.duration {
+			t.Errorf("%s: CommonRange(%v, %v, %v) = %v, %v, %v, want %v, %v, %v", tt.name,
+				tt.left, tt.right, tt.offset, l, r, d, tt.leftStart, tt.rightStart, tt.duration)
+		}
+	}
+}
+
+func TestKeyframeAtStart(t *testing.T) {
+	// The start of a file is always a keyframe; no ffprobe run is needed
+	for _, seconds := range []float64{0, -1} {
+		ok, err := KeyframeAt(context.Background(), "missing.mp4", seconds, 25)
+		if !ok || err != nil {
+			t.Errorf("KeyframeAt(%v) = %v, %v, want true, nil", seconds, ok, err)
+		}
+	}
+}
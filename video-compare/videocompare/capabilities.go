@@ -0,0 +1,88 @@
+package videocompare
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// Tools features may depend on, as named by Capabilities.Missing.
+const (
+	ToolFFmpeg  = "ffmpeg"
+	ToolFFprobe = "ffprobe"
+	ToolVMAF    = "libvmaf"
+)
+
+var libvmafFilterPattern = regexp.MustCompile(`(?m)^\s*\S*\s+libvmaf\s`)
+
+// Capabilities records which external tools are installed, so features
+// that need a missing one can be disabled up front rather than fail when
+// used.
+type Capabilities struct {
+	FFmpeg  bool `json:"ffmpeg"`
+	FFprobe bool `json:"ffprobe"`
+	// VMAF is whether the installed ffmpeg was built with libvmaf
+	VMAF bool `json:"vmaf"`
+}
+
+// DetectCapabilities looks for ffmpeg and ffprobe on the PATH, running
+// each once to be sure it works, and asks ffmpeg whether it has the
+// libvmaf filter.
+func DetectCapabilities(ctx context.Context) Capabilities {
+	var c Capabilities
+	if out, err := exec.CommandContext(ctx, "ffmpeg", "-hide_banner", "-filters").Output(); err == nil {
+		c.FFmpeg = true
+		c.VMAF = libvmafFilterPattern.Match(out)
+	}
+	if err := exec.CommandContext(ctx, "ffprobe", "-version").Run(); err == nil {
+		c.FFprobe = true
+	}
+	return c
+}
+
+// Has reports whether tool is available.
+func (c Capabilities) Has(tool string) bool {
+	switch tool {
+	case ToolFFmpeg:
+		return c.FFmpeg
+	case ToolFFprobe:
+		return c.FFprobe
+	case ToolVMAF:
+		return c.VMAF
+	}
+	return false
+}
+
+// Missing lists which of tools aren't available.
+func (c Capabilities) Missing(tools ...string) []string {
+	var missing []string
+	for _, tool := range tools {
+		if !c.Has(tool) {
+			missing = append(missing, tool)
+		}
+	}
+	return missing
+}
+
+// Explain says why a feature needing tools is unavailable, or returns ""
+// when they all are.
+func (c Capabilities) Explain(tools ...string) string {
+	var missing []string
+	for _, tool := range c.Missing(tools...) {
+		// Without ffmpeg at all, its missing libvmaf goes without saying
+		if tool != ToolVMAF || c.FFmpeg {
+			missing = append(missing, tool)
+		}
+	}
+	switch {
+	case len(missing) == 0:
+		return ""
+	case len(missing) == 1 && missing[0] == ToolVMAF:
+		return "needs an ffmpeg built with libvmaf"
+	case len(missing) == 1:
+		return fmt.Sprintf("needs %s, which wasn't found on the PATH", missing[0])
+	}
+	return fmt.Sprintf("needs %s, which weren't found on the PATH", strings.Join(missing, " and "))
+}
@@ -0,0 +1,9 @@
+// Package videocompare holds the comparison primitives shared by the
+// video-compare front-ends: probing files with ffprobe, grabbing frames
+// and scoring files with ffmpeg, measuring PSNR and SSIM between images,
+// and working out where two offset clips overlap.
+//
+// The file-based functions shell out to the ffprobe and ffmpeg binaries,
+// which must be on the PATH. VMAF additionally needs an ffmpeg built with
+// libvmaf. Nothing in this package depends on a GUI toolkit.
+package videocompare
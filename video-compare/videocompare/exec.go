@@ -0,0 +1,158 @@
+package videocompare
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ProbeTimeout bounds a single ffprobe run. It starts once the run has a
+// job slot, so probes queued behind a long batch don't time out waiting.
+const ProbeTimeout = 30 * time.Second
+
+// RunFFprobe runs ffprobe with JSON output and returns its stdout. It
+// waits for a job slot until ctx ends, then gives ffprobe ProbeTimeout.
+func RunFFprobe(ctx context.Context, args ...string) ([]byte, error) {
+	release, err := AcquireJob(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(ctx, ProbeTimeout)
+	defer cancel()
+
+	args = append([]string{"-v", "error", "-of", "json"}, args...)
+	out, stderr, err := run(ctx, nil, "ffprobe", args...)
+	if err != nil {
+		if msg := strings.TrimSpace(stderr); msg != "" {
+			return nil, fmt.Errorf("ffprobe: %v: %s", err, msg)
+		}
+		return nil, fmt.Errorf("ffprobe: %w", err)
+	}
+	return out, nil
+}
+
+// RunFFmpeg runs ffmpeg logging errors only and returns its stdout.
+func RunFFmpeg(ctx context.Context, args ...string) ([]byte, error) {
+	return RunFFmpegInput(ctx, nil, args...)
+}
+
+// RunFFmpegInput runs ffmpeg feeding stdin from r and returns its stdout,
+// for encoding frames rendered in-process.
+func RunFFmpegInput(ctx context.Context, r io.Reader, args ...string) ([]byte, error) {
+	release, err := AcquireJob(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	args = append([]string{"-hide_banner", "-nostdin", "-v", "error"}, args...)
+	out, stderr, err := run(ctx, r, "ffmpeg", args...)
+	if err != nil {
+		return nil, ffmpegError(ctx, err, strings.TrimSpace(stderr))
+	}
+	return out, nil
+}
+
+// RunFFmpegLog runs ffmpeg at the default log level and returns its log
+// output, for filters such as psnr and idet that report their results
+// there.
+func RunFFmpegLog(ctx context.Context, args ...string) (string, error) {
+	release, err := AcquireJob(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	args = append([]string{"-hide_banner", "-nostdin", "-nostats"}, args...)
+	_, stderr, err := run(ctx, nil, "ffmpeg", args...)
+	if err != nil {
+		return "", ffmpegError(ctx, err, LastLine(stderr))
+	}
+	return stderr, nil
+}
+
+// RunFFmpegTo runs ffmpeg logging errors only and copies its stdout to w
+// as it arrives, so long decodes can be reduced without holding all of
+// their output.
+func RunFFmpegTo(ctx context.Context, w io.Writer, args ...string) error {
+	release, err := AcquireJob(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	args = append([]string{"-hide_banner", "-nostdin", "-v", "error"}, args...)
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	cmd.Stdout = w
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return ffmpegError(ctx, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// StreamFFmpeg runs ffmpeg logging errors only and calls onLine for every
+// line it writes to stdout as it arrives, so long scans can report
+// progress.
+func StreamFFmpeg(ctx context.Context, onLine func(string), args ...string) error {
+	release, err := AcquireJob(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	args = append([]string{"-hide_banner", "-nostdin", "-v", "error"}, args...)
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("ffmpeg: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("ffmpeg: %w", err)
+	}
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		onLine(scanner.Text())
+	}
+	if err := cmd.Wait(); err != nil {
+		return ffmpegError(ctx, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// run runs a tool, feeding stdin from r when it isn't nil, and returns
+// its stdout and log output.
+func run(ctx context.Context, r io.Reader, name string, args ...string) ([]byte, string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if r != nil {
+		cmd.Stdin = r
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	return out, stderr.String(), err
+}
+
+// ffmpegError reports a failed ffmpeg run: ctx's error when it was
+// cancelled, otherwise err with msg from its log.
+func ffmpegError(ctx context.Context, err error, msg string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if msg != "" {
+		return fmt.Errorf("ffmpeg: %v: %s", err, msg)
+	}
+	return fmt.Errorf("ffmpeg: %w", err)
+}
+
+// LastLine returns the last non-empty line of a tool's log output, which
+// usually holds the error.
+func LastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return lines[len(lines)-1]
+}
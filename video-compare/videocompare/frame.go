@@ -0,0 +1,24 @@
+package videocompare
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"strconv"
+)
+
+// GrabFrame decodes the frame of path's first video stream shown at
+// seconds, seeking accurately rather than to the nearest keyframe.
+func GrabFrame(ctx context.Context, path string, seconds float64) (image.Image, error) {
+	out, err := RunFFmpeg(ctx, "-ss", strconv.FormatFloat(seconds, 'f', 3, 64), "-i", path,
+		"-map", "0:v:0", "-frames:v", "1", "-f", "image2pipe", "-c:v", "png", "-")
+	if err != nil {
+		return nil, fmt.Errorf("grabbing frame at %.3fs of %s: %w", seconds, path, err)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%s has no frame at %.3fs", path, seconds)
+	}
+	return png.Decode(bytes.NewReader(out))
+}
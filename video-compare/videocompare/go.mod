@@ -0,0 +1,5 @@
+module videocompare
+
+go 1.23
+
+require golang.org/x/image v0.24.0
@@ -0,0 +1,109 @@
+package videocompare
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/draw"
+)
+
+// ComparePSNR returns the luma peak signal-to-noise ratio of b against the
+// reference a in dB, +Inf when they are identical. b is resampled to a's
+// size first.
+func ComparePSNR(a, b image.Image) float64 {
+	ref, dist, _, _ := lumaPlanes(a, b)
+	return psnr(ref, dist)
+}
+
+// CompareSSIM returns the mean luma structural similarity of b against the
+// reference a over 8×8 windows, 1 for identical images. b is resampled to
+// a's size first.
+func CompareSSIM(a, b image.Image) float64 {
+	ref, dist, w, h := lumaPlanes(a, b)
+	return ssim(ref, dist, w, h)
+}
+
+// CompareImages returns both ComparePSNR and CompareSSIM, converting the
+// images only once.
+func CompareImages(a, b image.Image) (psnrDB, ssimIndex float64) {
+	ref, dist, w, h := lumaPlanes(a, b)
+	return psnr(ref, dist), ssim(ref, dist, w, h)
+}
+
+// lumaPlanes returns the luma of a and of b scaled to a's size, along with
+// that size.
+func lumaPlanes(a, b image.Image) (ref, dist []float64, w, h int) {
+	bounds := a.Bounds()
+	w, h = bounds.Dx(), bounds.Dy()
+	scaled := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.BiLinear.Scale(scaled, scaled.Bounds(), b, b.Bounds(), draw.Src, nil)
+	return Luma(a), Luma(scaled), w, h
+}
+
+// Luma returns the Rec. 709 luma of img in [0, 255], row by row.
+func Luma(img image.Image) []float64 {
+	b := img.Bounds()
+	out := make([]float64, 0, b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			out = append(out, 0.2126*float64(c.R)+0.7152*float64(c.G)+0.0722*float64(c.B))
+		}
+	}
+	return out
+}
+
+// psnr returns the peak signal-to-noise ratio between two equally sized
+// 8-bit planes, +Inf when they are identical.
+func psnr(a, b []float64) float64 {
+	var mse float64
+	for i := range a {
+		d := a[i] - b[i]
+		mse += d * d
+	}
+	if len(a) > 0 {
+		mse /= float64(len(a))
+	}
+	if mse == 0 {
+		return math.Inf(1)
+	}
+	return 10 * math.Log10(255*255/mse)
+}
+
+// ssim returns the mean structural similarity of two w×h 8-bit planes over
+// non-overlapping 8×8 windows.
+func ssim(a, b []float64, w, h int) float64 {
+	const (
+		window = 8
+		c1     = (0.01 * 255) * (0.01 * 255)
+		c2     = (0.03 * 255) * (0.03 * 255)
+	)
+	var total float64
+	var windows int
+	for y0 := 0; y0+window <= h; y0 += window {
+		for x0 := 0; x0+window <= w; x0 += window {
+			var sa, sb, saa, sbb, sab float64
+			for y := y0; y < y0+window; y++ {
+				for x := x0; x < x0+window; x++ {
+					va, vb := a[y*w+x], b[y*w+x]
+					sa += va
+					sb += vb
+					saa += va * va
+					sbb += vb * vb
+					sab += va * vb
+				}
+			}
+			n := float64(window * window)
+			ma, mb := sa/n, sb/n
+			va, vb := saa/n-ma*ma, sbb/n-mb*mb
+			cov := sab/n - ma*mb
+			total += ((2*ma*mb + c1) * (2*cov + c2)) / ((ma*ma + mb*mb + c1) * (va + vb + c2))
+			windows++
+		}
+	}
+	if windows == 0 {
+		return 1
+	}
+	return total / float64(windows)
+}
@@ -0,0 +1,86 @@
+package videocompare
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// gradient returns a w×h image whose gray level ramps across and down,
+// offset by shift and clamped to [0, 255].
+func gradient(w, h, shift int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(max(0, min(255, (x*7+y*3)%200+shift)))
+			img.SetRGBA(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	return img
+}
+
+func TestCompareImages(t *testing.T) {
+	ref := gradient(32, 32, 0)
+	tests := []struct {
+		name             string
+		dist             image.Image
+		psnr             float64 // dB, within 0.01
+		minSSIM, maxSSIM float64
+	}{
+		{"identical", gradient(32, 32, 0), math.Inf(1), 1, 1},
+		// Every luma value off by 10: MSE 100
+		{"brighter", gradient(32, 32, 10), 10 * math.Log10(255*255/100.0), 0.9, 1},
+		{"black", image.NewRGBA(image.Rect(0, 0, 32, 32)), 0, 0, 0.1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, s := CompareImages(ref, tt.dist)
+			switch {
+			case math.IsInf(tt.psnr, 1):
+				if !math.IsInf(p, 1) {
+					t.Errorf("PSNR = %v, want +Inf", p)
+				}
+			case tt.psnr > 0 && math.Abs(p-tt.psnr) > 0.01:
+				t.Errorf("PSNR = %v, want %v", p, tt.psnr)
+			}
+			if s < tt.minSSIM-1e-9 || s > tt.maxSSIM+1e-9 {
+				t.Errorf("SSIM = %v, want within [%v, %v]", s, tt.minSSIM, tt.maxSSIM)
+			}
+			if got := ComparePSNR(ref, tt.dist); got != p && !(math.IsInf(got, 1) && math.IsInf(p, 1)) {
+				t.Errorf("ComparePSNR = %v, CompareImages gave %v", got, p)
+			}
+			if got := CompareSSIM(ref, tt.dist); got != s {
+				t.Errorf("CompareSSIM = %v, CompareImages gave %v", got, s)
+			}
+		})
+	}
+}
+
+func TestCompareImagesScalesToReference(t *testing.T) {
+	// A flat image scales to the same flat image at any size
+	flat := func(w, h int) *image.RGBA {
+		img := image.NewRGBA(image.Rect(0, 0, w, h))
+		for i := range img.Pix {
+			img.Pix[i] = 128
+		}
+		return img
+	}
+	if p, s := CompareImages(flat(32, 32), flat(64, 48)); !math.IsInf(p, 1) || s != 1 {
+		t.Errorf("CompareImages of flat images of different sizes = %v, %v, want +Inf, 1", p, s)
+	}
+}
+
+func TestLuma(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 1))
+	img.SetRGBA(0, 0, color.RGBA{255, 0, 0, 255})
+	img.SetRGBA(1, 0, color.RGBA{0, 255, 0, 255})
+	img.SetRGBA(2, 0, color.RGBA{0, 0, 255, 255})
+	want := []float64{0.2126 * 255, 0.7152 * 255, 0.0722 * 255}
+	got := Luma(img)
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("Luma[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
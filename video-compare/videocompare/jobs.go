@@ -0,0 +1,138 @@
+package videocompare
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// JobStats is a snapshot of the background job limiter.
+type JobStats struct {
+	Running int `json:"running"`
+	Waiting int `json:"waiting"`
+	Limit   int `json:"limit"`
+}
+
+// jobLimiter is a counting semaphore whose size can change while jobs hold
+// it. Waiters are served in arrival order.
+type jobLimiter struct {
+	mu       sync.Mutex
+	limit    int
+	running  int
+	waiters  []chan struct{}
+	onChange func(JobStats)
+}
+
+var jobs = &jobLimiter{limit: DefaultJobLimit()}
+
+// DefaultJobLimit is the number of ffmpeg and ffprobe processes run at
+// once unless configured otherwise: one per CPU.
+func DefaultJobLimit() int {
+	return runtime.NumCPU()
+}
+
+// SetJobLimit changes how many ffmpeg and ffprobe processes may run at
+// once; values below 1 restore DefaultJobLimit. Running jobs finish, and
+// waiting ones start as soon as the new limit allows.
+func SetJobLimit(n int) {
+	if n < 1 {
+		n = DefaultJobLimit()
+	}
+	jobs.mu.Lock()
+	jobs.limit = n
+	jobs.grant()
+	jobs.unlockAndNotify()
+}
+
+// JobLimit returns how many ffmpeg and ffprobe processes may run at once.
+func JobLimit() int {
+	jobs.mu.Lock()
+	defer jobs.mu.Unlock()
+	return jobs.limit
+}
+
+// CurrentJobs reports how many jobs are running and waiting.
+func CurrentJobs() JobStats {
+	jobs.mu.Lock()
+	defer jobs.mu.Unlock()
+	return jobs.stats()
+}
+
+// OnJobsChanged registers fn to be called, from whichever goroutine made
+// the change, whenever a job starts, finishes or queues, or the limit
+// changes. It replaces any earlier function.
+func OnJobsChanged(fn func(JobStats)) {
+	jobs.mu.Lock()
+	jobs.onChange = fn
+	jobs.mu.Unlock()
+}
+
+// AcquireJob waits for a free slot under the job limit and returns the
+// function releasing it, or ctx's error if ctx ends first. Every ffmpeg
+// and ffprobe run in the background takes a slot, so opening many files or
+// starting a batch doesn't fork dozens of processes at once. A job must
+// not acquire a second slot while holding one.
+func AcquireJob(ctx context.Context) (release func(), err error) {
+	jobs.mu.Lock()
+	if jobs.running < jobs.limit && len(jobs.waiters) == 0 {
+		jobs.running++
+		jobs.unlockAndNotify()
+		return jobs.releaser(), nil
+	}
+	ready := make(chan struct{})
+	jobs.waiters = append(jobs.waiters, ready)
+	jobs.unlockAndNotify()
+
+	select {
+	case <-ready:
+		return jobs.releaser(), nil
+	case <-ctx.Done():
+		jobs.mu.Lock()
+		if i := slices.Index(jobs.waiters, ready); i >= 0 {
+			jobs.waiters = slices.Delete(jobs.waiters, i, i+1)
+			jobs.unlockAndNotify()
+		} else {
+			// The slot was granted as ctx ended; hand it on
+			jobs.mu.Unlock()
+			jobs.release()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// releaser returns a release function that only counts its first call.
+func (l *jobLimiter) releaser() func() {
+	var once sync.Once
+	return func() { once.Do(l.release) }
+}
+
+func (l *jobLimiter) release() {
+	l.mu.Lock()
+	l.running--
+	l.grant()
+	l.unlockAndNotify()
+}
+
+// grant starts waiting jobs while slots are free. l.mu must be held.
+func (l *jobLimiter) grant() {
+	for l.running < l.limit && len(l.waiters) > 0 {
+		close(l.waiters[0])
+		l.waiters = l.waiters[1:]
+		l.running++
+	}
+}
+
+func (l *jobLimiter) stats() JobStats {
+	return JobStats{Running: l.running, Waiting: len(l.waiters), Limit: l.limit}
+}
+
+// unlockAndNotify releases l.mu and reports the new state, outside the
+// lock so the callback may query the limiter.
+func (l *jobLimiter) unlockAndNotify() {
+	stats, fn := l.stats(), l.onChange
+	l.mu.Unlock()
+	if fn != nil {
+		fn(stats)
+	}
+}
@@ -0,0 +1,145 @@
+package videocompare
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// withLimiter swaps in a fresh limiter of the given size for one test.
+func withLimiter(t *testing.T, limit int) {
+	t.Helper()
+	old := jobs
+	jobs = &jobLimiter{limit: limit}
+	t.Cleanup(func() { jobs = old })
+}
+
+// waitFor polls the limiter until cond holds, failing the test after a
+// second.
+func waitFor(t *testing.T, what string, cond func(JobStats) bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond(CurrentJobs()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s: %+v", what, CurrentJobs())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAcquireJobFIFO(t *testing.T) {
+	withLimiter(t, 1)
+	release, err := AcquireJob(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan string, 2)
+	for i, name := range []string{"a", "b"} {
+		go func() {
+			release, err := AcquireJob(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			order <- name
+			release()
+		}()
+		// Queue a before b
+		waitFor(t, name+" to queue", func(s JobStats) bool { return s.Waiting == i+1 })
+	}
+
+	release()
+	for _, want := range []string{"a", "b"} {
+		if got := <-order; got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	}
+	waitFor(t, "the queue to drain", func(s JobStats) bool { return s.Running == 0 && s.Waiting == 0 })
+}
+
+func TestAcquireJobCancelWhileQueued(t *testing.T) {
+	withLimiter(t, 1)
+	release, err := AcquireJob(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := AcquireJob(ctx)
+		errc <- err
+	}()
+	waitFor(t, "the job to queue", func(s JobStats) bool { return s.Waiting == 1 })
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("AcquireJob after cancel = %v, want %v", err, context.Canceled)
+	}
+	if got := CurrentJobs(); got.Running != 1 || got.Waiting != 0 {
+		t.Errorf("after cancel: %+v, want 1 running and none waiting", got)
+	}
+
+	// The cancelled waiter mustn't keep the freed slot
+	release()
+	next, err := AcquireJob(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	next()
+}
+
+func TestSetJobLimitGrows(t *testing.T) {
+	withLimiter(t, 1)
+	release, err := AcquireJob(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	acquired := make(chan func(), 2)
+	for range 2 {
+		go func() {
+			release, err := AcquireJob(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			acquired <- release
+		}()
+	}
+	waitFor(t, "both jobs to queue", func(s JobStats) bool { return s.Waiting == 2 })
+
+	SetJobLimit(3)
+	for range 2 {
+		select {
+		case release := <-acquired:
+			defer release()
+		case <-time.After(time.Second):
+			t.Fatal("queued job didn't start after the limit grew")
+		}
+	}
+	if got := CurrentJobs(); got != (JobStats{Running: 3, Waiting: 0, Limit: 3}) {
+		t.Errorf("CurrentJobs() = %+v, want 3 running under a limit of 3", got)
+	}
+}
+
+func TestReleaseCountsOnce(t *testing.T) {
+	withLimiter(t, 2)
+	release, err := AcquireJob(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := AcquireJob(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other()
+
+	release()
+	release()
+	if got := CurrentJobs().Running; got != 1 {
+		t.Errorf("running after releasing one job twice = %d, want 1", got)
+	}
+}
@@ -0,0 +1,109 @@
+package videocompare
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Quality metrics computed over whole files by CompareFiles.
+const (
+	PSNR = "psnr"
+	SSIM = "ssim"
+	VMAF = "vmaf"
+)
+
+var metricScorePatterns = map[string]*regexp.Regexp{
+	PSNR: regexp.MustCompile(`PSNR .*average:(inf|[0-9.]+)`),
+	SSIM: regexp.MustCompile(`SSIM .*All:([0-9.]+)`),
+	VMAF: regexp.MustCompile(`VMAF score[:=]\s*([0-9.]+)`),
+}
+
+var metricFilters = map[string]string{
+	PSNR: "psnr",
+	SSIM: "ssim",
+	VMAF: "libvmaf",
+}
+
+// IsMetric reports whether name is a metric CompareFiles understands.
+func IsMetric(name string) bool {
+	_, ok := metricFilters[name]
+	return ok
+}
+
+// CompareFiles runs ffmpeg to score the distorted file against the
+// reference with metric, averaged over all frames. The distorted clip is
+// scaled to the reference size first so files of different resolutions can
+// still be compared. PSNR is in dB, capped at 100 for identical inputs.
+func CompareFiles(ctx context.Context, metric, reference, distorted string) (float64, error) {
+	filter, ok := metricFilters[metric]
+	if !ok {
+		return 0, fmt.Errorf("unknown metric %q", metric)
+	}
+	graph := fmt.Sprintf("[1:v][0:v]scale2ref[dist][ref];[dist][ref]%s", filter)
+	log, err := RunFFmpegLog(ctx, "-i", reference, "-i", distorted, "-lavfi", graph, "-f", "null", "-")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", metric, err)
+	}
+	return ParseScore(metric, log)
+}
+
+// ParseScore extracts the overall metric score from ffmpeg's log output.
+func ParseScore(metric, log string) (float64, error) {
+	pattern, ok := metricScorePatterns[metric]
+	if !ok {
+		return 0, fmt.Errorf("unknown metric %q", metric)
+	}
+	m := pattern.FindAllStringSubmatch(log, -1)
+	if len(m) == 0 {
+		return 0, fmt.Errorf("no %s score in ffmpeg output", metric)
+	}
+	value := m[len(m)-1][1]
+	if value == "inf" {
+		// Identical inputs; report a large finite value so it encodes as JSON
+		return 100, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// FrameScore is one frame's score from a metric filter's per-frame
+// statistics.
+type FrameScore struct {
+	Frame int     // index of the frame, from 0
+	Score float64 // PSNR in dB, 100 for identical frames
+}
+
+// ParseFramePSNR reads one line of the psnr filter's stats_file output,
+// such as "n:1 mse_avg:0.68 ... psnr_avg:49.79 ...". ok is false for
+// lines without a frame number and score.
+func ParseFramePSNR(line string) (s FrameScore, ok bool) {
+	var haveFrame, haveScore bool
+	for _, field := range strings.Fields(line) {
+		key, value, found := strings.Cut(field, ":")
+		if !found {
+			continue
+		}
+		switch key {
+		case "n":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return s, false
+			}
+			s.Frame, haveFrame = n-1, true
+		case "psnr_avg":
+			if value == "inf" {
+				// Identical frames, reported like an identical file
+				s.Score, haveScore = 100, true
+				continue
+			}
+			score, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return s, false
+			}
+			s.Score, haveScore = score, true
+		}
+	}
+	return s, haveFrame && haveScore
+}
@@ -0,0 +1,62 @@
+package videocompare
+
+import "testing"
+
+func TestParseFramePSNR(t *testing.T) {
+	tests := []struct {
+		line string
+		want FrameScore
+		ok   bool
+	}{
+		{"n:1 mse_avg:0.68 mse_y:0.82 mse_u:0.40 mse_v:0.39 psnr_avg:49.79 psnr_y:48.98 psnr_u:52.11 psnr_v:52.24",
+			FrameScore{Frame: 0, Score: 49.79}, true},
+		{"n:42 mse_avg:0.00 psnr_avg:inf psnr_y:inf", FrameScore{Frame: 41, Score: 100}, true},
+		{"psnr_avg:49.79 psnr_y:48.98", FrameScore{}, false},
+		{"n:3 mse_avg:0.68", FrameScore{}, false},
+		{"n:0 psnr_avg:40.00", FrameScore{}, false},
+		{"n:x psnr_avg:40.00", FrameScore{}, false},
+		{"n:2 psnr_avg:nan?", FrameScore{}, false},
+		{"", FrameScore{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseFramePSNR(tt.line)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("ParseFramePSNR(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseScore(t *testing.T) {
+	tests := []struct {
+		metric string
+		log    string
+		want   float64
+		ok     bool
+	}{
+		{PSNR, "[Parsed_psnr_1 @ 0x1] PSNR y:38.12 u:42.00 v:41.90 average:39.25 min:30.01 max:45.70", 39.25, true},
+		{PSNR, "[Parsed_psnr_1 @ 0x1] PSNR y:inf u:inf v:inf average:inf min:inf max:inf", 100, true},
+		{SSIM, "[Parsed_ssim_1 @ 0x1] SSIM Y:0.981 (17.2) U:0.990 (20.0) V:0.989 (19.6) All:0.984312 (18.04)", 0.984312, true},
+		{VMAF, "[Parsed_libvmaf_1 @ 0x1] VMAF score: 93.561234", 93.561234, true},
+		// The last score wins when a filter reports several
+		{PSNR, "PSNR average:30.00 min:1\nPSNR average:31.50 min:1", 31.5, true},
+		{PSNR, "no score here", 0, false},
+		{"bogus", "PSNR average:30.00", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseScore(tt.metric, tt.log)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseScore(%q, %q) = %v, %v, want %v (ok %v)", tt.metric, tt.log, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIsMetric(t *testing.T) {
+	for _, m := range []string{PSNR, SSIM, VMAF} {
+		if !IsMetric(m) {
+			t.Errorf("IsMetric(%q) = false", m)
+		}
+	}
+	if IsMetric("ms-ssim") {
+		t.Error(`IsMetric("ms-ssim") = true`)
+	}
+}
@@ -0,0 +1,270 @@
+package videocompare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Info holds the properties of a file's first video stream.
+type Info struct {
+	Codec       string  `json:"codec"`
+	Width       int     `json:"width"`
+	Height      int     `json:"height"`
+	FrameRate   string  `json:"frame_rate"` // as a ratio, e.g. 30000/1001
+	PixelFormat string  `json:"pixel_format"`
+	BitDepth    int     `json:"bit_depth"`
+	Chroma      string  `json:"chroma_subsampling"` // e.g. 4:2:0
+	ColorRange  string  `json:"color_range"`        // tv, pc or unspecified
+	Duration    float64 `json:"duration"`           // seconds
+	Bitrate     int     `json:"bitrate"`            // bits per second
+}
+
+// Difference is one property that differs between two files.
+type Difference struct {
+	Field string `json:"field"`
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+// Probe reads the properties of path's first video stream with ffprobe.
+func Probe(ctx context.Context, path string) (Info, error) {
+	out, err := RunFFprobe(ctx, "-select_streams", "v:0",
+		"-show_entries", "stream=codec_name,width,height,r_frame_rate,pix_fmt,bits_per_raw_sample,color_range,bit_rate:format=duration,bit_rate",
+		path)
+	if err != nil {
+		return Info{}, fmt.Errorf("probing %s: %w", path, err)
+	}
+
+	var probe struct {
+		Streams []struct {
+			CodecName  string `json:"codec_name"`
+			Width      int    `json:"width"`
+			Height     int    `json:"height"`
+			FrameRate  string `json:"r_frame_rate"`
+			PixFmt     string `json:"pix_fmt"`
+			BitsPerRaw string `json:"bits_per_raw_sample"`
+			ColorRange string `json:"color_range"`
+			BitRate    string `json:"bit_rate"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+			BitRate  string `json:"bit_rate"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return Info{}, fmt.Errorf("parsing ffprobe output for %s: %w", path, err)
+	}
+	if len(probe.Streams) == 0 {
+		return Info{}, fmt.Errorf("%s: no video stream", path)
+	}
+
+	s := probe.Streams[0]
+	info := Info{
+		Codec:       s.CodecName,
+		Width:       s.Width,
+		Height:      s.Height,
+		FrameRate:   s.FrameRate,
+		PixelFormat: s.PixFmt,
+		ColorRange:  s.ColorRange,
+	}
+	info.BitDepth, info.Chroma = ParsePixelFormat(s.PixFmt)
+	if bits, err := strconv.Atoi(s.BitsPerRaw); err == nil && bits > 0 {
+		info.BitDepth = bits
+	}
+	if info.ColorRange == "" || info.ColorRange == "unknown" {
+		info.ColorRange = "unspecified"
+	}
+	info.Duration, _ = strconv.ParseFloat(probe.Format.Duration, 64)
+	info.Bitrate, _ = strconv.Atoi(s.BitRate)
+	if info.Bitrate == 0 {
+		info.Bitrate, _ = strconv.Atoi(probe.Format.BitRate)
+	}
+	return info, nil
+}
+
+var pixFmtDepthPattern = regexp.MustCompile(`(?:p|^gray)(\d+)(le|be)?$`)
+
+// ParsePixelFormat derives bit depth and chroma subsampling from an ffmpeg
+// pixel format name such as yuv420p10le. The chroma is "" when the format
+// doesn't reveal it.
+func ParsePixelFormat(pixFmt string) (int, string) {
+	depth := 8
+	switch {
+	case strings.HasPrefix(pixFmt, "p010"):
+		depth = 10
+	case strings.HasPrefix(pixFmt, "p016"), strings.Contains(pixFmt, "48"), strings.Contains(pixFmt, "64"):
+		depth = 16
+	default:
+		if m := pixFmtDepthPattern.FindStringSubmatch(pixFmt); m != nil {
+			if d, err := strconv.Atoi(m[1]); err == nil && d > 8 {
+				depth = d
+			}
+		}
+	}
+
+	chroma := ""
+	switch {
+	case strings.Contains(pixFmt, "420"), strings.HasPrefix(pixFmt, "nv12"), strings.HasPrefix(pixFmt, "nv21"),
+		strings.HasPrefix(pixFmt, "p010"), strings.HasPrefix(pixFmt, "p016"):
+		chroma = "4:2:0"
+	case strings.Contains(pixFmt, "422"), strings.HasPrefix(pixFmt, "nv16"), strings.HasPrefix(pixFmt, "yuyv"),
+		strings.HasPrefix(pixFmt, "uyvy"):
+		chroma = "4:2:2"
+	case strings.Contains(pixFmt, "444"), strings.Contains(pixFmt, "rgb"), strings.Contains(pixFmt, "bgr"),
+		strings.HasPrefix(pixFmt, "gbr"):
+		chroma = "4:4:4"
+	case strings.Contains(pixFmt, "411"):
+		chroma = "4:1:1"
+	case strings.HasPrefix(pixFmt, "gray"):
+		chroma = "4:0:0"
+	}
+	return depth, chroma
+}
+
+// Diff lists the properties that differ between left and right, in a
+// fixed order. It returns an empty, non-nil slice when they match.
+func Diff(left, right Info) []Difference {
+	fields := []struct {
+		name        string
+		left, right string
+	}{
+		{"codec", left.Codec, right.Codec},
+		{"resolution", fmt.Sprintf("%dx%d", left.Width, left.Height), fmt.Sprintf("%dx%d", right.Width, right.Height)},
+		{"frame_rate", left.FrameRate, right.FrameRate},
+		{"pixel_format", left.PixelFormat, right.PixelFormat},
+		{"bit_depth", strconv.Itoa(left.BitDepth), strconv.Itoa(right.BitDepth)},
+		{"chroma_subsampling", left.Chroma, right.Chroma},
+		{"color_range", left.ColorRange, right.ColorRange},
+		{"duration", fmt.Sprintf("%.3f", left.Duration), fmt.Sprintf("%.3f", right.Duration)},
+		{"bitrate", strconv.Itoa(left.Bitrate), strconv.Itoa(right.Bitrate)},
+	}
+
+	diffs := []Difference{}
+	for _, f := range fields {
+		if f.left != f.right {
+			diffs = append(diffs, Difference{Field: f.name, Left: f.left, Right: f.right})
+		}
+	}
+	return diffs
+}
+
+// ProbeDuration returns the container duration of path in seconds.
+func ProbeDuration(ctx context.Context, path string) (float64, error) {
+	format, err := probeFormat(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+	d, err := strconv.ParseFloat(format.Duration, 64)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s has no duration", filepath.Base(path))
+	}
+	return d, nil
+}
+
+// ProbeStartTime returns the container start time of path in seconds, 0
+// when it has none. ffprobe and ffmpeg report frame times from the
+// original timestamps, players from 0, so subtracting it maps one onto
+// the other.
+func ProbeStartTime(ctx context.Context, path string) (float64, error) {
+	format, err := probeFormat(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+	start, _ := strconv.ParseFloat(format.StartTime, 64)
+	return start, nil
+}
+
+type formatInfo struct {
+	Duration  string `json:"duration"`
+	StartTime string `json:"start_time"`
+}
+
+func probeFormat(ctx context.Context, path string) (formatInfo, error) {
+	out, err := RunFFprobe(ctx, "-show_entries", "format=duration,start_time", path)
+	if err != nil {
+		return formatInfo{}, err
+	}
+	var probe struct {
+		Format formatInfo `json:"format"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return formatInfo{}, fmt.Errorf("parsing ffprobe output: %w", err)
+	}
+	return probe.Format, nil
+}
+
+// ProbeFrameRate reads the frame rate of path's first video stream: the
+// base rate, or the average one when the base rate isn't set.
+func ProbeFrameRate(ctx context.Context, path string) (float64, error) {
+	out, err := RunFFprobe(ctx, "-select_streams", "v:0", "-show_entries", "stream=r_frame_rate,avg_frame_rate", path)
+	if err != nil {
+		return 0, err
+	}
+	var probe struct {
+		Streams []struct {
+			Rate    string `json:"r_frame_rate"`
+			AvgRate string `json:"avg_frame_rate"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return 0, fmt.Errorf("parsing ffprobe output: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return 0, fmt.Errorf("no video stream")
+	}
+	s := probe.Streams[0]
+	for _, rate := range []string{s.Rate, s.AvgRate} {
+		if fps := ParseRate(rate); fps > 0 {
+			return fps, nil
+		}
+	}
+	return 0, fmt.Errorf("no frame rate for %s", filepath.Base(path))
+}
+
+// ProbeSampleRate returns the sample rate of path's first audio stream.
+func ProbeSampleRate(ctx context.Context, path string) (int, error) {
+	out, err := RunFFprobe(ctx, "-select_streams", "a:0", "-show_entries", "stream=sample_rate", path)
+	if err != nil {
+		return 0, err
+	}
+	var probe struct {
+		Streams []struct {
+			SampleRate string `json:"sample_rate"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return 0, fmt.Errorf("parsing ffprobe output: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return 0, fmt.Errorf("no audio stream")
+	}
+	rate, err := strconv.Atoi(probe.Streams[0].SampleRate)
+	if err != nil || rate <= 0 {
+		return 0, fmt.Errorf("no sample rate for %s", filepath.Base(path))
+	}
+	return rate, nil
+}
+
+// ParseRate converts a rate ffprobe prints as a ratio such as 30000/1001,
+// or as a plain number, to a float. It returns 0 for "0/0" and anything
+// it can't parse.
+func ParseRate(rate string) float64 {
+	num, den, ok := strings.Cut(strings.TrimSpace(rate), "/")
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	if !ok {
+		return n
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil || d == 0 {
+		return 0
+	}
+	return n / d
+}
@@ -0,0 +1,79 @@
+package videocompare
+
+import "testing"
+
+func TestParsePixelFormat(t *testing.T) {
+	tests := []struct {
+		pixFmt string
+		depth  int
+		chroma string
+	}{
+		{"yuv420p", 8, "4:2:0"},
+		{"yuv420p10le", 10, "4:2:0"},
+		{"yuv422p10be", 10, "4:2:2"},
+		{"yuv444p12le", 12, "4:4:4"},
+		{"nv12", 8, "4:2:0"},
+		{"p010le", 10, "4:2:0"},
+		{"p016le", 16, "4:2:0"},
+		{"yuyv422", 8, "4:2:2"},
+		{"rgb24", 8, "4:4:4"},
+		{"rgb48le", 16, "4:4:4"},
+		{"gbrp10le", 10, "4:4:4"},
+		{"yuv411p", 8, "4:1:1"},
+		{"gray", 8, "4:0:0"},
+		{"gray10le", 10, "4:0:0"},
+		{"pal8", 8, ""},
+		{"", 8, ""},
+	}
+	for _, tt := range tests {
+		depth, chroma := ParsePixelFormat(tt.pixFmt)
+		if depth != tt.depth || chroma != tt.chroma {
+			t.Errorf("ParsePixelFormat(%q) = %d, %q, want %d, %q", tt.pixFmt, depth, chroma, tt.depth, tt.chroma)
+		}
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		rate string
+		want float64
+	}{
+		{"25/1", 25},
+		{"30000/1001", 30000.0 / 1001},
+		{"24", 24},
+		{" 50/1 ", 50},
+		{"0/0", 0},
+		{"1/0", 0},
+		{"N/A", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := ParseRate(tt.rate); got != tt.want {
+			t.Errorf("ParseRate(%q) = %v, want %v", tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	left := Info{Codec: "h264", Width: 1920, Height: 1080, FrameRate: "25/1", PixelFormat: "yuv420p",
+		BitDepth: 8, Chroma: "4:2:0", ColorRange: "tv", Duration: 10, Bitrate: 5000000}
+	if diffs := Diff(left, left); diffs == nil || len(diffs) != 0 {
+		t.Errorf("Diff of identical files = %#v, want an empty non-nil slice", diffs)
+	}
+
+	right := left
+	right.Codec, right.Height, right.Duration = "hevc", 720, 10.0004
+	want := []Difference{
+		{"codec", "h264", "hevc"},
+		{"resolution", "1920x1080", "1920x720"},
+	}
+	diffs := Diff(left, right)
+	if len(diffs) != len(want) {
+		t.Fatalf("Diff = %#v, want %#v", diffs, want)
+	}
+	for i := range want {
+		if diffs[i] != want[i] {
+			t.Errorf("Diff[%d] = %#v, want %#v", i, diffs[i], want[i])
+		}
+	}
+}